@@ -1,18 +1,37 @@
 package main
 
 import (
+	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"reflect"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
+	"syscall"
+	"text/template"
 	"time"
 
 	"github.com/spf13/cobra"
 
+	"skillpm/internal/adapter"
 	"skillpm/internal/app"
+	"skillpm/internal/audit"
 	"skillpm/internal/config"
+	"skillpm/internal/doctor"
+	"skillpm/internal/resolver"
+	"skillpm/internal/security"
+	"skillpm/internal/selfupdate"
+	"skillpm/internal/source"
 	"skillpm/internal/store"
 	syncsvc "skillpm/internal/sync"
 )
@@ -33,9 +52,12 @@ func main() {
 	root := newRootCmd()
 	if err := root.Execute(); err != nil {
 		if isJSONMode(root) {
-			blob, _ := json.Marshal(map[string]string{
-				"error": err.Error(),
-			})
+			payload := map[string]any{"error": err.Error()}
+			var blocked *security.BlockedError
+			if errors.As(err, &blocked) {
+				payload["findings"] = blocked.Report.Findings
+			}
+			blob, _ := json.Marshal(payload)
 			fmt.Fprintln(os.Stderr, string(blob))
 		} else {
 			fmt.Fprintln(os.Stderr, err)
@@ -52,16 +74,53 @@ func isJSONMode(cmd *cobra.Command) bool {
 	return f != nil && f.Value.String() == "true"
 }
 
+// isQuiet reports whether --quiet was set. Commands built outside the root
+// command (as in tests) have no such flag and are never quiet.
+func isQuiet(cmd *cobra.Command) bool {
+	f := cmd.Flags().Lookup("quiet")
+	return f != nil && f.Value.String() == "true"
+}
+
+// useDecoration reports whether decorative output such as emoji is on. The
+// first that applies wins: --force-color, --no-color, a non-empty NO_COLOR
+// environment variable, then whether stdout is a terminal.
+func useDecoration(cmd *cobra.Command) bool {
+	if f := cmd.Flags().Lookup("force-color"); f != nil && f.Value.String() == "true" {
+		return true
+	}
+	if f := cmd.Flags().Lookup("no-color"); f != nil && f.Value.String() == "true" {
+		return false
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	return stdoutIsTerminal()
+}
+
+// decorate returns prefix when decorative output is on, and "" otherwise.
+func decorate(cmd *cobra.Command, prefix string) string {
+	if useDecoration(cmd) {
+		return prefix
+	}
+	return ""
+}
+
 func newRootCmd() *cobra.Command {
 	var configPath string
 	var jsonOutput bool
+	var quiet bool
+	var fields []string
+	var format string
+	var noColor bool
+	var forceColor bool
 	var scopeFlag string
 
 	newSvc := func() (*app.Service, error) {
 		return app.New(app.Options{
 			ConfigPath: configPath,
 			Scope:      config.Scope(scopeFlag),
-			JSONMode:   jsonOutput,
+			JSONMode:   jsonOutput || format != "",
+			Quiet:      quiet,
 		})
 	}
 
@@ -73,14 +132,34 @@ func newRootCmd() *cobra.Command {
 	}
 	cmd.PersistentFlags().StringVar(&configPath, "config", "", "path to config file")
 	cmd.PersistentFlags().BoolVar(&jsonOutput, "json", false, "output JSON")
+	cmd.PersistentFlags().BoolVar(&quiet, "quiet", false, "suppress progress output; print only errors and results")
+	cmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "disable color and emoji output (also set by NO_COLOR)")
+	cmd.PersistentFlags().BoolVar(&forceColor, "force-color", false, "keep color and emoji output even when stdout is not a terminal; overrides --no-color and NO_COLOR")
 	cmd.PersistentFlags().StringVar(&scopeFlag, "scope", "", "scope: global or project (auto-detected if omitted)")
+	cmd.PersistentFlags().StringArrayVar(&fields, "field", nil, "with --json, print only this dotted field path (repeatable)")
+	cmd.PersistentFlags().StringVar(&format, "format", "", "render results through a Go template instead of text or JSON: 'template:{{.SkillRef}}'")
+	cmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if _, err := parseOutputFormat(format); err != nil {
+			return err
+		}
+		if format != "" && len(fields) > 0 {
+			return fmt.Errorf("OUT_FORMAT: --format cannot be combined with --field")
+		}
+		if len(fields) > 0 && !jsonOutput {
+			return fmt.Errorf("OUT_FIELD: --field requires --json")
+		}
+		return nil
+	}
 
 	cmd.AddCommand(newSourceCmd(newSvc, &jsonOutput))
 	cmd.AddCommand(newSearchCmd(newSvc, &jsonOutput))
 	cmd.AddCommand(newInstallCmd(newSvc, &jsonOutput))
 	cmd.AddCommand(newUninstallCmd(newSvc, &jsonOutput))
 	cmd.AddCommand(newUpgradeCmd(newSvc, &jsonOutput))
+	cmd.AddCommand(newPinCmd(newSvc, &jsonOutput))
+	cmd.AddCommand(newUnpinCmd(newSvc, &jsonOutput))
 	cmd.AddCommand(newInjectCmd(newSvc, &jsonOutput))
+	cmd.AddCommand(newRemoveCmd(newSvc, &jsonOutput))
 	cmd.AddCommand(newSyncCmd(newSvc, &jsonOutput))
 	cmd.AddCommand(newDoctorCmd(newSvc, &jsonOutput))
 	cmd.AddCommand(newVersionCmd(&jsonOutput))
@@ -91,6 +170,14 @@ func newRootCmd() *cobra.Command {
 	cmd.AddCommand(newCreateCmd(newSvc, &jsonOutput))
 	cmd.AddCommand(newPublishCmd(newSvc, &jsonOutput))
 	cmd.AddCommand(newBundleCmd(newSvc, &jsonOutput))
+	cmd.AddCommand(newStoreCmd(newSvc, &jsonOutput))
+	cmd.AddCommand(newExportCmd(newSvc, &jsonOutput))
+	cmd.AddCommand(newImportCmd(newSvc, &jsonOutput))
+	cmd.AddCommand(newProvenanceCmd(newSvc, &jsonOutput))
+	cmd.AddCommand(newSkillCmd(newSvc, &jsonOutput))
+	cmd.AddCommand(newConfigCmd(&configPath, &jsonOutput))
+	cmd.AddCommand(newScopeCmd(&scopeFlag, &jsonOutput))
+	cmd.AddCommand(newMetricsCmd(newSvc, &jsonOutput))
 
 	cmd.CompletionOptions.DisableDefaultCmd = true
 	return cmd
@@ -100,6 +187,7 @@ func newSourceCmd(newSvc func() (*app.Service, error), jsonOutput *bool) *cobra.
 	var kind string
 	var branch string
 	var trustTier string
+	var noDuplicate bool
 
 	sourceCmd := &cobra.Command{Use: "source", Short: "Manage skill sources"}
 
@@ -111,23 +199,31 @@ func newSourceCmd(newSvc func() (*app.Service, error), jsonOutput *bool) *cobra.
 Examples:
   skillpm source add anthropic https://github.com/anthropics/skills.git
   skillpm source add mylab https://gitlab.com/team/skills --branch main
-  skillpm source add hub https://clawhub.ai --kind clawhub`,
+  skillpm source add hub https://clawhub.ai --kind clawhub
+  skillpm source add myskills https://artifacts.example.com/skills-v2.tar.gz --kind archive`,
 		Args: cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			svc, err := newSvc()
 			if err != nil {
 				return err
 			}
+			if dup, ok := svc.SourceDuplicate(args[0], args[1], kind); ok {
+				if noDuplicate {
+					return fmt.Errorf("SRC_DUPLICATE: %s is already configured as source %q", args[1], dup.Name)
+				}
+				fmt.Fprintf(os.Stderr, "warning: %s is already configured as source %q; use that name instead of adding it twice\n", args[1], dup.Name)
+			}
 			src, err := svc.SourceAdd(args[0], args[1], kind, branch, trustTier)
 			if err != nil {
 				return err
 			}
-			return print(*jsonOutput, src, fmt.Sprintf("added source %s (%s)", src.Name, src.Kind))
+			return print(cmd, *jsonOutput, src, fmt.Sprintf("added source %s (%s)", src.Name, src.Kind))
 		},
 	}
-	addCmd.Flags().StringVar(&kind, "kind", "", "source kind: git|dir|clawhub")
+	addCmd.Flags().StringVar(&kind, "kind", "", "source kind: git|dir|clawhub|archive")
 	addCmd.Flags().StringVar(&branch, "branch", "main", "git branch")
 	addCmd.Flags().StringVar(&trustTier, "trust-tier", "review", "trusted|review|untrusted")
+	addCmd.Flags().BoolVar(&noDuplicate, "no-duplicate", false, "fail instead of warning when another source already uses the same URL or registry")
 
 	removeCmd := &cobra.Command{
 		Use:   "remove <name>",
@@ -141,10 +237,11 @@ Examples:
 			if err := svc.SourceRemove(args[0]); err != nil {
 				return err
 			}
-			return print(*jsonOutput, map[string]string{"removed": args[0]}, "removed source "+args[0])
+			return print(cmd, *jsonOutput, map[string]string{"removed": args[0]}, "removed source "+args[0])
 		},
 	}
 
+	var detail bool
 	listCmd := &cobra.Command{
 		Use:   "list",
 		Short: "List sources",
@@ -153,9 +250,26 @@ Examples:
 			if err != nil {
 				return err
 			}
+			if detail {
+				statuses, err := svc.SourceStatus(context.Background())
+				if err != nil {
+					return err
+				}
+				if structuredOutput(cmd, *jsonOutput) {
+					return print(cmd, true, statuses, "")
+				}
+				if len(statuses) == 0 {
+					fmt.Println("no sources configured")
+					return nil
+				}
+				for _, st := range statuses {
+					printSourceStatus(st)
+				}
+				return nil
+			}
 			sources := svc.SourceList()
-			if *jsonOutput {
-				return print(true, sources, "")
+			if structuredOutput(cmd, *jsonOutput) {
+				return print(cmd, true, sources, "")
 			}
 			if len(sources) == 0 {
 				fmt.Println("no sources configured")
@@ -189,8 +303,8 @@ Examples:
 			if err != nil {
 				return err
 			}
-			if *jsonOutput {
-				return print(true, updated, "")
+			if structuredOutput(cmd, *jsonOutput) {
+				return print(cmd, true, updated, "")
 			}
 			for _, u := range updated {
 				fmt.Printf("updated %s: %s\n", u.Source.Name, u.Note)
@@ -199,45 +313,220 @@ Examples:
 		},
 	}
 
-	sourceCmd.AddCommand(addCmd, removeCmd, listCmd, updateCmd)
+	reviewCmd := &cobra.Command{
+		Use:   "review <name>",
+		Short: "Record that a source was re-audited",
+		Long: `Record that a source was re-audited, restarting its review_interval.
+doctor and status warn about sources whose interval has lapsed.
+
+Examples:
+  skillpm source review internal`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			svc, err := newSvc()
+			if err != nil {
+				return err
+			}
+			src, err := svc.SourceReview(args[0])
+			if err != nil {
+				return err
+			}
+			msg := fmt.Sprintf("reviewed source %s at %s", src.Name, src.LastReviewed)
+			if src.ReviewInterval == "" {
+				msg += " (no review_interval set)"
+			}
+			return print(cmd, *jsonOutput, src, msg)
+		},
+	}
+
+	var constraint string
+	versionsCmd := &cobra.Command{
+		Use:   "versions <source/skill>",
+		Short: "List a skill's versions and preview what a constraint selects",
+		Long: `List the versions a source publishes for a skill, newest first, and mark
+the one a constraint would resolve to, by the same rules as install.
+Nothing is fetched or installed.
+
+Clawhub sources take latest (default), an exact version, tag:<name> (or a
+bare tag) and sha256:<hash>; tags are resolved by the registry at install
+time. Git sources serve the checked-out commit as 0.0.0+git.<commit>.
+
+Examples:
+  skillpm source versions clawhub/pdf
+  skillpm source versions clawhub/pdf --constraint 1.2.0`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			svc, err := newSvc()
+			if err != nil {
+				return err
+			}
+			preview, err := svc.SourceVersions(context.Background(), args[0], constraint)
+			if err != nil {
+				return err
+			}
+			if structuredOutput(cmd, *jsonOutput) {
+				return print(cmd, true, preview, "")
+			}
+			if len(preview.Versions) == 0 {
+				fmt.Printf("no versions published for %s\n", preview.Ref)
+				return nil
+			}
+			for _, v := range preview.Versions {
+				marker := "  "
+				if v.Selected {
+					marker = "* "
+				}
+				fmt.Printf("%s%s\n", marker, v.Version)
+			}
+			label := preview.Constraint
+			if label == "" {
+				label = "latest"
+			}
+			if preview.Selected == "" {
+				fmt.Printf("%s: %s\n", label, preview.Reason)
+			} else {
+				fmt.Printf("%s selects %s (%s)\n", label, preview.Selected, preview.Reason)
+			}
+			return nil
+		},
+	}
+	versionsCmd.Flags().StringVar(&constraint, "constraint", "", "constraint to preview, e.g. 1.2.0 or tag:stable (defaults to the ref's @constraint or latest)")
+
+	listCmd.Flags().BoolVar(&detail, "detail", false, "show cache path, commit, last update and skill count")
+
+	sourceCmd.AddCommand(addCmd, removeCmd, listCmd, updateCmd, reviewCmd, versionsCmd)
 	return sourceCmd
 }
 
+func printSourceStatus(st source.SourceStatus) {
+	s := st.Source
+	target := s.URL
+	if s.Kind == "clawhub" {
+		target = s.Registry
+	}
+	fmt.Printf("- %s (%s) %s trust=%s\n", s.Name, s.Kind, target, s.TrustTier)
+	if st.CachePath == "" {
+		fmt.Println("  cache:   none (remote registry)")
+		return
+	}
+	fmt.Printf("  cache:   %s\n", st.CachePath)
+	if !st.Cloned {
+		fmt.Printf("  status:  not cloned (run 'skillpm source update %s')\n", s.Name)
+		return
+	}
+	commit := st.Commit
+	if commit == "" {
+		commit = "unknown"
+	}
+	fmt.Printf("  commit:  %s\n", commit)
+	if st.LastUpdated != nil {
+		fmt.Printf("  updated: %s\n", st.LastUpdated.Format(time.RFC3339))
+	}
+	fmt.Printf("  skills:  %d\n", st.SkillCount)
+}
+
 func newSearchCmd(newSvc func() (*app.Service, error), jsonOutput *bool) *cobra.Command {
-	var sourceName string
+	var sourceNames []string
+	var refresh bool
+	var dedupe bool
+	var trustTier string
+	var useRegex bool
+	var useGlob bool
 	cmd := &cobra.Command{
 		Use:   "search <query>",
 		Short: "Search available skills",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if useRegex && useGlob {
+				return fmt.Errorf("SRC_SEARCH: --regex and --glob cannot be combined")
+			}
 			svc, err := newSvc()
 			if err != nil {
 				return err
 			}
-			items, err := svc.Search(context.Background(), sourceName, args[0])
+			search := svc.Search
+			if refresh {
+				search = svc.SearchRefresh
+			}
+			if mode := searchMode(useRegex, useGlob); mode != source.SearchSubstring {
+				search = func(ctx context.Context, sourceNames []string, query string) ([]source.SearchResult, error) {
+					return svc.SearchPattern(ctx, sourceNames, query, mode, refresh)
+				}
+			}
+			items, err := search(context.Background(), sourceNames, args[0])
 			if err != nil {
 				return err
 			}
-			if *jsonOutput {
-				return print(true, items, "")
+			if trustTier != "" {
+				if items, err = svc.FilterSearchByTier(items, trustTier); err != nil {
+					return err
+				}
+			}
+			if dedupe {
+				items = svc.DedupeSearch(items)
+			}
+			if structuredOutput(cmd, *jsonOutput) {
+				return print(cmd, true, items, "")
+			}
+			if len(items) == 0 && trustTier != "" {
+				fmt.Printf("no results at tier %s\n", trustTier)
+				return nil
 			}
 			if len(items) == 0 {
 				fmt.Println("no results")
 				return nil
 			}
 			for _, item := range items {
-				fmt.Printf("- %s/%s: %s\n", item.Source, item.Slug, item.Description)
+				fmt.Printf("- %s/%s: %s%s%s\n", item.Source, item.Slug, item.Description, alsoNote(item.AlsoIn), deprecationNote(item.Deprecated, item.SupersededBy))
+				if item.Match == source.MatchBody {
+					fmt.Printf("    matched: %s\n", item.Snippet)
+				}
 			}
 			return nil
 		},
 	}
-	cmd.Flags().StringVar(&sourceName, "source", "", "source name")
+	cmd.Flags().StringSliceVar(&sourceNames, "source", nil, "source name(s) to search, comma-separated or repeated (default all)")
+	cmd.Flags().BoolVar(&refresh, "refresh", false, "ignore cached search results")
+	cmd.Flags().BoolVar(&dedupe, "dedupe", false, "show a skill offered by several sources once, from the most trusted source")
+	cmd.Flags().StringVar(&trustTier, "trust-tier", "", "only show results from sources at this trust tier or above (trusted|review|untrusted)")
+	cmd.Flags().BoolVar(&useRegex, "regex", false, "match the query as a case-insensitive regular expression against skill names and descriptions")
+	cmd.Flags().BoolVar(&useGlob, "glob", false, "match the query as a case-insensitive glob against skill names, slugs and descriptions")
 	return cmd
 }
 
+// searchMode maps the search command's pattern flags to a source.SearchMode.
+func searchMode(useRegex, useGlob bool) source.SearchMode {
+	switch {
+	case useRegex:
+		return source.SearchRegex
+	case useGlob:
+		return source.SearchGlob
+	}
+	return source.SearchSubstring
+}
+
+// alsoNote names the other sources or refs folded into a deduplicated entry.
+func alsoNote(also []string) string {
+	if len(also) == 0 {
+		return ""
+	}
+	return " (also in " + strings.Join(also, ", ") + ")"
+}
+
 func newInstallCmd(newSvc func() (*app.Service, error), jsonOutput *bool) *cobra.Command {
 	var force bool
 	var lockfile string
+	var resolveOnly bool
+	var keepGoing bool
+	var noManifest bool
+	var pinSource string
+	var interactive bool
+	var verifySignatures bool
+	var explain bool
+	var stream bool
+	var platform string
+	var retries int
+	var maxSeverity string
 	cmd := &cobra.Command{
 		Use:   "install <source/skill[@constraint]>...",
 		Short: "Install skills",
@@ -249,6 +538,7 @@ Examples:
   skillpm install https://gitlab.com/org/repo/-/tree/main/skills/review
   skillpm install clawhub/slack@1.2.3
   skillpm install anthropic/docx anthropic/pdf
+  skillpm install --json --stream --keep-going anthropic/docx anthropic/pdf
 
 Accepts: <source/skill[@constraint]> or <URL> (GitHub, GitLab, Bitbucket, any git host)`,
 		Args: cobra.MinimumNArgs(1),
@@ -257,50 +547,343 @@ Accepts: <source/skill[@constraint]> or <URL> (GitHub, GitLab, Bitbucket, any gi
 			if err != nil {
 				return err
 			}
-			if !*jsonOutput {
-				fmt.Printf("📦 Resolving and installing %d skill(s)...\n", len(args))
+			if pinSource != "" {
+				args = pinBareRefs(args, pinSource)
 			}
-			installed, err := svc.Install(context.Background(), args, lockfile, force)
-			if err != nil {
+			if stream && !structuredOutput(cmd, *jsonOutput) {
+				return fmt.Errorf("INS_INSTALL: --stream requires --json")
+			}
+			if stream && (resolveOnly || interactive) {
+				return fmt.Errorf("INS_INSTALL: --stream cannot be combined with --resolve-only or --interactive")
+			}
+			if keepGoing && !resolveOnly && !stream {
+				return fmt.Errorf("INS_INSTALL: --keep-going requires --resolve-only or --stream")
+			}
+			if resolveOnly {
+				return runResolveOnly(cmd, svc, args, lockfile, keepGoing, structuredOutput(cmd, *jsonOutput))
+			}
+			if interactive && !stdinIsTerminal() {
+				return fmt.Errorf("INS_INTERACTIVE: --interactive requires a terminal on stdin")
+			}
+			svc.Installer.VerifySignatures = verifySignatures
+			if err := setRetries(svc, retries); err != nil {
+				return err
+			}
+			if platform != "" {
+				if !source.IsKnownPlatform(platform) {
+					return fmt.Errorf("INS_PLATFORM: unknown platform %q; use a GOOS value such as linux, darwin or windows", platform)
+				}
+				svc.Installer.Platform = platform
+			}
+			if !cmd.Flags().Changed("max-severity") {
+				maxSeverity = svc.Config.Defaults.InstallMaxSeverity
+			}
+			if err := setMaxSeverity(svc, maxSeverity); err != nil {
 				return err
 			}
-			if *jsonOutput {
-				return print(true, installed, "")
+			if stream {
+				return svc.InstallStream(context.Background(), args, lockfile, force, !noManifest, keepGoing, func(ev app.InstallEvent) {
+					if err := printEvent(cmd, ev); err != nil {
+						fmt.Fprintln(os.Stderr, err)
+					}
+				})
+			}
+			if !structuredOutput(cmd, *jsonOutput) && !isQuiet(cmd) {
+				fmt.Printf("%sResolving and installing %d skill(s)...\n", decorate(cmd, "📦 "), len(args))
+			}
+			install := svc.Install
+			if noManifest {
+				install = svc.InstallWithoutManifest
+			}
+			in := bufio.NewReader(os.Stdin)
+			var installed []app.InstallResult
+			for {
+				installed, err = install(context.Background(), args, lockfile, force)
+				var choiceErr *resolver.ChoiceError
+				if err == nil || !interactive || !errors.As(err, &choiceErr) {
+					break
+				}
+				picks, promptErr := promptChoice(in, os.Stderr, choiceErr)
+				if promptErr != nil {
+					return promptErr
+				}
+				args = replaceRef(args, choiceErr.Ref, picks)
+			}
+			if err != nil {
+				return explainScanBlock(err, explain, *jsonOutput)
 			}
+			if structuredOutput(cmd, *jsonOutput) {
+				return print(cmd, true, installed, "")
+			}
+			records := make([]store.InstalledSkill, 0, len(installed))
 			for _, item := range installed {
-				fmt.Printf("installed %s@%s\n", item.SkillRef, item.ResolvedVersion)
-				fmt.Printf("  -> %s\n", store.InstalledRoot(svc.StateRoot))
+				fmt.Printf("installed %s@%s%s\n", item.SkillRef, item.ResolvedVersion, attemptsNote(item.Attempts))
+				if !isQuiet(cmd) {
+					fmt.Printf("  -> %s\n", store.InstalledRoot(svc.StateRoot))
+				}
+				records = append(records, item.InstalledSkill)
 			}
+			warnDeprecated(os.Stderr, records)
 			return nil
 		},
 	}
-	cmd.Flags().BoolVar(&force, "force", false, "allow suspicious skills")
+	cmd.Flags().BoolVar(&force, "force", false, "allow suspicious skills; ignored by the scan gate when --max-severity or defaults.install_max_severity is set")
 	cmd.Flags().StringVar(&lockfile, "lockfile", "", "skills.lock path")
+	cmd.Flags().BoolVar(&resolveOnly, "resolve-only", false, "resolve refs and print the result without scanning or installing")
+	cmd.Flags().BoolVar(&keepGoing, "keep-going", false, "with --resolve-only or --stream, report per-ref errors instead of stopping at the first")
+	cmd.Flags().BoolVar(&stream, "stream", false, "with --json, print one JSON object per skill (NDJSON) as each ref finishes installing")
+	cmd.Flags().BoolVar(&noManifest, "no-manifest", false, "in project scope, install without recording the skill in the manifest")
+	cmd.Flags().BoolVar(&interactive, "interactive", false, "prompt to choose when a ref matches several skills")
+	cmd.Flags().BoolVar(&explain, "explain", false, "when the security scan blocks, print every finding (rule, severity, file, line)")
+	cmd.Flags().BoolVar(&verifySignatures, "verify-signatures", false, "require every skill to carry a SKILL.md.sig that verifies against its source public_key")
+	cmd.Flags().StringVar(&pinSource, "source", "", "resolve bare skill names from this source only")
+	cmd.Flags().StringVar(&platform, "platform", "", "install for this GOOS instead of the running one (filters platform-tagged files)")
+	cmd.Flags().IntVar(&retries, "retry", 0, "retry a skill's fetch up to N times with backoff on network failures")
+	cmd.Flags().StringVar(&maxSeverity, "max-severity", "", "allow scan findings up to this severity and refuse anything above it; takes precedence over --force (default from defaults.install_max_severity)")
 	return cmd
 }
 
+// setMaxSeverity gates the security scan at name: findings up to it are
+// allowed without --force and findings above it are refused even with it.
+// An empty name leaves the scan policy unchanged.
+func setMaxSeverity(svc *app.Service, name string) error {
+	if name == "" {
+		return nil
+	}
+	sev, ok := map[string]security.Severity{
+		"info":     security.SeverityInfo,
+		"low":      security.SeverityLow,
+		"medium":   security.SeverityMedium,
+		"high":     security.SeverityHigh,
+		"critical": security.SeverityCritical,
+	}[strings.ToLower(name)]
+	if !ok {
+		return fmt.Errorf("SEC_MAX_SEVERITY: unknown severity %q; use info, low, medium, high or critical", name)
+	}
+	if svc.Installer.Security != nil && svc.Installer.Security.Scanner != nil {
+		svc.Installer.Security.Scanner.SetMaxSeverity(sev)
+	}
+	return nil
+}
+
+// setRetries sets how often source updates and skill fetches retry
+// transient failures.
+func setRetries(svc *app.Service, n int) error {
+	if n < 0 {
+		return fmt.Errorf("SRC_RETRY: --retry must be 0 or more, got %d", n)
+	}
+	svc.Resolver.Retries = n
+	svc.SourceMgr.Retries = n
+	return nil
+}
+
+// attemptsNote marks a skill that needed more than one fetch.
+func attemptsNote(attempts int) string {
+	if attempts > 1 {
+		return fmt.Sprintf(" (after %d attempts)", attempts)
+	}
+	return ""
+}
+
+// explainScanBlock appends the findings behind a blocked security scan to
+// err: the full table with --explain, otherwise a hint to ask for it. JSON
+// mode reports the findings alongside the error instead.
+func explainScanBlock(err error, explain, jsonMode bool) error {
+	var blocked *security.BlockedError
+	if jsonMode || !errors.As(err, &blocked) {
+		return err
+	}
+	if !explain {
+		return fmt.Errorf("%w\nhint: run again with --explain to see all %d finding(s)", err, len(blocked.Report.Findings))
+	}
+	return fmt.Errorf("%w\n\n%s", err, strings.TrimRight(security.FormatFindingsTable(blocked.Report), "\n"))
+}
+
+// deprecationNote is the suffix appended to a listed skill that its source
+// marks deprecated.
+func deprecationNote(deprecated bool, supersededBy string) string {
+	switch {
+	case supersededBy != "":
+		return " [deprecated; use " + supersededBy + "]"
+	case deprecated:
+		return " [deprecated]"
+	}
+	return ""
+}
+
+// warnDeprecated tells the user which of the installed skills their source
+// marks deprecated, and what replaces them.
+func warnDeprecated(w io.Writer, installed []store.InstalledSkill) {
+	for _, item := range installed {
+		if !item.Deprecated {
+			continue
+		}
+		if item.SupersededBy != "" {
+			fmt.Fprintf(w, "warning: %s is deprecated; use %s instead\n", item.SkillRef, item.SupersededBy)
+		} else {
+			fmt.Fprintf(w, "warning: %s is deprecated\n", item.SkillRef)
+		}
+	}
+}
+
+// stdinIsTerminal reports whether stdin is an interactive terminal.
+var stdinIsTerminal = func() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// stdoutIsTerminal reports whether stdout is a terminal.
+var stdoutIsTerminal = func() bool {
+	info, err := os.Stdout.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// promptChoice lists the options of an ambiguous ref and reads the user's
+// pick: an option number, or "a" for all of them.
+func promptChoice(in *bufio.Reader, out io.Writer, choiceErr *resolver.ChoiceError) ([]string, error) {
+	fmt.Fprintf(out, "%s matches several skills:\n", choiceErr.Ref)
+	for i, option := range choiceErr.Options {
+		fmt.Fprintf(out, "  %d) %s\n", i+1, option)
+	}
+	for {
+		fmt.Fprintf(out, "choose 1-%d, or a for all: ", len(choiceErr.Options))
+		line, err := in.ReadString('\n')
+		answer := strings.ToLower(strings.TrimSpace(line))
+		if answer == "a" || answer == "all" {
+			return choiceErr.Options, nil
+		}
+		if n, convErr := strconv.Atoi(answer); convErr == nil && n >= 1 && n <= len(choiceErr.Options) {
+			return []string{choiceErr.Options[n-1]}, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("INS_INTERACTIVE: no selection for %s", choiceErr.Ref)
+		}
+	}
+}
+
+// replaceRef substitutes picks for the first occurrence of ref in args.
+func replaceRef(args []string, ref string, picks []string) []string {
+	out := make([]string, 0, len(args)+len(picks))
+	replaced := false
+	for _, arg := range args {
+		if !replaced && arg == ref {
+			out = append(out, picks...)
+			replaced = true
+			continue
+		}
+		out = append(out, arg)
+	}
+	return out
+}
+
+// pinBareRefs qualifies bare skill names with sourceName, leaving
+// source-qualified refs and URLs untouched.
+func pinBareRefs(refs []string, sourceName string) []string {
+	out := make([]string, len(refs))
+	for i, raw := range refs {
+		if _, _, ok := resolver.ParseBareRef(raw); ok {
+			out[i] = sourceName + "/" + strings.TrimSpace(raw)
+			continue
+		}
+		out[i] = raw
+	}
+	return out
+}
+
+func runResolveOnly(cmd *cobra.Command, svc *app.Service, refs []string, lockfile string, keepGoing, jsonOutput bool) error {
+	entries, err := svc.ResolveOnly(cmd.Context(), refs, lockfile, keepGoing)
+	if err != nil {
+		return err
+	}
+	failed := 0
+	for _, e := range entries {
+		if e.Error != "" {
+			failed++
+		}
+	}
+	if jsonOutput {
+		if err := print(cmd, true, entries, ""); err != nil {
+			return err
+		}
+	} else {
+		for _, e := range entries {
+			if e.Error != "" {
+				fmt.Printf("failed %s: %s\n", e.Ref, e.Error)
+				continue
+			}
+			fmt.Printf("resolved %s@%s (%s)\n", e.SkillRef, e.ResolvedVersion, e.Checksum)
+			if !isQuiet(cmd) {
+				fmt.Printf("  files: %s\n", strings.Join(e.Files, ", "))
+			}
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("INS_RESOLVE: %d of %d refs failed to resolve", failed, len(refs))
+	}
+	return nil
+}
+
 func newUninstallCmd(newSvc func() (*app.Service, error), jsonOutput *bool) *cobra.Command {
 	var lockfile string
+	var force bool
+	var cascade bool
+	var yes bool
 	cmd := &cobra.Command{
 		Use:   "uninstall <source/skill>...",
 		Short: "Uninstall skills",
 		Long: `Remove installed skills and clean up state.
 
+Uninstall refuses to remove a skill that other installed skills list in
+their SKILL.md deps. Use --dependents to remove those skills too, or
+--force to remove only the named skills anyway.
+
+On a terminal, uninstall first prints what it will do (skills removed,
+agents they are taken out of, manifest entries dropped, dependents left
+broken) and asks for confirmation; --yes skips the question.
+
 Examples:
   skillpm uninstall anthropic/docx
-  skillpm uninstall anthropic/docx clawhub/slack`,
+  skillpm uninstall anthropic/docx clawhub/slack
+  skillpm uninstall anthropic/docx --dependents
+  skillpm uninstall anthropic/docx --yes`,
 		Args: cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			svc, err := newSvc()
 			if err != nil {
 				return err
 			}
-			removed, err := svc.Uninstall(context.Background(), args, lockfile)
+			if cascade && !force {
+				dependents, dErr := svc.Dependents(args)
+				if dErr != nil {
+					return dErr
+				}
+				refs := make([]string, 0, len(dependents))
+				for ref := range dependents {
+					refs = append(refs, ref)
+				}
+				sort.Strings(refs)
+				args = append(args, refs...)
+			}
+			if !yes && !structuredOutput(cmd, *jsonOutput) && stdinIsTerminal() {
+				plan, pErr := svc.PlanUninstall(args)
+				if pErr != nil {
+					return pErr
+				}
+				if len(plan.Remove) == 0 {
+					fmt.Println("no skills removed")
+					return nil
+				}
+				printUninstallPlan(os.Stdout, plan)
+				if !confirm(bufio.NewReader(os.Stdin), os.Stdout, "proceed? [y/N] ") {
+					return fmt.Errorf("INS_UNINSTALL: cancelled; nothing was removed")
+				}
+			}
+			removed, err := svc.Uninstall(context.Background(), args, lockfile, force)
 			if err != nil {
 				return err
 			}
-			if *jsonOutput {
-				return print(true, removed, "")
+			if structuredOutput(cmd, *jsonOutput) {
+				return print(cmd, true, removed, "")
 			}
 			if len(removed) == 0 {
 				fmt.Println("no skills removed")
@@ -309,17 +892,57 @@ Examples:
 			for _, ref := range removed {
 				fmt.Printf("removed %s\n", ref)
 			}
-			fmt.Printf("  -> cleaned %s\n", store.InstalledRoot(svc.StateRoot))
+			if !isQuiet(cmd) {
+				fmt.Printf("  -> cleaned %s\n", store.InstalledRoot(svc.StateRoot))
+			}
 			return nil
 		},
 	}
 	cmd.Flags().StringVar(&lockfile, "lockfile", "", "skills.lock path")
+	cmd.Flags().BoolVar(&force, "force", false, "uninstall even if other installed skills depend on it")
+	cmd.Flags().BoolVar(&cascade, "dependents", false, "also uninstall skills that depend on it")
+	cmd.Flags().BoolVar(&yes, "yes", false, "do not ask for confirmation on a terminal")
 	return cmd
 }
 
+// printUninstallPlan renders what an uninstall will change.
+func printUninstallPlan(out io.Writer, plan app.UninstallPlan) {
+	fmt.Fprintf(out, "uninstall will:\n")
+	fmt.Fprintf(out, "  remove %d skill(s): %s\n", len(plan.Remove), strings.Join(plan.Remove, ", "))
+	for _, a := range plan.Agents {
+		fmt.Fprintf(out, "  take %s out of %s\n", strings.Join(a.Skills, ", "), a.Agent)
+	}
+	if len(plan.Manifest) > 0 {
+		fmt.Fprintf(out, "  drop from the project manifest: %s\n", strings.Join(plan.Manifest, ", "))
+	}
+	refs := make([]string, 0, len(plan.Dependents))
+	for ref := range plan.Dependents {
+		refs = append(refs, ref)
+	}
+	sort.Strings(refs)
+	for _, ref := range refs {
+		fmt.Fprintf(out, "  leave %s broken (requires %s)\n", ref, strings.Join(plan.Dependents[ref], ", "))
+	}
+	if len(plan.NotInstalled) > 0 {
+		fmt.Fprintf(out, "  skip %s (not installed)\n", strings.Join(plan.NotInstalled, ", "))
+	}
+}
+
+// confirm asks question and reports whether the answer was yes. Anything
+// else, including end of input, is no.
+func confirm(in *bufio.Reader, out io.Writer, question string) bool {
+	fmt.Fprint(out, question)
+	line, _ := in.ReadString('\n')
+	answer := strings.ToLower(strings.TrimSpace(line))
+	return answer == "y" || answer == "yes"
+}
+
 func newUpgradeCmd(newSvc func() (*app.Service, error), jsonOutput *bool) *cobra.Command {
 	var force bool
 	var lockfile string
+	var lockfileOnly bool
+	var maxSeverity string
+	var verifySignatures bool
 	cmd := &cobra.Command{
 		Use:   "upgrade [source/skill ...]",
 		Short: "Upgrade installed skills",
@@ -327,20 +950,74 @@ func newUpgradeCmd(newSvc func() (*app.Service, error), jsonOutput *bool) *cobra
 
 Examples:
   skillpm upgrade                   # upgrade all
-  skillpm upgrade anthropic/docx    # upgrade specific skill`,
+  skillpm upgrade anthropic/docx    # upgrade specific skill
+  skillpm upgrade --lockfile-only   # bump skills.lock, install later`,
 		Args: cobra.ArbitraryArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			svc, err := newSvc()
 			if err != nil {
 				return err
 			}
-			upgraded, err := svc.Upgrade(context.Background(), args, lockfile, force)
+			if err := setMaxSeverity(svc, maxSeverity); err != nil {
+				return err
+			}
+			svc.Installer.VerifySignatures = verifySignatures
+			if lockfileOnly {
+				changes, skipped, err := svc.UpgradeLockfile(context.Background(), args, lockfile)
+				if err != nil {
+					return err
+				}
+				if structuredOutput(cmd, *jsonOutput) {
+					out := lockUpgradeJSON{Changes: changes, Skipped: skipped}
+					if out.Changes == nil {
+						out.Changes = []app.LockChange{}
+					}
+					if out.Skipped == nil {
+						out.Skipped = []string{}
+					}
+					return print(cmd, true, out, "")
+				}
+				printPinnedSkips(skipped)
+				if len(changes) == 0 {
+					fmt.Println("lockfile already up to date")
+					return nil
+				}
+				for _, c := range changes {
+					from := c.From
+					if from == "" {
+						from = "(unlocked)"
+					}
+					fmt.Printf("locked %s: %s -> %s\n", c.SkillRef, from, c.To)
+				}
+				if !isQuiet(cmd) {
+					fmt.Println("installed skills are unchanged; run 'skillpm upgrade' to install the locked versions")
+				}
+				return nil
+			}
+			pinned, err := svc.PinnedSkills(lockfile)
+			if err != nil {
+				return err
+			}
+			for _, ref := range args {
+				if slices.Contains(pinned, strings.SplitN(ref, "@", 2)[0]) {
+					fmt.Fprintf(os.Stderr, "warning: %s is pinned; upgrading it because it was named\n", ref)
+				}
+			}
+			upgraded, skipped, err := svc.Upgrade(context.Background(), args, lockfile, force)
 			if err != nil {
 				return err
 			}
-			if *jsonOutput {
-				return print(true, upgraded, "")
+			if structuredOutput(cmd, *jsonOutput) {
+				out := upgradeJSON{Upgraded: upgraded, Skipped: skipped}
+				if out.Upgraded == nil {
+					out.Upgraded = []store.InstalledSkill{}
+				}
+				if out.Skipped == nil {
+					out.Skipped = []string{}
+				}
+				return print(cmd, true, out, "")
 			}
+			printPinnedSkips(skipped)
 			if len(upgraded) == 0 {
 				fmt.Println("no upgrades available")
 				return nil
@@ -348,62 +1025,199 @@ Examples:
 			for _, item := range upgraded {
 				fmt.Printf("upgraded %s@%s\n", item.SkillRef, item.ResolvedVersion)
 			}
+			warnDeprecated(os.Stderr, upgraded)
 			return nil
 		},
 	}
-	cmd.Flags().BoolVar(&force, "force", false, "allow suspicious skills")
+	cmd.Flags().BoolVar(&force, "force", false, "allow suspicious skills; ignored by the scan gate when --max-severity is set")
 	cmd.Flags().StringVar(&lockfile, "lockfile", "", "skills.lock path")
+	cmd.Flags().BoolVar(&lockfileOnly, "lockfile-only", false, "resolve latest versions and rewrite skills.lock without installing anything")
+	cmd.Flags().StringVar(&maxSeverity, "max-severity", "", "allow scan findings up to this severity and refuse anything above it; takes precedence over --force")
+	cmd.Flags().BoolVar(&verifySignatures, "verify-signatures", false, "require every upgraded skill to carry a SKILL.md.sig that verifies against its source public_key")
 	return cmd
 }
 
-func newInjectCmd(newSvc func() (*app.Service, error), jsonOutput *bool) *cobra.Command {
-	var agentName string
-	var allAgents bool
+// upgradeJSON is the --json output of upgrade; Skipped lists the installed
+// skills left alone because they are pinned.
+type upgradeJSON struct {
+	Upgraded []store.InstalledSkill `json:"upgraded"`
+	Skipped  []string               `json:"skipped"`
+}
+
+// lockUpgradeJSON is the --json output of upgrade --lockfile-only.
+type lockUpgradeJSON struct {
+	Changes []app.LockChange `json:"changes"`
+	Skipped []string         `json:"skipped"`
+}
+
+// printPinnedSkips reports the installed skills an upgrade skipped because
+// they are pinned.
+func printPinnedSkips(skipped []string) {
+	for _, ref := range skipped {
+		fmt.Printf("%s: skipped (pinned)\n", ref)
+	}
+}
+
+func newPinCmd(newSvc func() (*app.Service, error), jsonOutput *bool) *cobra.Command {
+	var lockfile string
 	cmd := &cobra.Command{
-		Use:   "inject [source/skill ...]",
-		Short: "Inject selected skills to target agent(s)",
-		Long: `Inject installed skills into AI agent configuration directories.
+		Use:   "pin <source/skill[@version]>...",
+		Short: "Pin skills against upgrades",
+		Long: `Pin locked skills so 'skillpm upgrade' without arguments skips them.
+
+A pinned skill is still upgraded when named explicitly, with a warning.
+An @version must be the version in skills.lock.
 
 Examples:
-  skillpm inject --agent claude
-  skillpm inject --agent cursor anthropic/docx
-  skillpm inject --all
+  skillpm pin anthropic/docx
+  skillpm pin anthropic/docx@1.2.0`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runPin(cmd, newSvc, jsonOutput, args, lockfile, true)
+		},
+	}
+	cmd.Flags().StringVar(&lockfile, "lockfile", "", "skills.lock path")
+	return cmd
+}
 
-Without skill refs, injects all installed skills.`,
-		Args: cobra.ArbitraryArgs,
+func newUnpinCmd(newSvc func() (*app.Service, error), jsonOutput *bool) *cobra.Command {
+	var lockfile string
+	cmd := &cobra.Command{
+		Use:   "unpin <source/skill>...",
+		Short: "Unpin skills so upgrades include them again",
+		Args:  cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if agentName == "" && !allAgents {
-				return fmt.Errorf("either --agent or --all is required")
+			return runPin(cmd, newSvc, jsonOutput, args, lockfile, false)
+		},
+	}
+	cmd.Flags().StringVar(&lockfile, "lockfile", "", "skills.lock path")
+	return cmd
+}
+
+func runPin(cmd *cobra.Command, newSvc func() (*app.Service, error), jsonOutput *bool, args []string, lockfile string, pinned bool) error {
+	svc, err := newSvc()
+	if err != nil {
+		return err
+	}
+	changed, err := svc.SetPinned(args, lockfile, pinned)
+	if err != nil {
+		return err
+	}
+	if structuredOutput(cmd, *jsonOutput) {
+		if changed == nil {
+			changed = []string{}
+		}
+		return print(cmd, true, changed, "")
+	}
+	verb := "pinned"
+	if !pinned {
+		verb = "unpinned"
+	}
+	if len(changed) == 0 {
+		fmt.Printf("already %s\n", verb)
+		return nil
+	}
+	for _, ref := range changed {
+		fmt.Printf("%s %s\n", verb, ref)
+	}
+	return nil
+}
+
+func newInjectCmd(newSvc func() (*app.Service, error), jsonOutput *bool) *cobra.Command {
+	var agentNames []string
+	var allAgents bool
+	var dryRun bool
+	var includeDisabled bool
+	cmd := &cobra.Command{
+		Use:   "inject [source/skill ...]",
+		Short: "Inject selected skills to target agent(s)",
+		Long: `Inject installed skills into AI agent configuration directories.
+
+Examples:
+  skillpm inject --agent claude
+  skillpm inject --agent cursor anthropic/docx
+  skillpm inject --agent claude,cursor
+  skillpm inject --all
+  skillpm inject --agent claude --dry-run --json
+
+Without skill refs, injects all installed skills. --dry-run prints the
+per-agent plan (added, removed, unchanged, conflicts) without applying it;
+applied injects report the same fields in --json mode.`,
+		Args: cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(agentNames) == 0 && !allAgents {
+				return fmt.Errorf("either --agent or --all is required")
 			}
-			if agentName != "" && allAgents {
+			if len(agentNames) > 0 && allAgents {
 				return fmt.Errorf("cannot specify both --agent and --all")
 			}
+			if includeDisabled && !allAgents {
+				return fmt.Errorf("ADP_INJECT: --include-disabled requires --all")
+			}
 			svc, err := newSvc()
 			if err != nil {
 				return err
 			}
 			var targets []string
 			if allAgents {
-				for _, a := range svc.Config.Adapters {
-					if a.Enabled {
-						targets = append(targets, a.Name)
+				var enabled []string
+				var skipped []app.SkippedAdapter
+				targets, enabled, skipped, err = svc.InjectAllTargets(includeDisabled)
+				if err != nil {
+					return err
+				}
+				for _, name := range enabled {
+					fmt.Fprintf(os.Stderr, "warning: %s is disabled in config; injecting for this run only (set enabled = true to keep it)\n", name)
+				}
+				if len(skipped) > 0 && !isQuiet(cmd) {
+					parts := make([]string, len(skipped))
+					for i, sk := range skipped {
+						parts[i] = fmt.Sprintf("%s (%s)", sk.Name, sk.Reason)
 					}
+					fmt.Fprintf(os.Stderr, "skipped %d adapter(s): %s\n", len(skipped), strings.Join(parts, ", "))
 				}
-			} else {
-				targets = []string{agentName}
+			} else if targets, err = svc.InjectTargets(agentNames); err != nil {
+				return err
+			}
+			if dryRun {
+				plans := make([]app.InjectPlan, 0, len(targets))
+				for _, target := range targets {
+					plan, pErr := svc.PlanInject(context.Background(), target, args)
+					if pErr != nil {
+						return pErr
+					}
+					plans = append(plans, plan)
+					if !structuredOutput(cmd, *jsonOutput) {
+						printInjectPlan(plan)
+					}
+				}
+				if structuredOutput(cmd, *jsonOutput) {
+					return print(cmd, true, plans, "")
+				}
+				return nil
 			}
 			type agentResult struct {
-				Agent    string `json:"agent"`
+				app.InjectPlan
 				Injected int    `json:"injected"`
+				Skipped  string `json:"skipped,omitempty"`
 			}
 			results := make([]agentResult, 0)
+			skippedReadOnly := 0
 			for _, target := range targets {
-				r, iErr := svc.Inject(context.Background(), target, args)
+				r, plan, iErr := svc.InjectWithPlan(context.Background(), target, args)
+				var readOnly *adapter.ReadOnlyDirError
+				if allAgents && errors.As(iErr, &readOnly) {
+					// A read-only agent dir should not stop the other agents.
+					fmt.Fprintf(os.Stderr, "warning: skipped %s: %v\n", target, iErr)
+					results = append(results, agentResult{InjectPlan: app.InjectPlan{Agent: target}, Skipped: iErr.Error()})
+					skippedReadOnly++
+					continue
+				}
 				if iErr != nil {
 					return iErr
 				}
-				results = append(results, agentResult{Agent: target, Injected: len(r.Injected)})
-				if !*jsonOutput {
+				results = append(results, agentResult{InjectPlan: plan, Injected: len(r.Injected)})
+				if !structuredOutput(cmd, *jsonOutput) {
 					fmt.Printf("injected into %s:\n", target)
 					for _, ref := range r.Injected {
 						if p, ok := r.InjectedPaths[ref]; ok {
@@ -414,22 +1228,121 @@ Without skill refs, injects all installed skills.`,
 					}
 				}
 			}
-			if *jsonOutput {
-				return print(true, results, "")
+			if structuredOutput(cmd, *jsonOutput) {
+				if err := print(cmd, true, results, ""); err != nil {
+					return err
+				}
+			}
+			if skippedReadOnly > 0 && skippedReadOnly == len(targets) {
+				return fmt.Errorf("ADP_DIR_READONLY: no agent could be injected; every skills directory is read-only")
 			}
 			return nil
 		},
 	}
-	cmd.Flags().StringVar(&agentName, "agent", "", "target agent")
+	cmd.Flags().StringSliceVar(&agentNames, "agent", nil, "target agent(s), comma-separated or repeated")
 	cmd.Flags().BoolVar(&allAgents, "all", false, "inject into all enabled agents")
+	cmd.Flags().BoolVar(&includeDisabled, "include-disabled", false, "with --all, also inject into detected agents whose adapter is disabled, for this run only")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "show the injection plan without applying it")
 	return cmd
 }
 
+func newRemoveCmd(newSvc func() (*app.Service, error), jsonOutput *bool) *cobra.Command {
+	var agentNames []string
+	var allAgents bool
+	cmd := &cobra.Command{
+		Use:   "remove [source/skill ...]",
+		Short: "Remove injected skills from target agent(s)",
+		Long: `Remove injected skills from AI agent configuration directories. The
+skills stay installed; use uninstall to delete them.
+
+Examples:
+  skillpm remove --agent claude anthropic/docx
+  skillpm remove --agent claude,cursor
+  skillpm remove --all --json
+
+Without skill refs, removes every skill injected into the agent. --json
+reports the same per-agent fields as inject: removed lists what left the
+agent and unchanged the skills still injected.`,
+		Args: cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(agentNames) == 0 && !allAgents {
+				return fmt.Errorf("either --agent or --all is required")
+			}
+			if len(agentNames) > 0 && allAgents {
+				return fmt.Errorf("cannot specify both --agent and --all")
+			}
+			svc, err := newSvc()
+			if err != nil {
+				return err
+			}
+			var targets []string
+			if allAgents {
+				targets = svc.Runtime.AgentNames()
+				sort.Strings(targets)
+			} else if targets, err = svc.InjectTargets(agentNames); err != nil {
+				return err
+			}
+			type agentResult struct {
+				app.InjectPlan
+				RemovedCount int    `json:"removedCount"`
+				SnapshotPath string `json:"snapshotPath,omitempty"`
+			}
+			results := make([]agentResult, 0, len(targets))
+			for _, target := range targets {
+				r, plan, rErr := svc.RemoveWithPlan(context.Background(), target, args)
+				if rErr != nil {
+					return rErr
+				}
+				results = append(results, agentResult{InjectPlan: plan, RemovedCount: len(r.Removed), SnapshotPath: r.SnapshotPath})
+				if !structuredOutput(cmd, *jsonOutput) {
+					fmt.Printf("removed from %s:\n", target)
+					for _, ref := range r.Removed {
+						fmt.Printf("  %s\n", ref)
+					}
+					if len(plan.Unchanged) > 0 {
+						fmt.Printf("  %d still injected\n", len(plan.Unchanged))
+					}
+				}
+			}
+			if structuredOutput(cmd, *jsonOutput) {
+				return print(cmd, true, results, "")
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringSliceVar(&agentNames, "agent", nil, "target agent(s), comma-separated or repeated")
+	cmd.Flags().BoolVar(&allAgents, "all", false, "remove from all enabled agents")
+	return cmd
+}
+
+// printInjectPlan renders a dry-run inject plan for one agent.
+func printInjectPlan(plan app.InjectPlan) {
+	fmt.Printf("plan for %s:\n", plan.Agent)
+	for _, ref := range plan.Added {
+		fmt.Printf("  + %s\n", ref)
+	}
+	for _, ref := range plan.Removed {
+		fmt.Printf("  - %s\n", ref)
+	}
+	if len(plan.Unchanged) > 0 {
+		fmt.Printf("  %d unchanged\n", len(plan.Unchanged))
+	}
+	for _, c := range plan.Conflicts {
+		fmt.Printf("  ! %s: %s (%s)\n", c.Ref, c.Message, c.Code)
+	}
+}
+
 func newSyncCmd(newSvc func() (*app.Service, error), jsonOutput *bool) *cobra.Command {
 	var lockfile string
 	var force bool
 	var dryRun bool
 	var strict bool
+	var maxChanges int
+	var pruneRemoved bool
+	var retries int
+	var concurrency int
+	var maxSeverity string
+	var verifySignatures bool
 	cmd := &cobra.Command{
 		Use:   "sync",
 		Short: "Reconcile source updates with installed/injected state",
@@ -440,228 +1353,533 @@ Examples:
   skillpm sync
   skillpm sync --dry-run
   skillpm sync --json
-  skillpm sync --strict`,
+  skillpm sync --strict
+  skillpm sync --max-changes 10
+  skillpm sync --prune-removed`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			svc, err := newSvc()
 			if err != nil {
 				return err
 			}
-			report, err := svc.SyncRun(context.Background(), lockfile, force, dryRun)
+			svc.Sync.PruneRemoved = pruneRemoved
+			if err := setRetries(svc, retries); err != nil {
+				return err
+			}
+			if concurrency < 0 {
+				return fmt.Errorf("SYNC_CONCURRENCY: --concurrency must be 0 or more, got %d", concurrency)
+			}
+			svc.Sync.Concurrency = concurrency
+			if err := setMaxSeverity(svc, maxSeverity); err != nil {
+				return err
+			}
+			svc.Installer.VerifySignatures = verifySignatures
+			ctx := context.Background()
+			report, err := svc.SyncRun(ctx, lockfile, force, dryRun, strict, maxChanges)
+			if audit.ErrorCode(err) == "SYNC_TOO_MANY_CHANGES" {
+				recordSyncHistory(svc, report, strict)
+				if pErr := printSyncReport(cmd, structuredOutput(cmd, *jsonOutput), report, true, strict); pErr != nil && !isSyncRiskExit(pErr) {
+					return pErr
+				}
+				return err
+			}
 			if err != nil {
 				return err
 			}
-			if *jsonOutput {
-				if err := print(true, buildSyncJSONSummary(report, strict), ""); err != nil {
+			recordSyncHistory(svc, report, strict)
+			return printSyncReport(cmd, structuredOutput(cmd, *jsonOutput), report, dryRun, strict)
+		},
+	}
+	cmd.AddCommand(newSyncHistoryCmd(newSvc, jsonOutput))
+	cmd.Flags().StringVar(&lockfile, "lockfile", "", "skills.lock path")
+	cmd.Flags().BoolVar(&force, "force", false, "allow suspicious skills; ignored by the scan gate when --max-severity is set")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "show planned sync actions without mutating state/config")
+	cmd.Flags().BoolVar(&strict, "strict", false, "fail if sync encounters risks")
+	cmd.Flags().IntVar(&maxChanges, "max-changes", 0, "refuse to apply when the plan exceeds this many changes (0 = unlimited)")
+	cmd.Flags().BoolVar(&pruneRemoved, "prune-removed", false, "uninstall skills that are no longer in the lockfile and remove them from agents")
+	cmd.Flags().IntVar(&retries, "retry", 0, "retry source updates and skill fetches up to N times with backoff on network failures")
+	cmd.Flags().IntVar(&concurrency, "concurrency", 0, "update and resolve up to N sources at once (0 = default of 4)")
+	cmd.Flags().StringVar(&maxSeverity, "max-severity", "", "allow scan findings up to this severity and refuse anything above it; takes precedence over --force")
+	cmd.Flags().BoolVar(&verifySignatures, "verify-signatures", false, "require every upgraded skill to carry a SKILL.md.sig that verifies against its source public_key")
+	return cmd
+}
+
+// recordSyncHistory appends the run to the sync history when sync.history
+// is enabled. Failing to record never fails the sync.
+func recordSyncHistory(svc *app.Service, report syncsvc.Report, strict bool) {
+	if err := svc.RecordSyncHistory(report.Summary(strict)); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+	}
+}
+
+func newSyncHistoryCmd(newSvc func() (*app.Service, error), jsonOutput *bool) *cobra.Command {
+	var since string
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "Show recorded sync runs",
+		Long: `Show sync runs recorded in sync-history.jsonl, oldest first.
+Recording is enabled with sync.history = true in config.toml.
+
+Examples:
+  skillpm sync history
+  skillpm sync history --since 7d
+  skillpm sync history --since 2026-01-01 --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var cutoff time.Time
+			if since != "" {
+				t, err := parseSince(since, time.Now())
+				if err != nil {
 					return err
 				}
-				issueCount := totalSyncIssues(report)
-				if strict && issueCount > 0 {
-					if dryRun {
-						return &exitError{code: 2, msg: fmt.Sprintf("SYNC_RISK: sync plan includes %d risk items (strict mode)", issueCount)}
-					}
-					return &exitError{code: 2, msg: fmt.Sprintf("SYNC_RISK: sync completed with %d risk items (strict mode)", issueCount)}
-				}
-				return nil
+				cutoff = t
 			}
-			if dryRun {
-				totalActions := totalSyncActions(report)
-				issueCount := totalSyncIssues(report)
-				fmt.Printf("sync plan (dry-run): sources=%d upgrades=%d reinjected=%d\n", len(report.UpdatedSources), len(report.UpgradedSkills), len(report.Reinjected))
-				fmt.Printf("strict mode: %s\n", syncStrictStatus(strict))
-				fmt.Printf("planned strict failure reason: %s\n", syncStrictFailureReason(report, strict))
-				fmt.Printf("planned actions total: %d\n", totalActions)
-				fmt.Printf("planned outcome: %s\n", syncOutcome(report))
-				fmt.Printf("planned progress status: %s\n", syncProgressStatus(report))
-				fmt.Printf("planned progress class: %s\n", syncProgressClass(report))
-				fmt.Printf("planned progress hotspot: %s\n", syncProgressHotspot(report))
-				fmt.Printf("planned progress focus: %s\n", syncProgressFocus(report))
-				fmt.Printf("planned progress target: %s\n", syncProgressTarget(report))
-				fmt.Printf("planned progress signal: %s\n", syncProgressSignal(report))
-				fmt.Printf("planned actions breakdown: %s\n", syncActionBreakdown(report))
-				fmt.Printf("planned action samples: sources=%s upgrades=%s reinjected=%s\n", summarizeTop(report.UpdatedSources, 3), summarizeTop(report.UpgradedSkills, 3), summarizeTop(report.Reinjected, 3))
-				fmt.Printf("planned next action: %s\n", syncNextAction(report))
-				fmt.Printf("planned primary action: %s\n", syncPrimaryAction(report))
-				fmt.Printf("planned execution priority: %s\n", syncExecutionPriority(report))
-				fmt.Printf("planned follow-up gate: %s\n", syncFollowUpGate(report))
-				fmt.Printf("planned next step hint: %s\n", syncNextStepHint(report))
-				fmt.Printf("planned recommended command: %s\n", syncRecommendedCommand(report))
-				fmt.Printf("planned recommended commands: %s\n", strings.Join(syncRecommendedCommands(report), " -> "))
-				fmt.Printf("planned recommended agent: %s\n", syncRecommendedAgent(report))
-				fmt.Printf("planned summary line: %s\n", syncSummaryLine(report))
-				fmt.Printf("planned noop reason: %s\n", syncNoopReason(report))
-				fmt.Printf("planned can proceed: %t\n", issueCount == 0)
-				fmt.Printf("planned next batch ready: %t\n", syncNextBatchReady(report))
-				fmt.Printf("planned next batch blocker: %s\n", syncNextBatchBlocker(report))
-				fmt.Printf("planned risk items total: %d\n", issueCount)
-				fmt.Printf("planned risk status: %s\n", syncRiskStatus(report))
-				fmt.Printf("planned risk level: %s\n", syncRiskLevel(report))
-				fmt.Printf("planned risk class: %s\n", syncRiskClass(report))
-				fmt.Printf("planned risk breakdown: %s\n", syncRiskBreakdown(report))
-				riskInjectCommands := syncRiskInjectCommands(report)
-				fmt.Printf("planned risk inject commands: %s\n", summarizeTop(riskInjectCommands, 3))
-				riskAgents := syncRiskAgents(report)
-				fmt.Printf("planned risk hotspot: %s\n", syncRiskHotspot(report))
-				fmt.Printf("planned risk agents total: %d\n", len(riskAgents))
-				fmt.Printf("planned risk agents: %s\n", summarizeTop(riskAgents, 3))
-				fmt.Printf("planned risk samples: skipped=%s failed=%s\n", summarizeTop(report.SkippedReinjects, 3), summarizeTop(report.FailedReinjects, 3))
-				if totalActions == 0 {
-					fmt.Println("planned actions: none")
-				}
-				if len(report.UpdatedSources) == 0 {
-					fmt.Println("planned source updates: none")
-				} else {
-					fmt.Printf("planned source updates: %s\n", joinSorted(report.UpdatedSources))
-				}
-				if len(report.UpgradedSkills) == 0 {
-					fmt.Println("planned upgrades: none")
-				} else {
-					fmt.Printf("planned upgrades: %s\n", joinSorted(report.UpgradedSkills))
-				}
-				if len(report.Reinjected) == 0 {
-					fmt.Println("planned reinjections: none")
+			svc, err := newSvc()
+			if err != nil {
+				return err
+			}
+			entries, err := svc.SyncHistory(cutoff)
+			if err != nil {
+				return err
+			}
+			if structuredOutput(cmd, *jsonOutput) {
+				return print(cmd, true, entries, "")
+			}
+			if len(entries) == 0 {
+				if !svc.Config.Sync.History {
+					fmt.Println("no recorded sync runs (enable with sync.history = true in config.toml)")
 				} else {
-					fmt.Printf("planned reinjections: %s\n", joinSorted(report.Reinjected))
+					fmt.Println("no recorded sync runs")
 				}
-				if len(report.SkippedReinjects) == 0 {
-					fmt.Println("planned skipped reinjections: none")
-				} else {
-					fmt.Printf("planned skipped reinjections: %s\n", joinSorted(report.SkippedReinjects))
+				return nil
+			}
+			for _, entry := range entries {
+				var summary syncsvc.Summary
+				if err := json.Unmarshal(entry.Summary, &summary); err != nil {
+					continue
 				}
-				if len(report.FailedReinjects) == 0 {
-					fmt.Println("planned failed reinjections: none")
-				} else {
-					fmt.Printf("planned failed reinjections: %s\n", joinSortedWith(report.FailedReinjects, "; "))
+				mode := "applied"
+				if summary.DryRun {
+					mode = "planned"
 				}
-				if strict && issueCount > 0 {
-					return &exitError{code: 2, msg: fmt.Sprintf("SYNC_RISK: sync plan includes %d risk items (strict mode)", issueCount)}
+				fmt.Printf("%s %s outcome=%s sources=%d upgrades=%d reinjected=%d risk=%d\n",
+					entry.RecordedAt.Format(time.RFC3339), mode, summary.Outcome,
+					summary.ActionCounts.Sources, summary.ActionCounts.Upgrades, summary.ActionCounts.Reinjected, summary.RiskCounts.Total)
+				if !isQuiet(cmd) && summary.RiskCounts.Total > 0 {
+					fmt.Printf("  risk: skipped=%s failed=%s\n", summarizeTop(summary.SkippedReinjects, 3), summarizeTop(summary.FailedReinjects, 3))
 				}
-				return nil
-			}
-			totalActions := totalSyncActions(report)
-			issueCount := totalSyncIssues(report)
-			fmt.Printf("sync complete: sources=%d upgrades=%d reinjected=%d\n", len(report.UpdatedSources), len(report.UpgradedSkills), len(report.Reinjected))
-			fmt.Printf("strict mode: %s\n", syncStrictStatus(strict))
-			fmt.Printf("applied strict failure reason: %s\n", syncStrictFailureReason(report, strict))
-			fmt.Printf("applied actions total: %d\n", totalActions)
-			fmt.Printf("applied outcome: %s\n", syncOutcome(report))
-			fmt.Printf("applied progress status: %s\n", syncProgressStatus(report))
-			fmt.Printf("applied progress class: %s\n", syncProgressClass(report))
-			fmt.Printf("applied progress hotspot: %s\n", syncProgressHotspot(report))
-			fmt.Printf("applied progress focus: %s\n", syncProgressFocus(report))
-			fmt.Printf("applied progress target: %s\n", syncProgressTarget(report))
-			fmt.Printf("applied progress signal: %s\n", syncProgressSignal(report))
-			fmt.Printf("applied actions breakdown: %s\n", syncActionBreakdown(report))
-			fmt.Printf("applied action samples: sources=%s upgrades=%s reinjected=%s\n", summarizeTop(report.UpdatedSources, 3), summarizeTop(report.UpgradedSkills, 3), summarizeTop(report.Reinjected, 3))
-			fmt.Printf("applied next action: %s\n", syncNextAction(report))
-			fmt.Printf("applied primary action: %s\n", syncPrimaryAction(report))
-			fmt.Printf("applied execution priority: %s\n", syncExecutionPriority(report))
-			fmt.Printf("applied follow-up gate: %s\n", syncFollowUpGate(report))
-			fmt.Printf("applied next step hint: %s\n", syncNextStepHint(report))
-			fmt.Printf("applied recommended command: %s\n", syncRecommendedCommand(report))
-			fmt.Printf("applied recommended commands: %s\n", strings.Join(syncRecommendedCommands(report), " -> "))
-			fmt.Printf("applied recommended agent: %s\n", syncRecommendedAgent(report))
-			fmt.Printf("applied summary line: %s\n", syncSummaryLine(report))
-			fmt.Printf("applied noop reason: %s\n", syncNoopReason(report))
-			fmt.Printf("applied can proceed: %t\n", issueCount == 0)
-			fmt.Printf("applied next batch ready: %t\n", syncNextBatchReady(report))
-			fmt.Printf("applied next batch blocker: %s\n", syncNextBatchBlocker(report))
-			fmt.Printf("applied risk items total: %d\n", issueCount)
-			fmt.Printf("applied risk status: %s\n", syncRiskStatus(report))
-			fmt.Printf("applied risk level: %s\n", syncRiskLevel(report))
-			fmt.Printf("applied risk class: %s\n", syncRiskClass(report))
-			fmt.Printf("applied risk breakdown: %s\n", syncRiskBreakdown(report))
-			riskInjectCommands := syncRiskInjectCommands(report)
-			fmt.Printf("applied risk inject commands: %s\n", summarizeTop(riskInjectCommands, 3))
-			riskAgents := syncRiskAgents(report)
-			fmt.Printf("applied risk hotspot: %s\n", syncRiskHotspot(report))
-			fmt.Printf("applied risk agents total: %d\n", len(riskAgents))
-			fmt.Printf("applied risk agents: %s\n", summarizeTop(riskAgents, 3))
-			fmt.Printf("applied risk samples: skipped=%s failed=%s\n", summarizeTop(report.SkippedReinjects, 3), summarizeTop(report.FailedReinjects, 3))
-			if totalActions == 0 {
-				fmt.Println("applied actions: none")
-			}
-			if len(report.UpdatedSources) == 0 {
-				fmt.Println("updated sources: none")
-			} else {
-				fmt.Printf("updated sources: %s\n", joinSorted(report.UpdatedSources))
-			}
-			if len(report.UpgradedSkills) == 0 {
-				fmt.Println("upgraded skills: none")
-			} else {
-				fmt.Printf("upgraded skills: %s\n", joinSorted(report.UpgradedSkills))
-			}
-			if len(report.Reinjected) == 0 {
-				fmt.Println("reinjected agents: none")
-			} else {
-				fmt.Printf("reinjected agents: %s\n", joinSorted(report.Reinjected))
 			}
-			if len(report.SkippedReinjects) == 0 {
-				fmt.Println("skipped reinjections: none")
-			} else {
-				fmt.Printf("skipped reinjections: %s (runtime unavailable)\n", joinSorted(report.SkippedReinjects))
-			}
-			if len(report.FailedReinjects) == 0 {
-				fmt.Println("failed reinjections: none")
-			} else {
-				fmt.Printf("failed reinjections: %s\n", joinSortedWith(report.FailedReinjects, "; "))
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&since, "since", "", "only show runs newer than a duration (7d, 12h) or since a date (2006-01-02 or RFC 3339)")
+	return cmd
+}
+
+// parseSince turns a relative duration or an absolute date into a cutoff.
+func parseSince(s string, now time.Time) (time.Time, error) {
+	if d, err := parseDuration(s); err == nil && d > 0 {
+		return now.Add(-d), nil
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("SYNC_HISTORY: invalid --since %q (use e.g. 7d, 12h or 2006-01-02)", s)
+}
+
+// printSyncReport renders a sync report (applied, or planned when dryRun is
+// set) and returns the strict-mode exit error when risk items are present.
+func printSyncReport(cmd *cobra.Command, jsonMode bool, report syncsvc.Report, dryRun, strict bool) error {
+	sum := report.Summary(strict)
+	if jsonMode {
+		if err := print(cmd, true, sum, ""); err != nil {
+			return err
+		}
+		issueCount := sum.RiskCounts.Total
+		if strict && issueCount > 0 {
+			if dryRun {
+				return &exitError{code: 2, msg: fmt.Sprintf("SYNC_RISK: sync plan includes %d risk items (strict mode)", issueCount)}
 			}
+			return &exitError{code: 2, msg: fmt.Sprintf("SYNC_RISK: sync completed with %d risk items (strict mode)", issueCount)}
+		}
+		return nil
+	}
+	if dryRun {
+		totalActions := sum.ActionCounts.Total
+		issueCount := sum.RiskCounts.Total
+		fmt.Printf("sync plan (dry-run): sources=%d upgrades=%d reinjected=%d\n", len(report.UpdatedSources), len(report.UpgradedSkills), len(report.Reinjected))
+		if isQuiet(cmd) {
 			if strict && issueCount > 0 {
-				return &exitError{code: 2, msg: fmt.Sprintf("SYNC_RISK: sync completed with %d risk items (strict mode)", issueCount)}
+				return &exitError{code: 2, msg: fmt.Sprintf("SYNC_RISK: sync plan includes %d risk items (strict mode)", issueCount)}
 			}
 			return nil
-		},
+		}
+		fmt.Printf("strict mode: %s\n", sum.StrictStatus)
+		fmt.Printf("planned strict failure reason: %s\n", sum.StrictFailureReason)
+		fmt.Printf("planned actions total: %d\n", totalActions)
+		fmt.Printf("planned outcome: %s\n", sum.Outcome)
+		fmt.Printf("planned progress status: %s\n", sum.ProgressStatus)
+		fmt.Printf("planned progress class: %s\n", sum.ProgressClass)
+		fmt.Printf("planned progress hotspot: %s\n", sum.ProgressHotspot)
+		fmt.Printf("planned progress focus: %s\n", sum.ProgressFocus)
+		fmt.Printf("planned progress target: %s\n", sum.ProgressTarget)
+		fmt.Printf("planned progress signal: %s\n", sum.ProgressSignal)
+		fmt.Printf("planned actions breakdown: %s\n", sum.ActionBreakdown)
+		fmt.Printf("planned action samples: sources=%s upgrades=%s reinjected=%s\n", summarizeTop(report.UpdatedSources, 3), summarizeTop(report.UpgradedSkills, 3), summarizeTop(report.Reinjected, 3))
+		fmt.Printf("planned next action: %s\n", sum.NextAction)
+		fmt.Printf("planned primary action: %s\n", sum.PrimaryAction)
+		fmt.Printf("planned execution priority: %s\n", sum.ExecutionPriority)
+		fmt.Printf("planned follow-up gate: %s\n", sum.FollowUpGate)
+		fmt.Printf("planned next step hint: %s\n", sum.NextStepHint)
+		fmt.Printf("planned recommended command: %s\n", sum.RecommendedCommand)
+		fmt.Printf("planned recommended commands: %s\n", strings.Join(sum.RecommendedCommands, " -> "))
+		fmt.Printf("planned recommended agent: %s\n", sum.RecommendedAgent)
+		fmt.Printf("planned summary line: %s\n", sum.SummaryLine)
+		fmt.Printf("planned noop reason: %s\n", sum.NoopReason)
+		fmt.Printf("planned can proceed: %t\n", issueCount == 0)
+		fmt.Printf("planned next batch ready: %t\n", sum.NextBatchReady)
+		fmt.Printf("planned next batch blocker: %s\n", sum.NextBatchBlocker)
+		fmt.Printf("planned risk items total: %d\n", issueCount)
+		fmt.Printf("planned risk status: %s\n", sum.RiskStatus)
+		fmt.Printf("planned risk level: %s\n", sum.RiskLevel)
+		fmt.Printf("planned risk class: %s\n", sum.RiskClass)
+		fmt.Printf("planned risk breakdown: %s\n", sum.RiskBreakdown)
+		riskInjectCommands := sum.RiskInjectCommands
+		fmt.Printf("planned risk inject commands: %s\n", summarizeTop(riskInjectCommands, 3))
+		riskAgents := sum.RiskAgents
+		fmt.Printf("planned risk hotspot: %s\n", sum.RiskHotspot)
+		fmt.Printf("planned risk agents total: %d\n", len(riskAgents))
+		fmt.Printf("planned risk agents: %s\n", summarizeTop(riskAgents, 3))
+		fmt.Printf("planned risk samples: skipped=%s failed=%s\n", summarizeTop(report.SkippedReinjects, 3), summarizeTop(report.FailedReinjects, 3))
+		if totalActions == 0 {
+			fmt.Println("planned actions: none")
+		}
+		if len(report.UpdatedSources) == 0 {
+			fmt.Println("planned source updates: none")
+		} else {
+			fmt.Printf("planned source updates: %s\n", joinSorted(report.UpdatedSources))
+		}
+		if len(report.UpgradedSkills) == 0 {
+			fmt.Println("planned upgrades: none")
+		} else {
+			fmt.Printf("planned upgrades: %s\n", joinSorted(report.UpgradedSkills))
+		}
+		if len(report.RemovedSkills) > 0 {
+			fmt.Printf("planned removals: %s\n", joinSorted(report.RemovedSkills))
+		}
+		if len(report.Reinjected) == 0 {
+			fmt.Println("planned reinjections: none")
+		} else {
+			fmt.Printf("planned reinjections: %s\n", joinSorted(report.Reinjected))
+		}
+		if len(report.SkippedReinjects) == 0 {
+			fmt.Println("planned skipped reinjections: none")
+		} else {
+			fmt.Printf("planned skipped reinjections: %s\n", joinSorted(report.SkippedReinjects))
+		}
+		if len(report.FailedReinjects) == 0 {
+			fmt.Println("planned failed reinjections: none")
+		} else {
+			fmt.Printf("planned failed reinjections: %s\n", joinSortedWith(report.FailedReinjects, "; "))
+		}
+		if strict && issueCount > 0 {
+			return &exitError{code: 2, msg: fmt.Sprintf("SYNC_RISK: sync plan includes %d risk items (strict mode)", issueCount)}
+		}
+		return nil
 	}
-	cmd.Flags().StringVar(&lockfile, "lockfile", "", "skills.lock path")
-	cmd.Flags().BoolVar(&force, "force", false, "allow suspicious skills")
-	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "show planned sync actions without mutating state/config")
-	cmd.Flags().BoolVar(&strict, "strict", false, "fail if sync encounters risks")
-	return cmd
+	totalActions := sum.ActionCounts.Total
+	issueCount := sum.RiskCounts.Total
+	fmt.Printf("sync complete: sources=%d upgrades=%d reinjected=%d\n", len(report.UpdatedSources), len(report.UpgradedSkills), len(report.Reinjected))
+	if isQuiet(cmd) {
+		if strict && issueCount > 0 {
+			return &exitError{code: 2, msg: fmt.Sprintf("SYNC_RISK: sync completed with %d risk items (strict mode)", issueCount)}
+		}
+		return nil
+	}
+	fmt.Printf("strict mode: %s\n", sum.StrictStatus)
+	fmt.Printf("applied strict failure reason: %s\n", sum.StrictFailureReason)
+	fmt.Printf("applied actions total: %d\n", totalActions)
+	fmt.Printf("applied outcome: %s\n", sum.Outcome)
+	fmt.Printf("applied progress status: %s\n", sum.ProgressStatus)
+	fmt.Printf("applied progress class: %s\n", sum.ProgressClass)
+	fmt.Printf("applied progress hotspot: %s\n", sum.ProgressHotspot)
+	fmt.Printf("applied progress focus: %s\n", sum.ProgressFocus)
+	fmt.Printf("applied progress target: %s\n", sum.ProgressTarget)
+	fmt.Printf("applied progress signal: %s\n", sum.ProgressSignal)
+	fmt.Printf("applied actions breakdown: %s\n", sum.ActionBreakdown)
+	fmt.Printf("applied action samples: sources=%s upgrades=%s reinjected=%s\n", summarizeTop(report.UpdatedSources, 3), summarizeTop(report.UpgradedSkills, 3), summarizeTop(report.Reinjected, 3))
+	fmt.Printf("applied next action: %s\n", sum.NextAction)
+	fmt.Printf("applied primary action: %s\n", sum.PrimaryAction)
+	fmt.Printf("applied execution priority: %s\n", sum.ExecutionPriority)
+	fmt.Printf("applied follow-up gate: %s\n", sum.FollowUpGate)
+	fmt.Printf("applied next step hint: %s\n", sum.NextStepHint)
+	fmt.Printf("applied recommended command: %s\n", sum.RecommendedCommand)
+	fmt.Printf("applied recommended commands: %s\n", strings.Join(sum.RecommendedCommands, " -> "))
+	fmt.Printf("applied recommended agent: %s\n", sum.RecommendedAgent)
+	fmt.Printf("applied summary line: %s\n", sum.SummaryLine)
+	fmt.Printf("applied noop reason: %s\n", sum.NoopReason)
+	fmt.Printf("applied can proceed: %t\n", issueCount == 0)
+	fmt.Printf("applied next batch ready: %t\n", sum.NextBatchReady)
+	fmt.Printf("applied next batch blocker: %s\n", sum.NextBatchBlocker)
+	fmt.Printf("applied risk items total: %d\n", issueCount)
+	fmt.Printf("applied risk status: %s\n", sum.RiskStatus)
+	fmt.Printf("applied risk level: %s\n", sum.RiskLevel)
+	fmt.Printf("applied risk class: %s\n", sum.RiskClass)
+	fmt.Printf("applied risk breakdown: %s\n", sum.RiskBreakdown)
+	riskInjectCommands := sum.RiskInjectCommands
+	fmt.Printf("applied risk inject commands: %s\n", summarizeTop(riskInjectCommands, 3))
+	riskAgents := sum.RiskAgents
+	fmt.Printf("applied risk hotspot: %s\n", sum.RiskHotspot)
+	fmt.Printf("applied risk agents total: %d\n", len(riskAgents))
+	fmt.Printf("applied risk agents: %s\n", summarizeTop(riskAgents, 3))
+	fmt.Printf("applied risk samples: skipped=%s failed=%s\n", summarizeTop(report.SkippedReinjects, 3), summarizeTop(report.FailedReinjects, 3))
+	if totalActions == 0 {
+		fmt.Println("applied actions: none")
+	}
+	if len(report.UpdatedSources) == 0 {
+		fmt.Println("updated sources: none")
+	} else {
+		fmt.Printf("updated sources: %s\n", joinSorted(report.UpdatedSources))
+	}
+	if len(report.UpgradedSkills) == 0 {
+		fmt.Println("upgraded skills: none")
+	} else {
+		fmt.Printf("upgraded skills: %s\n", joinSorted(report.UpgradedSkills))
+	}
+	if len(report.RemovedSkills) > 0 {
+		fmt.Printf("removed skills: %s\n", joinSorted(report.RemovedSkills))
+	}
+	if len(report.Reinjected) == 0 {
+		fmt.Println("reinjected agents: none")
+	} else {
+		fmt.Printf("reinjected agents: %s\n", joinSorted(report.Reinjected))
+	}
+	if len(report.SkippedReinjects) == 0 {
+		fmt.Println("skipped reinjections: none")
+	} else {
+		fmt.Printf("skipped reinjections: %s (runtime unavailable)\n", joinSorted(report.SkippedReinjects))
+	}
+	if len(report.FailedReinjects) == 0 {
+		fmt.Println("failed reinjections: none")
+	} else {
+		fmt.Printf("failed reinjections: %s\n", joinSortedWith(report.FailedReinjects, "; "))
+	}
+	if strict && issueCount > 0 {
+		return &exitError{code: 2, msg: fmt.Sprintf("SYNC_RISK: sync completed with %d risk items (strict mode)", issueCount)}
+	}
+	return nil
+}
+
+// isSyncRiskExit reports whether err is the strict-mode SYNC_RISK exit.
+func isSyncRiskExit(err error) bool {
+	var exitErr *exitError
+	return errors.As(err, &exitErr) && exitErr.code == 2
 }
 
 func newDoctorCmd(newSvc func() (*app.Service, error), jsonOutput *bool) *cobra.Command {
+	var reinstallMissing bool
+	var repairFromLock bool
+	var watch bool
+	var interval time.Duration
+	var fix bool
+	var failOn string
+	var profile bool
+	var dryRun bool
 	cmd := &cobra.Command{
 		Use:   "doctor",
 		Short: "Run self-healing diagnostics",
+		Long: `Run self-healing diagnostics.
+
+--fix=false and --dry-run both leave everything untouched. --fix=false
+reports each fix it would apply as a warning; --dry-run reports it as
+fixed, marked "not applied (dry run)", so the counts match a real run.
+
+--fail-on makes the exit status reflect what remains after fixes: with
+"error", unfixed errors exit with status 2; with "warn", warnings do too.
+With --dry-run nothing is fixed, so fixes it would apply fail either
+threshold. The report is still printed (as JSON with --json) before
+exiting.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if repairFromLock && watch {
+				return fmt.Errorf("DOC_REPAIR_LOCK: --repair-from-lock cannot be combined with --watch")
+			}
+			switch failOn {
+			case "", "never", "error", "warn":
+			default:
+				return fmt.Errorf("DOC_FAIL_ON: --fail-on must be never, error or warn, got %q", failOn)
+			}
+			if watch && failOn != "" && failOn != "never" {
+				return fmt.Errorf("DOC_FAIL_ON: --fail-on cannot be combined with --watch")
+			}
+			if watch && profile {
+				return fmt.Errorf("DOC_PROFILE: --profile cannot be combined with --watch")
+			}
 			svc, err := newSvc()
 			if err != nil {
 				return err
 			}
-			report := svc.DoctorRun(context.Background())
-			if *jsonOutput {
-				return print(true, report, "")
+			svc.Doctor.ReinstallMissing = reinstallMissing
+			svc.Doctor.RepairFromLock = repairFromLock
+			svc.Doctor.ReportOnly = !fix
+			svc.Doctor.Profile = profile
+			svc.Doctor.DryRun = dryRun
+			if watch {
+				ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+				defer stop()
+				return svc.DoctorWatch(ctx, interval, func(report doctor.Report) {
+					if structuredOutput(cmd, *jsonOutput) {
+						if err := printEvent(cmd, report); err != nil {
+							fmt.Fprintln(os.Stderr, err)
+						}
+						return
+					}
+					fmt.Printf("--- %s ---\n", time.Now().Format(time.RFC3339))
+					printDoctorReport(report)
+				})
 			}
-			for _, c := range report.Checks {
-				fmt.Printf("[%-5s] %-16s %s\n", c.Status, c.Name, c.Message)
-				if c.Fix != "" {
-					fmt.Printf("  -> %s\n", c.Fix)
+			report := svc.DoctorRun(context.Background())
+			if structuredOutput(cmd, *jsonOutput) {
+				if err := print(cmd, true, report, ""); err != nil {
+					return err
 				}
+			} else {
+				printDoctorReport(report)
+				if profile {
+					printDoctorProfile(report)
+				}
+			}
+			return doctorFailure(report, failOn)
+		},
+	}
+	cmd.Flags().BoolVar(&reinstallMissing, "reinstall-missing", false, "reinstall injected skills that are no longer installed instead of clearing their injections")
+	cmd.Flags().BoolVar(&repairFromLock, "repair-from-lock", false, "rebuild installed state from the lockfile: reinstall missing locked skills and remove unlocked ones (backs up state first)")
+	cmd.Flags().BoolVar(&watch, "watch", false, "re-run diagnostics periodically and print only when health changes")
+	cmd.Flags().DurationVar(&interval, "interval", 5*time.Minute, "time between runs in --watch mode")
+	cmd.Flags().BoolVar(&fix, "fix", true, "apply fixes; --fix=false only reports drift")
+	cmd.Flags().StringVar(&failOn, "fail-on", "never", "exit with status 2 when problems remain: never, error, or warn (warnings or errors)")
+	cmd.Flags().BoolVar(&profile, "profile", false, "time each check and print the slowest checks")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "report the fixes doctor would apply, counted as fixed, without applying them")
+	cmd.AddCommand(newDoctorDiffCmd(jsonOutput))
+	return cmd
+}
+
+func newDoctorDiffCmd(jsonOutput *bool) *cobra.Command {
+	return &cobra.Command{
+		Use:   "diff <before.json> <after.json>",
+		Short: "Compare two saved doctor reports",
+		Long: `Compare two reports saved with "skillpm doctor --json" and show which
+checks changed status: what got fixed and what newly broke. Exits with
+status 2 when any check broke.
+
+Examples:
+  skillpm doctor --json > before.json
+  skillpm doctor --json > after.json
+  skillpm doctor diff before.json after.json`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			before, err := doctor.ReadReport(args[0])
+			if err != nil {
+				return err
+			}
+			after, err := doctor.ReadReport(args[1])
+			if err != nil {
+				return err
 			}
-			fmt.Println()
-			if report.Fixed == 0 && report.Warnings == 0 && report.Errors == 0 {
-				fmt.Println("all checks passed")
+			d := doctor.DiffReports(before, after)
+			if structuredOutput(cmd, *jsonOutput) {
+				if err := print(cmd, true, d, ""); err != nil {
+					return err
+				}
 			} else {
-				parts := []string{}
-				if report.Fixed > 0 {
-					parts = append(parts, fmt.Sprintf("%d fixed", report.Fixed))
+				if d.HealthyBefore != d.HealthyAfter {
+					fmt.Printf("healthy: %v -> %v\n", d.HealthyBefore, d.HealthyAfter)
 				}
-				if report.Warnings > 0 {
-					parts = append(parts, fmt.Sprintf("%d warnings", report.Warnings))
+				for _, c := range d.Changes {
+					before, after := string(c.Before), string(c.After)
+					if before == "" {
+						before = "-"
+					}
+					if after == "" {
+						after = "-"
+					}
+					fmt.Printf("%-7s %-16s %s -> %s  %s\n", c.Kind, c.Name, before, after, c.Message)
 				}
-				if report.Errors > 0 {
-					parts = append(parts, fmt.Sprintf("%d errors", report.Errors))
+				if len(d.Changes) == 0 {
+					fmt.Println("no check changed status")
+				} else if !isQuiet(cmd) {
+					fmt.Printf("\n%d fixed, %d broke, %d unchanged\n", len(d.Fixed), len(d.Broke), d.Unchanged)
 				}
-				fmt.Printf("done: %s\n", strings.Join(parts, ", "))
+			}
+			if len(d.Broke) > 0 {
+				return &exitError{code: 2, msg: fmt.Sprintf("DOC_REGRESSED: %d check(s) broke: %s", len(d.Broke), strings.Join(d.Broke, ", "))}
 			}
 			return nil
 		},
 	}
-	return cmd
+}
+
+// doctorFailure maps a doctor report to the exit contract of --fail-on:
+// an exitError with code 2 when the report has problems at or above the
+// threshold, nil otherwise. The fixes of a dry run were not applied, so
+// they count as problems at either threshold.
+func doctorFailure(report doctor.Report, failOn string) error {
+	switch {
+	case (failOn == "error" || failOn == "warn") && report.DryRun && report.Fixed > 0:
+		return &exitError{code: 2, msg: fmt.Sprintf("DOC_UNHEALTHY: dry run found %d fix(es) not applied, %d error(s) and %d warning(s)", report.Fixed, report.Errors, report.Warnings)}
+	case (failOn == "error" || failOn == "warn") && report.Errors > 0:
+		return &exitError{code: 2, msg: fmt.Sprintf("DOC_UNHEALTHY: doctor left %d error(s) and %d warning(s) unfixed", report.Errors, report.Warnings)}
+	case failOn == "warn" && report.Warnings > 0:
+		return &exitError{code: 2, msg: fmt.Sprintf("DOC_UNHEALTHY: doctor left %d warning(s) unfixed", report.Warnings)}
+	}
+	return nil
+}
+
+func printDoctorReport(report doctor.Report) {
+	for _, c := range report.Checks {
+		fmt.Printf("[%-5s] %-16s %s\n", c.Status, c.Name, c.Message)
+		if c.Fix != "" {
+			fmt.Printf("  -> %s\n", c.Fix)
+		}
+	}
+	fmt.Println()
+	if report.Fixed == 0 && report.Warnings == 0 && report.Errors == 0 {
+		fmt.Println("all checks passed")
+		return
+	}
+	parts := []string{}
+	if report.Fixed > 0 {
+		parts = append(parts, fmt.Sprintf("%d fixed", report.Fixed))
+	}
+	if report.Warnings > 0 {
+		parts = append(parts, fmt.Sprintf("%d warnings", report.Warnings))
+	}
+	if report.Errors > 0 {
+		parts = append(parts, fmt.Sprintf("%d errors", report.Errors))
+	}
+	suffix := ""
+	if report.DryRun {
+		suffix = " (dry-run)"
+	}
+	fmt.Printf("done: %s%s\n", strings.Join(parts, ", "), suffix)
+}
+
+// printDoctorProfile lists each check's wall-clock time, slowest first.
+func printDoctorProfile(report doctor.Report) {
+	fmt.Println()
+	fmt.Println("slowest checks:")
+	var total float64
+	for _, c := range report.Slowest() {
+		fmt.Printf("  %-16s %8.1fms\n", c.Name, c.DurationMs)
+		total += c.DurationMs
+	}
+	fmt.Printf("  %-16s %8.1fms\n", "total", total)
 }
 
 func newSelfCmd(newSvc func() (*app.Service, error), jsonOutput *bool) *cobra.Command {
 	selfCmd := &cobra.Command{Use: "self", Short: "Manage skillpm itself"}
 	var channel string
+	var dryRun bool
 	updateCmd := &cobra.Command{
 		Use:   "update",
 		Short: "Update skillpm binary with verification",
@@ -670,744 +1888,859 @@ func newSelfCmd(newSvc func() (*app.Service, error), jsonOutput *bool) *cobra.Co
 			if err != nil {
 				return err
 			}
+			if dryRun {
+				check, err := svc.SelfUpdateCheck(context.Background(), channel)
+				if err != nil {
+					return err
+				}
+				return print(cmd, *jsonOutput, check, selfUpdateCheckMessage(check))
+			}
 			if err := svc.SelfUpdate(context.Background(), channel); err != nil {
 				return err
 			}
-			return print(*jsonOutput, map[string]string{"channel": channel}, "updated")
+			return print(cmd, *jsonOutput, map[string]string{"channel": channel}, "updated")
 		},
 	}
 	updateCmd.Flags().StringVar(&channel, "channel", "stable", "release channel")
+	updateCmd.Flags().BoolVar(&dryRun, "dry-run", false, "report whether an update is available without downloading it")
 	selfCmd.AddCommand(updateCmd)
 	return selfCmd
 }
 
-const syncJSONSchemaVersion = "v1"
-
-type syncJSONSummary struct {
-	SchemaVersion       string             `json:"schemaVersion"`
-	UpdatedSources      []string           `json:"updatedSources"`
-	UpgradedSkills      []string           `json:"upgradedSkills"`
-	Reinjected          []string           `json:"reinjectedAgents"`
-	SkippedReinjects    []string           `json:"skippedReinjects"`
-	FailedReinjects     []string           `json:"failedReinjects"`
-	DryRun              bool               `json:"dryRun"`
-	StrictMode          bool               `json:"strictMode"`
-	StrictStatus        string             `json:"strictStatus"`
-	StrictFailureReason string             `json:"strictFailureReason"`
-	Mode                string             `json:"mode"`
-	Outcome             string             `json:"outcome"`
-	ProgressStatus      string             `json:"progressStatus"`
-	ProgressClass       string             `json:"progressClass"`
-	ProgressHotspot     string             `json:"progressHotspot"`
-	ProgressFocus       string             `json:"progressFocus"`
-	ProgressTarget      string             `json:"progressTarget"`
-	ProgressSignal      string             `json:"progressSignal"`
-	ActionBreakdown     string             `json:"actionBreakdown"`
-	NextAction          string             `json:"nextAction"`
-	PrimaryAction       string             `json:"primaryAction"`
-	ExecutionPriority   string             `json:"executionPriority"`
-	FollowUpGate        string             `json:"followUpGate"`
-	NextStepHint        string             `json:"nextStepHint"`
-	RecommendedCommand  string             `json:"recommendedCommand"`
-	RecommendedCommands []string           `json:"recommendedCommands"`
-	RecommendedAgent    string             `json:"recommendedAgent"`
-	SummaryLine         string             `json:"summaryLine"`
-	NoopReason          string             `json:"noopReason"`
-	RiskStatus          string             `json:"riskStatus"`
-	RiskLevel           string             `json:"riskLevel"`
-	RiskClass           string             `json:"riskClass"`
-	RiskBreakdown       string             `json:"riskBreakdown"`
-	RiskInjectCommands  []string           `json:"riskInjectCommands"`
-	RiskHotspot         string             `json:"riskHotspot"`
-	RiskAgents          []string           `json:"riskAgents"`
-	RiskAgentsTotal     int                `json:"riskAgentsTotal"`
-	HasProgress         bool               `json:"hasProgress"`
-	HasRisk             bool               `json:"hasRisk"`
-	CanProceed          bool               `json:"canProceed"`
-	NextBatchReady      bool               `json:"nextBatchReady"`
-	NextBatchBlocker    string             `json:"nextBatchBlocker"`
-	ActionCounts        syncJSONCounts     `json:"actionCounts"`
-	RiskCounts          syncJSONRiskCounts `json:"riskCounts"`
-	TopSamples          syncJSONTopSamples `json:"topSamples"`
-}
-
-type syncJSONCounts struct {
-	Sources       int `json:"sources"`
-	Upgrades      int `json:"upgrades"`
-	Reinjected    int `json:"reinjected"`
-	Skipped       int `json:"skipped"`
-	Failed        int `json:"failed"`
-	ProgressTotal int `json:"progressTotal"`
-	RiskTotal     int `json:"riskTotal"`
-	Total         int `json:"total"`
-}
-
-type syncJSONRiskCounts struct {
-	Skipped int `json:"skipped"`
-	Failed  int `json:"failed"`
-	Total   int `json:"total"`
-}
-
-type syncJSONTopSamples struct {
-	Sources    syncJSONSample `json:"sources"`
-	Upgrades   syncJSONSample `json:"upgrades"`
-	Reinjected syncJSONSample `json:"reinjected"`
-	Skipped    syncJSONSample `json:"skipped"`
-	Failed     syncJSONSample `json:"failed"`
-}
-
-type syncJSONSample struct {
-	Items     []string `json:"items"`
-	Remaining int      `json:"remaining"`
-}
-
-func buildSyncJSONSummary(report syncsvc.Report, strictMode bool) syncJSONSummary {
-	progressTotal := totalSyncProgressActions(report)
-	riskTotal := totalSyncIssues(report)
-	riskAgents := syncRiskAgents(report)
-	return syncJSONSummary{
-		SchemaVersion:       syncJSONSchemaVersion,
-		UpdatedSources:      sortedStringSlice(report.UpdatedSources),
-		UpgradedSkills:      sortedStringSlice(report.UpgradedSkills),
-		Reinjected:          sortedStringSlice(report.Reinjected),
-		SkippedReinjects:    sortedStringSlice(report.SkippedReinjects),
-		FailedReinjects:     sortedStringSlice(report.FailedReinjects),
-		DryRun:              report.DryRun,
-		StrictMode:          strictMode,
-		StrictStatus:        syncStrictStatus(strictMode),
-		StrictFailureReason: syncStrictFailureReason(report, strictMode),
-		Mode:                syncMode(report),
-		Outcome:             syncOutcome(report),
-		ProgressStatus:      syncProgressStatus(report),
-		ProgressClass:       syncProgressClass(report),
-		ProgressHotspot:     syncProgressHotspot(report),
-		ProgressFocus:       syncProgressFocus(report),
-		ProgressTarget:      syncProgressTarget(report),
-		ProgressSignal:      syncProgressSignal(report),
-		ActionBreakdown:     syncActionBreakdown(report),
-		NextAction:          syncNextAction(report),
-		PrimaryAction:       syncPrimaryAction(report),
-		ExecutionPriority:   syncExecutionPriority(report),
-		FollowUpGate:        syncFollowUpGate(report),
-		NextStepHint:        syncNextStepHint(report),
-		RecommendedCommand:  syncRecommendedCommand(report),
-		RecommendedCommands: syncRecommendedCommands(report),
-		RecommendedAgent:    syncRecommendedAgent(report),
-		SummaryLine:         syncSummaryLine(report),
-		NoopReason:          syncNoopReason(report),
-		RiskStatus:          syncRiskStatus(report),
-		RiskLevel:           syncRiskLevel(report),
-		RiskClass:           syncRiskClass(report),
-		RiskBreakdown:       syncRiskBreakdown(report),
-		RiskInjectCommands:  syncRiskInjectCommands(report),
-		RiskHotspot:         syncRiskHotspot(report),
-		RiskAgents:          riskAgents,
-		RiskAgentsTotal:     len(riskAgents),
-		HasProgress:         progressTotal > 0,
-		HasRisk:             riskTotal > 0,
-		CanProceed:          riskTotal == 0,
-		NextBatchReady:      syncNextBatchReady(report),
-		NextBatchBlocker:    syncNextBatchBlocker(report),
-		ActionCounts: syncJSONCounts{
-			Sources:       len(report.UpdatedSources),
-			Upgrades:      len(report.UpgradedSkills),
-			Reinjected:    len(report.Reinjected),
-			Skipped:       len(report.SkippedReinjects),
-			Failed:        len(report.FailedReinjects),
-			ProgressTotal: progressTotal,
-			RiskTotal:     riskTotal,
-			Total:         progressTotal + riskTotal,
-		},
-		RiskCounts: syncJSONRiskCounts{
-			Skipped: len(report.SkippedReinjects),
-			Failed:  len(report.FailedReinjects),
-			Total:   riskTotal,
-		},
-		TopSamples: syncJSONTopSamples{
-			Sources:    topSample(report.UpdatedSources, 3),
-			Upgrades:   topSample(report.UpgradedSkills, 3),
-			Reinjected: topSample(report.Reinjected, 3),
-			Skipped:    topSample(report.SkippedReinjects, 3),
-			Failed:     topSample(report.FailedReinjects, 3),
-		},
-	}
-}
-
-func topSample(items []string, limit int) syncJSONSample {
-	if limit <= 0 {
-		limit = 1
-	}
-	sorted := sortedStringSlice(items)
-	if len(sorted) <= limit {
-		return syncJSONSample{Items: sorted}
+// selfUpdateCheckMessage summarizes a dry-run self update.
+func selfUpdateCheckMessage(check selfupdate.Check) string {
+	signature := "signed"
+	if !check.Signed {
+		signature = "unsigned"
+		if check.SignatureRequired {
+			signature += "; update would be refused because signatures are required"
+		}
 	}
-	return syncJSONSample{
-		Items:     sorted[:limit],
-		Remaining: len(sorted) - limit,
+	if !check.UpdateAvailable {
+		return fmt.Sprintf("skillpm %s is up to date (%s offers %s, %s)", check.CurrentVersion, check.Channel, check.AvailableVersion, signature)
 	}
+	return fmt.Sprintf("update available: %s -> %s on %s (%s)", check.CurrentVersion, check.AvailableVersion, check.Channel, signature)
 }
 
-func stableStringSlice(items []string) []string {
-	out := make([]string, len(items))
-	copy(out, items)
-	return out
+func newExportCmd(newSvc func() (*app.Service, error), jsonOutput *bool) *cobra.Command {
+	var lockfile string
+	cmd := &cobra.Command{
+		Use:   "export <archive.tar.gz>",
+		Short: "Bundle installed skills into a portable archive",
+		Long: `Write every installed skill, the current skills.lock and a manifest of
+versions and checksums into a single .tar.gz that 'skillpm import' can
+install on another machine without network access.`,
+		Example: "  skillpm export skills-backup.tar.gz",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			svc, err := newSvc()
+			if err != nil {
+				return err
+			}
+			res, err := svc.Export(args[0], lockfile)
+			if err != nil {
+				return err
+			}
+			return print(cmd, *jsonOutput, res, fmt.Sprintf("exported %d skills to %s", len(res.Skills), res.Path))
+		},
+	}
+	cmd.Flags().StringVar(&lockfile, "lockfile", "", "skills.lock path to include")
+	return cmd
 }
 
-func sortedStringSlice(items []string) []string {
-	sorted := stableStringSlice(items)
-	sort.Strings(sorted)
-	return sorted
+func newImportCmd(newSvc func() (*app.Service, error), jsonOutput *bool) *cobra.Command {
+	var lockfile string
+	var force bool
+	var maxSeverity string
+	cmd := &cobra.Command{
+		Use:   "import <archive.tar.gz>",
+		Short: "Install skills from an archive written by export",
+		Long: `Install the skills in an archive written by 'skillpm export'. Each skill's
+content is checked against the checksum in the archive's skills.lock, and
+the import fails with IMP_CHECKSUM on any mismatch unless --force is set.
+The content is then security scanned as on install, with the trust tier of
+the configured source of the same name; --force and --max-severity apply
+to the scan gate as they do for install. Imported skills replace installed
+versions of the same refs, and their state and lockfile entries are rebuilt.`,
+		Example: "  skillpm import skills-backup.tar.gz",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			svc, err := newSvc()
+			if err != nil {
+				return err
+			}
+			if err := setMaxSeverity(svc, maxSeverity); err != nil {
+				return err
+			}
+			res, err := svc.Import(args[0], lockfile, force)
+			if err != nil {
+				return err
+			}
+			if structuredOutput(cmd, *jsonOutput) {
+				return print(cmd, true, res, "")
+			}
+			for _, ref := range res.Mismatched {
+				fmt.Fprintf(os.Stderr, "warning: %s does not match its lockfile checksum (imported with --force)\n", ref)
+			}
+			fmt.Printf("imported %d skills\n", len(res.Imported))
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&lockfile, "lockfile", "", "skills.lock path to update")
+	cmd.Flags().BoolVar(&force, "force", false, "import skills whose content does not match the lockfile checksum, and bypass medium/high scan findings unless --max-severity is set")
+	cmd.Flags().StringVar(&maxSeverity, "max-severity", "", "allow scan findings up to this severity and refuse anything above it; takes precedence over --force")
+	return cmd
 }
 
-func syncMode(report syncsvc.Report) string {
-	if report.DryRun {
-		return "dry-run"
+func newStoreCmd(newSvc func() (*app.Service, error), jsonOutput *bool) *cobra.Command {
+	storeCmd := &cobra.Command{Use: "store", Short: "Maintain the local skill store"}
+	var dryRun bool
+	gcCmd := &cobra.Command{
+		Use:     "gc",
+		Short:   "Remove installed directories not referenced by state",
+		Example: "  skillpm store gc --dry-run",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			svc, err := newSvc()
+			if err != nil {
+				return err
+			}
+			res, err := svc.StoreGC(dryRun)
+			if err != nil {
+				return err
+			}
+			if structuredOutput(cmd, *jsonOutput) {
+				return print(cmd, true, res, "")
+			}
+			verb := "removed"
+			if dryRun {
+				verb = "would remove"
+			}
+			for _, name := range res.Removed {
+				fmt.Printf("  - %s\n", name)
+			}
+			fmt.Printf("%s %d directories (%d bytes)\n", verb, len(res.Removed), res.ReclaimedBytes)
+			return nil
+		},
 	}
-	return "apply"
-}
+	gcCmd.Flags().BoolVar(&dryRun, "dry-run", false, "list unreferenced directories without removing them")
+	storeCmd.AddCommand(gcCmd)
 
-func syncStrictStatus(strict bool) string {
-	if strict {
-		return "enabled"
+	var lockfile string
+	checkCmd := &cobra.Command{
+		Use:   "check",
+		Short: "Verify the store is internally consistent without changing it",
+		Long: `Verify that the state and lockfile parse, that installed directories and
+state records match, that installed content matches its recorded checksum,
+and that every injected skill is installed. Exits 2 on any inconsistency;
+run 'skillpm doctor' to repair.`,
+		Example: "  skillpm store check --json",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			svc, err := newSvc()
+			if err != nil {
+				return err
+			}
+			report := svc.StoreCheck(lockfile)
+			if structuredOutput(cmd, *jsonOutput) {
+				if err := print(cmd, true, report, ""); err != nil {
+					return err
+				}
+			} else {
+				for _, issue := range report.Issues {
+					subject := issue.Ref
+					if subject == "" {
+						subject = issue.Path
+					}
+					if subject == "" {
+						fmt.Printf("[%s] %s\n", issue.Check, issue.Message)
+					} else {
+						fmt.Printf("[%s] %s: %s\n", issue.Check, subject, issue.Message)
+					}
+				}
+				if report.Consistent {
+					fmt.Printf("store consistent (%s)\n", strings.Join(report.Checked, ", "))
+				}
+			}
+			if !report.Consistent {
+				return &exitError{code: 2, msg: fmt.Sprintf("STORE_INCONSISTENT: store check found %d issue(s)", len(report.Issues))}
+			}
+			return nil
+		},
 	}
-	return "disabled"
+	checkCmd.Flags().StringVar(&lockfile, "lockfile", "", "skills.lock path")
+	storeCmd.AddCommand(checkCmd)
+	return storeCmd
 }
 
-func syncStrictFailureReason(report syncsvc.Report, strictMode bool) string {
-	if !strictMode {
-		return "strict-disabled"
-	}
-	failed := len(report.FailedReinjects)
-	skipped := len(report.SkippedReinjects)
-	if failed > 0 && skipped > 0 {
-		return "risk-present-mixed"
-	}
-	if failed > 0 {
-		return "risk-present-failed"
-	}
-	if skipped > 0 {
-		return "risk-present-skipped"
+func newMetricsCmd(newSvc func() (*app.Service, error), jsonOutput *bool) *cobra.Command {
+	var reset bool
+	cmd := &cobra.Command{
+		Use:   "metrics",
+		Short: "Show local operation counters",
+		Long: `Show how many installs, upgrades, syncs and injects have succeeded, as
+counted in metrics.json in the state root. Counting is off by default and
+enabled with metrics.enabled = true in config.toml; nothing is sent anywhere.
+
+Examples:
+  skillpm metrics
+  skillpm metrics --json
+  skillpm metrics --reset`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			svc, err := newSvc()
+			if err != nil {
+				return err
+			}
+			var m app.Metrics
+			if reset {
+				m, err = svc.ResetMetrics()
+			} else {
+				m, err = svc.Metrics()
+			}
+			if err != nil {
+				return err
+			}
+			if structuredOutput(cmd, *jsonOutput) {
+				return print(cmd, true, m, "")
+			}
+			if reset {
+				fmt.Println("metrics reset")
+				return nil
+			}
+			fmt.Printf("installs  %d\nupgrades  %d\nsyncs     %d\ninjects   %d\n", m.Installs, m.Upgrades, m.Syncs, m.Injects)
+			if !m.Since.IsZero() && !isQuiet(cmd) {
+				fmt.Printf("since %s\n", m.Since.Format(time.RFC3339))
+			}
+			if !svc.Config.Metrics.Enabled {
+				fmt.Fprintln(os.Stderr, "warning: metrics are disabled; enable with metrics.enabled = true in config.toml")
+			}
+			return nil
+		},
 	}
-	return "none"
+	cmd.Flags().BoolVar(&reset, "reset", false, "zero every counter")
+	return cmd
 }
 
-func syncNextBatchReady(report syncsvc.Report) bool {
-	return !report.DryRun && totalSyncIssues(report) == 0
+// newScopeCmd reports scope resolution without building a Service, so it
+// explains the choice even when the chosen scope fails to load.
+func newScopeCmd(scopeFlag *string, jsonOutput *bool) *cobra.Command {
+	scopeCmd := &cobra.Command{Use: "scope", Short: "Inspect scope resolution"}
+	showCmd := &cobra.Command{
+		Use:   "show",
+		Short: "Show the effective scope and why it was chosen",
+		Long: `Show the effective scope and why it was chosen: --scope wins, then the
+nearest .skillpm/scope.lock (scope = "global" or "project") above the
+current directory, then auto-detection of a project manifest.`,
+		Example: "  skillpm scope show\n  skillpm scope show --json",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cwd, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+			d, err := config.DecideScope(*scopeFlag, cwd)
+			if err != nil {
+				return err
+			}
+			if structuredOutput(cmd, *jsonOutput) {
+				return print(cmd, true, d, "")
+			}
+			fmt.Printf("scope: %s\n", d.Scope)
+			if d.ProjectRoot != "" {
+				fmt.Printf("project root: %s\n", d.ProjectRoot)
+			}
+			fmt.Printf("reason: %s\n", d.Reason)
+			return nil
+		},
+	}
+	scopeCmd.AddCommand(showCmd)
+	return scopeCmd
 }
 
-func syncNextBatchBlocker(report syncsvc.Report) string {
-	if syncNextBatchReady(report) {
-		return "none"
-	}
-	if totalSyncIssues(report) > 0 {
-		return "risk-present"
-	}
-	if report.DryRun {
-		return "dry-run-mode"
+// newConfigCmd inspects the config file directly rather than through a
+// Service, so it still works when the config fails to load.
+func newConfigCmd(configPath *string, jsonOutput *bool) *cobra.Command {
+	configCmd := &cobra.Command{Use: "config", Short: "Inspect and edit skillpm configuration"}
+	validateCmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Check config.toml and report every problem with its key",
+		Example: `  skillpm config validate
+  skillpm config validate --config ./config.toml --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := config.ResolveConfigPath(*configPath)
+			if err != nil {
+				return err
+			}
+			issues, err := config.CheckFile(path)
+			if err != nil {
+				return err
+			}
+			errCount := 0
+			for _, issue := range issues {
+				if !issue.Advisory {
+					errCount++
+				}
+			}
+			if structuredOutput(cmd, *jsonOutput) {
+				if issues == nil {
+					issues = []config.ValidationIssue{}
+				}
+				payload := map[string]any{"path": path, "valid": errCount == 0, "issues": issues}
+				if err := print(cmd, true, payload, ""); err != nil {
+					return err
+				}
+			} else {
+				for _, issue := range issues {
+					level := "error"
+					if issue.Advisory {
+						level = "warn "
+					}
+					fmt.Printf("[%s] %s\n", level, issue)
+				}
+				if errCount == 0 {
+					fmt.Printf("config valid: %s (%d warnings)\n", path, len(issues))
+				}
+			}
+			if errCount > 0 {
+				return fmt.Errorf("CFG_INVALID: %d problems in %s", errCount, path)
+			}
+			return nil
+		},
 	}
-	return "unknown"
-}
 
-func totalSyncActions(report syncsvc.Report) int {
-	return totalSyncProgressActions(report) + totalSyncIssues(report)
-}
+	var manifest bool
+	editCmd := &cobra.Command{
+		Use:   "edit",
+		Short: "Edit config.toml in $EDITOR and save it only if it validates",
+		Long: `Open config.toml (or, with --manifest, the project's .skillpm/skills.toml)
+in $VISUAL or $EDITOR. Edits go to a temporary copy that replaces the file
+only once it passes the same checks as 'config validate'; an invalid edit
+is reported and can be reopened, and the original is left untouched.`,
+		Example: `  skillpm config edit
+  EDITOR="code --wait" skillpm config edit --manifest`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := config.ResolveConfigPath(*configPath)
+			if err != nil {
+				return err
+			}
+			check := func(data []byte) ([]config.ValidationIssue, error) { return config.CheckData(path, data) }
+			if manifest {
+				cwd, err := os.Getwd()
+				if err != nil {
+					return err
+				}
+				root, ok := config.FindProjectRoot(cwd)
+				if !ok {
+					return fmt.Errorf("PRJ_NO_MANIFEST: no project manifest found; run 'skillpm init' first")
+				}
+				path = config.ProjectManifestPath(root)
+				check = func(data []byte) ([]config.ValidationIssue, error) { return config.CheckManifestData(path, data) }
+			} else if _, err := os.Stat(path); os.IsNotExist(err) {
+				if _, err := config.Ensure(path); err != nil {
+					return err
+				}
+			}
+			changed, err := editFile(path, check, bufio.NewReader(os.Stdin), os.Stderr)
+			if err != nil {
+				return err
+			}
+			msg := "no changes to " + path
+			if changed {
+				msg = "saved " + path
+			}
+			return print(cmd, *jsonOutput, map[string]any{"path": path, "changed": changed}, msg)
+		},
+	}
+	editCmd.Flags().BoolVar(&manifest, "manifest", false, "edit the project manifest instead of config.toml")
 
-func totalSyncProgressActions(report syncsvc.Report) int {
-	return len(report.UpdatedSources) + len(report.UpgradedSkills) + len(report.Reinjected)
+	configCmd.AddCommand(validateCmd, editCmd, newConfigProfileCmd(configPath, jsonOutput))
+	return configCmd
 }
 
-func totalSyncIssues(report syncsvc.Report) int {
-	return len(report.SkippedReinjects) + len(report.FailedReinjects)
-}
+func newConfigProfileCmd(configPath *string, jsonOutput *bool) *cobra.Command {
+	profileCmd := &cobra.Command{
+		Use:   "profile",
+		Short: "Switch between named configurations",
+		Long: `Keep several complete configurations (sources, adapters, security) and
+switch between them. Profiles live in ~/.skillpm/profiles/<name>.toml; the
+"default" profile is ~/.skillpm/config.toml. Commands use the active
+profile unless --config is given.`,
+	}
 
-func syncProgressStatus(report syncsvc.Report) string {
-	if totalSyncProgressActions(report) > 0 {
-		return "progress-made"
+	var fromDefaults bool
+	createCmd := &cobra.Command{
+		Use:   "create <name>",
+		Short: "Create a profile from the current config",
+		Example: `  skillpm config profile create work
+  skillpm config profile create personal --from-defaults`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := config.DefaultConfig()
+			if !fromDefaults {
+				path, err := config.ResolveConfigPath(*configPath)
+				if err != nil {
+					return err
+				}
+				if cfg, err = config.Ensure(path); err != nil {
+					return err
+				}
+			}
+			path, err := config.CreateProfile(args[0], cfg)
+			if err != nil {
+				return err
+			}
+			return print(cmd, *jsonOutput, map[string]any{"profile": args[0], "path": path}, fmt.Sprintf("created profile %s at %s (run 'skillpm config profile use %s' to switch)", args[0], path, args[0]))
+		},
 	}
-	return "no-progress"
-}
+	createCmd.Flags().BoolVar(&fromDefaults, "from-defaults", false, "start from the default config instead of copying the current one")
 
-func syncProgressClass(report syncsvc.Report) string {
-	hasSourceUpdates := len(report.UpdatedSources) > 0
-	hasUpgrades := len(report.UpgradedSkills) > 0
-	hasReinjections := len(report.Reinjected) > 0
+	useCmd := &cobra.Command{
+		Use:   "use <name>",
+		Short: "Make a profile active",
+		Example: `  skillpm config profile use work
+  skillpm config profile use default`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := config.UseProfile(args[0]); err != nil {
+				return err
+			}
+			return print(cmd, *jsonOutput, map[string]any{"active": args[0], "path": config.ProfilePath(args[0])}, fmt.Sprintf("using profile %s (%s)", args[0], config.ProfilePath(args[0])))
+		},
+	}
 
-	switch {
-	case hasReinjections:
-		return "reinjection"
-	case hasUpgrades:
-		return "upgrade"
-	case hasSourceUpdates:
-		return "source-refresh"
-	default:
-		return "none"
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List profiles with the active one marked",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			names, err := config.ListProfiles()
+			if err != nil {
+				return err
+			}
+			active, err := config.ActiveProfile()
+			if err != nil {
+				return err
+			}
+			type profileItem struct {
+				Name   string `json:"name"`
+				Path   string `json:"path"`
+				Active bool   `json:"active"`
+			}
+			items := make([]profileItem, len(names))
+			for i, name := range names {
+				items[i] = profileItem{Name: name, Path: config.ProfilePath(name), Active: name == active}
+			}
+			if structuredOutput(cmd, *jsonOutput) {
+				return print(cmd, true, items, "")
+			}
+			for _, item := range items {
+				marker := "  "
+				if item.Active {
+					marker = "* "
+				}
+				fmt.Printf("%s%s\t%s\n", marker, item.Name, item.Path)
+			}
+			return nil
+		},
 	}
-}
-
-func syncActionBreakdown(report syncsvc.Report) string {
-	return fmt.Sprintf("sources=%d upgrades=%d reinjected=%d skipped=%d failed=%d", len(report.UpdatedSources), len(report.UpgradedSkills), len(report.Reinjected), len(report.SkippedReinjects), len(report.FailedReinjects))
-}
 
-func syncProgressHotspot(report syncsvc.Report) string {
-	if len(report.UpgradedSkills) > 0 {
-		return sortedStringSlice(report.UpgradedSkills)[0]
-	}
-	if len(report.Reinjected) > 0 {
-		return sortedStringSlice(report.Reinjected)[0]
-	}
-	if len(report.UpdatedSources) > 0 {
-		return sortedStringSlice(report.UpdatedSources)[0]
-	}
-	return "none"
+	profileCmd.AddCommand(createCmd, useCmd, listCmd)
+	return profileCmd
 }
 
-func syncProgressFocus(report syncsvc.Report) string {
-	if len(report.Reinjected) > 0 {
-		return sortedStringSlice(report.Reinjected)[0]
+// runEditor opens path in $VISUAL or $EDITOR, falling back to vi.
+var runEditor = func(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
 	}
-	if len(report.UpgradedSkills) > 0 {
-		return sortedStringSlice(report.UpgradedSkills)[0]
+	if editor == "" {
+		editor = "vi"
 	}
-	if len(report.UpdatedSources) > 0 {
-		return sortedStringSlice(report.UpdatedSources)[0]
+	parts := strings.Fields(editor)
+	c := exec.Command(parts[0], append(parts[1:], path)...)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("CFG_EDIT: editor %q failed: %w", editor, err)
 	}
-	return "none"
+	return nil
 }
 
-func syncProgressTarget(report syncsvc.Report) string {
-	if totalSyncProgressActions(report) == 0 {
-		return "none"
+// editFile has the user edit a temporary copy of path and renames it over
+// path only when check finds no errors. It reports whether path changed.
+func editFile(path string, check func([]byte) ([]config.ValidationIssue, error), in *bufio.Reader, out io.Writer) (bool, error) {
+	orig, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
 	}
-	if syncProgressClass(report) == "reinjection" {
-		return syncProgressFocus(report)
+	info, err := os.Stat(path)
+	if err != nil {
+		return false, err
 	}
-	return syncProgressHotspot(report)
-}
-
-func syncProgressSignal(report syncsvc.Report) string {
-	if totalSyncProgressActions(report) == 0 {
-		return "none"
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".edit-*")
+	if err != nil {
+		return false, fmt.Errorf("CFG_EDIT: %w", err)
 	}
-	return fmt.Sprintf("%s:%s", syncProgressClass(report), syncProgressTarget(report))
-}
-
-func syncOutcome(report syncsvc.Report) string {
-	if totalSyncActions(report) == 0 {
-		return "noop"
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+	_, writeErr := tmp.Write(orig)
+	if closeErr := tmp.Close(); writeErr == nil {
+		writeErr = closeErr
 	}
-	issues := totalSyncIssues(report)
-	progress := totalSyncProgressActions(report)
-	if progress == 0 && issues > 0 {
-		return "blocked"
+	if writeErr == nil {
+		writeErr = os.Chmod(tmpPath, info.Mode().Perm())
 	}
-	if progress > 0 && issues > 0 {
-		return "changed-with-risk"
+	if writeErr != nil {
+		return false, fmt.Errorf("CFG_EDIT: %w", writeErr)
 	}
-	return "changed"
-}
 
-func syncNextAction(report syncsvc.Report) string {
-	switch syncOutcome(report) {
-	case "noop":
-		if report.DryRun {
-			return "plan-next-iteration"
-		}
-		return "monitor"
-	case "blocked":
-		if len(report.FailedReinjects) > 0 {
-			if report.DryRun {
-				return "resolve-failures-then-apply"
-			}
-			return "resolve-reinjection-failures"
-		}
-		if report.DryRun {
-			return "resolve-skips-then-apply"
-		}
-		return "resolve-reinjection-skips"
-	case "changed-with-risk":
-		if len(report.FailedReinjects) > 0 {
-			if report.DryRun {
-				return "resolve-failures-then-apply-plan"
-			}
-			return "review-failed-risk-items"
-		}
-		if report.DryRun {
-			return "resolve-skips-then-apply-plan"
+	for {
+		if err := runEditor(tmpPath); err != nil {
+			return false, err
 		}
-		return "review-skipped-risk-items"
-	default:
-		if report.DryRun {
-			return "apply-plan"
+		data, err := os.ReadFile(tmpPath)
+		if err != nil {
+			return false, fmt.Errorf("CFG_EDIT: %w", err)
 		}
-		return "verify-and-continue"
-	}
-}
-
-func syncPrimaryAction(report syncsvc.Report) string {
-	switch syncOutcome(report) {
-	case "noop":
-		if report.DryRun {
-			return "No changes detected; queue the next iteration to keep momentum."
+		if bytes.Equal(data, orig) {
+			return false, nil
 		}
-		return "No changes detected; keep monitoring and retry on the next cycle."
-	case "blocked":
-		if report.DryRun {
-			return "Sync plan is blocked by reinjection risk; resolve skipped/failed agents before applying changes."
+		var problems []string
+		issues, checkErr := check(data)
+		if checkErr != nil {
+			problems = append(problems, checkErr.Error())
 		}
-		return "Reinjection is blocked; resolve skipped/failed agents first before adding new work."
-	case "changed-with-risk":
-		if len(report.FailedReinjects) > 0 {
-			if report.DryRun {
-				return "Sync plan includes progress with failed reinjections; clear failures before applying this iteration."
+		for _, issue := range issues {
+			if !issue.Advisory {
+				problems = append(problems, issue.String())
 			}
-			return "Progress landed with failed reinjections; fix failures before expanding scope."
 		}
-		if report.DryRun {
-			return "Sync plan includes progress with skipped reinjections; clear skips before applying this iteration."
+		if len(problems) == 0 {
+			if err := os.Rename(tmpPath, path); err != nil {
+				return false, fmt.Errorf("CFG_EDIT: %w", err)
+			}
+			return true, nil
 		}
-		return "Progress landed with skipped reinjections; clear skips before expanding scope."
-	default:
-		if report.DryRun {
-			return "Apply this sync plan to convert planned progress into committed state."
+		for _, p := range problems {
+			fmt.Fprintf(out, "[error] %s\n", p)
 		}
-		return "Progress is applied and clear; move directly to the next feature increment."
-	}
-}
-
-func syncExecutionPriority(report syncsvc.Report) string {
-	if totalSyncIssues(report) > 0 {
-		if len(report.FailedReinjects) > 0 {
-			return "stabilize-failures"
+		notSaved := fmt.Errorf("CFG_INVALID: %d problems; %s was not changed", len(problems), path)
+		if !stdinIsTerminal() {
+			return false, notSaved
 		}
-		return "stabilize-risks"
-	}
-	if totalSyncProgressActions(report) > 0 {
-		if report.DryRun {
-			return "apply-feature-iteration"
+		fmt.Fprint(out, "reopen the editor? [Y/n] ")
+		line, _ := in.ReadString('\n')
+		if answer := strings.ToLower(strings.TrimSpace(line)); answer == "n" || answer == "no" {
+			return false, notSaved
 		}
-		return "feature-iteration"
-	}
-	if report.DryRun {
-		return "plan-feature-iteration"
 	}
-	return "monitor-next-cycle"
 }
 
-func syncFollowUpGate(report syncsvc.Report) string {
-	if totalSyncIssues(report) > 0 {
-		return "blocked-by-risk"
-	}
-	if totalSyncProgressActions(report) > 0 {
-		if report.DryRun {
-			return "ready-to-apply"
-		}
-		return "ready-for-next-iteration"
-	}
-	if report.DryRun {
-		return "plan-next-iteration"
-	}
-	return "monitor-next-cycle"
+func joinSorted(items []string) string {
+	return joinSortedWith(items, ", ")
 }
 
-func syncNextStepHint(report syncsvc.Report) string {
-	if totalSyncIssues(report) > 0 {
-		if len(report.FailedReinjects) > 0 {
-			return "reinject-failed-agents"
-		}
-		return "reinject-skipped-agents"
+func summarizeTop(items []string, limit int) string {
+	if len(items) == 0 {
+		return "none"
 	}
-	if report.DryRun {
-		if totalSyncProgressActions(report) > 0 {
-			return "apply-sync-plan"
-		}
-		return "queue-feature-iteration"
+	if limit <= 0 {
+		limit = 1
 	}
-	if totalSyncProgressActions(report) > 0 {
-		return "start-next-feature-iteration"
+	sorted := append([]string(nil), items...)
+	sort.Strings(sorted)
+	if len(sorted) <= limit {
+		return strings.Join(sorted, ", ")
 	}
-	return "wait-next-sync-cycle"
+	return fmt.Sprintf("%s ... (+%d more)", strings.Join(sorted[:limit], ", "), len(sorted)-limit)
 }
 
-func syncRecommendedCommand(report syncsvc.Report) string {
-	switch syncOutcome(report) {
-	case "noop":
-		if report.DryRun {
-			return "skillpm sync"
-		}
-		return "skillpm sync --dry-run"
-	case "blocked", "changed-with-risk":
-		if totalSyncIssues(report) == 0 {
-			if report.DryRun {
-				return "skillpm sync"
-			}
-			return "skillpm sync --dry-run"
-		}
-		agent := syncRecommendedAgent(report)
-		if agent != "" && agent != "none" {
-			return fmt.Sprintf("skillpm inject --agent %s <skill-ref>", agent)
-		}
-		return "skillpm inject --agent <agent> <skill-ref>"
-	default:
-		if report.DryRun {
-			return "skillpm sync"
-		}
-		return "skillpm source list"
-	}
+func joinSortedWith(items []string, sep string) string {
+	copied := append([]string(nil), items...)
+	sort.Strings(copied)
+	return strings.Join(copied, sep)
 }
 
-func syncRecommendedCommands(report syncsvc.Report) []string {
-	commands := []string{syncRecommendedCommand(report)}
-	hasIssues := totalSyncIssues(report) > 0
-	hasProgress := totalSyncProgressActions(report) > 0
-	if hasIssues {
-		commands = append(commands, syncRiskInjectCommands(report)...)
-		commands = append(commands, "skillpm source list")
-		if hasProgress && !report.DryRun {
-			commands = append(commands, "go test ./...")
-		}
-		commands = append(commands, "skillpm sync --dry-run")
-		if report.DryRun {
-			commands = append(commands, "skillpm sync")
-			if hasProgress {
-				commands = append(commands, "go test ./...")
-			}
-		}
-		return uniqueNonEmpty(commands)
-	}
-	if hasProgress {
-		commands = append(commands, "skillpm source list", "go test ./...", "skillpm sync --dry-run")
-		return uniqueNonEmpty(commands)
+// outputFields returns the --field selectors applied to JSON output by
+// print. Commands built outside the root command (as in tests) have none.
+func outputFields(cmd *cobra.Command) []string {
+	fields, err := cmd.Flags().GetStringArray("field")
+	if err != nil {
+		return nil
 	}
-	commands = append(commands, "skillpm source list")
-	return uniqueNonEmpty(commands)
+	return fields
 }
 
-func syncRiskInjectCommands(report syncsvc.Report) []string {
-	agents := syncRiskAgents(report)
-	commands := make([]string, 0, len(agents))
-	for _, agent := range agents {
-		commands = append(commands, fmt.Sprintf("skillpm inject --agent %s <skill-ref>", agent))
-	}
-	return commands
+// structuredOutput reports whether a command should print its results for
+// machines rather than people: --json was given, or --format asked for a
+// template, which renders the same results the JSON output would carry.
+func structuredOutput(cmd *cobra.Command, jsonOutput bool) bool {
+	return jsonOutput || outputTemplate(cmd) != nil
 }
 
-func uniqueNonEmpty(items []string) []string {
-	seen := make(map[string]struct{}, len(items))
-	out := make([]string, 0, len(items))
-	for _, item := range items {
-		trimmed := strings.TrimSpace(item)
-		if trimmed == "" {
-			continue
-		}
-		if _, ok := seen[trimmed]; ok {
-			continue
-		}
-		seen[trimmed] = struct{}{}
-		out = append(out, trimmed)
+func print(cmd *cobra.Command, jsonOutput bool, payload any, message string) error {
+	if tmpl := outputTemplate(cmd); tmpl != nil {
+		return renderTemplate(os.Stdout, tmpl, payload)
 	}
-	return out
-}
-
-func syncRecommendedAgent(report syncsvc.Report) string {
-	for _, item := range sortedStringSlice(report.FailedReinjects) {
-		agent := riskAgentName(item)
-		if agent != "" {
-			return agent
+	if jsonOutput {
+		if fields := outputFields(cmd); len(fields) > 0 {
+			projected, err := projectFields(payload, fields)
+			if err != nil {
+				return err
+			}
+			payload = projected
 		}
-	}
-	for _, item := range sortedStringSlice(report.SkippedReinjects) {
-		agent := riskAgentName(item)
-		if agent != "" {
-			return agent
+		blob, err := json.MarshalIndent(payload, "", "  ")
+		if err != nil {
+			return err
 		}
+		fmt.Println(string(blob))
+		return nil
 	}
-	return "none"
-}
-
-func riskAgentName(item string) string {
-	agent := strings.TrimSpace(item)
-	if agent == "" {
-		return ""
-	}
-	if idx := strings.Index(agent, ":"); idx >= 0 {
-		agent = strings.TrimSpace(agent[:idx])
-	}
-	if idx := strings.Index(agent, " "); idx >= 0 {
-		agent = strings.TrimSpace(agent[:idx])
-	}
-	if idx := strings.Index(agent, "("); idx >= 0 {
-		agent = strings.TrimSpace(agent[:idx])
+	if message != "" {
+		fmt.Println(message)
 	}
-	return agent
-}
-
-func syncSummaryLine(report syncsvc.Report) string {
-	return fmt.Sprintf("outcome=%s progress=%d risk=%d mode=%s", syncOutcome(report), totalSyncProgressActions(report), totalSyncIssues(report), syncMode(report))
+	return nil
 }
 
-func syncNoopReason(report syncsvc.Report) string {
-	if syncOutcome(report) != "noop" {
-		return "not-applicable"
+// printEvent is print for streamed JSON output: each payload is written
+// compactly on its own line so consumers can read the stream line by line.
+func printEvent(cmd *cobra.Command, payload any) error {
+	if tmpl := outputTemplate(cmd); tmpl != nil {
+		return renderTemplate(os.Stdout, tmpl, payload)
 	}
-	if report.DryRun {
-		return "dry-run detected no source/upgrade/reinjection deltas"
+	if fields := outputFields(cmd); len(fields) > 0 {
+		projected, err := projectFields(payload, fields)
+		if err != nil {
+			return err
+		}
+		payload = projected
 	}
-	return "no source updates, skill upgrades, or reinjection changes detected"
-}
-
-func syncRiskBreakdown(report syncsvc.Report) string {
-	return fmt.Sprintf("skipped=%d failed=%d", len(report.SkippedReinjects), len(report.FailedReinjects))
-}
-
-func syncRiskStatus(report syncsvc.Report) string {
-	if totalSyncIssues(report) > 0 {
-		return "attention-needed"
+	blob, err := json.Marshal(payload)
+	if err != nil {
+		return err
 	}
-	return "clear"
+	fmt.Println(string(blob))
+	return nil
 }
 
-func syncRiskLevel(report syncsvc.Report) string {
-	if len(report.FailedReinjects) > 0 {
-		return "high"
-	}
-	if len(report.SkippedReinjects) > 0 {
-		return "medium"
+// outputTemplate returns the parsed --format template, which print applies
+// in place of JSON. The root command has already rejected a bad template.
+func outputTemplate(cmd *cobra.Command) *template.Template {
+	format, err := cmd.Flags().GetString("format")
+	if err != nil {
+		return nil
 	}
-	return "none"
+	tmpl, _ := parseOutputFormat(format)
+	return tmpl
 }
 
-func syncRiskClass(report syncsvc.Report) string {
-	failed := len(report.FailedReinjects) > 0
-	skipped := len(report.SkippedReinjects) > 0
-	switch {
-	case failed && skipped:
-		return "mixed"
-	case failed:
-		return "failed-only"
-	case skipped:
-		return "skipped-only"
-	default:
-		return "none"
+// parseOutputFormat parses a "template:<text>" --format value. An empty
+// value selects the default output and returns a nil template.
+func parseOutputFormat(format string) (*template.Template, error) {
+	if format == "" {
+		return nil, nil
 	}
-}
-
-func syncRiskHotspot(report syncsvc.Report) string {
-	if len(report.FailedReinjects) > 0 {
-		return sortedStringSlice(report.FailedReinjects)[0]
+	text, ok := strings.CutPrefix(format, "template:")
+	if !ok {
+		return nil, fmt.Errorf("OUT_FORMAT: unsupported --format %q (use template:<go-template>)", format)
 	}
-	if len(report.SkippedReinjects) > 0 {
-		return sortedStringSlice(report.SkippedReinjects)[0]
+	tmpl, err := template.New("format").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("OUT_FORMAT: %w", err)
 	}
-	return "none"
+	return tmpl, nil
 }
 
-func syncRiskAgents(report syncsvc.Report) []string {
-	agents := make([]string, 0, len(report.FailedReinjects)+len(report.SkippedReinjects))
-	seen := map[string]struct{}{}
-	for _, item := range report.FailedReinjects {
-		agent := riskAgentName(item)
-		if agent == "" {
-			continue
+// renderTemplate executes tmpl once per element of a list payload, or once
+// for any other payload, ending each result with a newline.
+func renderTemplate(w io.Writer, tmpl *template.Template, payload any) error {
+	items := []any{payload}
+	if v := reflect.ValueOf(payload); v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+		items = make([]any, v.Len())
+		for i := range items {
+			items[i] = v.Index(i).Interface()
 		}
-		if _, ok := seen[agent]; ok {
-			continue
-		}
-		seen[agent] = struct{}{}
-		agents = append(agents, agent)
 	}
-	for _, item := range report.SkippedReinjects {
-		agent := riskAgentName(item)
-		if agent == "" {
-			continue
+	for _, item := range items {
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, item); err != nil {
+			return fmt.Errorf("OUT_FORMAT: %w", err)
 		}
-		if _, ok := seen[agent]; ok {
-			continue
+		if !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+			buf.WriteByte('\n')
+		}
+		if _, err := w.Write(buf.Bytes()); err != nil {
+			return err
 		}
-		seen[agent] = struct{}{}
-		agents = append(agents, agent)
 	}
-	sort.Strings(agents)
-	return agents
-}
-
-func joinSorted(items []string) string {
-	return joinSortedWith(items, ", ")
+	return nil
 }
 
-func summarizeTop(items []string, limit int) string {
-	if len(items) == 0 {
-		return "none"
+// projectFields reduces payload to the given dotted field paths, keyed by
+// path. Array payloads are projected element by element, and numeric path
+// segments index into arrays.
+func projectFields(payload any, fields []string) (any, error) {
+	blob, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
 	}
-	if limit <= 0 {
-		limit = 1
+	var doc any
+	if err := json.Unmarshal(blob, &doc); err != nil {
+		return nil, err
 	}
-	sorted := append([]string(nil), items...)
-	sort.Strings(sorted)
-	if len(sorted) <= limit {
-		return strings.Join(sorted, ", ")
+	if items, ok := doc.([]any); ok {
+		out := make([]map[string]any, len(items))
+		for i, item := range items {
+			projected, err := projectObject(item, fields)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = projected
+		}
+		return out, nil
 	}
-	return fmt.Sprintf("%s ... (+%d more)", strings.Join(sorted[:limit], ", "), len(sorted)-limit)
+	return projectObject(doc, fields)
 }
 
-func joinSortedWith(items []string, sep string) string {
-	copied := append([]string(nil), items...)
-	sort.Strings(copied)
-	return strings.Join(copied, sep)
+func projectObject(doc any, fields []string) (map[string]any, error) {
+	out := make(map[string]any, len(fields))
+	for _, field := range fields {
+		value, ok := lookupField(doc, field)
+		if !ok {
+			return nil, fmt.Errorf("OUT_FIELD_UNKNOWN: field %q not found in output", field)
+		}
+		out[field] = value
+	}
+	return out, nil
 }
 
-func print(jsonOutput bool, payload any, message string) error {
-	if jsonOutput {
-		blob, err := json.MarshalIndent(payload, "", "  ")
-		if err != nil {
-			return err
+func lookupField(doc any, path string) (any, bool) {
+	cur := doc
+	for _, part := range strings.Split(path, ".") {
+		switch node := cur.(type) {
+		case map[string]any:
+			next, ok := node[part]
+			if !ok {
+				return nil, false
+			}
+			cur = next
+		case []any:
+			idx, err := strconv.Atoi(part)
+			if err != nil || idx < 0 || idx >= len(node) {
+				return nil, false
+			}
+			cur = node[idx]
+		default:
+			return nil, false
 		}
-		fmt.Println(string(blob))
-		return nil
-	}
-	if message != "" {
-		fmt.Println(message)
 	}
-	return nil
+	return cur, true
 }
 
 func newInitCmd(newSvc func() (*app.Service, error), jsonOutput *bool) *cobra.Command {
-	return &cobra.Command{
+	var withSources bool
+	cmd := &cobra.Command{
 		Use:   "init",
 		Short: "Initialize a project for skillpm",
-		Long:  "Creates a .skillpm/skills.toml project manifest in the current directory.",
-		Args:  cobra.NoArgs,
+		Long: `Creates a .skillpm/skills.toml project manifest in the current directory.
+
+With --with-sources, init also adds the recommended sources and every
+detected agent (enabled at project scope) to the manifest. It can be re-run
+on an initialized project and only adds what is missing.`,
+		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cwd, err := os.Getwd()
 			if err != nil {
 				return err
 			}
+			gitignore := ".skillpm/installed/\n.skillpm/state.toml\n.skillpm/staging/\n.skillpm/snapshots/"
+			if withSources {
+				svc, err := newSvc()
+				if err != nil {
+					return err
+				}
+				setup, err := svc.SetupProject(cwd)
+				if err != nil {
+					return err
+				}
+				if structuredOutput(cmd, *jsonOutput) {
+					return print(cmd, true, struct {
+						app.ProjectSetup
+						GitignoreSuggestion string `json:"gitignore_suggestion"`
+					}{setup, gitignore}, "")
+				}
+				if setup.Created {
+					fmt.Printf("initialized project at %s\n", setup.Manifest)
+				} else {
+					fmt.Printf("project already initialized at %s\n", setup.Manifest)
+				}
+				for _, name := range setup.Sources {
+					fmt.Printf("  added source %s\n", name)
+				}
+				for _, name := range setup.Adapters {
+					fmt.Printf("  enabled agent %s\n", name)
+				}
+				if len(setup.Sources) == 0 && len(setup.Adapters) == 0 {
+					fmt.Println("  sources and detected agents already configured")
+				}
+				if !setup.Created || isQuiet(cmd) {
+					return nil
+				}
+				printGitignoreSuggestion()
+				return nil
+			}
 			path, err := config.InitProject(cwd)
 			if err != nil {
 				return err
 			}
-			if *jsonOutput {
-				return print(true, map[string]string{
+			if structuredOutput(cmd, *jsonOutput) {
+				return print(cmd, true, map[string]string{
 					"manifest":             path,
-					"gitignore_suggestion": ".skillpm/installed/\n.skillpm/state.toml\n.skillpm/staging/\n.skillpm/snapshots/",
+					"gitignore_suggestion": gitignore,
 				}, "")
 			}
 			fmt.Printf("initialized project at %s\n", path)
-			fmt.Println("\nadd to .gitignore:")
-			fmt.Println("  .skillpm/installed/")
-			fmt.Println("  .skillpm/state.toml")
-			fmt.Println("  .skillpm/staging/")
-			fmt.Println("  .skillpm/snapshots/")
+			if isQuiet(cmd) {
+				return nil
+			}
+			printGitignoreSuggestion()
 			return nil
 		},
 	}
+	cmd.Flags().BoolVar(&withSources, "with-sources", false, "also add recommended sources and detected agents to the manifest")
+	return cmd
+}
+
+func printGitignoreSuggestion() {
+	fmt.Println("\nadd to .gitignore:")
+	fmt.Println("  .skillpm/installed/")
+	fmt.Println("  .skillpm/state.toml")
+	fmt.Println("  .skillpm/staging/")
+	fmt.Println("  .skillpm/snapshots/")
 }
 
 func newListCmd(newSvc func() (*app.Service, error), jsonOutput *bool) *cobra.Command {
-	return &cobra.Command{
+	var age string
+	var dedupe bool
+	var sourceName string
+	var showSourceRef bool
+	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List installed skills",
-		Args:  cobra.NoArgs,
+		Example: `  skillpm list
+  skillpm list --age 90d
+  skillpm list --source my-repo --show-source-ref`,
+		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			var minAge time.Duration
+			if age != "" {
+				d, err := parseDuration(age)
+				if err != nil || d <= 0 {
+					return fmt.Errorf("LIST_AGE: invalid --age %q (use e.g. 90d or 720h)", age)
+				}
+				minAge = d
+			}
 			svc, err := newSvc()
 			if err != nil {
 				return err
@@ -1416,27 +2749,56 @@ func newListCmd(newSvc func() (*app.Service, error), jsonOutput *bool) *cobra.Co
 			if err != nil {
 				return err
 			}
-			if *jsonOutput {
+			if minAge > 0 {
+				installed = installedBefore(installed, time.Now().Add(-minAge))
+			}
+			if sourceName != "" {
+				installed = installedFromSource(installed, sourceName)
+			}
+			var also map[string][]string
+			if dedupe {
+				installed, also = svc.DedupeInstalled(installed)
+			}
+			if structuredOutput(cmd, *jsonOutput) {
 				type listEntry struct {
-					SkillRef string `json:"skillRef"`
-					Version  string `json:"version"`
-					Scope    string `json:"scope"`
+					SkillRef       string     `json:"skillRef"`
+					Version        string     `json:"version"`
+					Scope          string     `json:"scope"`
+					Source         string     `json:"source,omitempty"`
+					SourceRef      string     `json:"sourceRef,omitempty"`
+					ResolvedCommit string     `json:"resolvedCommit,omitempty"`
+					InstalledAt    *time.Time `json:"installedAt,omitempty"`
+					InstalledBy    string     `json:"installedBy,omitempty"`
+					AlsoAs         []string   `json:"alsoAs,omitempty"`
 				}
 				entries := make([]listEntry, len(installed))
 				for i, item := range installed {
 					entries[i] = listEntry{
-						SkillRef: item.SkillRef,
-						Version:  item.ResolvedVersion,
-						Scope:    string(svc.Scope),
+						SkillRef:       item.SkillRef,
+						Version:        item.ResolvedVersion,
+						Scope:          string(svc.Scope),
+						Source:         item.Source,
+						SourceRef:      item.SourceRef,
+						ResolvedCommit: item.ResolvedCommit,
+						InstalledBy:    item.InstalledBy,
+						AlsoAs:         also[item.SkillRef],
+					}
+					if !item.InstalledAt.IsZero() {
+						installedAt := item.InstalledAt
+						entries[i].InstalledAt = &installedAt
 					}
 				}
-				return print(true, entries, "")
+				return print(cmd, true, entries, "")
 			}
 			if len(installed) == 0 {
 				scope := string(svc.Scope)
 				if scope == "" {
 					scope = "global"
 				}
+				if sourceName != "" {
+					fmt.Printf("no installed skills from source %s (%s)\n", sourceName, scope)
+					return nil
+				}
 				fmt.Printf("no installed skills (%s)\n", scope)
 				return nil
 			}
@@ -1451,11 +2813,148 @@ func newListCmd(newSvc func() (*app.Service, error), jsonOutput *bool) *cobra.Co
 			fmt.Printf("%s:\n", header)
 			fmt.Printf("  state: %s\n", svc.StateRoot)
 			for _, item := range installed {
-				fmt.Printf("  %s@%s\n", item.SkillRef, item.ResolvedVersion)
+				line := fmt.Sprintf("  %s@%s", item.SkillRef, item.ResolvedVersion)
+				if minAge > 0 {
+					line += fmt.Sprintf(" (installed %s)", item.InstalledAt.Format("2006-01-02"))
+				}
+				if showSourceRef {
+					line += " " + sourceRefNote(item.SourceRef)
+				}
+				fmt.Println(line + alsoNote(also[item.SkillRef]))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&age, "age", "", "only list skills installed longer ago than this (e.g. 90d)")
+	cmd.Flags().BoolVar(&dedupe, "dedupe", false, "show identical skills installed from several sources once")
+	cmd.Flags().StringVar(&sourceName, "source", "", "only list skills installed from this source")
+	cmd.Flags().BoolVar(&showSourceRef, "show-source-ref", false, "show the source ref each skill was installed from")
+	return cmd
+}
+
+// installedFromSource keeps skills whose recorded source is name.
+func installedFromSource(installed []store.InstalledSkill, name string) []store.InstalledSkill {
+	out := make([]store.InstalledSkill, 0, len(installed))
+	for _, item := range installed {
+		if item.Source == name {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// sourceRefNote renders a source ref for list output; records written by
+// older versions have none.
+func sourceRefNote(ref string) string {
+	if ref == "" {
+		return "[source ref unknown]"
+	}
+	return "[" + ref + "]"
+}
+
+// installedBefore keeps skills installed before cutoff. Records without an
+// install time are dropped since their age is unknown.
+func installedBefore(installed []store.InstalledSkill, cutoff time.Time) []store.InstalledSkill {
+	out := make([]store.InstalledSkill, 0, len(installed))
+	for _, item := range installed {
+		if !item.InstalledAt.IsZero() && item.InstalledAt.Before(cutoff) {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+func newProvenanceCmd(newSvc func() (*app.Service, error), jsonOutput *bool) *cobra.Command {
+	return &cobra.Command{
+		Use:   "provenance <source/skill>",
+		Short: "Show where, when and how an installed skill was obtained",
+		Example: `  skillpm provenance my-repo/code-review
+  skillpm provenance my-repo/code-review --json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			svc, err := newSvc()
+			if err != nil {
+				return err
+			}
+			p, err := svc.Provenance(args[0])
+			if err != nil {
+				return err
+			}
+			if structuredOutput(cmd, *jsonOutput) {
+				return print(cmd, true, p, "")
+			}
+			installedAt := "unknown"
+			if !p.InstalledAt.IsZero() {
+				installedAt = p.InstalledAt.Format(time.RFC3339)
+			}
+			fmt.Printf("skill:      %s@%s\n", p.SkillRef, p.ResolvedVersion)
+			fmt.Printf("source:     %s (%s)\n", p.Source, valueOrUnknown(p.SourceURL))
+			fmt.Printf("source ref: %s\n", valueOrUnknown(p.SourceRef))
+			fmt.Printf("commit:     %s\n", valueOrUnknown(p.ResolvedCommit))
+			fmt.Printf("checksum:   %s\n", valueOrUnknown(p.Checksum))
+			fmt.Printf("trust tier: %s\n", valueOrUnknown(p.TrustTier))
+			fmt.Printf("installed:  %s by skillpm %s\n", installedAt, valueOrUnknown(p.InstalledBy))
+			return nil
+		},
+	}
+}
+
+func newSkillCmd(newSvc func() (*app.Service, error), jsonOutput *bool) *cobra.Command {
+	skillCmd := &cobra.Command{Use: "skill", Short: "Inspect installed skills"}
+	skillCmd.AddCommand(&cobra.Command{
+		Use:   "show <source/skill>",
+		Short: "Show everything recorded about an installed skill",
+		Example: `  skillpm skill show my-repo/code-review
+  skillpm skill show my-repo/code-review --json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			svc, err := newSvc()
+			if err != nil {
+				return err
+			}
+			d, err := svc.SkillShow(args[0])
+			if err != nil {
+				return err
+			}
+			if structuredOutput(cmd, *jsonOutput) {
+				return print(cmd, true, d, "")
+			}
+			fmt.Printf("skill:      %s@%s%s\n", d.SkillRef, d.ResolvedVersion, deprecationNote(d.Deprecated, d.SupersededBy))
+			fmt.Printf("source:     %s (%s)\n", d.Source, valueOrUnknown(d.SourceURL))
+			fmt.Printf("checksum:   %s\n", valueOrUnknown(d.Checksum))
+			fmt.Printf("trust tier: %s\n", valueOrUnknown(d.TrustTier))
+			fmt.Printf("dir:        %s\n", d.Dir)
+			if d.Platform != "" {
+				fmt.Printf("platform:   %s\n", d.Platform)
+			}
+			if len(d.Deps) > 0 {
+				fmt.Printf("deps:       %s\n", strings.Join(d.Deps, ", "))
+			}
+			injected := "none"
+			if len(d.InjectedInto) > 0 {
+				injected = strings.Join(d.InjectedInto, ", ")
+			}
+			fmt.Printf("injected:   %s\n", injected)
+			fmt.Printf("files:      %d\n", len(d.Files))
+			for _, f := range d.Files {
+				fmt.Printf("  %s\n", f)
+			}
+			fmt.Printf("\n%s", d.Content)
+			if !strings.HasSuffix(d.Content, "\n") {
+				fmt.Println()
 			}
 			return nil
 		},
+	})
+	return skillCmd
+}
+
+// valueOrUnknown returns v, or "unknown" for records that predate the field.
+func valueOrUnknown(v string) string {
+	if v == "" {
+		return "unknown"
 	}
+	return v
 }
 
 func newStatusCmd(newSvc func() (*app.Service, error), jsonOutput *bool) *cobra.Command {
@@ -1497,6 +2996,7 @@ Examples:
 				InstalledCount  int      `json:"installedCount"`
 				SourceCount     int      `json:"sourceCount"`
 				EnabledAdapters []string `json:"enabledAdapters"`
+				ReviewsDue      []string `json:"reviewsDue,omitempty"`
 			}
 
 			result := statusResult{
@@ -1506,10 +3006,11 @@ Examples:
 				InstalledCount:  len(installed),
 				SourceCount:     len(sources),
 				EnabledAdapters: enabledAdapters,
+				ReviewsDue:      svc.SourcesDueForReview(),
 			}
 
-			if *jsonOutput {
-				return print(true, result, "")
+			if structuredOutput(cmd, *jsonOutput) {
+				return print(cmd, true, result, "")
 			}
 
 			fmt.Printf("skillpm %s (%s scope)\n", result.Version, result.Scope)
@@ -1525,6 +3026,9 @@ Examples:
 			} else {
 				fmt.Printf("  adapters:  none\n")
 			}
+			if len(result.ReviewsDue) > 0 {
+				fmt.Printf("  reviews:   due for %s (run 'skillpm source review <name>' after re-auditing)\n", strings.Join(result.ReviewsDue, ", "))
+			}
 			return nil
 		},
 	}
@@ -1551,12 +3055,11 @@ func newCreateCmd(newSvc func() (*app.Service, error), jsonOutput *bool) *cobra.
 			if err != nil {
 				return err
 			}
-			if *jsonOutput {
-				blob, _ := json.Marshal(map[string]string{
+			if structuredOutput(cmd, *jsonOutput) {
+				return print(cmd, true, map[string]string{
 					"name": args[0],
 					"path": skillDir,
-				})
-				fmt.Println(string(blob))
+				}, "")
 			} else {
 				fmt.Printf("Created skill %q at %s\n", args[0], skillDir)
 				fmt.Println("  Edit SKILL.md to add your skill instructions.")
@@ -1609,9 +3112,8 @@ func newPublishCmd(newSvc func() (*app.Service, error), jsonOutput *bool) *cobra
 			if err != nil {
 				return err
 			}
-			if *jsonOutput {
-				blob, _ := json.Marshal(result)
-				fmt.Println(string(blob))
+			if structuredOutput(cmd, *jsonOutput) {
+				return print(cmd, true, result, "")
 			} else {
 				fmt.Printf("Published %s@%s\n", result.Slug, result.Version)
 				fmt.Printf("   URL: %s\n", result.URL)
@@ -1645,12 +3147,11 @@ func newBundleCmd(newSvc func() (*app.Service, error), jsonOutput *bool) *cobra.
 			if err := svc.BundleCreate(args[0], args[1:]); err != nil {
 				return err
 			}
-			if *jsonOutput {
-				blob, _ := json.Marshal(map[string]interface{}{
+			if structuredOutput(cmd, *jsonOutput) {
+				return print(cmd, true, map[string]interface{}{
 					"name":   args[0],
 					"skills": args[1:],
-				})
-				fmt.Println(string(blob))
+				}, "")
 			} else {
 				fmt.Printf("Created bundle %q with %d skills\n", args[0], len(args)-1)
 			}
@@ -1669,9 +3170,8 @@ func newBundleCmd(newSvc func() (*app.Service, error), jsonOutput *bool) *cobra.
 				return err
 			}
 			bundles := svc.BundleList()
-			if *jsonOutput {
-				blob, _ := json.Marshal(bundles)
-				fmt.Println(string(blob))
+			if structuredOutput(cmd, *jsonOutput) {
+				return print(cmd, true, bundles, "")
 			} else {
 				if len(bundles) == 0 {
 					fmt.Println("No bundles defined.")
@@ -1705,9 +3205,8 @@ func newBundleCmd(newSvc func() (*app.Service, error), jsonOutput *bool) *cobra.
 			if err != nil {
 				return err
 			}
-			if *jsonOutput {
-				blob, _ := json.Marshal(installed)
-				fmt.Println(string(blob))
+			if structuredOutput(cmd, *jsonOutput) {
+				return print(cmd, true, installed, "")
 			} else {
 				fmt.Printf("Installed %d skills from bundle %q\n", len(installed), args[0])
 				for _, s := range installed {
@@ -18,8 +18,8 @@ func newVersionCmd(jsonOutput *bool) *cobra.Command {
 				"commit":  config.Commit,
 				"date":    config.Date,
 			}
-			if *jsonOutput {
-				return print(true, info, "")
+			if structuredOutput(cmd, *jsonOutput) {
+				return print(cmd, true, info, "")
 			}
 			fmt.Printf("skillpm %s\ncommit: %s\nbuilt at: %s\n", config.Version, config.Commit, config.Date)
 			return nil
@@ -2,10 +2,14 @@ package adapter
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"reflect"
 	"strings"
+	"syscall"
 	"testing"
 
 	"skillpm/internal/config"
@@ -625,6 +629,59 @@ func TestInjectRejectsMissingInstalledSkills(t *testing.T) {
 	}
 }
 
+func TestInjectReportsReadOnlySkillsDir(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("root ignores directory permissions")
+	}
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	stateRoot := filepath.Join(home, ".skillpm")
+	cfg := config.DefaultConfig()
+	cfg.Adapters = []config.AdapterConfig{{Name: "claude", Enabled: true, Scope: "global"}}
+	installedDir := filepath.Join(store.InstalledRoot(stateRoot), "test_code-review@1.0.0")
+	if err := os.MkdirAll(installedDir, 0o755); err != nil {
+		t.Fatalf("mkdir installed dir failed: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(installedDir, "SKILL.md"), []byte("---\nname: code-review\ndescription: Review\n---\n"), 0o644); err != nil {
+		t.Fatalf("write SKILL.md failed: %v", err)
+	}
+	skillsDir := filepath.Join(home, ".claude", "skills")
+	if err := os.MkdirAll(skillsDir, 0o755); err != nil {
+		t.Fatalf("mkdir skills dir failed: %v", err)
+	}
+	if err := os.Chmod(skillsDir, 0o555); err != nil {
+		t.Fatalf("chmod skills dir failed: %v", err)
+	}
+	t.Cleanup(func() { _ = os.Chmod(skillsDir, 0o755) })
+
+	runtime, err := NewRuntime(stateRoot, cfg, "")
+	if err != nil {
+		t.Fatalf("new runtime failed: %v", err)
+	}
+	adp, err := runtime.Get("claude")
+	if err != nil {
+		t.Fatalf("get adapter failed: %v", err)
+	}
+	_, err = adp.Inject(context.Background(), adapterapi.InjectRequest{SkillRefs: []string{"test/code-review"}})
+	var readOnly *ReadOnlyDirError
+	if !errors.As(err, &readOnly) || readOnly.Dir != skillsDir || !strings.HasPrefix(err.Error(), "ADP_DIR_READONLY:") {
+		t.Fatalf("expected ADP_DIR_READONLY naming %s, got %v", skillsDir, err)
+	}
+}
+
+func TestIsReadOnly(t *testing.T) {
+	for _, errno := range []syscall.Errno{syscall.EACCES, syscall.EPERM, syscall.EROFS} {
+		err := fmt.Errorf("copy: %w", &fs.PathError{Op: "open", Path: "/x", Err: errno})
+		if !isReadOnly(err) {
+			t.Fatalf("expected %v to be read-only", errno)
+		}
+	}
+	if isReadOnly(fmt.Errorf("copy: %w", fs.ErrNotExist)) {
+		t.Fatal("expected missing file not to be read-only")
+	}
+}
+
 func TestInjectRejectsKiroNameMismatch(t *testing.T) {
 	home := t.TempDir()
 	t.Setenv("HOME", home)
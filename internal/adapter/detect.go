@@ -1,15 +1,22 @@
 package adapter
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"sort"
+
+	"skillpm/internal/config"
 )
 
+// Detection is an agent found on this machine. Override and Note are set
+// when the adapter's config points its skills directory somewhere else.
 type Detection struct {
-	Name   string `json:"name"`
-	Path   string `json:"path"`
-	Reason string `json:"reason"`
+	Name     string `json:"name"`
+	Path     string `json:"path"`
+	Reason   string `json:"reason"`
+	Override string `json:"override,omitempty"`
+	Note     string `json:"note,omitempty"`
 }
 
 func DetectAvailable() []Detection {
@@ -57,3 +64,33 @@ func DetectAvailable() []Detection {
 	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
 	return out
 }
+
+// DetectWithOverrides is DetectAvailable with configured dir overrides
+// applied: an agent is also detected when its override directory exists,
+// and a detection notes when the override diverges from the default
+// skills directory.
+func DetectWithOverrides(adapters []config.AdapterConfig) []Detection {
+	dirs := dirOverrides(adapters)
+	out := DetectAvailable()
+	found := map[string]int{}
+	for i, d := range out {
+		found[d.Name] = i
+	}
+	home, _ := os.UserHomeDir()
+	for name, dir := range dirs {
+		def := agentSkillsDir(name, home)
+		note := ""
+		if filepath.Clean(def) != dir {
+			note = fmt.Sprintf("configured dir %s overrides default %s", dir, def)
+		}
+		if i, ok := found[name]; ok {
+			out[i].Override, out[i].Note = dir, note
+			continue
+		}
+		if stat, err := os.Stat(dir); err == nil && stat.IsDir() {
+			out = append(out, Detection{Name: name, Path: dir, Reason: "configured dir exists", Override: dir, Note: note})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
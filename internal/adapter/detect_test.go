@@ -4,6 +4,8 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"skillpm/internal/config"
 )
 
 func TestDetectAvailableFindsKnownRoots(t *testing.T) {
@@ -78,3 +80,42 @@ func TestDetectAvailablePartial(t *testing.T) {
 		}
 	}
 }
+
+func TestDetectWithOverridesNotesDivergence(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("OPENCLAW_STATE_DIR", filepath.Join(home, "missing-openclaw"))
+	custom := filepath.Join(home, "elsewhere", "cursor-skills")
+	for _, dir := range []string{filepath.Join(home, ".claude"), custom} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("mkdir failed: %v", err)
+		}
+	}
+	found := DetectWithOverrides([]config.AdapterConfig{
+		{Name: "claude", Dir: "~/alt/claude-skills"},
+		{Name: "cursor", Dir: custom},
+	})
+	got := map[string]Detection{}
+	for _, d := range found {
+		got[d.Name] = d
+	}
+	claude, ok := got["claude"]
+	if !ok || claude.Override != filepath.Join(home, "alt", "claude-skills") || claude.Note == "" {
+		t.Fatalf("expected claude detection to note the override, got %+v", claude)
+	}
+	cursor, ok := got["cursor"]
+	if !ok || cursor.Path != custom || cursor.Reason != "configured dir exists" {
+		t.Fatalf("expected cursor detected through its override dir, got %+v", found)
+	}
+
+	rt, err := NewRuntime(filepath.Join(home, ".skillpm"), config.Config{Adapters: []config.AdapterConfig{{Name: "cursor", Enabled: true, Dir: custom}}}, "")
+	if err != nil {
+		t.Fatalf("new runtime failed: %v", err)
+	}
+	if dir := rt.AgentSkillsDir("cursor"); dir != custom {
+		t.Fatalf("expected override skills dir, got %s", dir)
+	}
+	if dir := rt.SkillsDirForScope("cursor", filepath.Join(home, "proj")); dir != filepath.Join(home, "proj", ".cursor", "skills") {
+		t.Fatalf("expected override to leave project scope alone, got %s", dir)
+	}
+}
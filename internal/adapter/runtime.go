@@ -2,11 +2,14 @@ package adapter
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/pelletier/go-toml/v2"
@@ -18,20 +21,23 @@ import (
 )
 
 type Runtime struct {
-	adapters map[string]adapterapi.Adapter
+	adapters    map[string]adapterapi.Adapter
+	stateRoot   string
+	projectRoot string
+	dirs        map[string]string // configured skills dir overrides by agent
 }
 
 func NewRuntime(stateRoot string, cfg config.Config, projectRoot string) (*Runtime, error) {
 	if err := store.EnsureLayout(stateRoot); err != nil {
 		return nil, err
 	}
-	r := &Runtime{adapters: map[string]adapterapi.Adapter{}}
+	r := &Runtime{adapters: map[string]adapterapi.Adapter{}, stateRoot: stateRoot, projectRoot: projectRoot, dirs: dirOverrides(cfg.Adapters)}
 	for _, a := range cfg.Adapters {
 		if !a.Enabled {
 			continue
 		}
 		name := strings.ToLower(a.Name)
-		adapter, err := buildAdapter(name, stateRoot, projectRoot)
+		adapter, err := buildAdapter(name, stateRoot, projectRoot, r.dirs[name])
 		if err != nil {
 			return nil, err
 		}
@@ -48,6 +54,21 @@ func (r *Runtime) Get(name string) (adapterapi.Adapter, error) {
 	return a, nil
 }
 
+// Enable registers the named adapter for the lifetime of the runtime,
+// without changing the config it was built from.
+func (r *Runtime) Enable(name string) error {
+	name = strings.ToLower(name)
+	if _, ok := r.adapters[name]; ok {
+		return nil
+	}
+	a, err := buildAdapter(name, r.stateRoot, r.projectRoot, r.dirs[name])
+	if err != nil {
+		return err
+	}
+	r.adapters[name] = a
+	return nil
+}
+
 // AgentNames returns all registered adapter names.
 func (r *Runtime) AgentNames() []string {
 	if r == nil {
@@ -60,10 +81,38 @@ func (r *Runtime) AgentNames() []string {
 	return names
 }
 
-// AgentSkillsDir returns the skills directory path for the given agent.
+// AgentSkillsDir returns the global skills directory path for the given
+// agent, honoring a configured dir override.
 func (r *Runtime) AgentSkillsDir(name string) string {
-	home, _ := os.UserHomeDir()
-	return agentSkillsDir(name, home)
+	return r.SkillsDirForScope(name, "")
+}
+
+// SkillsDirForScope is AgentSkillsDirForScope with the runtime's configured
+// dir overrides applied. Overrides only affect the global directory.
+func (r *Runtime) SkillsDirForScope(name, projectRoot string) string {
+	if r != nil && projectRoot == "" {
+		if dir := r.dirs[strings.ToLower(name)]; dir != "" {
+			return dir
+		}
+	}
+	return AgentSkillsDirForScope(name, projectRoot)
+}
+
+// dirOverrides collects the expanded dir override of every adapter that
+// sets one, keyed by lower-case adapter name.
+func dirOverrides(adapters []config.AdapterConfig) map[string]string {
+	dirs := map[string]string{}
+	for _, a := range adapters {
+		if a.Dir == "" {
+			continue
+		}
+		dir, err := config.ExpandPath(a.Dir)
+		if err != nil {
+			continue
+		}
+		dirs[strings.ToLower(a.Name)] = filepath.Clean(dir)
+	}
+	return dirs
 }
 
 func (r *Runtime) ProbeAll(ctx context.Context) ([]adapterapi.ProbeResult, error) {
@@ -94,7 +143,7 @@ func AgentSkillsDirForScope(name, projectRoot string) string {
 	return agentSkillsDir(name, home)
 }
 
-func buildAdapter(name, stateRoot, projectRoot string) (adapterapi.Adapter, error) {
+func buildAdapter(name, stateRoot, projectRoot, dir string) (adapterapi.Adapter, error) {
 	home, _ := os.UserHomeDir()
 	snapshotRoot := filepath.Join(store.SnapshotRoot(stateRoot), "adapters")
 	if err := os.MkdirAll(snapshotRoot, 0o755); err != nil {
@@ -102,6 +151,9 @@ func buildAdapter(name, stateRoot, projectRoot string) (adapterapi.Adapter, erro
 	}
 
 	layout := resolveAgentLayout(name, home, projectRoot)
+	if projectRoot == "" && dir != "" {
+		layout = layout.withSkillsDir(dir)
+	}
 
 	return &fileAdapter{
 		name:         name,
@@ -191,6 +243,9 @@ func (f *fileAdapter) Inject(_ context.Context, req adapterapi.InjectRequest) (a
 	// Copy skill folders to agent's skills directory
 	if err := f.copySkillsToAgent(plans); err != nil {
 		_ = f.writeState(prev)
+		if isReadOnly(err) {
+			return adapterapi.InjectResult{}, &ReadOnlyDirError{Agent: f.name, Dir: f.skillsDir, Err: err}
+		}
 		return adapterapi.InjectResult{}, fmt.Errorf("ADP_INJECT_COPY: %w", err)
 	}
 	if err := f.verifyCopiedSkills(plans); err != nil {
@@ -216,6 +271,26 @@ func (f *fileAdapter) Inject(_ context.Context, req adapterapi.InjectRequest) (a
 	}, nil
 }
 
+// ReadOnlyDirError is returned by Inject when the agent's skills directory
+// cannot be written to.
+type ReadOnlyDirError struct {
+	Agent string
+	Dir   string
+	Err   error
+}
+
+func (e *ReadOnlyDirError) Error() string {
+	return fmt.Sprintf("ADP_DIR_READONLY: %s skills directory %s is not writable (%v); make it writable or disable the %s adapter", e.Agent, e.Dir, e.Err, e.Agent)
+}
+
+func (e *ReadOnlyDirError) Unwrap() error { return e.Err }
+
+// isReadOnly reports whether err came from writing to a directory the
+// process may not modify, either by permissions or a read-only mount.
+func isReadOnly(err error) bool {
+	return errors.Is(err, fs.ErrPermission) || errors.Is(err, syscall.EROFS)
+}
+
 // copySkillsToAgent copies each skill's installed content into the agent's skills dir.
 func (f *fileAdapter) copySkillsToAgent(plans []skillCopyPlan) error {
 	if err := os.MkdirAll(f.skillsDir, 0o755); err != nil {
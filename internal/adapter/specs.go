@@ -27,6 +27,20 @@ type agentLayout struct {
 	contract  skillContract
 }
 
+// withSkillsDir points the layout at an overridden skills directory, which
+// replaces the default one among the roots harvest and validate scan.
+func (l agentLayout) withSkillsDir(dir string) agentLayout {
+	roots := []string{dir}
+	for _, p := range l.rootPaths {
+		if p != l.skillsDir {
+			roots = append(roots, p)
+		}
+	}
+	l.skillsDir = dir
+	l.rootPaths = roots
+	return l
+}
+
 func resolveAgentLayout(name, home, projectRoot string) agentLayout {
 	layout := agentLayout{
 		targetDir: agentTargetDir(name, home, projectRoot),
@@ -0,0 +1,472 @@
+package app
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"skillpm/internal/config"
+	"skillpm/internal/fsutil"
+	"skillpm/internal/security"
+	"skillpm/internal/source"
+	storepkg "skillpm/internal/store"
+)
+
+const (
+	exportManifestName = "manifest.json"
+	exportLockName     = "skills.lock"
+	exportSkillsDir    = "skills"
+	exportVersion      = 1
+	maxImportSize      = 200 << 20 // 200MB archive
+	maxImportEntry     = 10 << 20  // 10MB per unpacked file
+	maxImportExpanded  = 500 << 20 // 500MB unpacked in total
+)
+
+// ExportManifest is the manifest.json of an export archive. It records
+// enough of each installed skill to rebuild its state record on import.
+type ExportManifest struct {
+	Version    int             `json:"version"`
+	ExportedAt time.Time       `json:"exportedAt"`
+	ExportedBy string          `json:"exportedBy,omitempty"`
+	Skills     []ExportedSkill `json:"skills"`
+}
+
+// ExportedSkill is one installed skill in an export archive; its files are
+// under skills/<Dir>/. InstalledChecksum hashes those files; it differs
+// from Checksum when platform filtering left some of the source's files
+// out.
+type ExportedSkill struct {
+	SkillRef          string   `json:"skillRef"`
+	Source            string   `json:"source"`
+	Skill             string   `json:"skill"`
+	ResolvedVersion   string   `json:"resolvedVersion"`
+	Checksum          string   `json:"checksum"`
+	InstalledChecksum string   `json:"installedChecksum,omitempty"`
+	SourceRef         string   `json:"sourceRef,omitempty"`
+	TrustTier         string   `json:"trustTier,omitempty"`
+	Platform          string   `json:"platform,omitempty"`
+	Deps              []string `json:"deps,omitempty"`
+	Dir               string   `json:"dir"`
+}
+
+// ExportResult describes a written export archive.
+type ExportResult struct {
+	Path   string   `json:"path"`
+	Skills []string `json:"skills"`
+}
+
+// ImportResult describes an imported archive. Mismatched lists skills whose
+// content did not match the lockfile checksum and were imported anyway
+// because of force.
+type ImportResult struct {
+	Imported   []string `json:"imported"`
+	Mismatched []string `json:"mismatched,omitempty"`
+	LockPath   string   `json:"lockPath"`
+}
+
+// Export writes every installed skill, the current lockfile and a manifest
+// of versions and checksums into a single .tar.gz at outPath.
+func (s *Service) Export(outPath, lockPath string) (ExportResult, error) {
+	st, err := storepkg.LoadState(s.StateRoot)
+	if err != nil {
+		return ExportResult{}, fmt.Errorf("EXP_EXPORT: %w", err)
+	}
+	if len(st.Installed) == 0 {
+		return ExportResult{}, fmt.Errorf("EXP_EXPORT: no installed skills to export")
+	}
+	lock, err := storepkg.LoadLockfile(s.resolveLockPath(lockPath))
+	if err != nil {
+		return ExportResult{}, fmt.Errorf("EXP_EXPORT: %w", err)
+	}
+	lockBlob, err := storepkg.EncodeLockfile(lock)
+	if err != nil {
+		return ExportResult{}, fmt.Errorf("EXP_EXPORT: %w", err)
+	}
+
+	manifest := ExportManifest{Version: exportVersion, ExportedAt: time.Now().UTC(), ExportedBy: config.Version}
+	entries := map[string][]byte{exportLockName: lockBlob}
+	res := ExportResult{Path: outPath}
+	for _, rec := range st.Installed {
+		dir := storepkg.FindInstalledDir(s.StateRoot, rec.SkillRef)
+		if dir == "" {
+			return ExportResult{}, fmt.Errorf("EXP_EXPORT: %s is recorded in state but has no installed directory", rec.SkillRef)
+		}
+		name := filepath.Base(dir)
+		err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, walkErr error) error {
+			if walkErr != nil {
+				return walkErr
+			}
+			if !d.Type().IsRegular() {
+				return nil
+			}
+			rel, err := filepath.Rel(dir, p)
+			if err != nil {
+				return err
+			}
+			data, err := os.ReadFile(p)
+			if err != nil {
+				return err
+			}
+			entries[path.Join(exportSkillsDir, name, filepath.ToSlash(rel))] = data
+			return nil
+		})
+		if err != nil {
+			return ExportResult{}, fmt.Errorf("EXP_EXPORT: reading %s: %w", rec.SkillRef, err)
+		}
+		sum, err := installedChecksum(dir)
+		if err != nil {
+			return ExportResult{}, fmt.Errorf("EXP_EXPORT: reading %s: %w", rec.SkillRef, err)
+		}
+		manifest.Skills = append(manifest.Skills, ExportedSkill{
+			SkillRef:          rec.SkillRef,
+			Source:            rec.Source,
+			Skill:             rec.Skill,
+			ResolvedVersion:   rec.ResolvedVersion,
+			Checksum:          rec.Checksum,
+			InstalledChecksum: sum,
+			SourceRef:         rec.SourceRef,
+			TrustTier:         rec.TrustTier,
+			Platform:          rec.Platform,
+			Deps:              rec.Deps,
+			Dir:               name,
+		})
+		res.Skills = append(res.Skills, rec.SkillRef)
+	}
+	manifestBlob, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return ExportResult{}, fmt.Errorf("EXP_EXPORT: %w", err)
+	}
+	entries[exportManifestName] = manifestBlob
+
+	blob, err := writeTarGz(entries)
+	if err != nil {
+		return ExportResult{}, fmt.Errorf("EXP_EXPORT: %w", err)
+	}
+	if err := fsutil.AtomicWrite(outPath, blob, 0o644); err != nil {
+		return ExportResult{}, fmt.Errorf("EXP_EXPORT: %w", err)
+	}
+	return res, nil
+}
+
+// Import installs the skills in an archive written by Export. Each skill's
+// content must hash to the checksum the archive's lockfile claims (or its
+// manifest, for skills not in the lockfile, and the manifest's installed
+// checksum for platform-filtered skills); a mismatch or a missing checksum
+// fails the import with IMP_CHECKSUM unless force is set. The content then
+// passes the same security scan gate as install, with force applied as
+// there. Imported skills replace installed versions of the same refs all at
+// once, and their state and lockfile entries are rebuilt; if any step
+// fails, the replaced versions are put back.
+func (s *Service) Import(archivePath, lockPath string, force bool) (ImportResult, error) {
+	res, err := s.importArchive(archivePath, lockPath, force)
+	s.auditMutation("import", "", []string{archivePath}, res.Imported, err)
+	return res, err
+}
+
+func (s *Service) importArchive(archivePath, lockPath string, force bool) (ImportResult, error) {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return ImportResult{}, fmt.Errorf("IMP_ARCHIVE: %w", err)
+	}
+	defer f.Close()
+	entries, err := readTarGz(f)
+	if err != nil {
+		return ImportResult{}, err
+	}
+	manifestBlob, ok := entries[exportManifestName]
+	if !ok {
+		return ImportResult{}, fmt.Errorf("IMP_ARCHIVE: %s is not a skillpm export (no %s)", archivePath, exportManifestName)
+	}
+	var manifest ExportManifest
+	if err := json.Unmarshal(manifestBlob, &manifest); err != nil {
+		return ImportResult{}, fmt.Errorf("IMP_ARCHIVE: invalid %s: %w", exportManifestName, err)
+	}
+	if manifest.Version != exportVersion {
+		return ImportResult{}, fmt.Errorf("IMP_ARCHIVE: unsupported export version %d", manifest.Version)
+	}
+	archiveLock := storepkg.Lockfile{Version: storepkg.LockVersion}
+	if blob, ok := entries[exportLockName]; ok {
+		if archiveLock, err = storepkg.DecodeLockfile(blob); err != nil {
+			return ImportResult{}, fmt.Errorf("IMP_ARCHIVE: invalid %s: %w", exportLockName, err)
+		}
+	}
+
+	res := ImportResult{LockPath: s.resolveLockPath(lockPath)}
+	contents := make([]map[string][]byte, len(manifest.Skills))
+	for i, sk := range manifest.Skills {
+		if sk.SkillRef == "" || sk.Dir == "" || sk.Dir != path.Base(sk.Dir) || sk.Dir == "." || sk.Dir == ".." {
+			return ImportResult{}, fmt.Errorf("IMP_ARCHIVE: invalid manifest entry for %q", sk.SkillRef)
+		}
+		prefix := path.Join(exportSkillsDir, sk.Dir) + "/"
+		files := map[string][]byte{}
+		for name, data := range entries {
+			if rel, ok := strings.CutPrefix(name, prefix); ok {
+				files[rel] = data
+			}
+		}
+		if _, ok := files["SKILL.md"]; !ok {
+			return ImportResult{}, fmt.Errorf("IMP_ARCHIVE: %s has no SKILL.md in the archive", sk.SkillRef)
+		}
+		contents[i] = files
+		want := sk.Checksum
+		if locked, ok := storepkg.FindLock(archiveLock, sk.SkillRef); ok {
+			want = locked.Checksum
+		}
+		if sk.Platform != "" {
+			// Platform filtering left files out, so only the installed
+			// tree's checksum can match.
+			want = sk.InstalledChecksum
+		}
+		if want == "" || exportedChecksum(files) != want {
+			res.Mismatched = append(res.Mismatched, sk.SkillRef)
+		}
+	}
+	if len(res.Mismatched) > 0 && !force {
+		return ImportResult{}, fmt.Errorf("IMP_CHECKSUM: content does not match the lockfile checksum for %s; use --force to import anyway", strings.Join(res.Mismatched, ", "))
+	}
+	if err := s.scanContents(context.Background(), s.importScanContents(manifest.Skills, contents), force); err != nil {
+		return ImportResult{}, err
+	}
+
+	st, err := storepkg.LoadState(s.StateRoot)
+	if err != nil {
+		return ImportResult{}, fmt.Errorf("IMP_ARCHIVE: %w", err)
+	}
+	prevState, err := storepkg.LoadState(s.StateRoot)
+	if err != nil {
+		return ImportResult{}, fmt.Errorf("IMP_ARCHIVE: %w", err)
+	}
+	localLock, err := storepkg.LoadLockfile(res.LockPath)
+	if err != nil {
+		return ImportResult{}, fmt.Errorf("IMP_ARCHIVE: %w", err)
+	}
+	if err := os.MkdirAll(storepkg.StagingRoot(s.StateRoot), 0o755); err != nil {
+		return ImportResult{}, fmt.Errorf("IMP_ARCHIVE: %w", err)
+	}
+	stage, err := os.MkdirTemp(storepkg.StagingRoot(s.StateRoot), "import-*")
+	if err != nil {
+		return ImportResult{}, fmt.Errorf("IMP_ARCHIVE: %w", err)
+	}
+	defer os.RemoveAll(stage)
+	rollback, err := s.placeImportedSkills(stage, manifest.Skills, contents)
+	if err != nil {
+		return ImportResult{}, err
+	}
+	now := time.Now().UTC()
+	for _, sk := range manifest.Skills {
+		storepkg.UpsertInstalled(&st, storepkg.InstalledSkill{
+			SkillRef:        sk.SkillRef,
+			Source:          sk.Source,
+			Skill:           sk.Skill,
+			ResolvedVersion: sk.ResolvedVersion,
+			Checksum:        sk.Checksum,
+			SourceRef:       sk.SourceRef,
+			InstalledAt:     now,
+			InstalledBy:     config.Version,
+			TrustTier:       sk.TrustTier,
+			Deps:            sk.Deps,
+			Platform:        sk.Platform,
+		})
+		locked, ok := storepkg.FindLock(archiveLock, sk.SkillRef)
+		if !ok {
+			locked = storepkg.LockSkill{SkillRef: sk.SkillRef, ResolvedVersion: sk.ResolvedVersion, Checksum: sk.Checksum, SourceRef: sk.SourceRef, Deps: sk.Deps}
+		}
+		storepkg.UpsertLock(&localLock, locked)
+		res.Imported = append(res.Imported, sk.SkillRef)
+	}
+	if os.Getenv("SKILLPM_TEST_FAIL_IMPORT_SAVE") == "1" {
+		rollback()
+		return ImportResult{}, fmt.Errorf("IMP_TEST_FAIL_SAVE: injected save failure")
+	}
+	if err := storepkg.SaveState(s.StateRoot, st); err != nil {
+		rollback()
+		return ImportResult{}, fmt.Errorf("IMP_ARCHIVE: %w", err)
+	}
+	if err := storepkg.SaveLockfile(res.LockPath, localLock); err != nil {
+		_ = storepkg.SaveState(s.StateRoot, prevState)
+		rollback()
+		return ImportResult{}, fmt.Errorf("IMP_ARCHIVE: %w", err)
+	}
+	return res, nil
+}
+
+// importScanContents prepares imported skills for the security scan. The
+// trust tier comes from the local config's source of the same name, not the
+// archive, so an export cannot vouch for itself; unknown sources get the
+// default review tier.
+func (s *Service) importScanContents(skills []ExportedSkill, contents []map[string][]byte) []security.SkillContent {
+	out := make([]security.SkillContent, len(skills))
+	for i, sk := range skills {
+		tier := "review"
+		if src, ok := config.FindSource(s.Config, sk.Source); ok && src.TrustTier != "" {
+			tier = src.TrustTier
+		}
+		files := map[string]string{}
+		for rel, data := range contents[i] {
+			if rel != "SKILL.md" && rel != "metadata.toml" {
+				files[rel] = string(data)
+			}
+		}
+		out[i] = security.SkillContent{
+			SkillRef:  sk.SkillRef,
+			Content:   string(contents[i]["SKILL.md"]),
+			Files:     files,
+			Source:    sk.Source,
+			TrustTier: tier,
+			Version:   sk.ResolvedVersion,
+		}
+	}
+	return out
+}
+
+// placeImportedSkills writes every skill into stage, then swaps them in
+// for the installed versions of the same refs, which are moved into stage
+// rather than deleted. If a swap fails the ones already made are undone;
+// otherwise the returned rollback undoes them all, and the replaced
+// versions go when stage is removed.
+func (s *Service) placeImportedSkills(stage string, skills []ExportedSkill, contents []map[string][]byte) (func(), error) {
+	staged := make([]string, len(skills))
+	for i := range skills {
+		dir := filepath.Join(stage, fmt.Sprintf("skill-%d", i))
+		for rel, data := range contents[i] {
+			target := filepath.Join(dir, filepath.FromSlash(rel))
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return nil, fmt.Errorf("IMP_ARCHIVE: %w", err)
+			}
+			if err := os.WriteFile(target, data, 0o644); err != nil {
+				return nil, fmt.Errorf("IMP_ARCHIVE: %w", err)
+			}
+		}
+		staged[i] = dir
+	}
+
+	var placed []string
+	backups := map[string]string{}
+	rollback := func() {
+		for _, dir := range placed {
+			_ = os.RemoveAll(dir)
+		}
+		for dir, backup := range backups {
+			_ = os.Rename(backup, dir)
+		}
+	}
+	for i, sk := range skills {
+		if old := storepkg.FindInstalledDir(s.StateRoot, sk.SkillRef); old != "" {
+			backup := filepath.Join(stage, fmt.Sprintf("replaced-%d", i))
+			if err := os.Rename(old, backup); err != nil {
+				rollback()
+				return nil, fmt.Errorf("IMP_ARCHIVE: %w", err)
+			}
+			backups[old] = backup
+		}
+		dest := storepkg.InstalledDirPath(s.StateRoot, sk.SkillRef, sk.ResolvedVersion, s.Installer.MaxDirNameLength)
+		if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+			rollback()
+			return nil, fmt.Errorf("IMP_ARCHIVE: %w", err)
+		}
+		if err := os.Rename(staged[i], dest); err != nil {
+			rollback()
+			return nil, fmt.Errorf("IMP_ARCHIVE: %w", err)
+		}
+		placed = append(placed, dest)
+	}
+	return rollback, nil
+}
+
+// exportedChecksum hashes a skill's archived files the way installedChecksum
+// hashes an installed directory.
+func exportedChecksum(files map[string][]byte) string {
+	ancillary := map[string]string{}
+	for rel, data := range files {
+		if rel == "SKILL.md" || rel == "metadata.toml" {
+			continue
+		}
+		ancillary[rel] = string(data)
+	}
+	return source.ComputeChecksum(files["SKILL.md"], ancillary)
+}
+
+// writeTarGz packs entries, keyed by slash-separated path, into a gzipped
+// tar with entries in sorted order.
+func writeTarGz(entries map[string][]byte) ([]byte, error) {
+	names := make([]string, 0, len(entries))
+	for name := range entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for _, name := range names {
+		data := entries[name]
+		hdr := &tar.Header{Name: name, Mode: 0o644, Size: int64(len(data)), Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return nil, err
+		}
+		if _, err := tw.Write(data); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// readTarGz reads the regular files of a gzipped tar into memory, keyed by
+// cleaned slash-separated path. Entries that would escape the archive root,
+// or unpack past maxImportEntry each or maxImportExpanded in total, fail
+// the read.
+func readTarGz(r io.Reader) (map[string][]byte, error) {
+	gz, err := gzip.NewReader(io.LimitReader(r, maxImportSize))
+	if err != nil {
+		return nil, fmt.Errorf("IMP_ARCHIVE: %w", err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+	entries := map[string][]byte{}
+	var expanded int64
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("IMP_ARCHIVE: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		name := path.Clean(hdr.Name)
+		if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return nil, fmt.Errorf("IMP_ARCHIVE: entry %q escapes the archive root", hdr.Name)
+		}
+		data, err := io.ReadAll(io.LimitReader(tr, maxImportEntry+1))
+		if err != nil {
+			return nil, fmt.Errorf("IMP_ARCHIVE: %w", err)
+		}
+		if len(data) > maxImportEntry {
+			return nil, fmt.Errorf("IMP_ARCHIVE: entry %q exceeds %d bytes", hdr.Name, maxImportEntry)
+		}
+		if expanded += int64(len(data)); expanded > maxImportExpanded {
+			return nil, fmt.Errorf("IMP_ARCHIVE: archive unpacks to more than %d bytes", maxImportExpanded)
+		}
+		entries[name] = data
+	}
+	return entries, nil
+}
@@ -0,0 +1,105 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"skillpm/internal/config"
+)
+
+func TestInjectAllTargetsSkipsDisabledAdapters(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	if err := os.MkdirAll(filepath.Join(home, ".cursor"), 0o755); err != nil {
+		t.Fatalf("mkdir failed: %v", err)
+	}
+	cfgPath := filepath.Join(home, ".skillpm", "config.toml")
+	cfg := config.DefaultConfig()
+	cfg.Adapters = []config.AdapterConfig{
+		{Name: "claude", Enabled: true, Scope: "global"},
+		{Name: "cursor", Enabled: false, Scope: "global"},
+		{Name: "codex", Enabled: false, Scope: "global"},
+	}
+	if err := config.Save(cfgPath, cfg); err != nil {
+		t.Fatalf("save config failed: %v", err)
+	}
+	svc, err := New(Options{ConfigPath: cfgPath})
+	if err != nil {
+		t.Fatalf("new service failed: %v", err)
+	}
+
+	targets, enabled, skipped, err := svc.InjectAllTargets(false)
+	if err != nil {
+		t.Fatalf("inject targets failed: %v", err)
+	}
+	if strings.Join(targets, ",") != "claude" || len(enabled) != 0 {
+		t.Fatalf("expected only claude, got targets=%v enabled=%v", targets, enabled)
+	}
+	if len(skipped) != 2 || !skipped[0].Detected || skipped[1].Detected {
+		t.Fatalf("unexpected skipped adapters: %+v", skipped)
+	}
+	if !strings.Contains(skipped[0].Reason, "--include-disabled") {
+		t.Fatalf("expected hint for detected adapter, got %q", skipped[0].Reason)
+	}
+	if _, err := svc.Runtime.Get("cursor"); err == nil {
+		t.Fatalf("expected cursor to stay unregistered")
+	}
+
+	targets, enabled, skipped, err = svc.InjectAllTargets(true)
+	if err != nil {
+		t.Fatalf("inject targets failed: %v", err)
+	}
+	if strings.Join(targets, ",") != "claude,cursor" || strings.Join(enabled, ",") != "cursor" {
+		t.Fatalf("expected cursor enabled for this run, got targets=%v enabled=%v", targets, enabled)
+	}
+	if len(skipped) != 1 || skipped[0].Name != "codex" {
+		t.Fatalf("expected undetected codex to stay skipped, got %+v", skipped)
+	}
+	if _, err := svc.Runtime.Get("cursor"); err != nil {
+		t.Fatalf("expected cursor registered for this run: %v", err)
+	}
+	reloaded, err := config.Load(cfgPath)
+	if err != nil {
+		t.Fatalf("reload config failed: %v", err)
+	}
+	if a, _ := config.FindAdapter(reloaded, "cursor"); a.Enabled {
+		t.Fatalf("expected config to keep cursor disabled")
+	}
+}
+
+func TestInjectTargetsValidatesAgentNames(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	cfgPath := filepath.Join(home, ".skillpm", "config.toml")
+	cfg := config.DefaultConfig()
+	cfg.Adapters = []config.AdapterConfig{
+		{Name: "claude", Enabled: true, Scope: "global"},
+		{Name: "cursor", Enabled: true, Scope: "global"},
+		{Name: "codex", Enabled: false, Scope: "global"},
+	}
+	if err := config.Save(cfgPath, cfg); err != nil {
+		t.Fatalf("save config failed: %v", err)
+	}
+	svc, err := New(Options{ConfigPath: cfgPath})
+	if err != nil {
+		t.Fatalf("new service failed: %v", err)
+	}
+
+	targets, err := svc.InjectTargets([]string{"cursor", "Claude", "cursor"})
+	if err != nil {
+		t.Fatalf("inject targets failed: %v", err)
+	}
+	if strings.Join(targets, ",") != "cursor,claude" {
+		t.Fatalf("expected deduplicated targets in order, got %v", targets)
+	}
+
+	_, err = svc.InjectTargets([]string{"claude", "cusor"})
+	if err == nil || !strings.HasPrefix(err.Error(), "ADP_NOT_SUPPORTED:") || !strings.Contains(err.Error(), `did you mean "cursor"?`) {
+		t.Fatalf("expected suggestion for misspelled adapter, got %v", err)
+	}
+	if _, err := svc.InjectTargets([]string{"codex"}); err == nil || strings.Contains(err.Error(), "did you mean") {
+		t.Fatalf("expected disabled codex to be rejected without a suggestion, got %v", err)
+	}
+}
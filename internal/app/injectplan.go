@@ -0,0 +1,302 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"skillpm/internal/adapter"
+	"skillpm/internal/config"
+	storepkg "skillpm/internal/store"
+	"skillpm/pkg/adapterapi"
+)
+
+// InjectPlan is the change an inject makes to one agent, relative to the
+// agent's recorded injection state. Dry runs and applied injects report the
+// same shape so a plan can be compared with the result that followed it.
+type InjectPlan struct {
+	Agent     string           `json:"agent"`
+	DryRun    bool             `json:"dryRun"`
+	Added     []string         `json:"added"`
+	Removed   []string         `json:"removed"`
+	Unchanged []string         `json:"unchanged"`
+	Conflicts []InjectConflict `json:"conflicts,omitempty"`
+}
+
+// InjectConflict is a requested skill that cannot be injected cleanly.
+type InjectConflict struct {
+	Ref     string `json:"ref"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// SkippedAdapter is a configured adapter that `inject --all` left out.
+type SkippedAdapter struct {
+	Name     string `json:"name"`
+	Detected bool   `json:"detected"`
+	Reason   string `json:"reason"`
+}
+
+// InjectAllTargets returns the adapters `inject --all` injects into and the
+// disabled ones it skips. With includeDisabled, disabled adapters whose
+// agent is detected on this machine are enabled for this service only and
+// returned in enabled instead of skipped.
+func (s *Service) InjectAllTargets(includeDisabled bool) (targets, enabled []string, skipped []SkippedAdapter, err error) {
+	detected := map[string]struct{}{}
+	for _, d := range adapter.DetectAvailable() {
+		detected[strings.ToLower(d.Name)] = struct{}{}
+	}
+	for _, a := range s.Config.Adapters {
+		if a.Enabled {
+			targets = append(targets, a.Name)
+			continue
+		}
+		_, isDetected := detected[strings.ToLower(a.Name)]
+		if includeDisabled && isDetected {
+			if err := s.Runtime.Enable(a.Name); err != nil {
+				return nil, nil, nil, err
+			}
+			targets = append(targets, a.Name)
+			enabled = append(enabled, a.Name)
+			continue
+		}
+		reason := "disabled in config"
+		if isDetected {
+			reason = "disabled in config; agent detected (use --include-disabled)"
+		}
+		skipped = append(skipped, SkippedAdapter{Name: a.Name, Detected: isDetected, Reason: reason})
+	}
+	return targets, enabled, skipped, nil
+}
+
+// InjectTargets validates agent names given to `inject --agent` against the
+// enabled adapters, dropping duplicates while keeping their order. Unknown
+// names fail with ADP_NOT_SUPPORTED and the closest enabled name, if any.
+func (s *Service) InjectTargets(names []string) ([]string, error) {
+	enabled := s.Runtime.AgentNames()
+	sort.Strings(enabled)
+	var out []string
+	seen := map[string]struct{}{}
+	for _, raw := range names {
+		name := strings.ToLower(strings.TrimSpace(raw))
+		if name == "" {
+			continue
+		}
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		if _, err := s.Runtime.Get(name); err != nil {
+			msg := fmt.Sprintf("ADP_NOT_SUPPORTED: adapter %q is not enabled", raw)
+			if guess := closestName(name, enabled); guess != "" {
+				msg += fmt.Sprintf("; did you mean %q?", guess)
+			}
+			return nil, fmt.Errorf("%s (enabled: %s)", msg, strings.Join(enabled, ", "))
+		}
+		out = append(out, name)
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("ADP_INJECT: --agent needs at least one agent name")
+	}
+	return out, nil
+}
+
+// closestName returns the candidate within two edits of name, if any.
+func closestName(name string, candidates []string) string {
+	best, bestDist := "", 3
+	for _, c := range candidates {
+		if d := editDistance(name, c); d < bestDist {
+			best, bestDist = c, d
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+// PlanInject reports what Inject would change for agentName without
+// touching the agent or the state file.
+func (s *Service) PlanInject(ctx context.Context, agentName string, refs []string) (InjectPlan, error) {
+	refs, err := s.injectRefs(refs)
+	if err != nil {
+		return InjectPlan{}, err
+	}
+	if _, err := s.Runtime.Get(agentName); err != nil {
+		return InjectPlan{}, err
+	}
+	st, err := storepkg.LoadState(s.StateRoot)
+	if err != nil {
+		return InjectPlan{}, err
+	}
+	prev := s.injectedSkills(st, agentName)
+	after := append(append([]string(nil), prev...), refs...)
+	plan := diffInjection(agentName, prev, after)
+	plan.DryRun = true
+	plan.Conflicts = s.injectConflicts(st, agentName, prev, refs)
+	return plan, nil
+}
+
+// InjectWithPlan injects like Inject and also reports the applied change in
+// the same form PlanInject uses.
+func (s *Service) InjectWithPlan(ctx context.Context, agentName string, refs []string) (adapterapi.InjectResult, InjectPlan, error) {
+	plan, err := s.PlanInject(ctx, agentName, refs)
+	if err != nil {
+		s.auditMutation("inject", agentName, refs, nil, err)
+		return adapterapi.InjectResult{}, InjectPlan{}, err
+	}
+	res, err := s.Inject(ctx, agentName, refs)
+	if err != nil {
+		return res, InjectPlan{}, err
+	}
+	prev := append(append([]string(nil), plan.Unchanged...), plan.Removed...)
+	applied := diffInjection(agentName, prev, res.Injected)
+	applied.Conflicts = plan.Conflicts
+	return res, applied, nil
+}
+
+// RemoveWithPlan removes injected skills like RemoveInjected and reports
+// the change in the same form InjectWithPlan uses: Removed lists what left
+// the agent and Unchanged the skills still injected afterwards.
+func (s *Service) RemoveWithPlan(ctx context.Context, agentName string, refs []string) (adapterapi.RemoveResult, InjectPlan, error) {
+	st, err := storepkg.LoadState(s.StateRoot)
+	if err != nil {
+		return adapterapi.RemoveResult{}, InjectPlan{}, err
+	}
+	prev := append([]string(nil), s.injectedSkills(st, agentName)...)
+	res, err := s.RemoveInjected(ctx, agentName, refs)
+	if err != nil {
+		return res, InjectPlan{}, err
+	}
+	st, err = storepkg.LoadState(s.StateRoot)
+	if err != nil {
+		return res, InjectPlan{}, err
+	}
+	return res, diffInjection(agentName, prev, s.injectedSkills(st, agentName)), nil
+}
+
+// injectRefs defaults an empty ref list to every installed skill.
+func (s *Service) injectRefs(refs []string) ([]string, error) {
+	if len(refs) == 0 {
+		st, err := storepkg.LoadState(s.StateRoot)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range st.Installed {
+			refs = append(refs, item.SkillRef)
+		}
+	}
+	if len(refs) == 0 {
+		return nil, fmt.Errorf("ADP_INJECT: no installed skills to inject")
+	}
+	return refs, nil
+}
+
+// injectedSkills returns the skills recorded as injected into agentName for
+// the service's scope.
+func (s *Service) injectedSkills(st storepkg.State, agentName string) []string {
+	for _, inj := range st.Injections {
+		if inj.Agent == agentName && inj.EffectiveScope(string(s.Scope)) == string(s.Scope) {
+			return inj.Skills
+		}
+	}
+	return nil
+}
+
+// injectConflicts flags requested refs that are not installed, that share a
+// skills directory name with another injected skill, or whose directory is
+// already occupied by content skillpm does not manage.
+func (s *Service) injectConflicts(st storepkg.State, agentName string, prev, refs []string) []InjectConflict {
+	installed := map[string]struct{}{}
+	for _, rec := range st.Installed {
+		installed[rec.SkillRef] = struct{}{}
+	}
+	managed := map[string]struct{}{}
+	byName := map[string]string{}
+	for _, ref := range prev {
+		managed[ref] = struct{}{}
+		byName[adapter.ExtractSkillName(ref)] = ref
+	}
+	var projectRoot string
+	if s.Scope == config.ScopeProject {
+		projectRoot = s.ProjectRoot
+	}
+	skillsDir := s.Runtime.SkillsDirForScope(agentName, projectRoot)
+
+	var out []InjectConflict
+	seen := map[string]struct{}{}
+	for _, ref := range refs {
+		if _, ok := seen[ref]; ok {
+			continue
+		}
+		seen[ref] = struct{}{}
+		if _, ok := installed[ref]; !ok {
+			out = append(out, InjectConflict{Ref: ref, Code: "INS_NOT_INSTALLED", Message: "skill is not installed"})
+			continue
+		}
+		name := adapter.ExtractSkillName(ref)
+		if other, ok := byName[name]; ok && other != ref {
+			out = append(out, InjectConflict{Ref: ref, Code: "ADP_INJECT_CONFLICT", Message: fmt.Sprintf("directory %q is also used by %s", name, other)})
+			continue
+		}
+		byName[name] = ref
+		if _, ok := managed[ref]; ok {
+			continue
+		}
+		dest := filepath.Join(skillsDir, name)
+		if _, err := os.Stat(dest); err == nil {
+			out = append(out, InjectConflict{Ref: ref, Code: "ADP_INJECT_CONFLICT", Message: fmt.Sprintf("%s exists and is not managed by skillpm; it will be overwritten", dest)})
+		}
+	}
+	return out
+}
+
+// diffInjection compares injected skill sets before and after an inject.
+func diffInjection(agentName string, prev, after []string) InjectPlan {
+	before := map[string]struct{}{}
+	for _, ref := range prev {
+		before[ref] = struct{}{}
+	}
+	now := map[string]struct{}{}
+	for _, ref := range after {
+		now[ref] = struct{}{}
+	}
+	plan := InjectPlan{Agent: agentName, Added: []string{}, Removed: []string{}, Unchanged: []string{}}
+	for ref := range now {
+		if _, ok := before[ref]; ok {
+			plan.Unchanged = append(plan.Unchanged, ref)
+		} else {
+			plan.Added = append(plan.Added, ref)
+		}
+	}
+	for ref := range before {
+		if _, ok := now[ref]; !ok {
+			plan.Removed = append(plan.Removed, ref)
+		}
+	}
+	sort.Strings(plan.Added)
+	sort.Strings(plan.Removed)
+	sort.Strings(plan.Unchanged)
+	return plan
+}
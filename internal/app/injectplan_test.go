@@ -0,0 +1,97 @@
+package app
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestPlanInjectMatchesAppliedInject(t *testing.T) {
+	svc := newScanTestService(t, map[string]map[string]string{
+		"alpha": {"SKILL.md": "# Alpha\nFormats code.\n"},
+		"beta":  {"SKILL.md": "# Beta\nWrites docs.\n"},
+	})
+	ctx := context.Background()
+	lockPath := filepath.Join(t.TempDir(), "skills.lock")
+	if _, err := svc.Install(ctx, []string{"local/alpha", "local/beta"}, lockPath, false); err != nil {
+		t.Fatalf("install failed: %v", err)
+	}
+	if _, err := svc.Inject(ctx, "openclaw", []string{"local/alpha"}); err != nil {
+		t.Fatalf("inject failed: %v", err)
+	}
+
+	plan, err := svc.PlanInject(ctx, "openclaw", nil)
+	if err != nil {
+		t.Fatalf("plan failed: %v", err)
+	}
+	if !plan.DryRun || !reflect.DeepEqual(plan.Added, []string{"local/beta"}) || !reflect.DeepEqual(plan.Unchanged, []string{"local/alpha"}) || len(plan.Removed) != 0 {
+		t.Fatalf("unexpected plan: %+v", plan)
+	}
+	if len(plan.Conflicts) != 0 {
+		t.Fatalf("expected no conflicts, got %+v", plan.Conflicts)
+	}
+
+	_, applied, err := svc.InjectWithPlan(ctx, "openclaw", nil)
+	if err != nil {
+		t.Fatalf("inject failed: %v", err)
+	}
+	if applied.DryRun || !reflect.DeepEqual(applied.Added, plan.Added) || !reflect.DeepEqual(applied.Unchanged, plan.Unchanged) || !reflect.DeepEqual(applied.Removed, plan.Removed) {
+		t.Fatalf("applied result %+v does not match plan %+v", applied, plan)
+	}
+}
+
+func TestRemoveWithPlanReportsRemainingSkills(t *testing.T) {
+	svc := newScanTestService(t, map[string]map[string]string{
+		"alpha": {"SKILL.md": "# Alpha\nFormats code.\n"},
+		"beta":  {"SKILL.md": "# Beta\nWrites docs.\n"},
+	})
+	ctx := context.Background()
+	if _, err := svc.Install(ctx, []string{"local/alpha", "local/beta"}, filepath.Join(t.TempDir(), "skills.lock"), false); err != nil {
+		t.Fatalf("install failed: %v", err)
+	}
+	if _, err := svc.Inject(ctx, "openclaw", nil); err != nil {
+		t.Fatalf("inject failed: %v", err)
+	}
+
+	res, plan, err := svc.RemoveWithPlan(ctx, "openclaw", []string{"local/alpha"})
+	if err != nil {
+		t.Fatalf("remove failed: %v", err)
+	}
+	if !reflect.DeepEqual(res.Removed, []string{"local/alpha"}) {
+		t.Fatalf("unexpected removed refs: %+v", res.Removed)
+	}
+	if plan.Agent != "openclaw" || !reflect.DeepEqual(plan.Removed, []string{"local/alpha"}) || !reflect.DeepEqual(plan.Unchanged, []string{"local/beta"}) || len(plan.Added) != 0 {
+		t.Fatalf("unexpected remove plan: %+v", plan)
+	}
+}
+
+func TestPlanInjectReportsConflicts(t *testing.T) {
+	svc := newScanTestService(t, map[string]map[string]string{
+		"alpha": {"SKILL.md": "# Alpha\nFormats code.\n"},
+	})
+	ctx := context.Background()
+	if _, err := svc.Install(ctx, []string{"local/alpha"}, filepath.Join(t.TempDir(), "skills.lock"), false); err != nil {
+		t.Fatalf("install failed: %v", err)
+	}
+	unmanaged := filepath.Join(svc.Runtime.AgentSkillsDir("claude"), "alpha")
+	if err := os.MkdirAll(unmanaged, 0o755); err != nil {
+		t.Fatalf("mkdir failed: %v", err)
+	}
+
+	plan, err := svc.PlanInject(ctx, "claude", []string{"local/alpha", "local/missing"})
+	if err != nil {
+		t.Fatalf("plan failed: %v", err)
+	}
+	codes := map[string]string{}
+	for _, c := range plan.Conflicts {
+		codes[c.Ref] = c.Code
+	}
+	if codes["local/alpha"] != "ADP_INJECT_CONFLICT" || codes["local/missing"] != "INS_NOT_INSTALLED" {
+		t.Fatalf("unexpected conflicts: %+v", plan.Conflicts)
+	}
+	if _, err := os.Stat(filepath.Join(unmanaged, "SKILL.md")); !os.IsNotExist(err) {
+		t.Fatalf("dry run must not write to the agent, stat err=%v", err)
+	}
+}
@@ -0,0 +1,94 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"skillpm/internal/fsutil"
+	storepkg "skillpm/internal/store"
+)
+
+// Metrics are local counters of successful operations, kept in
+// metrics.json when metrics.enabled is set. Since is when counting started
+// or was last reset.
+type Metrics struct {
+	Installs  int       `json:"installs"`
+	Upgrades  int       `json:"upgrades"`
+	Syncs     int       `json:"syncs"`
+	Injects   int       `json:"injects"`
+	Since     time.Time `json:"since,omitempty"`
+	UpdatedAt time.Time `json:"updatedAt,omitempty"`
+}
+
+var metricsMu sync.Mutex
+
+// countMetric increments one counter when metrics are enabled. Failures
+// are ignored so counting never fails the operation being counted.
+func (s *Service) countMetric(bump func(*Metrics)) {
+	if !s.Config.Metrics.Enabled {
+		return
+	}
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+	m, err := loadMetrics(s.StateRoot)
+	if err != nil {
+		return
+	}
+	now := time.Now().UTC()
+	if m.Since.IsZero() {
+		m.Since = now
+	}
+	bump(&m)
+	m.UpdatedAt = now
+	_ = saveMetrics(s.StateRoot, m)
+}
+
+// Metrics returns the recorded counters; all zero when nothing has been
+// counted yet.
+func (s *Service) Metrics() (Metrics, error) {
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+	return loadMetrics(s.StateRoot)
+}
+
+// ResetMetrics zeroes every counter and restarts Since.
+func (s *Service) ResetMetrics() (Metrics, error) {
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+	now := time.Now().UTC()
+	m := Metrics{Since: now, UpdatedAt: now}
+	if err := saveMetrics(s.StateRoot, m); err != nil {
+		return Metrics{}, err
+	}
+	return m, nil
+}
+
+func loadMetrics(root string) (Metrics, error) {
+	blob, err := os.ReadFile(storepkg.MetricsPath(root))
+	if errors.Is(err, os.ErrNotExist) {
+		return Metrics{}, nil
+	}
+	if err != nil {
+		return Metrics{}, fmt.Errorf("METRICS_READ: %w", err)
+	}
+	var m Metrics
+	if err := json.Unmarshal(blob, &m); err != nil {
+		return Metrics{}, fmt.Errorf("METRICS_READ: %s: %w", storepkg.MetricsPath(root), err)
+	}
+	return m, nil
+}
+
+func saveMetrics(root string, m Metrics) error {
+	blob, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("METRICS_WRITE: %w", err)
+	}
+	if err := fsutil.AtomicWrite(storepkg.MetricsPath(root), append(blob, '\n'), 0o644); err != nil {
+		return fmt.Errorf("METRICS_WRITE: %w", err)
+	}
+	return nil
+}
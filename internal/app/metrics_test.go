@@ -0,0 +1,59 @@
+package app
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	storepkg "skillpm/internal/store"
+)
+
+func TestMetricsCountOnlyWhenEnabled(t *testing.T) {
+	svc, _ := newFlowTestService(t)
+	ctx := context.Background()
+	lockPath := filepath.Join(t.TempDir(), "skills.lock")
+
+	if _, err := svc.Install(ctx, []string{"local/forms"}, lockPath, false); err != nil {
+		t.Fatalf("install failed: %v", err)
+	}
+	if _, err := os.Stat(storepkg.MetricsPath(svc.StateRoot)); !os.IsNotExist(err) {
+		t.Fatalf("expected no metrics file while disabled, got %v", err)
+	}
+
+	svc.Config.Metrics.Enabled = true
+	if _, err := svc.Install(ctx, []string{"local/demo"}, lockPath, false); err != nil {
+		t.Fatalf("install failed: %v", err)
+	}
+	if _, err := svc.Install(ctx, []string{"local/missing"}, lockPath, false); err == nil {
+		t.Fatal("expected install of a missing skill to fail")
+	}
+	if _, err := svc.Inject(ctx, "openclaw", []string{"local/demo"}); err != nil {
+		t.Fatalf("inject failed: %v", err)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			svc.countMetric(func(m *Metrics) { m.Syncs++ })
+		}()
+	}
+	wg.Wait()
+
+	m, err := svc.Metrics()
+	if err != nil {
+		t.Fatalf("metrics failed: %v", err)
+	}
+	if m.Installs != 1 || m.Injects != 1 || m.Syncs != 8 || m.Upgrades != 0 || m.Since.IsZero() {
+		t.Fatalf("unexpected counters: %+v", m)
+	}
+
+	if _, err := svc.ResetMetrics(); err != nil {
+		t.Fatalf("reset failed: %v", err)
+	}
+	if m, _ := svc.Metrics(); m.Installs != 0 || m.Injects != 0 || m.Syncs != 0 {
+		t.Fatalf("expected zeroed counters after reset, got %+v", m)
+	}
+}
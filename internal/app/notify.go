@@ -0,0 +1,97 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"skillpm/internal/audit"
+	syncsvc "skillpm/internal/sync"
+)
+
+// SyncNotification is the JSON body posted to the notify webhook after a
+// sync: the summary sync --json prints, with Outcome set to failed or
+// refused (over --max-changes) and the error added when the sync returned
+// one. A refused run carries the plan; a failed one an empty summary.
+type SyncNotification struct {
+	syncsvc.Summary
+	Error     string `json:"error,omitempty"`
+	ErrorCode string `json:"errorCode,omitempty"`
+}
+
+// SyncNeedsNotify reports whether a sync with the given outcome should be
+// posted to the configured webhook.
+func (s *Service) SyncNeedsNotify(outcome string) bool {
+	if s.Config.Notify.WebhookURL == "" {
+		return false
+	}
+	if s.Config.Notify.On == "always" {
+		return true
+	}
+	switch outcome {
+	case "blocked", "changed-with-risk", "failed", "refused":
+		return true
+	}
+	return false
+}
+
+// notifySync posts the result of an applied or refused sync. Delivery is
+// best-effort: a failure is recorded in the audit log and never fails the
+// sync.
+func (s *Service) notifySync(ctx context.Context, report syncsvc.Report, strict bool, err error) {
+	n := SyncNotification{Summary: report.Summary(strict)}
+	if err != nil {
+		n.Outcome = "failed"
+		n.ErrorCode = audit.ErrorCode(err)
+		if n.ErrorCode == "SYNC_TOO_MANY_CHANGES" {
+			n.Outcome = "refused"
+		}
+		n.Error = err.Error()
+	}
+	nErr := s.NotifySync(ctx, n.Outcome, n)
+	if nErr == nil || s.Audit == nil {
+		return
+	}
+	_ = s.Audit.Log(audit.Event{
+		Operation: "sync_notify",
+		Phase:     "complete",
+		Status:    "error",
+		Code:      audit.ErrorCode(nErr),
+		Message:   nErr.Error(),
+		Fields:    map[string]string{"outcome": n.Outcome},
+	})
+}
+
+// NotifySync POSTs summary as JSON to the configured webhook when
+// SyncNeedsNotify(outcome) holds. Callers treat errors as warnings: a
+// failed notification never fails the sync itself.
+func (s *Service) NotifySync(ctx context.Context, outcome string, summary any) error {
+	if !s.SyncNeedsNotify(outcome) {
+		return nil
+	}
+	body, err := json.Marshal(summary)
+	if err != nil {
+		return fmt.Errorf("SYNC_NOTIFY: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.Config.Notify.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("SYNC_NOTIFY: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := s.httpClient
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("SYNC_NOTIFY: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("SYNC_NOTIFY: webhook returned %s", resp.Status)
+	}
+	return nil
+}
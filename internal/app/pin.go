@@ -0,0 +1,92 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	storepkg "skillpm/internal/store"
+)
+
+// SetPinned pins or unpins refs in the lockfile. Pinned skills are skipped
+// by upgrades that do not name them. A ref may carry "@version", which must
+// be the locked version. It returns the refs whose pin changed.
+func (s *Service) SetPinned(refs []string, lockPath string, pinned bool) ([]string, error) {
+	changed, err := s.setPinned(refs, lockPath, pinned)
+	operation := "pin"
+	if !pinned {
+		operation = "unpin"
+	}
+	s.auditMutation(operation, "", refs, changed, err)
+	return changed, err
+}
+
+func (s *Service) setPinned(refs []string, lockPath string, pinned bool) ([]string, error) {
+	lockPath = s.resolveLockPath(lockPath)
+	lock, err := storepkg.LoadLockfile(lockPath)
+	if err != nil {
+		return nil, err
+	}
+	var changed []string
+	for _, ref := range refs {
+		skillRef, version, _ := strings.Cut(ref, "@")
+		i := lockIndex(lock, skillRef)
+		if i < 0 {
+			return nil, fmt.Errorf("PIN_NOT_LOCKED: %s is not in %s", skillRef, lockPath)
+		}
+		if locked := lock.Skills[i].ResolvedVersion; version != "" && version != locked {
+			return nil, fmt.Errorf("PIN_VERSION: %s is locked at %s, not %s; install %s first", skillRef, locked, version, ref)
+		}
+		if lock.Skills[i].Pinned != pinned {
+			lock.Skills[i].Pinned = pinned
+			changed = append(changed, skillRef)
+		}
+	}
+	if len(changed) == 0 {
+		return nil, nil
+	}
+	if err := storepkg.SaveLockfile(lockPath, lock); err != nil {
+		return nil, err
+	}
+	return changed, nil
+}
+
+// PinnedSkills returns the pinned skills in the lockfile, sorted.
+func (s *Service) PinnedSkills(lockPath string) ([]string, error) {
+	lock, err := storepkg.LoadLockfile(s.resolveLockPath(lockPath))
+	if err != nil {
+		return nil, err
+	}
+	var out []string
+	for _, rec := range lock.Skills {
+		if rec.Pinned {
+			out = append(out, rec.SkillRef)
+		}
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+// splitPinned separates the refs pinned in lock from the rest. The pinned
+// refs are returned sorted.
+func splitPinned(refs []string, lock storepkg.Lockfile) (unpinned, pinned []string) {
+	unpinned = make([]string, 0, len(refs))
+	for _, ref := range refs {
+		if rec, ok := storepkg.FindLock(lock, ref); ok && rec.Pinned {
+			pinned = append(pinned, ref)
+			continue
+		}
+		unpinned = append(unpinned, ref)
+	}
+	sort.Strings(pinned)
+	return unpinned, pinned
+}
+
+func lockIndex(lock storepkg.Lockfile, skillRef string) int {
+	for i := range lock.Skills {
+		if lock.Skills[i].SkillRef == skillRef {
+			return i
+		}
+	}
+	return -1
+}
@@ -32,6 +32,7 @@ type Options struct {
 	Scope       config.Scope
 	ProjectRoot string
 	JSONMode    bool // suppress git progress and enable quiet mode
+	Quiet       bool // suppress git progress without switching to JSON
 }
 
 type Service struct {
@@ -55,9 +56,9 @@ type Service struct {
 }
 
 func New(opts Options) (*Service, error) {
-	configPath := opts.ConfigPath
-	if configPath == "" {
-		configPath = config.DefaultConfigPath()
+	configPath, err := config.ResolveConfigPath(opts.ConfigPath)
+	if err != nil {
+		return nil, err
 	}
 	cfg, err := config.Ensure(configPath)
 	if err != nil {
@@ -73,8 +74,6 @@ func New(opts Options) (*Service, error) {
 	}
 	if scope == config.ScopeProject && projectRoot != "" {
 		// Explicit scope + explicit root — skip auto-detection.
-	} else if scope == "" {
-		scope, projectRoot, _ = config.ResolveScope("", cwd)
 	} else {
 		scope, projectRoot, err = config.ResolveScope(string(scope), cwd)
 		if err != nil {
@@ -105,15 +104,16 @@ func New(opts Options) (*Service, error) {
 		return nil, err
 	}
 	logger := audit.New(storepkg.AuditPath(stateRoot))
-	sourceMgr := source.NewManager(opts.HTTPClient, stateRoot, opts.JSONMode)
+	sourceMgr := source.NewManager(opts.HTTPClient, stateRoot, opts.JSONMode || opts.Quiet)
+	sourceMgr.SearchCacheTTL = config.ResolveSearchCacheTTL(cfg)
 	resolverSvc := &resolver.Service{Sources: sourceMgr}
 	securityEngine := security.New(cfg.Security)
-	installerSvc := &installer.Service{Root: stateRoot, Security: securityEngine, Audit: logger}
+	installerSvc := &installer.Service{Root: stateRoot, Security: securityEngine, Audit: logger, MaxDirNameLength: config.ResolveMaxDirNameLength(cfg)}
 	runtimeSvc, err := adapter.NewRuntime(stateRoot, cfg, projectRoot)
 	if err != nil {
 		return nil, err
 	}
-	harvestSvc := &harvest.Service{Runtime: runtimeSvc, StateRoot: stateRoot}
+	harvestSvc := &harvest.Service{Runtime: runtimeSvc, StateRoot: stateRoot, Scanner: securityEngine.Scanner}
 	syncService := &syncsvc.Service{
 		Sources:     sourceMgr,
 		Resolver:    resolverSvc,
@@ -123,6 +123,7 @@ func New(opts Options) (*Service, error) {
 		Security:    securityEngine,
 		Manifest:    manifest,
 		ProjectRoot: projectRoot,
+		Scope:       scope,
 	}
 	lockPath := ""
 	if scope == config.ScopeProject && projectRoot != "" {
@@ -136,7 +137,7 @@ func New(opts Options) (*Service, error) {
 		Scope:       scope,
 		ProjectRoot: projectRoot,
 	}
-	return &Service{
+	svc := &Service{
 		ConfigPath:  configPath,
 		Config:      cfg,
 		StateRoot:   stateRoot,
@@ -152,7 +153,11 @@ func New(opts Options) (*Service, error) {
 		Doctor:      doctorSvc,
 		Audit:       logger,
 		httpClient:  opts.HTTPClient,
-	}, nil
+	}
+	doctorSvc.Reinstall = svc.reinstallRefs
+	doctorSvc.Uninstall = svc.uninstallRefs
+	doctorSvc.UpstreamSkill = svc.upstreamSkill
+	return svc, nil
 }
 
 func (s *Service) SaveConfig() error {
@@ -164,6 +169,65 @@ func (s *Service) InitProject(dir string) (string, error) {
 	return config.InitProject(dir)
 }
 
+// ProjectSetup reports what SetupProject configured.
+type ProjectSetup struct {
+	Manifest string   `json:"manifest"`
+	Created  bool     `json:"created"`
+	Sources  []string `json:"sources"`
+	Adapters []string `json:"adapters"`
+}
+
+// recommendedProjectSources are the default sources SetupProject seeds.
+var recommendedProjectSources = []string{"anthropic", "clawhub"}
+
+// SetupProject initializes the project at dir if needed, then adds the
+// recommended sources and every detected agent to its manifest. Entries the
+// manifest already has are left alone, so running it again changes nothing.
+func (s *Service) SetupProject(dir string) (ProjectSetup, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return ProjectSetup{}, fmt.Errorf("PRJ_INIT: %w", err)
+	}
+	setup := ProjectSetup{Manifest: config.ProjectManifestPath(abs), Sources: []string{}, Adapters: []string{}}
+	if _, statErr := os.Stat(setup.Manifest); statErr != nil {
+		if _, err := config.InitProject(abs); err != nil {
+			return ProjectSetup{}, err
+		}
+		setup.Created = true
+	}
+	m, err := config.LoadProjectManifest(abs)
+	if err != nil {
+		return ProjectSetup{}, err
+	}
+
+	defaults := config.DefaultConfig()
+	for _, name := range recommendedProjectSources {
+		src, ok := config.FindSource(defaults, name)
+		if !ok {
+			continue
+		}
+		if _, exists := config.FindSource(config.Config{Sources: m.Sources}, name); exists {
+			continue
+		}
+		m.Sources = append(m.Sources, src)
+		setup.Sources = append(setup.Sources, name)
+	}
+	for _, d := range s.DetectAdapters() {
+		if _, exists := config.FindAdapter(config.Config{Adapters: m.Adapters}, d.Name); exists {
+			continue
+		}
+		m.Adapters = append(m.Adapters, config.AdapterConfig{Name: d.Name, Enabled: true, Scope: string(config.ScopeProject)})
+		setup.Adapters = append(setup.Adapters, d.Name)
+	}
+	if len(setup.Sources) == 0 && len(setup.Adapters) == 0 {
+		return setup, nil
+	}
+	if err := config.SaveProjectManifest(abs, m); err != nil {
+		return ProjectSetup{}, err
+	}
+	return setup, nil
+}
+
 // ListInstalled returns installed skills for the current scope.
 func (s *Service) ListInstalled() ([]storepkg.InstalledSkill, error) {
 	st, err := storepkg.LoadState(s.StateRoot)
@@ -173,6 +237,55 @@ func (s *Service) ListInstalled() ([]storepkg.InstalledSkill, error) {
 	return st.Installed, nil
 }
 
+// Provenance describes where, when and how an installed skill was obtained.
+// Fields unknown for records written by older versions are left empty.
+type Provenance struct {
+	SkillRef        string    `json:"skillRef"`
+	Source          string    `json:"source"`
+	SourceURL       string    `json:"sourceUrl,omitempty"`
+	SourceRef       string    `json:"sourceRef,omitempty"`
+	ResolvedVersion string    `json:"resolvedVersion"`
+	ResolvedCommit  string    `json:"resolvedCommit,omitempty"`
+	Checksum        string    `json:"checksum"`
+	TrustTier       string    `json:"trustTier,omitempty"`
+	InstalledAt     time.Time `json:"installedAt"`
+	InstalledBy     string    `json:"installedBy,omitempty"`
+}
+
+// Provenance returns provenance metadata for an installed skill.
+func (s *Service) Provenance(ref string) (Provenance, error) {
+	parsed, err := resolver.ParseRef(ref)
+	if err != nil {
+		return Provenance{}, err
+	}
+	skillRef := parsed.Source + "/" + parsed.Skill
+	st, err := storepkg.LoadState(s.StateRoot)
+	if err != nil {
+		return Provenance{}, err
+	}
+	for _, rec := range st.Installed {
+		if rec.SkillRef != skillRef {
+			continue
+		}
+		p := Provenance{
+			SkillRef:        rec.SkillRef,
+			Source:          rec.Source,
+			SourceRef:       rec.SourceRef,
+			ResolvedVersion: rec.ResolvedVersion,
+			ResolvedCommit:  rec.ResolvedCommit,
+			Checksum:        rec.Checksum,
+			TrustTier:       rec.TrustTier,
+			InstalledAt:     rec.InstalledAt,
+			InstalledBy:     rec.InstalledBy,
+		}
+		if src, ok := config.FindSource(s.Config, rec.Source); ok {
+			p.SourceURL = src.URL
+		}
+		return p, nil
+	}
+	return Provenance{}, fmt.Errorf("INS_NOT_INSTALLED: %s is not installed", skillRef)
+}
+
 // SaveManifest persists the project manifest (only valid for project scope).
 func (s *Service) SaveManifest() error {
 	if s.Scope != config.ScopeProject || s.Manifest == nil || s.ProjectRoot == "" {
@@ -182,6 +295,30 @@ func (s *Service) SaveManifest() error {
 }
 
 func (s *Service) SourceAdd(name, target, kind, branch, trustTier string) (config.SourceConfig, error) {
+	src, err := newSourceConfig(name, target, kind, branch, trustTier)
+	if err != nil {
+		return config.SourceConfig{}, err
+	}
+	if err := config.AddSource(&s.Config, src); err != nil {
+		return config.SourceConfig{}, err
+	}
+	if err := s.SaveConfig(); err != nil {
+		return config.SourceConfig{}, err
+	}
+	return src, nil
+}
+
+// SourceDuplicate reports an existing source that already fetches from
+// target, so adding it again under another name would fetch it twice.
+func (s *Service) SourceDuplicate(name, target, kind string) (config.SourceConfig, bool) {
+	src, err := newSourceConfig(name, target, kind, "", "")
+	if err != nil {
+		return config.SourceConfig{}, false
+	}
+	return config.FindDuplicateSource(s.Config, src)
+}
+
+func newSourceConfig(name, target, kind, branch, trustTier string) (config.SourceConfig, error) {
 	if name == "" || target == "" {
 		return config.SourceConfig{}, fmt.Errorf("SRC_ADD: name and target are required")
 	}
@@ -197,12 +334,23 @@ func (s *Service) SourceAdd(name, target, kind, branch, trustTier string) (confi
 	}
 	src := config.SourceConfig{Name: name, Kind: kind, TrustTier: trustTier}
 	switch kind {
-	case "git", "dir":
+	case "git":
 		src.URL = target
 		src.Branch = branch
-		if kind == "git" {
-			src.ScanPaths = []string{"skills"}
+		src.ScanPaths = []string{"skills"}
+	case "dir":
+		// Dir sources are read in place, so a relative path must not
+		// depend on where later commands run.
+		src.URL = target
+		if !strings.HasPrefix(target, "~") {
+			abs, err := filepath.Abs(target)
+			if err != nil {
+				return config.SourceConfig{}, fmt.Errorf("SRC_ADD: %w", err)
+			}
+			src.URL = abs
 		}
+	case "archive":
+		src.URL = target
 	case "clawhub":
 		src.Site = target
 		src.Registry = target
@@ -211,12 +359,6 @@ func (s *Service) SourceAdd(name, target, kind, branch, trustTier string) (confi
 	default:
 		return config.SourceConfig{}, fmt.Errorf("SRC_ADD: unsupported source kind %q", kind)
 	}
-	if err := config.AddSource(&s.Config, src); err != nil {
-		return config.SourceConfig{}, err
-	}
-	if err := s.SaveConfig(); err != nil {
-		return config.SourceConfig{}, err
-	}
 	return src, nil
 }
 
@@ -227,12 +369,86 @@ func (s *Service) SourceRemove(name string) error {
 	return s.SaveConfig()
 }
 
+// SourceReview records that the named source has just been re-audited,
+// restarting its review interval.
+func (s *Service) SourceReview(name string) (config.SourceConfig, error) {
+	src, err := config.MarkSourceReviewed(&s.Config, name, time.Now())
+	if err != nil {
+		return config.SourceConfig{}, err
+	}
+	if err := s.SaveConfig(); err != nil {
+		return config.SourceConfig{}, err
+	}
+	return src, nil
+}
+
+// SourcesDueForReview lists sources whose review interval has lapsed.
+func (s *Service) SourcesDueForReview() []string {
+	return config.SourcesDueForReview(s.Config, time.Now())
+}
+
 func (s *Service) SourceList() []config.SourceConfig {
 	out := append([]config.SourceConfig{}, s.Config.Sources...)
 	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
 	return out
 }
 
+// VersionPreview lists a skill's published versions, newest first, and the
+// one a constraint would resolve to. It is computed without fetching skill
+// content and changes nothing.
+type VersionPreview struct {
+	Ref        string         `json:"ref"`
+	Constraint string         `json:"constraint,omitempty"`
+	Selected   string         `json:"selected,omitempty"`
+	Reason     string         `json:"reason,omitempty"`
+	Versions   []VersionEntry `json:"versions"`
+}
+
+// VersionEntry is one listed version.
+type VersionEntry struct {
+	Version  string `json:"version"`
+	Selected bool   `json:"selected,omitempty"`
+}
+
+// SourceVersions lists the versions of the <source>/<skill> ref and previews
+// which one constraint selects. A constraint given in the ref itself is used
+// when constraint is empty.
+func (s *Service) SourceVersions(ctx context.Context, ref, constraint string) (VersionPreview, error) {
+	pr, err := resolver.ParseRef(ref)
+	if err != nil {
+		return VersionPreview{}, err
+	}
+	if constraint == "" {
+		constraint = pr.Constraint
+	}
+	src, ok := config.FindSource(s.Config, pr.Source)
+	if !ok {
+		return VersionPreview{}, fmt.Errorf("SRC_VERSIONS: source %q not found", pr.Source)
+	}
+	versions, err := s.SourceMgr.Versions(ctx, src, pr.Skill)
+	if err != nil {
+		return VersionPreview{}, err
+	}
+	preview := VersionPreview{Ref: pr.Source + "/" + pr.Skill, Constraint: constraint, Versions: make([]VersionEntry, len(versions))}
+	selected, reason, err := s.SourceMgr.SelectVersion(ctx, src, pr.Skill, versions, constraint)
+	if err != nil && audit.ErrorCode(err) != "SRC_VERSION_NONE" {
+		return VersionPreview{}, err
+	}
+	preview.Selected, preview.Reason = selected, reason
+	if err != nil {
+		preview.Reason = strings.TrimPrefix(err.Error(), "SRC_VERSION_NONE: ")
+	}
+	for i, v := range versions {
+		preview.Versions[i] = VersionEntry{Version: v, Selected: v == preview.Selected}
+	}
+	return preview, nil
+}
+
+// SourceStatus reports cache details for all configured sources.
+func (s *Service) SourceStatus(ctx context.Context) ([]source.SourceStatus, error) {
+	return s.SourceMgr.Status(ctx, s.Config)
+}
+
 func (s *Service) SourceUpdate(ctx context.Context, name string) ([]source.UpdateResult, error) {
 	updated, err := s.SourceMgr.Update(ctx, &s.Config, name)
 	if err != nil {
@@ -244,11 +460,154 @@ func (s *Service) SourceUpdate(ctx context.Context, name string) ([]source.Updat
 	return updated, nil
 }
 
-func (s *Service) Search(ctx context.Context, sourceName, query string) ([]source.SearchResult, error) {
-	return s.SourceMgr.Search(ctx, s.Config, sourceName, query)
+// Search searches the named sources, or every source when sourceNames is
+// empty.
+func (s *Service) Search(ctx context.Context, sourceNames []string, query string) ([]source.SearchResult, error) {
+	return s.SourceMgr.Search(ctx, s.Config, sourceNames, query)
+}
+
+// SearchRefresh searches without reusing cached results.
+func (s *Service) SearchRefresh(ctx context.Context, sourceNames []string, query string) ([]source.SearchResult, error) {
+	return s.SourceMgr.SearchRefresh(ctx, s.Config, sourceNames, query)
+}
+
+// SearchPattern searches sources with query matched as a regex or glob
+// according to mode, bypassing cached results when refresh is set.
+func (s *Service) SearchPattern(ctx context.Context, sourceNames []string, query string, mode source.SearchMode, refresh bool) ([]source.SearchResult, error) {
+	return s.SourceMgr.SearchPattern(ctx, s.Config, sourceNames, query, mode, refresh)
+}
+
+// DedupeSearch collapses search results that several sources provide,
+// keeping the one from the most preferred trust tier.
+func (s *Service) DedupeSearch(results []source.SearchResult) []source.SearchResult {
+	return source.DedupeResults(results, s.sourceRank)
+}
+
+// FilterSearchByTier keeps search results whose source is at least as
+// trusted as tier, where trusted > review > untrusted. Results from sources
+// missing from the config are dropped.
+func (s *Service) FilterSearchByTier(results []source.SearchResult, tier string) ([]source.SearchResult, error) {
+	limit := resolver.TrustTierRank(nil, tier)
+	if limit == resolver.TrustTierRank(nil, "") {
+		return nil, fmt.Errorf("SRC_TRUST_TIER: invalid trust tier %q (want trusted, review or untrusted)", tier)
+	}
+	out := make([]source.SearchResult, 0, len(results))
+	for _, r := range results {
+		src, ok := config.FindSource(s.Config, r.Source)
+		if ok && resolver.TrustTierRank(nil, src.TrustTier) <= limit {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+// DedupeInstalled collapses installed skills that are the same content
+// (same skill name and checksum) installed from several sources. It
+// returns the records to show, each from the most preferred trust tier,
+// and for each of them the refs of the copies folded into it.
+func (s *Service) DedupeInstalled(installed []storepkg.InstalledSkill) ([]storepkg.InstalledSkill, map[string][]string) {
+	order := s.Config.Resolution.PreferTierOrder
+	var out []storepkg.InstalledSkill
+	also := map[string][]string{}
+	index := map[string]int{}
+	for _, rec := range installed {
+		key := adapter.ExtractSkillName(rec.SkillRef) + "\x00" + rec.Checksum
+		i, ok := index[key]
+		if !ok || rec.Checksum == "" {
+			index[key] = len(out)
+			out = append(out, rec)
+			continue
+		}
+		kept := out[i]
+		if resolver.TrustTierRank(order, rec.TrustTier) < resolver.TrustTierRank(order, kept.TrustTier) {
+			also[rec.SkillRef] = append(also[kept.SkillRef], kept.SkillRef)
+			delete(also, kept.SkillRef)
+			out[i] = rec
+			kept = rec
+		} else {
+			also[kept.SkillRef] = append(also[kept.SkillRef], rec.SkillRef)
+		}
+		sort.Strings(also[kept.SkillRef])
+	}
+	return out, also
+}
+
+// sourceRank orders sources by resolution.prefer_tier_order.
+func (s *Service) sourceRank(name string) int {
+	src, _ := config.FindSource(s.Config, name)
+	return resolver.TrustTierRank(s.Config.Resolution.PreferTierOrder, src.TrustTier)
+}
+
+func (s *Service) Install(ctx context.Context, refs []string, lockPath string, force bool) ([]InstallResult, error) {
+	return s.installAndAudit(ctx, refs, lockPath, force, true)
+}
+
+// InstallWithoutManifest installs like Install but never records the skills
+// in the project manifest, leaving them as scratch installs.
+func (s *Service) InstallWithoutManifest(ctx context.Context, refs []string, lockPath string, force bool) ([]InstallResult, error) {
+	return s.installAndAudit(ctx, refs, lockPath, force, false)
+}
+
+// InstallResult is one skill installed by Install: its persisted record
+// plus how many fetches resolving it took, which is reported but not kept.
+type InstallResult struct {
+	storepkg.InstalledSkill
+	Attempts int `json:"attempts,omitempty"`
 }
 
-func (s *Service) Install(ctx context.Context, refs []string, lockPath string, force bool) ([]storepkg.InstalledSkill, error) {
+// InstallEvent is the outcome of one skill in a streamed install: the
+// installed skill, or the error that stopped the ref it was requested by.
+type InstallEvent struct {
+	Ref   string         `json:"ref"`
+	Skill *InstallResult `json:"skill,omitempty"`
+	Code  string         `json:"code,omitempty"`
+	Error string         `json:"error,omitempty"`
+}
+
+// InstallStream installs refs one at a time, each in its own transaction,
+// and calls emit for every skill installed (dependencies included) as soon
+// as its ref commits. A failing ref stops the run unless keepGoing is set,
+// in which case it is emitted as an error event and the run fails at the
+// end with the number of failed refs.
+func (s *Service) InstallStream(ctx context.Context, refs []string, lockPath string, force, recordManifest, keepGoing bool, emit func(InstallEvent)) error {
+	if len(refs) == 0 {
+		return fmt.Errorf("INS_INSTALL: at least one skill ref is required")
+	}
+	failed := 0
+	for _, ref := range refs {
+		installed, err := s.installAndAudit(ctx, []string{ref}, lockPath, force, recordManifest)
+		if err != nil {
+			if !keepGoing {
+				return err
+			}
+			failed++
+			emit(InstallEvent{Ref: ref, Code: audit.ErrorCode(err), Error: err.Error()})
+			continue
+		}
+		for i := range installed {
+			emit(InstallEvent{Ref: ref, Skill: &installed[i]})
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("INS_INSTALL: %d of %d refs failed to install", failed, len(refs))
+	}
+	return nil
+}
+
+func (s *Service) installAndAudit(ctx context.Context, refs []string, lockPath string, force, recordManifest bool) ([]InstallResult, error) {
+	installed, err := s.install(ctx, refs, lockPath, force, recordManifest)
+	changed := make([]string, 0, len(installed))
+	for _, rec := range installed {
+		changed = append(changed, rec.SkillRef+"@"+rec.ResolvedVersion)
+	}
+	s.auditMutation("install", "", refs, changed, err)
+	if err == nil {
+		s.countMetric(func(m *Metrics) { m.Installs++ })
+	}
+	return installed, err
+}
+
+func (s *Service) install(ctx context.Context, refs []string, lockPath string, force, recordManifest bool) ([]InstallResult, error) {
 	if len(refs) == 0 {
 		return nil, fmt.Errorf("INS_INSTALL: at least one skill ref is required")
 	}
@@ -295,18 +654,34 @@ func (s *Service) Install(ctx context.Context, refs []string, lockPath string, f
 	if err := s.scanResolved(ctx, resolved, force); err != nil {
 		return nil, err
 	}
-	installed, installErr := s.Installer.Install(ctx, resolved, lockPath, force)
+	records, installErr := s.Installer.Install(ctx, resolved, lockPath, force)
 	if installErr != nil {
 		return nil, installErr
 	}
+	attempts := make(map[string]int, len(resolved))
+	for _, r := range resolved {
+		attempts[r.SkillRef] = r.Attempts
+	}
+	installed := make([]InstallResult, len(records))
+	for i, rec := range records {
+		installed[i] = InstallResult{InstalledSkill: rec, Attempts: attempts[rec.SkillRef]}
+	}
 
 	// Update project manifest with installed skills.
 	// Use resolved skills (not original refs) so that expanded scan-path
 	// directories produce individual manifest entries.
-	if s.Scope == config.ScopeProject && s.Manifest != nil {
+	if recordManifest && s.Scope == config.ScopeProject && s.Manifest != nil {
 		// Build constraint map from original refs.
 		constraintMap := make(map[string]string)
+		bareConstraints := make(map[string]string)
 		for _, raw := range refs {
+			if name, c, ok := resolver.ParseBareRef(raw); ok {
+				if c == "" {
+					c = "latest"
+				}
+				bareConstraints[name] = c
+				continue
+			}
 			parsed, pErr := resolver.ParseRef(raw)
 			if pErr != nil {
 				continue
@@ -321,6 +696,8 @@ func (s *Service) Install(ctx context.Context, refs []string, lockPath string, f
 			constraint := "latest"
 			if c, ok := constraintMap[r.SkillRef]; ok {
 				constraint = c
+			} else if c, ok := bareConstraints[r.Skill]; ok {
+				constraint = c
 			} else {
 				// Inherit constraint from parent scan-path ref if expanded.
 				for origRef, c := range constraintMap {
@@ -342,35 +719,175 @@ func (s *Service) Install(ctx context.Context, refs []string, lockPath string, f
 	return installed, nil
 }
 
-func (s *Service) Uninstall(ctx context.Context, refs []string, lockPath string) ([]string, error) {
+// ResolvedEntry is one resolver result reported by ResolveOnly. Ref is the
+// ref as requested; a scan-path URL can expand into several entries.
+type ResolvedEntry struct {
+	Ref             string   `json:"ref"`
+	SkillRef        string   `json:"skillRef,omitempty"`
+	ResolvedVersion string   `json:"resolvedVersion,omitempty"`
+	Source          string   `json:"source,omitempty"`
+	SourceRef       string   `json:"sourceRef,omitempty"`
+	Checksum        string   `json:"checksum,omitempty"`
+	TrustTier       string   `json:"trustTier,omitempty"`
+	Files           []string `json:"files,omitempty"`
+	Deps            []string `json:"deps,omitempty"`
+	Error           string   `json:"error,omitempty"`
+}
+
+// ResolveOnly runs resolution for refs and reports the result without
+// scanning, installing or touching config. With keepGoing, resolution
+// errors are recorded per ref instead of aborting the run.
+func (s *Service) ResolveOnly(ctx context.Context, refs []string, lockPath string, keepGoing bool) ([]ResolvedEntry, error) {
 	if len(refs) == 0 {
-		return nil, fmt.Errorf("INS_UNINSTALL: at least one skill ref is required")
+		return nil, fmt.Errorf("INS_RESOLVE: at least one skill ref is required")
 	}
-	skillRefs := make([]string, 0, len(refs))
+	lock, err := storepkg.LoadLockfile(s.resolveLockPath(lockPath))
+	if err != nil {
+		return nil, err
+	}
+	out := make([]ResolvedEntry, 0, len(refs))
 	for _, raw := range refs {
-		parsed, err := resolver.ParseRef(raw)
+		resolved, err := s.Resolver.ResolveMany(ctx, s.Config, []string{raw}, lock)
 		if err != nil {
-			return nil, err
+			if !keepGoing {
+				return nil, err
+			}
+			out = append(out, ResolvedEntry{Ref: raw, Error: err.Error()})
+			continue
+		}
+		for _, r := range resolved {
+			files := make([]string, 0, len(r.Files)+1)
+			files = append(files, "SKILL.md")
+			for rel := range r.Files {
+				files = append(files, rel)
+			}
+			sort.Strings(files[1:])
+			out = append(out, ResolvedEntry{
+				Ref:             raw,
+				SkillRef:        r.SkillRef,
+				ResolvedVersion: r.ResolvedVersion,
+				Source:          r.Source,
+				SourceRef:       r.SourceRef,
+				Checksum:        r.Checksum,
+				TrustTier:       r.TrustTier,
+				Files:           files,
+				Deps:            r.Deps,
+			})
 		}
-		skillRefs = append(skillRefs, parsed.Source+"/"+parsed.Skill)
 	}
-	removed, err := s.Installer.Uninstall(ctx, skillRefs, s.resolveLockPath(lockPath))
+	return out, nil
+}
+
+// Uninstall removes refs from the state, lockfile, agents and manifest.
+// Unless force is set it refuses while other installed skills depend on
+// them; see Dependents.
+func (s *Service) Uninstall(ctx context.Context, refs []string, lockPath string, force bool) ([]string, error) {
+	removed, err := s.uninstall(ctx, refs, lockPath, force)
+	s.auditMutation("uninstall", "", refs, removed, err)
+	return removed, err
+}
+
+// upstreamSkill reads the cached SKILL.md of an installed skill from its
+// source, for doctor's deprecation check.
+func (s *Service) upstreamSkill(rec storepkg.InstalledSkill) (string, bool) {
+	src, ok := config.FindSource(s.Config, rec.Source)
+	if !ok {
+		return "", false
+	}
+	return s.SourceMgr.CachedSkill(src, rec.Skill)
+}
+
+// Dependents returns installed skills outside refs whose recorded deps name
+// one of refs, directly or through another dependent, mapped to the refs
+// they require. Uninstalling refs alone would leave these skills broken.
+func (s *Service) Dependents(refs []string) (map[string][]string, error) {
+	st, err := storepkg.LoadState(s.StateRoot)
 	if err != nil {
 		return nil, err
 	}
-	// Clean up: remove uninstalled skills from all agents that had them injected.
-	if len(removed) > 0 && s.Runtime != nil {
-		st, stErr := storepkg.LoadState(s.StateRoot)
-		if stErr == nil {
-			for _, inj := range st.Injections {
-				adp, adpErr := s.Runtime.Get(inj.Agent)
-				if adpErr != nil {
-					continue
+	removing := map[string]struct{}{}
+	for _, raw := range refs {
+		parsed, err := resolver.ParseRef(raw)
+		if err != nil {
+			return nil, err
+		}
+		removing[parsed.Source+"/"+parsed.Skill] = struct{}{}
+	}
+	deps := map[string][]string{}
+	for _, rec := range st.Installed {
+		deps[rec.SkillRef] = rec.Deps
+	}
+
+	out := map[string][]string{}
+	for changed := true; changed; {
+		changed = false
+		for ref, required := range deps {
+			if _, ok := removing[ref]; ok {
+				continue
+			}
+			for _, dep := range required {
+				if target, ok := matchDep(dep, removing); ok {
+					out[ref] = append(out[ref], target)
 				}
-				_, _ = adp.Remove(ctx, adapterapi.RemoveRequest{SkillRefs: removed, Scope: string(s.Scope)})
 			}
+			if len(out[ref]) > 0 {
+				removing[ref] = struct{}{}
+				changed = true
+			}
+		}
+	}
+	for ref := range out {
+		sort.Strings(out[ref])
+	}
+	return out, nil
+}
+
+// matchDep finds the skill in set that a deps entry names. Entries may be
+// full refs, optionally with a version, or bare skill names.
+func matchDep(dep string, set map[string]struct{}) (string, bool) {
+	if parsed, err := resolver.ParseRef(dep); err == nil {
+		ref := parsed.Source + "/" + parsed.Skill
+		_, ok := set[ref]
+		return ref, ok
+	}
+	name, _, ok := resolver.ParseBareRef(dep)
+	if !ok {
+		return "", false
+	}
+	for ref := range set {
+		if adapter.ExtractSkillName(ref) == name {
+			return ref, true
+		}
+	}
+	return "", false
+}
+
+func (s *Service) uninstall(ctx context.Context, refs []string, lockPath string, force bool) ([]string, error) {
+	if len(refs) == 0 {
+		return nil, fmt.Errorf("INS_UNINSTALL: at least one skill ref is required")
+	}
+	if !force {
+		dependents, err := s.Dependents(refs)
+		if err != nil {
+			return nil, err
+		}
+		if len(dependents) > 0 {
+			parts := make([]string, 0, len(dependents))
+			for ref, required := range dependents {
+				parts = append(parts, fmt.Sprintf("%s (requires %s)", ref, strings.Join(required, ", ")))
+			}
+			sort.Strings(parts)
+			return nil, fmt.Errorf("INS_HAS_DEPENDENTS: still required by %s; use --dependents to remove them too or --force", strings.Join(parts, "; "))
 		}
 	}
+	plan, err := s.PlanUninstall(refs)
+	if err != nil {
+		return nil, err
+	}
+	removed, err := s.uninstallInstalled(ctx, plan.Refs, s.resolveLockPath(lockPath))
+	if err != nil {
+		return nil, err
+	}
 
 	// Update project manifest
 	if s.Scope == config.ScopeProject && s.Manifest != nil && len(removed) > 0 {
@@ -384,34 +901,55 @@ func (s *Service) Uninstall(ctx context.Context, refs []string, lockPath string)
 	return removed, nil
 }
 
-func (s *Service) Upgrade(ctx context.Context, refs []string, lockPath string, force bool) ([]storepkg.InstalledSkill, error) {
-	state, err := storepkg.LoadState(s.StateRoot)
+// uninstallInstalled uninstalls refs and removes the ones it uninstalled
+// from every agent that had them injected.
+func (s *Service) uninstallInstalled(ctx context.Context, refs []string, lockPath string) ([]string, error) {
+	removed, err := s.Installer.Uninstall(ctx, refs, lockPath)
 	if err != nil {
 		return nil, err
 	}
-	if len(state.Installed) == 0 {
-		return nil, nil
-	}
-	if len(refs) == 0 {
-		for _, rec := range state.Installed {
-			refs = append(refs, rec.SkillRef)
+	if len(removed) > 0 && s.Runtime != nil {
+		st, stErr := storepkg.LoadState(s.StateRoot)
+		if stErr == nil {
+			for _, inj := range st.Injections {
+				adp, adpErr := s.Runtime.Get(inj.Agent)
+				if adpErr != nil {
+					continue
+				}
+				_, _ = adp.Remove(ctx, adapterapi.RemoveRequest{SkillRefs: removed, Scope: inj.EffectiveScope(string(s.Scope))})
+			}
 		}
 	}
-	cleanRefs := make([]string, 0, len(refs))
-	for _, r := range refs {
-		if strings.Contains(r, "@") {
-			r = strings.SplitN(r, "@", 2)[0]
-		}
-		cleanRefs = append(cleanRefs, r)
+	return removed, nil
+}
+
+// Upgrade installs the latest versions of refs, defaulting to every
+// installed skill that is not pinned in the lockfile. It also returns the
+// installed skills it skipped because they are pinned.
+func (s *Service) Upgrade(ctx context.Context, refs []string, lockPath string, force bool) ([]storepkg.InstalledSkill, []string, error) {
+	state, err := storepkg.LoadState(s.StateRoot)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(state.Installed) == 0 {
+		return nil, nil, nil
 	}
 	lockPath = s.resolveLockPath(lockPath)
 	lock, err := storepkg.LoadLockfile(lockPath)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	targets := upgradeRefs(state, refs)
+	var skipped []string
+	if len(refs) == 0 {
+		targets, skipped = splitPinned(targets, lock)
 	}
-	resolved, err := s.Resolver.ResolveMany(ctx, s.Config, cleanRefs, lock)
+	if len(targets) == 0 {
+		return nil, skipped, nil
+	}
+	resolved, err := s.Resolver.ResolveMany(ctx, s.Config, targets, lock)
 	if err != nil {
-		return nil, err
+		return nil, skipped, err
 	}
 	installedVersion := map[string]string{}
 	for _, rec := range state.Installed {
@@ -424,26 +962,117 @@ func (s *Service) Upgrade(ctx context.Context, refs []string, lockPath string, f
 		}
 	}
 	if len(upgrades) == 0 {
-		return nil, nil
+		return nil, skipped, nil
 	}
 	if err := s.scanResolved(ctx, upgrades, force); err != nil {
-		return nil, err
+		return nil, skipped, err
 	}
-	return s.Installer.Install(ctx, upgrades, lockPath, force)
+	installed, err := s.Installer.Install(ctx, upgrades, lockPath, force)
+	if err == nil {
+		s.countMetric(func(m *Metrics) { m.Upgrades++ })
+	}
+	return installed, skipped, err
 }
 
-func (s *Service) Inject(ctx context.Context, agentName string, refs []string) (adapterapi.InjectResult, error) {
+// LockChange is one lockfile entry rewritten by UpgradeLockfile. From is
+// empty for skills that were not locked yet.
+type LockChange struct {
+	SkillRef string `json:"skillRef"`
+	From     string `json:"from,omitempty"`
+	To       string `json:"to"`
+}
+
+// UpgradeLockfile resolves the latest versions of unpinned installed
+// skills (or of refs) and rewrites their lockfile entries without installing anything:
+// the store and state are left untouched, so the lock can be reviewed and
+// committed before the upgrade is applied. Like Upgrade, it also returns
+// the installed skills it skipped because they are pinned.
+func (s *Service) UpgradeLockfile(ctx context.Context, refs []string, lockPath string) ([]LockChange, []string, error) {
+	state, err := storepkg.LoadState(s.StateRoot)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(state.Installed) == 0 {
+		return nil, nil, nil
+	}
+	lockPath = s.resolveLockPath(lockPath)
+	lock, err := storepkg.LoadLockfile(lockPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	targets := upgradeRefs(state, refs)
+	var skipped []string
 	if len(refs) == 0 {
-		st, err := storepkg.LoadState(s.StateRoot)
-		if err != nil {
-			return adapterapi.InjectResult{}, err
+		targets, skipped = splitPinned(targets, lock)
+	}
+	if len(targets) == 0 {
+		return nil, skipped, nil
+	}
+	// Resolve against an empty lock so locked versions do not pin the result.
+	resolved, err := s.Resolver.ResolveMany(ctx, s.Config, targets, storepkg.Lockfile{})
+	if err != nil {
+		return nil, skipped, err
+	}
+	var changes []LockChange
+	for _, rec := range resolved {
+		prev, locked := storepkg.FindLock(lock, rec.SkillRef)
+		if locked && prev.ResolvedVersion == rec.ResolvedVersion && prev.Checksum == rec.Checksum {
+			continue
+		}
+		entry := storepkg.LockSkill{
+			SkillRef:        rec.SkillRef,
+			ResolvedVersion: rec.ResolvedVersion,
+			Checksum:        rec.Checksum,
+			SourceRef:       rec.SourceRef,
+			Deps:            rec.Deps,
 		}
-		for _, item := range st.Installed {
-			refs = append(refs, item.SkillRef)
+		if rec.ResolverHash != "" {
+			entry.Metadata = map[string]string{"resolverHash": rec.ResolverHash}
 		}
+		storepkg.UpsertLock(&lock, entry)
+		changes = append(changes, LockChange{SkillRef: rec.SkillRef, From: prev.ResolvedVersion, To: rec.ResolvedVersion})
+	}
+	if len(changes) == 0 {
+		return nil, skipped, nil
 	}
+	if err := storepkg.SaveLockfile(lockPath, lock); err != nil {
+		return nil, skipped, err
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].SkillRef < changes[j].SkillRef })
+	return changes, skipped, nil
+}
+
+// upgradeRefs returns refs without their constraints, defaulting to every
+// installed skill.
+func upgradeRefs(state storepkg.State, refs []string) []string {
 	if len(refs) == 0 {
-		return adapterapi.InjectResult{}, fmt.Errorf("ADP_INJECT: no installed skills to inject")
+		for _, rec := range state.Installed {
+			refs = append(refs, rec.SkillRef)
+		}
+	}
+	cleanRefs := make([]string, 0, len(refs))
+	for _, r := range refs {
+		if strings.Contains(r, "@") {
+			r = strings.SplitN(r, "@", 2)[0]
+		}
+		cleanRefs = append(cleanRefs, r)
+	}
+	return cleanRefs
+}
+
+func (s *Service) Inject(ctx context.Context, agentName string, refs []string) (adapterapi.InjectResult, error) {
+	res, err := s.inject(ctx, agentName, refs)
+	s.auditMutation("inject", agentName, refs, res.Injected, err)
+	if err == nil {
+		s.countMetric(func(m *Metrics) { m.Injects++ })
+	}
+	return res, err
+}
+
+func (s *Service) inject(ctx context.Context, agentName string, refs []string) (adapterapi.InjectResult, error) {
+	refs, err := s.injectRefs(refs)
+	if err != nil {
+		return adapterapi.InjectResult{}, err
 	}
 	adp, err := s.Runtime.Get(agentName)
 	if err != nil {
@@ -457,7 +1086,7 @@ func (s *Service) Inject(ctx context.Context, agentName string, refs []string) (
 	if err != nil {
 		return adapterapi.InjectResult{}, err
 	}
-	storepkg.SetInjection(&st, storepkg.InjectionState{Agent: agentName, Skills: res.Injected, UpdatedAt: time.Now().UTC()})
+	storepkg.SetInjection(&st, storepkg.InjectionState{Agent: agentName, Scope: string(s.Scope), Skills: res.Injected, UpdatedAt: time.Now().UTC()})
 	if err := storepkg.SaveState(s.StateRoot, st); err != nil {
 		return adapterapi.InjectResult{}, err
 	}
@@ -466,6 +1095,12 @@ func (s *Service) Inject(ctx context.Context, agentName string, refs []string) (
 }
 
 func (s *Service) RemoveInjected(ctx context.Context, agentName string, refs []string) (adapterapi.RemoveResult, error) {
+	res, err := s.removeInjected(ctx, agentName, refs)
+	s.auditMutation("remove", agentName, refs, res.Removed, err)
+	return res, err
+}
+
+func (s *Service) removeInjected(ctx context.Context, agentName string, refs []string) (adapterapi.RemoveResult, error) {
 	adp, err := s.Runtime.Get(agentName)
 	if err != nil {
 		return adapterapi.RemoveResult{}, err
@@ -482,24 +1117,46 @@ func (s *Service) RemoveInjected(ctx context.Context, agentName string, refs []s
 	if err != nil {
 		return adapterapi.RemoveResult{}, err
 	}
-	storepkg.SetInjection(&st, storepkg.InjectionState{Agent: agentName, Skills: listed.Skills, UpdatedAt: time.Now().UTC()})
+	storepkg.SetInjection(&st, storepkg.InjectionState{Agent: agentName, Scope: string(s.Scope), Skills: listed.Skills, UpdatedAt: time.Now().UTC()})
 	if err := storepkg.SaveState(s.StateRoot, st); err != nil {
 		return adapterapi.RemoveResult{}, err
 	}
 	return res, nil
 }
 
-func (s *Service) SyncRun(ctx context.Context, lockPath string, force bool, dryRun bool) (syncsvc.Report, error) {
-	report, err := s.Sync.Run(ctx, &s.Config, s.resolveLockPath(lockPath), force, dryRun)
+// SyncRun reconciles sources, installs and injections, or only plans that
+// when dryRun is set. With maxChanges above zero it plans first and refuses
+// a plan with more changes, returning the plan and SYNC_TOO_MANY_CHANGES.
+// Every run that is not a dry run goes to the notify webhook as the summary
+// sync --json prints for strict, whatever its outcome; only applied runs
+// are counted.
+func (s *Service) SyncRun(ctx context.Context, lockPath string, force, dryRun, strict bool, maxChanges int) (syncsvc.Report, error) {
+	if dryRun {
+		return s.Sync.Run(ctx, &s.Config, s.resolveLockPath(lockPath), force, true)
+	}
+	report, err := s.applySync(ctx, s.resolveLockPath(lockPath), force, maxChanges)
+	s.notifySync(ctx, report, strict, err)
+	return report, err
+}
+
+func (s *Service) applySync(ctx context.Context, lockPath string, force bool, maxChanges int) (syncsvc.Report, error) {
+	if maxChanges > 0 {
+		plan, err := s.Sync.Run(ctx, &s.Config, lockPath, force, true)
+		if err != nil {
+			return syncsvc.Report{}, err
+		}
+		if planned := plan.Changes(); planned > maxChanges {
+			return plan, fmt.Errorf("SYNC_TOO_MANY_CHANGES: sync plan has %d changes, above --max-changes %d; review with 'skillpm sync --dry-run' and apply manually", planned, maxChanges)
+		}
+	}
+	report, err := s.Sync.Run(ctx, &s.Config, lockPath, force, false)
 	if err != nil {
 		return syncsvc.Report{}, err
 	}
-	if dryRun {
-		return report, nil
-	}
 	if err := s.SaveConfig(); err != nil {
 		return syncsvc.Report{}, err
 	}
+	s.countMetric(func(m *Metrics) { m.Syncs++ })
 	return report, nil
 }
 
@@ -523,8 +1180,72 @@ func (s *Service) DoctorRun(ctx context.Context) doctor.Report {
 	return s.Doctor.Run(ctx)
 }
 
+// DoctorWatch re-runs diagnostics every interval until ctx is cancelled,
+// calling onChange whenever the outcome differs from the previous run.
+func (s *Service) DoctorWatch(ctx context.Context, interval time.Duration, onChange func(doctor.Report)) error {
+	return s.Doctor.Watch(ctx, interval, onChange)
+}
+
+// StoreGC removes installed directories not referenced by state.
+func (s *Service) StoreGC(dryRun bool) (storepkg.GCResult, error) {
+	return storepkg.CollectGarbage(s.StateRoot, dryRun)
+}
+
+// reinstallRefs reinstalls refs at their locked versions for doctor's lock
+// repair and returns those reinstalled. A ref whose resolved content does
+// not hash to its locked checksum is left alone rather than replaced.
+func (s *Service) reinstallRefs(ctx context.Context, refs []string) []string {
+	lockPath := s.resolveLockPath("")
+	lock, err := storepkg.LoadLockfile(lockPath)
+	if err != nil {
+		return nil
+	}
+	var ok []string
+	for _, ref := range refs {
+		installed, err := s.reinstallLocked(ctx, ref, lock, lockPath)
+		changed := make([]string, 0, len(installed))
+		for _, rec := range installed {
+			changed = append(changed, rec.SkillRef+"@"+rec.ResolvedVersion)
+		}
+		s.auditMutation("install", "", []string{ref}, changed, err)
+		if err == nil {
+			ok = append(ok, ref)
+		}
+	}
+	return ok
+}
+
+func (s *Service) reinstallLocked(ctx context.Context, ref string, lock storepkg.Lockfile, lockPath string) ([]storepkg.InstalledSkill, error) {
+	resolved, err := s.Resolver.ResolveMany(ctx, s.Config, []string{ref}, lock)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range resolved {
+		entry, locked := storepkg.FindLock(lock, item.SkillRef)
+		if locked && entry.Checksum != "" && item.Checksum != entry.Checksum {
+			return nil, fmt.Errorf("DOC_LOCK_CHECKSUM: %s@%s resolves to %s, not the locked %s", item.SkillRef, item.ResolvedVersion, item.Checksum, entry.Checksum)
+		}
+	}
+	if err := s.scanResolved(ctx, resolved, false); err != nil {
+		return nil, err
+	}
+	return s.Installer.Install(ctx, resolved, lockPath, false)
+}
+
+// uninstallRefs removes installed refs for doctor's lock repair, taking
+// them out of every agent like Uninstall but leaving the manifest alone,
+// and returns those that were removed.
+func (s *Service) uninstallRefs(ctx context.Context, refs []string) []string {
+	removed, err := s.uninstallInstalled(ctx, refs, s.resolveLockPath(""))
+	s.auditMutation("uninstall", "", refs, removed, err)
+	if err != nil {
+		return nil
+	}
+	return removed
+}
+
 func (s *Service) DetectAdapters() []adapter.Detection {
-	return adapter.DetectAvailable()
+	return adapter.DetectWithOverrides(s.Config.Adapters)
 }
 
 func (s *Service) EnableDetectedAdapters() ([]string, error) {
@@ -554,7 +1275,7 @@ func (s *Service) EnableDetectedAdapters() ([]string, error) {
 		return nil, err
 	}
 	s.Runtime = runtimeSvc
-	s.Harvest = &harvest.Service{Runtime: runtimeSvc, StateRoot: s.StateRoot}
+	s.Harvest = &harvest.Service{Runtime: runtimeSvc, StateRoot: s.StateRoot, Scanner: s.Harvest.Scanner}
 	s.Sync.Runtime = runtimeSvc
 	s.Doctor.Runtime = runtimeSvc
 	sort.Strings(enabled)
@@ -570,21 +1291,62 @@ func (s *Service) SelfUpdate(ctx context.Context, channel string) error {
 	return err
 }
 
+// SelfUpdateCheck reports whether the channel offers a newer skillpm
+// without downloading it.
+func (s *Service) SelfUpdateCheck(ctx context.Context, channel string) (selfupdate.Check, error) {
+	updater := selfupdate.New(s.httpClient)
+	return updater.Check(ctx, channel, config.Version, s.Config.Security.RequireSignatures)
+}
+
 func (s *Service) scanResolved(ctx context.Context, resolved []resolver.ResolvedSkill, force bool) error {
+	return s.scanContents(ctx, resolvedToScanContents(resolved), force)
+}
+
+// scanContents scans contents and enforces the scan gate for force,
+// recording the scan in the audit log.
+func (s *Service) scanContents(ctx context.Context, contents []security.SkillContent, force bool) error {
 	if s.Installer.Security == nil || s.Installer.Security.Scanner == nil {
 		return nil
 	}
-	contents := resolvedToScanContents(resolved)
-	report := s.Installer.Security.Scanner.Scan(ctx, contents)
+	scanner := s.Installer.Security.Scanner
+	report := scanner.Scan(ctx, contents)
 	if s.Audit != nil {
 		_ = s.Audit.Log(audit.Event{
 			Operation: "security_scan",
 			Phase:     "complete",
 			Status:    report.MaxSeverity().String(),
-			Message:   fmt.Sprintf("skills=%d findings=%d max_severity=%s", len(resolved), len(report.Findings), report.MaxSeverity()),
+			Message:   fmt.Sprintf("skills=%d findings=%d max_severity=%s", len(contents), len(report.Findings), report.MaxSeverity()),
+			Fields:    map[string]string{"gate": scanner.Gate(force)},
 		})
 	}
-	return s.Installer.Security.Scanner.Enforce(report, force)
+	return scanner.Enforce(report, force)
+}
+
+// auditMutation records an install, uninstall, inject or remove outcome.
+// Failures carry the error code prefix of err so they can be filtered.
+func (s *Service) auditMutation(operation, agent string, refs, changed []string, err error) {
+	if s.Audit == nil {
+		return
+	}
+	ev := audit.Event{
+		Operation: operation,
+		Phase:     "complete",
+		Status:    "ok",
+		Fields: map[string]string{
+			"refs":    strings.Join(refs, ","),
+			"changed": strings.Join(changed, ","),
+			"scope":   string(s.Scope),
+		},
+	}
+	if agent != "" {
+		ev.Fields["agent"] = agent
+	}
+	if err != nil {
+		ev.Status = "error"
+		ev.Code = audit.ErrorCode(err)
+		ev.Message = err.Error()
+	}
+	_ = s.Audit.Log(ev)
 }
 
 func resolvedToScanContents(skills []resolver.ResolvedSkill) []security.SkillContent {
@@ -651,6 +1413,11 @@ func (s *Service) resolveLockPath(lockPath string) string {
 	if s.Scope == config.ScopeProject && s.ProjectRoot != "" {
 		return config.ProjectLockPath(s.ProjectRoot)
 	}
+	if lf := s.Config.Defaults.Lockfile; lf != "" {
+		if expanded, err := config.ExpandPath(lf); err == nil {
+			return expanded
+		}
+	}
 	cwd, err := os.Getwd()
 	if err != nil {
 		return "skills.lock"
@@ -684,7 +1451,7 @@ func (s *Service) PublishSkill(ctx context.Context, sourceName, skillDir, versio
 }
 
 // BundleInstall installs all skills in a named bundle.
-func (s *Service) BundleInstall(ctx context.Context, bundleName, lockPath string, force bool) ([]storepkg.InstalledSkill, error) {
+func (s *Service) BundleInstall(ctx context.Context, bundleName, lockPath string, force bool) ([]InstallResult, error) {
 	if s.Manifest == nil {
 		return nil, fmt.Errorf("BUNDLE_INSTALL: bundles require a project manifest (run 'skillpm init' first)")
 	}
@@ -0,0 +1,92 @@
+package app
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"skillpm/internal/audit"
+	"skillpm/internal/store"
+)
+
+func readAuditEvents(t *testing.T, stateRoot string) []audit.Event {
+	t.Helper()
+	f, err := os.Open(store.AuditPath(stateRoot))
+	if err != nil {
+		t.Fatalf("open audit log failed: %v", err)
+	}
+	defer f.Close()
+	var events []audit.Event
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var ev audit.Event
+		if err := json.Unmarshal(sc.Bytes(), &ev); err != nil {
+			t.Fatalf("decode audit event failed: %v", err)
+		}
+		events = append(events, ev)
+	}
+	return events
+}
+
+func findAuditEvent(events []audit.Event, operation, status string) (audit.Event, bool) {
+	for _, ev := range events {
+		if ev.Operation == operation && ev.Phase == "complete" && ev.Status == status {
+			return ev, true
+		}
+	}
+	return audit.Event{}, false
+}
+
+func TestServiceMutationsEmitAuditEvents(t *testing.T) {
+	svc := newScanTestService(t, map[string]map[string]string{
+		"clean": {"SKILL.md": "# Clean Skill\nThis is a normal formatting skill.\n"},
+	})
+	ctx := context.Background()
+	lockPath := filepath.Join(t.TempDir(), "project", "skills.lock")
+
+	if _, err := svc.Install(ctx, []string{"local/clean@1.0.0"}, lockPath, false); err != nil {
+		t.Fatalf("install failed: %v", err)
+	}
+	if _, err := svc.Inject(ctx, "openclaw", []string{"local/clean"}); err != nil {
+		t.Fatalf("inject failed: %v", err)
+	}
+	if _, err := svc.RemoveInjected(ctx, "openclaw", []string{"local/clean"}); err != nil {
+		t.Fatalf("remove failed: %v", err)
+	}
+	if _, err := svc.Uninstall(ctx, []string{"local/clean"}, lockPath, false); err != nil {
+		t.Fatalf("uninstall failed: %v", err)
+	}
+
+	events := readAuditEvents(t, svc.StateRoot)
+	for _, op := range []string{"install", "inject", "remove", "uninstall"} {
+		ev, ok := findAuditEvent(events, op, "ok")
+		if !ok {
+			t.Fatalf("expected %s audit event, got %+v", op, events)
+		}
+		if ev.Fields["refs"] == "" || ev.Fields["scope"] != string(svc.Scope) {
+			t.Fatalf("unexpected %s audit fields: %+v", op, ev.Fields)
+		}
+	}
+	if ev, _ := findAuditEvent(events, "inject", "ok"); ev.Fields["agent"] != "openclaw" {
+		t.Fatalf("expected inject event to record agent, got %+v", ev.Fields)
+	}
+}
+
+func TestServiceFailedInjectLogsErrorCode(t *testing.T) {
+	svc := newScanTestService(t, map[string]map[string]string{
+		"clean": {"SKILL.md": "# Clean Skill\n"},
+	})
+	if _, err := svc.Inject(context.Background(), "openclaw", nil); err == nil {
+		t.Fatal("expected inject with nothing installed to fail")
+	}
+	ev, ok := findAuditEvent(readAuditEvents(t, svc.StateRoot), "inject", "error")
+	if !ok {
+		t.Fatal("expected failed inject audit event")
+	}
+	if ev.Code != "ADP_INJECT" {
+		t.Fatalf("expected ADP_INJECT code, got %q", ev.Code)
+	}
+}
@@ -5,13 +5,22 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"skillpm/internal/config"
+	"skillpm/internal/resolver"
+	"skillpm/internal/source"
+	storepkg "skillpm/internal/store"
+	syncsvc "skillpm/internal/sync"
 )
 
 func TestEnableDetectedAdapters(t *testing.T) {
@@ -83,3 +92,403 @@ func TestServiceSelfUpdate(t *testing.T) {
 		t.Fatalf("expected target to be updated")
 	}
 }
+
+func TestServiceResolveOnlyDoesNotInstall(t *testing.T) {
+	svc := newScanTestService(t, map[string]map[string]string{
+		"clean": {
+			"SKILL.md":      "# Clean Skill\n",
+			"docs/guide.md": "guide",
+		},
+	})
+	ctx := context.Background()
+	lockPath := filepath.Join(t.TempDir(), "skills.lock")
+
+	entries, err := svc.ResolveOnly(ctx, []string{"local/clean", "local/missing"}, lockPath, true)
+	if err != nil {
+		t.Fatalf("resolve-only failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %+v", entries)
+	}
+	ok, bad := entries[0], entries[1]
+	if ok.SkillRef != "local/clean" || ok.Checksum == "" || ok.Error != "" {
+		t.Fatalf("unexpected resolved entry: %+v", ok)
+	}
+	if len(ok.Files) != 2 || ok.Files[0] != "SKILL.md" || ok.Files[1] != "docs/guide.md" {
+		t.Fatalf("unexpected file list: %v", ok.Files)
+	}
+	if bad.Ref != "local/missing" || bad.Error == "" {
+		t.Fatalf("expected per-ref error, got %+v", bad)
+	}
+
+	if _, err := svc.ResolveOnly(ctx, []string{"local/clean", "local/missing"}, lockPath, false); err == nil {
+		t.Fatal("expected resolution error without keep-going")
+	}
+
+	installed, err := svc.ListInstalled()
+	if err != nil {
+		t.Fatalf("list installed failed: %v", err)
+	}
+	if len(installed) != 0 {
+		t.Fatalf("resolve-only must not install, got %d skills", len(installed))
+	}
+	if _, err := os.Stat(lockPath); !os.IsNotExist(err) {
+		t.Fatalf("resolve-only must not write a lockfile: %v", err)
+	}
+}
+
+func TestServiceResolveBareRefAcrossSources(t *testing.T) {
+	svc := newScanTestService(t, map[string]map[string]string{
+		"shared": {"SKILL.md": "# Shared\n"},
+	})
+	otherURL := setupBareRepo(t, map[string]map[string]string{
+		"shared": {"SKILL.md": "# Shared elsewhere\n"},
+		"pdf":    {"SKILL.md": "# PDF\n"},
+	})
+	svc.Config.Sources = append(svc.Config.Sources, config.SourceConfig{
+		Name:      "other",
+		Kind:      "git",
+		URL:       otherURL,
+		Branch:    "main",
+		ScanPaths: []string{"skills"},
+		TrustTier: "trusted",
+	})
+	ctx := context.Background()
+	lockPath := filepath.Join(t.TempDir(), "skills.lock")
+
+	// Only "other" has pdf.
+	entries, err := svc.ResolveOnly(ctx, []string{"pdf"}, lockPath, false)
+	if err != nil {
+		t.Fatalf("resolve bare ref failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].SkillRef != "other/pdf" {
+		t.Fatalf("expected other/pdf, got %+v", entries)
+	}
+
+	// Both have shared; the trusted source wins.
+	entries, err = svc.ResolveOnly(ctx, []string{"shared"}, lockPath, false)
+	if err != nil {
+		t.Fatalf("resolve bare ref failed: %v", err)
+	}
+	if entries[0].SkillRef != "other/shared" {
+		t.Fatalf("expected trusted source to win, got %+v", entries)
+	}
+
+	// Same tier on both sides is ambiguous.
+	svc.Config.Sources[1].TrustTier = "review"
+	if _, err := svc.ResolveOnly(ctx, []string{"shared"}, lockPath, false); err == nil || !strings.Contains(err.Error(), "RES_AMBIGUOUS") {
+		t.Fatalf("expected RES_AMBIGUOUS, got %v", err)
+	}
+
+	if _, err := svc.ResolveOnly(ctx, []string{"nope"}, lockPath, false); err == nil || !strings.Contains(err.Error(), "RES_NOT_FOUND_ANY") || !strings.Contains(err.Error(), "local, other") {
+		t.Fatalf("expected RES_NOT_FOUND_ANY listing sources, got %v", err)
+	}
+}
+
+func TestServiceProvenanceRecordsCommitAndInstaller(t *testing.T) {
+	svc := newScanTestService(t, map[string]map[string]string{
+		"clean": {"SKILL.md": "# Clean Skill\n"},
+	})
+	ctx := context.Background()
+	lockPath := filepath.Join(t.TempDir(), "skills.lock")
+	if _, err := svc.Install(ctx, []string{"local/clean"}, lockPath, false); err != nil {
+		t.Fatalf("install failed: %v", err)
+	}
+
+	p, err := svc.Provenance("local/clean")
+	if err != nil {
+		t.Fatalf("provenance failed: %v", err)
+	}
+	if len(p.ResolvedCommit) != 40 {
+		t.Fatalf("expected full commit hash, got %q", p.ResolvedCommit)
+	}
+	if p.InstalledBy != config.Version {
+		t.Fatalf("expected installedBy %q, got %q", config.Version, p.InstalledBy)
+	}
+	if p.SourceURL != svc.Config.Sources[0].URL || p.InstalledAt.IsZero() {
+		t.Fatalf("unexpected provenance: %+v", p)
+	}
+
+	if _, err := svc.Provenance("local/missing"); err == nil || !strings.HasPrefix(err.Error(), "INS_NOT_INSTALLED") {
+		t.Fatalf("expected INS_NOT_INSTALLED, got %v", err)
+	}
+}
+
+func TestServiceInstallReportsChoicesForAmbiguousRefs(t *testing.T) {
+	svc := newScanTestService(t, map[string]map[string]string{
+		"shared":       {"SKILL.md": "# Shared\n"},
+		"group/first":  {"SKILL.md": "# First\n"},
+		"group/second": {"SKILL.md": "# Second\n"},
+	})
+	otherURL := setupBareRepo(t, map[string]map[string]string{
+		"shared": {"SKILL.md": "# Shared elsewhere\n"},
+	})
+	svc.Config.Sources = append(svc.Config.Sources, config.SourceConfig{
+		Name:      "other",
+		Kind:      "git",
+		URL:       otherURL,
+		Branch:    "main",
+		ScanPaths: []string{"skills"},
+		TrustTier: "review",
+	})
+	ctx := context.Background()
+	lockPath := filepath.Join(t.TempDir(), "skills.lock")
+
+	_, err := svc.Install(ctx, []string{"shared"}, lockPath, false)
+	var choiceErr *resolver.ChoiceError
+	if !errors.As(err, &choiceErr) || choiceErr.Ref != "shared" || strings.Join(choiceErr.Options, ",") != "local/shared,other/shared" {
+		t.Fatalf("expected choices for bare ref, got %v", err)
+	}
+
+	_, err = svc.Install(ctx, []string{"local/group"}, lockPath, false)
+	if !errors.As(err, &choiceErr) || choiceErr.Ref != "local/group" || strings.Join(choiceErr.Options, ",") != "local/group/first,local/group/second" {
+		t.Fatalf("expected choices for skill directory, got %v", err)
+	}
+	if _, err := svc.Install(ctx, choiceErr.Options[:1], lockPath, false); err != nil {
+		t.Fatalf("install of chosen option failed: %v", err)
+	}
+}
+
+func TestDependentsFollowsSkillDeps(t *testing.T) {
+	svc := newScanTestService(t, map[string]map[string]string{
+		"base":   {"SKILL.md": "# Base\nShared helpers.\n"},
+		"middle": {"SKILL.md": "---\nname: middle\ndeps: [local/base]\n---\n# Middle\n"},
+		"top":    {"SKILL.md": "---\nname: top\ndeps:\n  - middle\n---\n# Top\n"},
+		"other":  {"SKILL.md": "# Other\nUnrelated.\n"},
+	})
+	ctx := context.Background()
+	lockPath := filepath.Join(t.TempDir(), "skills.lock")
+	if _, err := svc.Install(ctx, []string{"local/base", "local/middle", "local/top", "local/other"}, lockPath, false); err != nil {
+		t.Fatalf("install failed: %v", err)
+	}
+
+	dependents, err := svc.Dependents([]string{"local/base"})
+	if err != nil {
+		t.Fatalf("dependents failed: %v", err)
+	}
+	want := map[string][]string{"local/middle": {"local/base"}, "local/top": {"local/middle"}}
+	if !reflect.DeepEqual(dependents, want) {
+		t.Fatalf("expected %v, got %v", want, dependents)
+	}
+
+	dependents, err = svc.Dependents([]string{"local/top"})
+	if err != nil || len(dependents) != 0 {
+		t.Fatalf("expected no dependents of a leaf skill, got %v (%v)", dependents, err)
+	}
+}
+
+func TestInstallAndSearchSurfaceDeprecation(t *testing.T) {
+	svc := newScanTestService(t, map[string]map[string]string{
+		"old": {"SKILL.md": "---\nname: old\ndeprecated: true\nsuperseded_by: local/new\n---\n# Old\n"},
+		"new": {"SKILL.md": "# New\nThe replacement.\n"},
+	})
+	ctx := context.Background()
+	installed, err := svc.Install(ctx, []string{"local/old"}, filepath.Join(t.TempDir(), "skills.lock"), false)
+	if err != nil {
+		t.Fatalf("install failed: %v", err)
+	}
+	if len(installed) != 1 || !installed[0].Deprecated || installed[0].SupersededBy != "local/new" {
+		t.Fatalf("expected deprecation on the install record, got %+v", installed)
+	}
+
+	results, err := svc.Search(ctx, []string{"local"}, "old")
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if len(results) != 1 || !results[0].Deprecated || results[0].SupersededBy != "local/new" {
+		t.Fatalf("expected search to mark old deprecated, got %+v", results)
+	}
+
+	rpt := svc.DoctorRun(ctx)
+	var found bool
+	for _, c := range rpt.Checks {
+		if c.Name == "deprecated" && strings.Contains(c.Message, "local/old (use local/new)") {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected doctor to flag local/old, got %+v", rpt.Checks)
+	}
+}
+
+func TestNotifySyncPostsOnlyWhenAttentionNeeded(t *testing.T) {
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		blob, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(blob))
+	}))
+	defer srv.Close()
+
+	svc := &Service{Config: config.Config{Notify: config.NotifyConfig{WebhookURL: srv.URL}}}
+	summary := map[string]string{"outcome": "blocked"}
+	for _, outcome := range []string{"noop", "changed", "blocked", "changed-with-risk"} {
+		if err := svc.NotifySync(context.Background(), outcome, summary); err != nil {
+			t.Fatalf("notify %s: %v", outcome, err)
+		}
+	}
+	if len(bodies) != 2 || bodies[0] != `{"outcome":"blocked"}` {
+		t.Fatalf("expected two attention notifications, got %q", bodies)
+	}
+
+	svc.Config.Notify.On = "always"
+	if !svc.SyncNeedsNotify("noop") {
+		t.Fatalf("expected on=always to notify every outcome")
+	}
+
+	srv.Close()
+	if err := svc.NotifySync(context.Background(), "blocked", summary); err == nil || !strings.Contains(err.Error(), "SYNC_NOTIFY") {
+		t.Fatalf("expected SYNC_NOTIFY error from an unreachable webhook, got %v", err)
+	}
+}
+
+func TestSyncRunCountsOnlyAppliedRunsAndNotifiesEveryOutcome(t *testing.T) {
+	var sent []SyncNotification
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var n SyncNotification
+		_ = json.NewDecoder(r.Body).Decode(&n)
+		sent = append(sent, n)
+	}))
+	defer srv.Close()
+
+	svc, _ := newFlowTestService(t)
+	ctx := context.Background()
+	lockPath := filepath.Join(t.TempDir(), "skills.lock")
+	if _, err := svc.Install(ctx, []string{"local/forms"}, lockPath, false); err != nil {
+		t.Fatalf("install failed: %v", err)
+	}
+	if _, err := svc.Inject(ctx, "openclaw", []string{"local/forms"}); err != nil {
+		t.Fatalf("inject failed: %v", err)
+	}
+	svc.Config.Metrics.Enabled = true
+	svc.Config.Notify = config.NotifyConfig{WebhookURL: srv.URL, On: "always"}
+
+	if _, err := svc.SyncRun(ctx, lockPath, false, true, false, 0); err != nil {
+		t.Fatalf("dry run failed: %v", err)
+	}
+	if _, err := svc.SyncRun(ctx, lockPath, false, false, false, 1); err == nil || !strings.Contains(err.Error(), "SYNC_TOO_MANY_CHANGES") {
+		t.Fatalf("expected the source update and reinjection to exceed --max-changes 1, got %v", err)
+	}
+	if _, err := svc.SyncRun(ctx, lockPath, false, false, false, 10); err != nil {
+		t.Fatalf("sync failed: %v", err)
+	}
+
+	m, err := svc.Metrics()
+	if err != nil {
+		t.Fatalf("metrics failed: %v", err)
+	}
+	if m.Syncs != 1 {
+		t.Fatalf("expected only the applied sync counted, got %d", m.Syncs)
+	}
+	var outcomes []string
+	for _, n := range sent {
+		outcomes = append(outcomes, n.Outcome)
+	}
+	if !reflect.DeepEqual(outcomes, []string{"refused", "changed"}) {
+		t.Fatalf("expected refused then changed notifications, got %v", outcomes)
+	}
+	if refused := sent[0]; refused.ErrorCode != "SYNC_TOO_MANY_CHANGES" || refused.SchemaVersion != syncsvc.SummarySchemaVersion || refused.ActionCounts.Total == 0 {
+		t.Fatalf("expected the refused plan's summary with its error code, got %+v", refused)
+	}
+	if applied := sent[1]; applied.Error != "" || applied.SchemaVersion != syncsvc.SummarySchemaVersion {
+		t.Fatalf("expected the applied run's summary without an error, got %+v", applied)
+	}
+}
+
+func TestSyncRunAuditsFailedNotification(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	svc, _ := newFlowTestService(t)
+	ctx := context.Background()
+	lockPath := filepath.Join(t.TempDir(), "skills.lock")
+	if _, err := svc.Install(ctx, []string{"local/forms"}, lockPath, false); err != nil {
+		t.Fatalf("install failed: %v", err)
+	}
+	svc.Config.Notify = config.NotifyConfig{WebhookURL: srv.URL, On: "always"}
+	if _, err := svc.SyncRun(ctx, lockPath, false, false, false, 0); err != nil {
+		t.Fatalf("a failed notification must not fail the sync: %v", err)
+	}
+	blob, err := os.ReadFile(storepkg.AuditPath(svc.StateRoot))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(blob), `"operation":"sync_notify"`) || !strings.Contains(string(blob), "SYNC_NOTIFY") {
+		t.Fatalf("expected the failed notification in the audit log, got %s", blob)
+	}
+}
+
+func TestDedupeInstalledKeepsMostTrustedCopy(t *testing.T) {
+	svc := &Service{}
+	installed := []storepkg.InstalledSkill{
+		{SkillRef: "mirror/pdf", Checksum: "sha256:a", TrustTier: "review"},
+		{SkillRef: "internal/pdf", Checksum: "sha256:a", TrustTier: "trusted"},
+		{SkillRef: "hub/pdf", Checksum: "sha256:b", TrustTier: "review"},
+		{SkillRef: "hub/docx", Checksum: "sha256:c", TrustTier: "review"},
+	}
+	got, also := svc.DedupeInstalled(installed)
+	var refs []string
+	for _, rec := range got {
+		refs = append(refs, rec.SkillRef)
+	}
+	if strings.Join(refs, ",") != "internal/pdf,hub/pdf,hub/docx" {
+		t.Fatalf("unexpected deduped refs: %v", refs)
+	}
+	if !reflect.DeepEqual(also, map[string][]string{"internal/pdf": {"mirror/pdf"}}) {
+		t.Fatalf("unexpected folded refs: %v", also)
+	}
+}
+
+func TestFilterSearchByTierDropsLessTrustedSources(t *testing.T) {
+	svc := &Service{Config: config.Config{Sources: []config.SourceConfig{
+		{Name: "internal", TrustTier: "trusted"},
+		{Name: "hub", TrustTier: "review"},
+		{Name: "wild", TrustTier: "untrusted"},
+	}}}
+	results := []source.SearchResult{{Source: "internal", Slug: "pdf"}, {Source: "hub", Slug: "pdf"}, {Source: "wild", Slug: "pdf"}, {Source: "gone", Slug: "pdf"}}
+	for tier, want := range map[string]string{"trusted": "internal", "review": "internal,hub", "untrusted": "internal,hub,wild"} {
+		got, err := svc.FilterSearchByTier(results, tier)
+		if err != nil {
+			t.Fatalf("filter %s: %v", tier, err)
+		}
+		var sources []string
+		for _, r := range got {
+			sources = append(sources, r.Source)
+		}
+		if strings.Join(sources, ",") != want {
+			t.Fatalf("tier %s: expected %s, got %v", tier, want, sources)
+		}
+	}
+	if _, err := svc.FilterSearchByTier(results, "vetted"); err == nil || !strings.Contains(err.Error(), "SRC_TRUST_TIER") {
+		t.Fatalf("expected SRC_TRUST_TIER for unknown tier, got %v", err)
+	}
+}
+
+func TestSyncHistoryRecordsOnlyWhenEnabled(t *testing.T) {
+	svc := &Service{StateRoot: t.TempDir()}
+	if err := svc.RecordSyncHistory(map[string]string{"outcome": "noop"}); err != nil {
+		t.Fatalf("record: %v", err)
+	}
+	if _, err := os.Stat(storepkg.SyncHistoryPath(svc.StateRoot)); !os.IsNotExist(err) {
+		t.Fatalf("expected no history file while disabled, got %v", err)
+	}
+
+	svc.Config.Sync.History = true
+	for _, outcome := range []string{"changed", "blocked"} {
+		if err := svc.RecordSyncHistory(map[string]string{"outcome": outcome}); err != nil {
+			t.Fatalf("record: %v", err)
+		}
+	}
+	entries, err := svc.SyncHistory(time.Time{})
+	if err != nil {
+		t.Fatalf("history: %v", err)
+	}
+	if len(entries) != 2 || string(entries[1].Summary) != `{"outcome":"blocked"}` {
+		t.Fatalf("unexpected history: %+v", entries)
+	}
+	entries, err = svc.SyncHistory(time.Now().Add(time.Hour))
+	if err != nil || len(entries) != 0 {
+		t.Fatalf("expected --since to filter every entry, got %+v (%v)", entries, err)
+	}
+}
@@ -2,10 +2,14 @@ package app
 
 import (
 	"context"
+	"encoding/json"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 
+	"skillpm/internal/audit"
 	"skillpm/internal/config"
 	"skillpm/internal/store"
 	syncsvc "skillpm/internal/sync"
@@ -91,7 +95,7 @@ func TestServiceInstallUpgradeUninstallPaths(t *testing.T) {
 		t.Fatalf("expected installed version 1.0.0, got %q", installed[0].ResolvedVersion)
 	}
 
-	if _, err := svc.Uninstall(ctx, []string{"bad-ref"}, lockPath); err == nil {
+	if _, err := svc.Uninstall(ctx, []string{"bad-ref"}, lockPath, false); err == nil {
 		t.Fatalf("expected uninstall parse error for invalid ref")
 	}
 
@@ -109,7 +113,7 @@ func TestServiceInstallUpgradeUninstallPaths(t *testing.T) {
 		t.Fatalf("save lockfile failed: %v", err)
 	}
 
-	upgraded, err := svc.Upgrade(ctx, nil, lockPath, false)
+	upgraded, _, err := svc.Upgrade(ctx, nil, lockPath, false)
 	if err != nil {
 		t.Fatalf("upgrade failed: %v", err)
 	}
@@ -120,7 +124,7 @@ func TestServiceInstallUpgradeUninstallPaths(t *testing.T) {
 		t.Fatalf("expected upgraded version 2.0.0, got %q", upgraded[0].ResolvedVersion)
 	}
 
-	removed, err := svc.Uninstall(ctx, []string{"local/forms@latest"}, lockPath)
+	removed, err := svc.Uninstall(ctx, []string{"local/forms@latest"}, lockPath, false)
 	if err != nil {
 		t.Fatalf("uninstall failed: %v", err)
 	}
@@ -128,11 +132,11 @@ func TestServiceInstallUpgradeUninstallPaths(t *testing.T) {
 		t.Fatalf("expected local/forms removed, got %#v", removed)
 	}
 
-	if _, err := svc.Uninstall(ctx, nil, lockPath); err == nil {
+	if _, err := svc.Uninstall(ctx, nil, lockPath, false); err == nil {
 		t.Fatalf("expected uninstall error for empty refs")
 	}
 
-	noUpgrades, err := svc.Upgrade(ctx, nil, lockPath, false)
+	noUpgrades, _, err := svc.Upgrade(ctx, nil, lockPath, false)
 	if err != nil {
 		t.Fatalf("upgrade with no installed skills failed: %v", err)
 	}
@@ -143,17 +147,209 @@ func TestServiceInstallUpgradeUninstallPaths(t *testing.T) {
 	if _, err := svc.Install(ctx, []string{"local/forms@1.0.0"}, lockPath, false); err != nil {
 		t.Fatalf("reinstall failed: %v", err)
 	}
-	if _, err := svc.Upgrade(ctx, []string{"bad-ref"}, lockPath, false); err == nil {
+	if _, _, err := svc.Upgrade(ctx, []string{"bad-ref"}, lockPath, false); err == nil {
 		t.Fatalf("expected upgrade parse error for invalid ref")
 	}
 }
 
+func TestInstallReportsAttemptsOutsideTheStoredRecord(t *testing.T) {
+	svc, _ := newFlowTestService(t)
+	installed, err := svc.Install(context.Background(), []string{"local/forms"}, filepath.Join(t.TempDir(), "skills.lock"), false)
+	if err != nil {
+		t.Fatalf("install failed: %v", err)
+	}
+	if len(installed) != 1 || installed[0].Attempts != 1 {
+		t.Fatalf("expected local/forms installed on the first fetch, got %+v", installed)
+	}
+	blob, err := json.Marshal(installed[0])
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	var out map[string]any
+	if err := json.Unmarshal(blob, &out); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+	if out["skillRef"] != "local/forms" || out["attempts"] != float64(1) {
+		t.Fatalf("expected the record flattened alongside attempts, got %s", blob)
+	}
+	state, err := store.LoadState(svc.StateRoot)
+	if err != nil {
+		t.Fatalf("load state failed: %v", err)
+	}
+	if !reflect.DeepEqual(state.Installed, []store.InstalledSkill{installed[0].InstalledSkill}) {
+		t.Fatalf("expected state to hold the plain record, got %+v", state.Installed)
+	}
+}
+
+func TestUpgradeSkipsPinnedSkills(t *testing.T) {
+	svc, _ := newFlowTestService(t)
+	ctx := context.Background()
+	lockPath := filepath.Join(t.TempDir(), "skills.lock")
+	if _, err := svc.Install(ctx, []string{"local/forms@1.0.0"}, lockPath, false); err != nil {
+		t.Fatalf("install failed: %v", err)
+	}
+	if _, err := svc.SetPinned([]string{"local/forms@2.0.0"}, lockPath, true); audit.ErrorCode(err) != "PIN_VERSION" {
+		t.Fatalf("expected PIN_VERSION for a version that is not locked, got %v", err)
+	}
+	if _, err := svc.SetPinned([]string{"local/missing"}, lockPath, true); audit.ErrorCode(err) != "PIN_NOT_LOCKED" {
+		t.Fatalf("expected PIN_NOT_LOCKED, got %v", err)
+	}
+	changed, err := svc.SetPinned([]string{"local/forms@1.0.0"}, lockPath, true)
+	if err != nil || len(changed) != 1 {
+		t.Fatalf("expected local/forms pinned, got %v, %v", changed, err)
+	}
+
+	lock, err := store.LoadLockfile(lockPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lock.Skills[0].ResolvedVersion = "2.0.0"
+	lock.Skills[0].Checksum = "sha256:forced"
+	lock.Skills = append(lock.Skills, store.LockSkill{SkillRef: "local/demo", ResolvedVersion: "1.0.0", Checksum: "sha256:demo", SourceRef: "main", Pinned: true})
+	if err := store.SaveLockfile(lockPath, lock); err != nil {
+		t.Fatal(err)
+	}
+	changes, skipped, err := svc.UpgradeLockfile(ctx, nil, lockPath)
+	if err != nil || len(changes) != 0 || !reflect.DeepEqual(skipped, []string{"local/forms"}) {
+		t.Fatalf("expected only the installed pinned skill skipped by a lockfile upgrade, got %v, %v, %v", changes, skipped, err)
+	}
+	upgraded, skipped, err := svc.Upgrade(ctx, nil, lockPath, false)
+	if err != nil || len(upgraded) != 0 || !reflect.DeepEqual(skipped, []string{"local/forms"}) {
+		t.Fatalf("expected only the installed pinned skill skipped by a bulk upgrade, got %v, %v, %v", upgraded, skipped, err)
+	}
+	upgraded, skipped, err = svc.Upgrade(ctx, []string{"local/forms"}, lockPath, false)
+	if len(skipped) != 0 {
+		t.Fatalf("expected nothing skipped when the skill is named, got %v", skipped)
+	}
+	if err != nil || len(upgraded) != 1 || upgraded[0].ResolvedVersion != "2.0.0" {
+		t.Fatalf("expected a named pinned skill upgraded, got %v, %v", upgraded, err)
+	}
+	if pinned, err := svc.PinnedSkills(lockPath); err != nil || !reflect.DeepEqual(pinned, []string{"local/demo", "local/forms"}) {
+		t.Fatalf("expected the pin kept across the upgrade, got %v, %v", pinned, err)
+	}
+
+	if changed, err := svc.SetPinned([]string{"local/forms"}, lockPath, false); err != nil || len(changed) != 1 {
+		t.Fatalf("expected local/forms unpinned, got %v, %v", changed, err)
+	}
+	if pinned, _ := svc.PinnedSkills(lockPath); !reflect.DeepEqual(pinned, []string{"local/demo"}) {
+		t.Fatalf("expected only local/demo still pinned, got %v", pinned)
+	}
+}
+
+func TestSyncUpgradesEditedDirSourceSkill(t *testing.T) {
+	svc, _ := newFlowTestService(t)
+	ctx := context.Background()
+	lockPath := filepath.Join(t.TempDir(), "skills.lock")
+	root := t.TempDir()
+	skillMd := filepath.Join(root, "drafts", "SKILL.md")
+	if err := os.MkdirAll(filepath.Dir(skillMd), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(skillMd, []byte("# drafts\nfirst draft"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.SourceAdd("mine", root, "dir", "", "trusted"); err != nil {
+		t.Fatalf("source add (dir) failed: %v", err)
+	}
+	installed, err := svc.Install(ctx, []string{"mine/drafts"}, lockPath, false)
+	if err != nil {
+		t.Fatalf("install failed: %v", err)
+	}
+	if len(installed) != 1 || !strings.HasPrefix(installed[0].ResolvedVersion, "0.0.0+dir.") {
+		t.Fatalf("expected a content-hash dir version, got %+v", installed)
+	}
+
+	report, err := svc.SyncRun(ctx, lockPath, false, false, false, 0)
+	if err != nil {
+		t.Fatalf("sync failed: %v", err)
+	}
+	if len(report.UpgradedSkills) != 0 {
+		t.Fatalf("expected no upgrades before the skill changes, got %v", report.UpgradedSkills)
+	}
+
+	if err := os.WriteFile(skillMd, []byte("# drafts\nsecond draft"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	report, err = svc.SyncRun(ctx, lockPath, false, false, false, 0)
+	if err != nil {
+		t.Fatalf("sync failed: %v", err)
+	}
+	if len(report.UpgradedSkills) != 1 || report.UpgradedSkills[0] != "mine/drafts" {
+		t.Fatalf("expected the edited skill upgraded, got %v", report.UpgradedSkills)
+	}
+	d, err := svc.SkillShow("mine/drafts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.ResolvedVersion == installed[0].ResolvedVersion || !strings.Contains(d.Content, "second draft") {
+		t.Fatalf("expected the edit installed, got %s: %q", d.ResolvedVersion, d.Content)
+	}
+}
+
+func TestSyncReplacesGitVersionOfDirSourceSkill(t *testing.T) {
+	svc, _ := newFlowTestService(t)
+	ctx := context.Background()
+	lockPath := filepath.Join(t.TempDir(), "skills.lock")
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "drafts"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "drafts", "SKILL.md"), []byte("# drafts\nfirst draft"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.SourceAdd("mine", root, "dir", "", "trusted"); err != nil {
+		t.Fatalf("source add (dir) failed: %v", err)
+	}
+	if _, err := svc.Install(ctx, []string{"mine/drafts"}, lockPath, false); err != nil {
+		t.Fatalf("install failed: %v", err)
+	}
+
+	// Older releases served dir sources through git and recorded
+	// 0.0.0+git.<hash> versions.
+	const gitVersion = "0.0.0+git.abc1234"
+	st, err := store.LoadState(svc.StateRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range st.Installed {
+		st.Installed[i].ResolvedVersion = gitVersion
+	}
+	if err := store.SaveState(svc.StateRoot, st); err != nil {
+		t.Fatal(err)
+	}
+	lock, err := store.LoadLockfile(lockPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range lock.Skills {
+		lock.Skills[i].ResolvedVersion = gitVersion
+	}
+	if err := store.SaveLockfile(lockPath, lock); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := svc.SyncRun(ctx, lockPath, false, false, false, 0)
+	if err != nil {
+		t.Fatalf("sync failed: %v", err)
+	}
+	if len(report.UpgradedSkills) != 1 || report.UpgradedSkills[0] != "mine/drafts" {
+		t.Fatalf("expected the git-versioned skill upgraded, got %v", report.UpgradedSkills)
+	}
+	d, err := svc.SkillShow("mine/drafts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(d.ResolvedVersion, "0.0.0+dir.") {
+		t.Fatalf("expected a content-hash dir version, got %s", d.ResolvedVersion)
+	}
+}
+
 func TestServiceSyncHarvestDoctorPaths(t *testing.T) {
 	svc, openclawState := newFlowTestService(t)
 	ctx := context.Background()
 	lockPath := filepath.Join(t.TempDir(), "sync", "skills.lock")
 
-	report, err := svc.SyncRun(ctx, lockPath, false, false)
+	report, err := svc.SyncRun(ctx, lockPath, false, false, false, 0)
 	if err != nil {
 		t.Fatalf("sync run failed: %v", err)
 	}
@@ -163,7 +359,7 @@ func TestServiceSyncHarvestDoctorPaths(t *testing.T) {
 
 	originalSync := svc.Sync
 	svc.Sync = &syncsvc.Service{}
-	if _, err := svc.SyncRun(ctx, lockPath, false, false); err == nil {
+	if _, err := svc.SyncRun(ctx, lockPath, false, false, false, 0); err == nil {
 		t.Fatalf("expected sync setup error when dependencies are missing")
 	}
 	svc.Sync = originalSync
@@ -260,3 +456,151 @@ func newFlowTestService(t *testing.T) (*Service, string) {
 	}
 	return svc, openclawState
 }
+
+func TestUpgradeLockfileLeavesInstallsUntouched(t *testing.T) {
+	svc, _ := newFlowTestService(t)
+	ctx := context.Background()
+	lockPath := filepath.Join(t.TempDir(), "skills.lock")
+	if _, err := svc.Install(ctx, []string{"local/forms"}, lockPath, false); err != nil {
+		t.Fatalf("install failed: %v", err)
+	}
+	lock, err := store.LoadLockfile(lockPath)
+	if err != nil {
+		t.Fatalf("load lockfile failed: %v", err)
+	}
+	current := lock.Skills[0].ResolvedVersion
+	lock.Skills[0].ResolvedVersion = "0.0.1"
+	lock.Skills[0].Checksum = "sha256:old"
+	if err := store.SaveLockfile(lockPath, lock); err != nil {
+		t.Fatalf("save lockfile failed: %v", err)
+	}
+	before, err := store.LoadState(svc.StateRoot)
+	if err != nil {
+		t.Fatalf("load state failed: %v", err)
+	}
+
+	changes, _, err := svc.UpgradeLockfile(ctx, nil, lockPath)
+	if err != nil {
+		t.Fatalf("upgrade lockfile failed: %v", err)
+	}
+	if len(changes) != 1 || changes[0].SkillRef != "local/forms" || changes[0].From != "0.0.1" || changes[0].To != current {
+		t.Fatalf("unexpected lock changes: %+v", changes)
+	}
+	lock, err = store.LoadLockfile(lockPath)
+	if err != nil {
+		t.Fatalf("reload lockfile failed: %v", err)
+	}
+	if lock.Skills[0].ResolvedVersion != current || lock.Skills[0].Checksum == "sha256:old" {
+		t.Fatalf("expected lockfile rewritten, got %+v", lock.Skills[0])
+	}
+	after, err := store.LoadState(svc.StateRoot)
+	if err != nil {
+		t.Fatalf("reload state failed: %v", err)
+	}
+	if len(after.Installed) != 1 || after.Installed[0].ResolvedVersion != before.Installed[0].ResolvedVersion || after.Installed[0].Checksum != before.Installed[0].Checksum {
+		t.Fatalf("expected installed state unchanged, got %+v", after.Installed)
+	}
+
+	changes, _, err = svc.UpgradeLockfile(ctx, nil, lockPath)
+	if err != nil || len(changes) != 0 {
+		t.Fatalf("expected no changes on second run, got %+v (%v)", changes, err)
+	}
+}
+
+func TestInstallStreamEmitsPerSkillAndKeepsGoing(t *testing.T) {
+	svc, _ := newFlowTestService(t)
+	ctx := context.Background()
+	lockPath := filepath.Join(t.TempDir(), "skills.lock")
+
+	var events []InstallEvent
+	emit := func(ev InstallEvent) { events = append(events, ev) }
+	err := svc.InstallStream(ctx, []string{"local/forms", "local/missing", "local/demo"}, lockPath, false, true, true, emit)
+	if err == nil {
+		t.Fatal("expected streamed install to report the failed ref")
+	}
+	if len(events) != 3 {
+		t.Fatalf("expected three events, got %+v", events)
+	}
+	if events[0].Skill == nil || events[0].Skill.SkillRef != "local/forms" {
+		t.Fatalf("expected forms first, got %+v", events[0])
+	}
+	if events[1].Ref != "local/missing" || events[1].Skill != nil || events[1].Error == "" || events[1].Code == "" {
+		t.Fatalf("expected inline error for missing ref, got %+v", events[1])
+	}
+	if events[2].Skill == nil || events[2].Skill.SkillRef != "local/demo" {
+		t.Fatalf("expected demo after the error, got %+v", events[2])
+	}
+	installed, err := svc.ListInstalled()
+	if err != nil {
+		t.Fatalf("list installed failed: %v", err)
+	}
+	if len(installed) != 2 {
+		t.Fatalf("expected both good refs installed, got %+v", installed)
+	}
+
+	events = nil
+	if err := svc.InstallStream(ctx, []string{"local/missing", "local/forms"}, lockPath, false, true, false, emit); err == nil {
+		t.Fatal("expected streamed install to stop without keep-going")
+	}
+	if len(events) != 0 {
+		t.Fatalf("expected no events before the first failure, got %+v", events)
+	}
+}
+
+func TestSkillShowAggregatesInstallAndInjection(t *testing.T) {
+	svc, _ := newFlowTestService(t)
+	ctx := context.Background()
+	if _, err := svc.Install(ctx, []string{"local/forms"}, filepath.Join(t.TempDir(), "skills.lock"), false); err != nil {
+		t.Fatalf("install failed: %v", err)
+	}
+	if _, err := svc.Inject(ctx, "openclaw", []string{"local/forms"}); err != nil {
+		t.Fatalf("inject failed: %v", err)
+	}
+
+	d, err := svc.SkillShow("local/forms")
+	if err != nil {
+		t.Fatalf("skill show failed: %v", err)
+	}
+	if d.SkillRef != "local/forms" || d.ResolvedVersion == "" || d.Checksum == "" || d.Content == "" {
+		t.Fatalf("expected installed details, got %+v", d)
+	}
+	if len(d.InjectedInto) != 1 || d.InjectedInto[0] != "openclaw" {
+		t.Fatalf("expected injection into openclaw, got %v", d.InjectedInto)
+	}
+
+	if _, err := svc.SkillShow("local/demo"); audit.ErrorCode(err) != "SKILL_NOT_INSTALLED" {
+		t.Fatalf("expected SKILL_NOT_INSTALLED, got %v", err)
+	}
+}
+
+func TestReinstallRefsLeavesSkillWhoseChecksumDiffersFromLock(t *testing.T) {
+	svc, _ := newFlowTestService(t)
+	ctx := context.Background()
+	lockPath := svc.resolveLockPath("")
+	if _, err := svc.Install(ctx, []string{"local/forms"}, lockPath, false); err != nil {
+		t.Fatalf("install failed: %v", err)
+	}
+	lock, err := store.LoadLockfile(lockPath)
+	if err != nil {
+		t.Fatalf("load lockfile failed: %v", err)
+	}
+	lock.Skills[0].Checksum = "sha256:tampered"
+	if err := store.SaveLockfile(lockPath, lock); err != nil {
+		t.Fatalf("save lockfile failed: %v", err)
+	}
+	before, err := store.LoadState(svc.StateRoot)
+	if err != nil {
+		t.Fatalf("load state failed: %v", err)
+	}
+
+	if got := svc.reinstallRefs(ctx, []string{"local/forms"}); len(got) != 0 {
+		t.Fatalf("expected no reinstall against a mismatched lock, got %v", got)
+	}
+	after, err := store.LoadState(svc.StateRoot)
+	if err != nil {
+		t.Fatalf("reload state failed: %v", err)
+	}
+	if len(after.Installed) != 1 || after.Installed[0].InstalledAt != before.Installed[0].InstalledAt {
+		t.Fatalf("expected the installed skill left in place, got %+v", after.Installed)
+	}
+}
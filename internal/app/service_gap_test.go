@@ -61,7 +61,7 @@ func TestServiceSyncRunSaveConfigFailure(t *testing.T) {
 	lockPath := filepath.Join(t.TempDir(), "sync", "skills.lock")
 
 	svc.ConfigPath = "/dev/null/config.toml"
-	if _, err := svc.SyncRun(ctx, lockPath, false, false); err == nil {
+	if _, err := svc.SyncRun(ctx, lockPath, false, false, false, 0); err == nil {
 		t.Fatalf("expected sync run error when saving config fails")
 	}
 }
@@ -72,7 +72,49 @@ func TestServiceSyncRunDryRunSkipsConfigSave(t *testing.T) {
 	lockPath := filepath.Join(t.TempDir(), "sync", "skills.lock")
 
 	svc.ConfigPath = "/dev/null/config.toml"
-	if _, err := svc.SyncRun(ctx, lockPath, false, true); err != nil {
+	if _, err := svc.SyncRun(ctx, lockPath, false, true, false, 0); err != nil {
 		t.Fatalf("dry-run sync should not save config: %v", err)
 	}
 }
+
+func TestResolveLockPathUsesConfigDefault(t *testing.T) {
+	svc, _ := newFlowTestService(t)
+	teamLock := filepath.Join(t.TempDir(), "team.lock")
+	svc.Config.Defaults.Lockfile = teamLock
+	if got := svc.resolveLockPath(""); got != teamLock {
+		t.Fatalf("expected config default %s, got %s", teamLock, got)
+	}
+	if got := svc.resolveLockPath("explicit.lock"); got != "explicit.lock" {
+		t.Fatalf("expected explicit lockfile to win, got %s", got)
+	}
+}
+
+func TestPlanUninstallReportsAgentsAndLeavesStateAlone(t *testing.T) {
+	svc, _ := newFlowTestService(t)
+	ctx := context.Background()
+	lockPath := filepath.Join(t.TempDir(), "skills.lock")
+	if _, err := svc.Install(ctx, []string{"local/forms"}, lockPath, false); err != nil {
+		t.Fatalf("install failed: %v", err)
+	}
+	if _, err := svc.Inject(ctx, "openclaw", nil); err != nil {
+		t.Fatalf("inject failed: %v", err)
+	}
+
+	plan, err := svc.PlanUninstall([]string{"local/forms", "local/forms@1.0.0", "local/demo"})
+	if err != nil {
+		t.Fatalf("plan failed: %v", err)
+	}
+	if len(plan.Remove) != 1 || plan.Remove[0] != "local/forms" {
+		t.Fatalf("expected local/forms to be removed once, got %v", plan.Remove)
+	}
+	if len(plan.NotInstalled) != 1 || plan.NotInstalled[0] != "local/demo" {
+		t.Fatalf("expected local/demo to be reported as not installed, got %v", plan.NotInstalled)
+	}
+	if len(plan.Agents) != 1 || plan.Agents[0].Agent != "openclaw" || plan.Agents[0].Skills[0] != "local/forms" {
+		t.Fatalf("expected openclaw to lose local/forms, got %+v", plan.Agents)
+	}
+	installed, err := svc.ListInstalled()
+	if err != nil || len(installed) != 1 {
+		t.Fatalf("planning must not uninstall, got %v, %v", installed, err)
+	}
+}
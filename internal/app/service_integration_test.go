@@ -62,7 +62,7 @@ func TestServiceInstallInjectSyncFlow(t *testing.T) {
 	if _, err := svc.SourceUpdate(context.Background(), "myhub"); err != nil {
 		t.Fatalf("source update failed: %v", err)
 	}
-	if _, err := svc.Search(context.Background(), "myhub", "forms"); err != nil {
+	if _, err := svc.Search(context.Background(), []string{"myhub"}, "forms"); err != nil {
 		t.Fatalf("search failed: %v", err)
 	}
 
@@ -89,7 +89,7 @@ func TestServiceInstallInjectSyncFlow(t *testing.T) {
 		t.Fatalf("expected inject result to be validated")
 	}
 
-	report, err := svc.SyncRun(context.Background(), lockPath, true, false)
+	report, err := svc.SyncRun(context.Background(), lockPath, true, false, false, 0)
 	if err != nil {
 		t.Fatalf("sync failed: %v", err)
 	}
@@ -162,7 +162,7 @@ func TestServiceInstallInjectGitSourceEndToEnd(t *testing.T) {
 	}
 
 	// Step 2: Search
-	results, err := svc.Search(ctx, "test", "docx")
+	results, err := svc.Search(ctx, []string{"test"}, "docx")
 	if err != nil {
 		t.Fatalf("search failed: %v", err)
 	}
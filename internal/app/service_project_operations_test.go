@@ -112,7 +112,7 @@ func setupProjectWithMultipleSkills(t *testing.T) (*Service, string) {
 func TestProjectUpgradeNoChanges(t *testing.T) {
 	svc, projectDir := setupProjectWithSkill(t, "review")
 
-	upgraded, err := svc.Upgrade(context.Background(), nil, "", false)
+	upgraded, _, err := svc.Upgrade(context.Background(), nil, "", false)
 	if err != nil {
 		t.Fatalf("upgrade: %v", err)
 	}
@@ -133,7 +133,7 @@ func TestProjectUpgradeAllInstalled(t *testing.T) {
 	svc, _ := setupProjectWithMultipleSkills(t)
 
 	// No version change → no upgrades
-	upgraded, err := svc.Upgrade(context.Background(), nil, "", false)
+	upgraded, _, err := svc.Upgrade(context.Background(), nil, "", false)
 	if err != nil {
 		t.Fatalf("upgrade: %v", err)
 	}
@@ -146,7 +146,7 @@ func TestProjectUpgradeSpecificRef(t *testing.T) {
 	svc, projectDir := setupProjectWithMultipleSkills(t)
 
 	// Upgrade only alpha — should succeed with no changes (same version)
-	upgraded, err := svc.Upgrade(context.Background(), []string{"testrepo/alpha"}, "", false)
+	upgraded, _, err := svc.Upgrade(context.Background(), []string{"testrepo/alpha"}, "", false)
 	if err != nil {
 		t.Fatalf("upgrade: %v", err)
 	}
@@ -184,7 +184,7 @@ func TestProjectUpgradeNoInstalled(t *testing.T) {
 		t.Fatalf("new service: %v", err)
 	}
 
-	upgraded, err := svc.Upgrade(context.Background(), nil, "", false)
+	upgraded, _, err := svc.Upgrade(context.Background(), nil, "", false)
 	if err != nil {
 		t.Fatalf("upgrade with no installed: %v", err)
 	}
@@ -354,7 +354,7 @@ func TestProjectRemovePreservesOther(t *testing.T) {
 func TestProjectSyncNoUpgrades(t *testing.T) {
 	svc, _ := setupProjectWithSkill(t, "review")
 
-	report, err := svc.SyncRun(context.Background(), "", false, false)
+	report, err := svc.SyncRun(context.Background(), "", false, false, false, 0)
 	if err != nil {
 		t.Fatalf("sync: %v", err)
 	}
@@ -374,7 +374,7 @@ func TestProjectSyncDryRun(t *testing.T) {
 		t.Fatalf("load state before: %v", err)
 	}
 
-	report, err := svc.SyncRun(context.Background(), "", false, true)
+	report, err := svc.SyncRun(context.Background(), "", false, true, false, 0)
 	if err != nil {
 		t.Fatalf("sync dry-run: %v", err)
 	}
@@ -402,7 +402,7 @@ func TestProjectSyncReinjection(t *testing.T) {
 	}
 
 	// Sync should attempt reinjection
-	report, err := svc.SyncRun(context.Background(), "", false, false)
+	report, err := svc.SyncRun(context.Background(), "", false, false, false, 0)
 	if err != nil {
 		t.Fatalf("sync: %v", err)
 	}
@@ -48,6 +48,47 @@ func TestInitProject(t *testing.T) {
 	}
 }
 
+func TestSetupProjectSeedsSourcesAndAgentsOnce(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("OPENCLAW_STATE_DIR", filepath.Join(home, "no-openclaw"))
+	if err := os.MkdirAll(filepath.Join(home, ".claude"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	svc, err := New(Options{ConfigPath: filepath.Join(home, ".skillpm", "config.toml")})
+	if err != nil {
+		t.Fatalf("new service failed: %v", err)
+	}
+	projectDir := filepath.Join(t.TempDir(), "myproject")
+	if err := os.MkdirAll(projectDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	setup, err := svc.SetupProject(projectDir)
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	if !setup.Created || len(setup.Sources) != 2 || len(setup.Adapters) != 1 || setup.Adapters[0] != "claude" {
+		t.Fatalf("unexpected setup: %+v", setup)
+	}
+	m, err := config.LoadProjectManifest(projectDir)
+	if err != nil {
+		t.Fatalf("load manifest failed: %v", err)
+	}
+	if len(m.Sources) != 2 || len(m.Adapters) != 1 || m.Adapters[0].Scope != "project" || !m.Adapters[0].Enabled {
+		t.Fatalf("unexpected manifest: %+v", m)
+	}
+
+	again, err := svc.SetupProject(projectDir)
+	if err != nil {
+		t.Fatalf("second setup failed: %v", err)
+	}
+	if again.Created || len(again.Sources) != 0 || len(again.Adapters) != 0 {
+		t.Fatalf("expected second setup to change nothing, got %+v", again)
+	}
+}
+
 func TestProjectScopeAutoDetection(t *testing.T) {
 	home := t.TempDir()
 	t.Setenv("HOME", home)
@@ -172,7 +213,7 @@ func TestProjectInstallAndUninstall(t *testing.T) {
 	}
 
 	// Uninstall
-	removed, err := svc.Uninstall(context.Background(), []string{"testrepo/review"}, "")
+	removed, err := svc.Uninstall(context.Background(), []string{"testrepo/review"}, "", false)
 	if err != nil {
 		t.Fatalf("project uninstall failed: %v", err)
 	}
@@ -314,3 +355,54 @@ func TestListInstalled(t *testing.T) {
 		t.Fatalf("got skill ref %q, want testrepo/forms", list[0].SkillRef)
 	}
 }
+
+func TestProjectInstallWithoutManifest(t *testing.T) {
+	home := t.TempDir()
+	openclawState := filepath.Join(home, "openclaw-state")
+	t.Setenv("HOME", home)
+	t.Setenv("OPENCLAW_STATE_DIR", openclawState)
+	t.Setenv("OPENCLAW_CONFIG_PATH", filepath.Join(home, "openclaw-config.toml"))
+	if err := os.MkdirAll(openclawState, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	repoURL := setupBareRepo(t, map[string]map[string]string{
+		"review": {"SKILL.md": "# review\nCode review skill"},
+	})
+	projectDir := filepath.Join(t.TempDir(), "myproject")
+	if err := os.MkdirAll(projectDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := config.InitProject(projectDir); err != nil {
+		t.Fatal(err)
+	}
+	svc, err := New(Options{
+		ConfigPath:  filepath.Join(home, ".skillpm", "config.toml"),
+		Scope:       config.ScopeProject,
+		ProjectRoot: projectDir,
+	})
+	if err != nil {
+		t.Fatalf("new service with project scope failed: %v", err)
+	}
+	if _, err := svc.SourceAdd("testrepo", repoURL, "", "", ""); err != nil {
+		t.Fatalf("source add failed: %v", err)
+	}
+
+	if _, err := svc.InstallWithoutManifest(context.Background(), []string{"testrepo/review"}, "", false); err != nil {
+		t.Fatalf("scratch install failed: %v", err)
+	}
+	projectState, err := store.LoadState(config.ProjectStateRoot(projectDir))
+	if err != nil {
+		t.Fatalf("load project state failed: %v", err)
+	}
+	if len(projectState.Installed) != 1 {
+		t.Fatalf("expected 1 installed in project state, got %d", len(projectState.Installed))
+	}
+	manifest, err := config.LoadProjectManifest(projectDir)
+	if err != nil {
+		t.Fatalf("load manifest failed: %v", err)
+	}
+	if len(manifest.Skills) != 0 {
+		t.Fatalf("expected manifest untouched, got %+v", manifest.Skills)
+	}
+}
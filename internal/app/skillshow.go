@@ -0,0 +1,108 @@
+package app
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"skillpm/internal/resolver"
+	storepkg "skillpm/internal/store"
+)
+
+// SkillDetails is everything recorded about one installed skill: its
+// provenance, installed content and the agents it is injected into.
+type SkillDetails struct {
+	Provenance
+	Dir          string   `json:"dir"`
+	Content      string   `json:"content"`
+	Files        []string `json:"files"`
+	Deps         []string `json:"deps,omitempty"`
+	Deprecated   bool     `json:"deprecated,omitempty"`
+	SupersededBy string   `json:"supersededBy,omitempty"`
+	Platform     string   `json:"platform,omitempty"`
+	InjectedInto []string `json:"injectedInto"`
+}
+
+// SkillShow returns the details of an installed skill. Refs that are not
+// installed fail with SKILL_NOT_INSTALLED.
+func (s *Service) SkillShow(ref string) (SkillDetails, error) {
+	parsed, err := resolver.ParseRef(ref)
+	if err != nil {
+		return SkillDetails{}, err
+	}
+	skillRef := parsed.Source + "/" + parsed.Skill
+	st, err := storepkg.LoadState(s.StateRoot)
+	if err != nil {
+		return SkillDetails{}, err
+	}
+	var rec *storepkg.InstalledSkill
+	for i := range st.Installed {
+		if st.Installed[i].SkillRef == skillRef {
+			rec = &st.Installed[i]
+			break
+		}
+	}
+	if rec == nil {
+		return SkillDetails{}, fmt.Errorf("SKILL_NOT_INSTALLED: %s is not installed", skillRef)
+	}
+	prov, err := s.Provenance(skillRef)
+	if err != nil {
+		return SkillDetails{}, err
+	}
+	d := SkillDetails{
+		Provenance:   prov,
+		Files:        []string{},
+		Deps:         rec.Deps,
+		Deprecated:   rec.Deprecated,
+		SupersededBy: rec.SupersededBy,
+		Platform:     rec.Platform,
+		InjectedInto: []string{},
+	}
+	scope := string(s.Scope)
+	for _, inj := range st.Injections {
+		if inj.EffectiveScope(scope) != scope {
+			continue
+		}
+		for _, injected := range inj.Skills {
+			if injected == skillRef {
+				d.InjectedInto = append(d.InjectedInto, inj.Agent)
+				break
+			}
+		}
+	}
+	sort.Strings(d.InjectedInto)
+
+	d.Dir = storepkg.FindInstalledDir(s.StateRoot, skillRef)
+	if d.Dir == "" {
+		return SkillDetails{}, fmt.Errorf("SKILL_NOT_INSTALLED: %s is recorded in state but has no installed directory; run 'skillpm doctor'", skillRef)
+	}
+	content, err := os.ReadFile(filepath.Join(d.Dir, "SKILL.md"))
+	if err != nil {
+		return SkillDetails{}, fmt.Errorf("SKILL_READ: %w", err)
+	}
+	d.Content = string(content)
+	err = filepath.WalkDir(d.Dir, func(path string, entry fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(d.Dir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel != "SKILL.md" && rel != "metadata.toml" {
+			d.Files = append(d.Files, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return SkillDetails{}, fmt.Errorf("SKILL_READ: %w", err)
+	}
+	sort.Strings(d.Files)
+	return d, nil
+}
@@ -0,0 +1,136 @@
+package app
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"skillpm/internal/source"
+	storepkg "skillpm/internal/store"
+)
+
+// StoreIssue is one inconsistency found by StoreCheck.
+type StoreIssue struct {
+	Check   string `json:"check"`
+	Ref     string `json:"ref,omitempty"`
+	Path    string `json:"path,omitempty"`
+	Message string `json:"message"`
+}
+
+// StoreCheckReport is the result of a read-only store audit. Checked lists
+// the checks that ran; a state file that fails to parse stops the checks
+// that depend on it.
+type StoreCheckReport struct {
+	Consistent bool         `json:"consistent"`
+	Checked    []string     `json:"checked"`
+	Issues     []StoreIssue `json:"issues"`
+}
+
+// StoreCheck verifies that the on-disk store is internally consistent: the
+// state and lockfile parse, installed directories and state records match
+// one to one, installed content matches its recorded checksum (skipped for
+// installs that left out other platforms' files), and every injected skill
+// is installed. Nothing is repaired; see doctor for that.
+func (s *Service) StoreCheck(lockPath string) StoreCheckReport {
+	report := StoreCheckReport{Checked: []string{}, Issues: []StoreIssue{}}
+	add := func(check, ref, path, format string, args ...any) {
+		report.Issues = append(report.Issues, StoreIssue{Check: check, Ref: ref, Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	report.Checked = append(report.Checked, "state", "lockfile")
+	st, stErr := storepkg.LoadState(s.StateRoot)
+	if stErr != nil {
+		add("state", "", storepkg.StatePath(s.StateRoot), "%v", stErr)
+	}
+	lockPath = s.resolveLockPath(lockPath)
+	if _, err := storepkg.LoadLockfile(lockPath); err != nil {
+		add("lockfile", "", lockPath, "%v", err)
+	}
+	if stErr != nil {
+		return report
+	}
+
+	report.Checked = append(report.Checked, "installed-dirs", "checksums", "injections")
+	referenced := map[string]struct{}{}
+	installed := map[string]struct{}{}
+	for _, rec := range st.Installed {
+		installed[rec.SkillRef] = struct{}{}
+		for _, name := range storepkg.InstalledDirNames(rec.SkillRef, rec.ResolvedVersion) {
+			referenced[name] = struct{}{}
+		}
+		dir := storepkg.FindInstalledDir(s.StateRoot, rec.SkillRef)
+		if dir == "" {
+			add("installed-dirs", rec.SkillRef, "", "recorded in state but has no installed directory")
+			continue
+		}
+		if rec.Checksum == "" || rec.Platform != "" {
+			continue
+		}
+		sum, err := installedChecksum(dir)
+		if err != nil {
+			add("checksums", rec.SkillRef, dir, "cannot read installed content: %v", err)
+		} else if sum != rec.Checksum {
+			add("checksums", rec.SkillRef, dir, "content hashes to %s, state records %s", sum, rec.Checksum)
+		}
+	}
+	entries, err := os.ReadDir(storepkg.InstalledRoot(s.StateRoot))
+	if err != nil && !os.IsNotExist(err) {
+		add("installed-dirs", "", storepkg.InstalledRoot(s.StateRoot), "%v", err)
+	}
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		if _, ok := referenced[e.Name()]; !ok {
+			add("installed-dirs", "", filepath.Join(storepkg.InstalledRoot(s.StateRoot), e.Name()), "directory has no state record")
+		}
+	}
+	for _, inj := range st.Injections {
+		for _, ref := range inj.Skills {
+			if _, ok := installed[ref]; !ok {
+				add("injections", ref, "", "injected into %s but not installed", inj.Agent)
+			}
+		}
+	}
+
+	report.Consistent = len(report.Issues) == 0
+	return report
+}
+
+// installedChecksum hashes an installed skill directory the way sources
+// checksum a resolved skill: SKILL.md plus every ancillary file, leaving out
+// the metadata.toml the installer adds.
+func installedChecksum(dir string) (string, error) {
+	content, err := os.ReadFile(filepath.Join(dir, "SKILL.md"))
+	if err != nil {
+		return "", err
+	}
+	files := map[string]string{}
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel == "SKILL.md" || rel == "metadata.toml" {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		files[rel] = string(data)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return source.ComputeChecksum(content, files), nil
+}
@@ -0,0 +1,58 @@
+package app
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	storepkg "skillpm/internal/store"
+)
+
+func TestStoreCheckReportsInconsistencies(t *testing.T) {
+	svc, _ := newFlowTestService(t)
+	ctx := context.Background()
+	lockPath := filepath.Join(t.TempDir(), "skills.lock")
+	if _, err := svc.Install(ctx, []string{"local/forms"}, lockPath, false); err != nil {
+		t.Fatalf("install failed: %v", err)
+	}
+	if report := svc.StoreCheck(lockPath); !report.Consistent {
+		t.Fatalf("expected a fresh install to be consistent, got %+v", report.Issues)
+	}
+
+	dir := storepkg.FindInstalledDir(svc.StateRoot, "local/forms")
+	if err := os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte("# tampered\n"), 0o644); err != nil {
+		t.Fatalf("tamper failed: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(storepkg.InstalledRoot(svc.StateRoot), "stray@1.0.0"), 0o755); err != nil {
+		t.Fatalf("mkdir stray failed: %v", err)
+	}
+	st, err := storepkg.LoadState(svc.StateRoot)
+	if err != nil {
+		t.Fatalf("load state failed: %v", err)
+	}
+	st.Injections = append(st.Injections, storepkg.InjectionState{Agent: "claude", Skills: []string{"local/forms", "local/ghost"}})
+	if err := storepkg.SaveState(svc.StateRoot, st); err != nil {
+		t.Fatalf("save state failed: %v", err)
+	}
+
+	report := svc.StoreCheck(lockPath)
+	var checks []string
+	for _, issue := range report.Issues {
+		checks = append(checks, issue.Check+":"+issue.Ref)
+	}
+	sort.Strings(checks)
+	if report.Consistent || strings.Join(checks, ",") != "checksums:local/forms,injections:local/ghost,installed-dirs:" {
+		t.Fatalf("unexpected issues: %+v", report.Issues)
+	}
+
+	if err := os.WriteFile(storepkg.StatePath(svc.StateRoot), []byte("version = ["), 0o644); err != nil {
+		t.Fatalf("corrupt state failed: %v", err)
+	}
+	report = svc.StoreCheck(lockPath)
+	if len(report.Issues) != 1 || report.Issues[0].Check != "state" || strings.Join(report.Checked, ",") != "state,lockfile" {
+		t.Fatalf("expected only a state issue, got %+v", report)
+	}
+}
@@ -0,0 +1,70 @@
+package app
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"skillpm/internal/fsutil"
+	storepkg "skillpm/internal/store"
+)
+
+// SyncHistoryEntry is one recorded sync run: when it ran and the JSON
+// summary `sync --json` printed for it.
+type SyncHistoryEntry struct {
+	RecordedAt time.Time       `json:"recordedAt"`
+	Summary    json.RawMessage `json:"summary"`
+}
+
+var syncHistoryMu sync.Mutex
+
+// RecordSyncHistory appends summary to the sync history when
+// sync.history is enabled, and does nothing otherwise.
+func (s *Service) RecordSyncHistory(summary any) error {
+	if !s.Config.Sync.History {
+		return nil
+	}
+	blob, err := json.Marshal(summary)
+	if err != nil {
+		return fmt.Errorf("SYNC_HISTORY: %w", err)
+	}
+	entry := SyncHistoryEntry{RecordedAt: time.Now().UTC(), Summary: blob}
+	if err := fsutil.AppendJSONL(storepkg.SyncHistoryPath(s.StateRoot), &syncHistoryMu, entry); err != nil {
+		return fmt.Errorf("SYNC_HISTORY: %w", err)
+	}
+	return nil
+}
+
+// SyncHistory returns recorded sync runs at or after since, oldest first.
+// A zero since returns every entry. Unreadable lines are skipped.
+func (s *Service) SyncHistory(since time.Time) ([]SyncHistoryEntry, error) {
+	f, err := os.Open(storepkg.SyncHistoryPath(s.StateRoot))
+	if errors.Is(err, os.ErrNotExist) {
+		return []SyncHistoryEntry{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("SYNC_HISTORY: %w", err)
+	}
+	defer f.Close()
+	out := []SyncHistoryEntry{}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16<<20)
+	for scanner.Scan() {
+		var entry SyncHistoryEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		if entry.RecordedAt.Before(since) {
+			continue
+		}
+		out = append(out, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("SYNC_HISTORY: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,90 @@
+package app
+
+import (
+	"sort"
+
+	"skillpm/internal/config"
+	"skillpm/internal/resolver"
+	storepkg "skillpm/internal/store"
+)
+
+// UninstallPlan is everything an uninstall of Refs would change. Dependents
+// are installed skills left out of the uninstall that require one of the
+// removed skills, mapped to the skills they require.
+type UninstallPlan struct {
+	Refs         []string            `json:"refs"`
+	Remove       []string            `json:"remove"`
+	NotInstalled []string            `json:"notInstalled,omitempty"`
+	Agents       []UninstallAgent    `json:"agents"`
+	Manifest     []string            `json:"manifest,omitempty"`
+	Dependents   map[string][]string `json:"dependents,omitempty"`
+}
+
+// UninstallAgent is an agent that loses injected skills in an uninstall.
+type UninstallAgent struct {
+	Agent  string   `json:"agent"`
+	Scope  string   `json:"scope,omitempty"`
+	Skills []string `json:"skills"`
+}
+
+// PlanUninstall reports what Uninstall would do for refs without changing
+// anything.
+func (s *Service) PlanUninstall(refs []string) (UninstallPlan, error) {
+	plan := UninstallPlan{Refs: []string{}, Remove: []string{}, Agents: []UninstallAgent{}}
+	seen := map[string]struct{}{}
+	for _, raw := range refs {
+		parsed, err := resolver.ParseRef(raw)
+		if err != nil {
+			return UninstallPlan{}, err
+		}
+		ref := parsed.Source + "/" + parsed.Skill
+		if _, ok := seen[ref]; ok {
+			continue
+		}
+		seen[ref] = struct{}{}
+		plan.Refs = append(plan.Refs, ref)
+	}
+	st, err := storepkg.LoadState(s.StateRoot)
+	if err != nil {
+		return UninstallPlan{}, err
+	}
+	installed := map[string]struct{}{}
+	for _, rec := range st.Installed {
+		installed[rec.SkillRef] = struct{}{}
+	}
+	removing := map[string]struct{}{}
+	for _, ref := range plan.Refs {
+		if _, ok := installed[ref]; !ok {
+			plan.NotInstalled = append(plan.NotInstalled, ref)
+			continue
+		}
+		removing[ref] = struct{}{}
+		plan.Remove = append(plan.Remove, ref)
+	}
+	for _, inj := range st.Injections {
+		agent := UninstallAgent{Agent: inj.Agent, Scope: inj.EffectiveScope(string(s.Scope))}
+		for _, ref := range inj.Skills {
+			if _, ok := removing[ref]; ok {
+				agent.Skills = append(agent.Skills, ref)
+			}
+		}
+		if len(agent.Skills) > 0 {
+			sort.Strings(agent.Skills)
+			plan.Agents = append(plan.Agents, agent)
+		}
+	}
+	sort.Slice(plan.Agents, func(i, j int) bool { return plan.Agents[i].Agent < plan.Agents[j].Agent })
+	if s.Scope == config.ScopeProject && s.Manifest != nil {
+		for _, skill := range s.Manifest.Skills {
+			if _, ok := removing[skill.Ref]; ok {
+				plan.Manifest = append(plan.Manifest, skill.Ref)
+			}
+		}
+	}
+	if len(plan.Remove) > 0 {
+		if plan.Dependents, err = s.Dependents(plan.Remove); err != nil {
+			return UninstallPlan{}, err
+		}
+	}
+	return plan, nil
+}
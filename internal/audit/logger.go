@@ -1,6 +1,7 @@
 package audit
 
 import (
+	"strings"
 	"sync"
 	"time"
 
@@ -33,3 +34,21 @@ func (l *Logger) Log(ev Event) error {
 	ev.Timestamp = time.Now().UTC().Format(time.RFC3339Nano)
 	return fsutil.AppendJSONL(l.path, &l.mu, ev)
 }
+
+// ErrorCode extracts the leading CODE prefix from errors formatted as
+// "CODE: message". It returns "" when err has no such prefix.
+func ErrorCode(err error) string {
+	if err == nil {
+		return ""
+	}
+	code, _, ok := strings.Cut(err.Error(), ":")
+	if !ok || code == "" {
+		return ""
+	}
+	for _, r := range code {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') && r != '_' {
+			return ""
+		}
+	}
+	return code
+}
@@ -2,6 +2,7 @@ package audit
 
 import (
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
@@ -109,3 +110,20 @@ func TestLogOpenFileFailure(t *testing.T) {
 		t.Fatalf("expected open file failure")
 	}
 }
+
+func TestErrorCode(t *testing.T) {
+	cases := map[string]string{
+		"SEC_SCAN_CRITICAL: blocked": "SEC_SCAN_CRITICAL",
+		"ADP_INJECT: no skills":      "ADP_INJECT",
+		"plain failure":              "",
+		"open /tmp/x: no such file":  "",
+	}
+	for msg, want := range cases {
+		if got := ErrorCode(errors.New(msg)); got != want {
+			t.Fatalf("ErrorCode(%q) = %q, want %q", msg, got, want)
+		}
+	}
+	if ErrorCode(nil) != "" {
+		t.Fatal("expected empty code for nil error")
+	}
+}
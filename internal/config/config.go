@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/pelletier/go-toml/v2"
 	"skillpm/internal/fsutil"
@@ -38,15 +39,30 @@ func Load(path string) (Config, error) {
 	}
 	var cfg Config
 	if err := toml.Unmarshal(data, &cfg); err != nil {
-		return Config{}, fmt.Errorf("DOC_CONFIG_PARSE: %w", err)
+		return Config{}, parseError(path, err)
 	}
 	cfg = Normalize(cfg)
+	cfg = anchorDirSources(cfg, filepath.Dir(path))
 	if err := Validate(cfg); err != nil {
 		return Config{}, err
 	}
 	return cfg, nil
 }
 
+// anchorDirSources makes relative dir source paths absolute against base,
+// the directory of the config file, so they name the same directory
+// wherever skillpm runs. Configs written before 'source add' stored
+// absolute paths are migrated this way on their next save.
+func anchorDirSources(cfg Config, base string) Config {
+	for i, src := range cfg.Sources {
+		if src.Kind != "dir" || src.URL == "" || strings.HasPrefix(src.URL, "~") || filepath.IsAbs(src.URL) {
+			continue
+		}
+		cfg.Sources[i].URL = filepath.Join(base, src.URL)
+	}
+	return cfg
+}
+
 func Save(path string, cfg Config) error {
 	if path == "" {
 		path = DefaultConfigPath()
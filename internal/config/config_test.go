@@ -3,7 +3,10 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestDefaultConfigIsValid(t *testing.T) {
@@ -39,6 +42,29 @@ func TestEnsureCreatesAndLoadsConfig(t *testing.T) {
 	}
 }
 
+func TestLoadAnchorsRelativeDirSources(t *testing.T) {
+	tmp := t.TempDir()
+	path := filepath.Join(tmp, "config.toml")
+	cfg := DefaultConfig()
+	cfg.Sources = append(cfg.Sources,
+		SourceConfig{Name: "rel", Kind: "dir", URL: "skills/mine", TrustTier: "review"},
+		SourceConfig{Name: "home", Kind: "dir", URL: "~/skills", TrustTier: "review"},
+	)
+	if err := Save(path, cfg); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if src, _ := FindSource(loaded, "rel"); src.URL != filepath.Join(tmp, "skills", "mine") {
+		t.Fatalf("expected the relative dir anchored at the config dir, got %q", src.URL)
+	}
+	if src, _ := FindSource(loaded, "home"); src.URL != "~/skills" {
+		t.Fatalf("expected ~ paths left alone, got %q", src.URL)
+	}
+}
+
 func TestAddSourceRejectsDuplicate(t *testing.T) {
 	cfg := DefaultConfig()
 	err := AddSource(&cfg, SourceConfig{Name: "clawhub", Kind: "clawhub", Site: "https://clawhub.ai/", TrustTier: "review"})
@@ -46,3 +72,170 @@ func TestAddSourceRejectsDuplicate(t *testing.T) {
 		t.Fatalf("expected duplicate source error")
 	}
 }
+
+func TestFindDuplicateSourceNormalizesLocation(t *testing.T) {
+	cfg := Config{Sources: []SourceConfig{
+		{Name: "anthropic", Kind: "git", URL: "https://github.com/anthropics/skills.git"},
+		{Name: "hub", Kind: "clawhub", Site: "https://clawhub.ai/", Registry: "https://clawhub.ai/"},
+	}}
+	cases := []struct {
+		src  SourceConfig
+		want string
+	}{
+		{SourceConfig{Name: "mirror", Kind: "git", URL: "https://GitHub.com/anthropics/skills/"}, "anthropic"},
+		{SourceConfig{Name: "hub2", Kind: "clawhub", Registry: "https://clawhub.ai"}, "hub"},
+		{SourceConfig{Name: "anthropic", Kind: "git", URL: "https://github.com/anthropics/skills"}, ""},
+		{SourceConfig{Name: "other", Kind: "git", URL: "https://github.com/anthropics/skills-extra.git"}, ""},
+	}
+	for _, tc := range cases {
+		dup, ok := FindDuplicateSource(cfg, tc.src)
+		if got := map[bool]string{true: dup.Name, false: ""}[ok]; got != tc.want {
+			t.Fatalf("%s: expected duplicate %q, got %q", tc.src.URL+tc.src.Registry, tc.want, got)
+		}
+	}
+}
+
+func TestValidateRejectsUnknownPreferredTier(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Resolution.PreferTierOrder = []string{"trusted", "internal"}
+	err := Validate(cfg)
+	if err == nil || !strings.Contains(err.Error(), `invalid trust tier "internal"`) {
+		t.Fatalf("expected invalid tier error, got %v", err)
+	}
+	cfg.Resolution.PreferTierOrder = []string{"review", "trusted"}
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("expected valid order, got %v", err)
+	}
+}
+
+func TestValidateRejectsNonHTTPWebhook(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Notify.WebhookURL = "ftp://hooks.example.com/sync"
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "DOC_CONFIG_NOTIFY") {
+		t.Fatalf("expected notify error, got %v", err)
+	}
+	cfg.Notify.WebhookURL = "https://hooks.example.com/sync"
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("expected valid webhook, got %v", err)
+	}
+}
+
+func TestValidateDefaults(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Defaults.Lockfile = "team/skills.lock"
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "DOC_CONFIG_DEFAULTS: lockfile") {
+		t.Fatalf("expected relative lockfile error, got %v", err)
+	}
+	cfg.Defaults.Lockfile = "~/team/skills.lock"
+	cfg.Defaults.InstallMaxSeverity = "severe"
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), `unknown severity "severe"`) {
+		t.Fatalf("expected severity error, got %v", err)
+	}
+	cfg.Defaults.InstallMaxSeverity = "Medium"
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("expected valid defaults, got %v", err)
+	}
+}
+
+func TestResolveMaxDirNameLength(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Storage.MaxDirNameLength = 80
+	if got := ResolveMaxDirNameLength(cfg); got != 80 {
+		t.Fatalf("expected explicit 80, got %d", got)
+	}
+	cfg.Storage.MaxDirNameLength = -1
+	if got := ResolveMaxDirNameLength(cfg); got != 0 {
+		t.Fatalf("expected negative value to disable hashing, got %d", got)
+	}
+	cfg.Storage.MaxDirNameLength = 0
+	want := 0
+	if runtime.GOOS == "windows" {
+		want = windowsMaxDirNameLength
+	}
+	if got := ResolveMaxDirNameLength(cfg); got != want {
+		t.Fatalf("expected platform default %d, got %d", want, got)
+	}
+}
+
+func TestCheckReportsEveryIssueWithKey(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Sources[0].TrustTier = "bogus"
+	cfg.Sources[1].Kind = "ftp"
+	cfg.Sync.Interval = "daily"
+	cfg.Adapters = append(cfg.Adapters, AdapterConfig{Name: "notepad", Enabled: true, Scope: "global"})
+
+	issues := Check(cfg)
+	want := map[string]bool{
+		"sources[0].trust_tier": false,
+		"sources[1].kind":       false,
+		"sync.interval":         true,
+		"adapters[11].name":     true,
+	}
+	if len(issues) != len(want) {
+		t.Fatalf("expected %d issues, got %+v", len(want), issues)
+	}
+	for _, issue := range issues {
+		advisory, ok := want[issue.Key]
+		if !ok || advisory != issue.Advisory {
+			t.Fatalf("unexpected issue %+v", issue)
+		}
+	}
+
+	err := Validate(cfg)
+	if err == nil || !strings.HasPrefix(err.Error(), "SEC_CONFIG_TRUST:") {
+		t.Fatalf("expected first hard issue from Validate, got %v", err)
+	}
+}
+
+func TestCheckFileReportsUnknownKeysAndParsePosition(t *testing.T) {
+	tmp := t.TempDir()
+	path := filepath.Join(tmp, "config.toml")
+	if err := Save(path, DefaultConfig()); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if err := os.WriteFile(path, append([]byte("colour = 'blue'\n"), data...), 0o644); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	issues, err := CheckFile(path)
+	if err != nil {
+		t.Fatalf("check file failed: %v", err)
+	}
+	if len(issues) != 1 || issues[0].Key != "colour" || !issues[0].Advisory {
+		t.Fatalf("expected one unknown-key issue, got %+v", issues)
+	}
+
+	if err := os.WriteFile(path, []byte("version = 1\n[sync\n"), 0o644); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	_, err = Load(path)
+	if err == nil || !strings.Contains(err.Error(), "DOC_CONFIG_PARSE: "+path+":2:") {
+		t.Fatalf("expected positioned parse error, got %v", err)
+	}
+}
+
+func TestReviewDue(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	cases := []struct {
+		src  SourceConfig
+		want bool
+	}{
+		{SourceConfig{}, false},
+		{SourceConfig{ReviewInterval: "90d"}, true},
+		{SourceConfig{ReviewInterval: "90d", LastReviewed: "2026-01-01T00:00:00Z"}, false},
+		{SourceConfig{ReviewInterval: "720h", LastReviewed: "2026-01-01T00:00:00Z"}, true},
+	}
+	for _, tc := range cases {
+		if got := ReviewDue(tc.src, now); got != tc.want {
+			t.Fatalf("ReviewDue(%+v) = %v, want %v", tc.src, got, tc.want)
+		}
+	}
+	cfg := DefaultConfig()
+	cfg.Sources[0].ReviewInterval = "quarterly"
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "review interval") {
+		t.Fatalf("expected invalid review interval error, got %v", err)
+	}
+}
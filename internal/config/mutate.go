@@ -41,6 +41,38 @@ func FindSource(cfg Config, name string) (SourceConfig, bool) {
 	return SourceConfig{}, false
 }
 
+// FindDuplicateSource returns the configured source, other than one named
+// like src, that points at the same git URL, directory or clawhub registry.
+// Locations are compared case-insensitively and ignore a trailing "/" or
+// ".git".
+func FindDuplicateSource(cfg Config, src SourceConfig) (SourceConfig, bool) {
+	loc := sourceLocation(src)
+	if loc == "" {
+		return SourceConfig{}, false
+	}
+	for _, s := range cfg.Sources {
+		if s.Name != src.Name && sourceLocation(s) == loc {
+			return s, true
+		}
+	}
+	return SourceConfig{}, false
+}
+
+// sourceLocation normalizes where a source fetches skills from.
+func sourceLocation(src SourceConfig) string {
+	loc := src.URL
+	if src.Kind == "clawhub" {
+		loc = src.Registry
+		if loc == "" {
+			loc = src.Site
+		}
+	}
+	loc = strings.ToLower(strings.TrimSpace(loc))
+	loc = strings.TrimRight(loc, "/")
+	loc = strings.TrimSuffix(loc, ".git")
+	return strings.TrimRight(loc, "/")
+}
+
 func FindAdapter(cfg Config, name string) (AdapterConfig, bool) {
 	for _, a := range cfg.Adapters {
 		if a.Name == name {
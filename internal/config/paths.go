@@ -4,9 +4,18 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
+	"time"
 )
 
+// windowsMaxDirNameLength keeps installed paths well inside MAX_PATH.
+const windowsMaxDirNameLength = 64
+
+// defaultSearchCacheTTL is how long search results are reused when the
+// config does not set search.cache_ttl.
+const defaultSearchCacheTTL = 10 * time.Minute
+
 func DefaultConfigPath() string {
 	home, err := os.UserHomeDir()
 	if err != nil {
@@ -43,3 +52,31 @@ func ResolveStorageRoot(cfg Config) (string, error) {
 	}
 	return filepath.Clean(expanded), nil
 }
+
+// ResolveSearchCacheTTL returns how long cached search results stay fresh.
+// Unset or unparseable values fall back to the default.
+func ResolveSearchCacheTTL(cfg Config) time.Duration {
+	if cfg.Search.CacheTTL == "" {
+		return defaultSearchCacheTTL
+	}
+	ttl, err := time.ParseDuration(cfg.Search.CacheTTL)
+	if err != nil || ttl < 0 {
+		return defaultSearchCacheTTL
+	}
+	return ttl
+}
+
+// ResolveMaxDirNameLength returns the effective installed-dir name threshold.
+// Unset values default to a short limit on Windows and no limit elsewhere.
+func ResolveMaxDirNameLength(cfg Config) int {
+	switch {
+	case cfg.Storage.MaxDirNameLength < 0:
+		return 0
+	case cfg.Storage.MaxDirNameLength > 0:
+		return cfg.Storage.MaxDirNameLength
+	case runtime.GOOS == "windows":
+		return windowsMaxDirNameLength
+	default:
+		return 0
+	}
+}
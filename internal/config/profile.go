@@ -0,0 +1,145 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"skillpm/internal/fsutil"
+)
+
+// DefaultProfile names the config at DefaultConfigPath.
+const DefaultProfile = "default"
+
+var profileNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+// ProfilesDir holds one config file per named profile, next to the default
+// config.
+func ProfilesDir() string {
+	return filepath.Join(filepath.Dir(DefaultConfigPath()), "profiles")
+}
+
+// activeProfilePath records the name of the profile in use.
+func activeProfilePath() string {
+	return filepath.Join(filepath.Dir(DefaultConfigPath()), "active-profile")
+}
+
+// ProfilePath returns the config file of the named profile.
+func ProfilePath(name string) string {
+	if name == "" || name == DefaultProfile {
+		return DefaultConfigPath()
+	}
+	return filepath.Join(ProfilesDir(), name+".toml")
+}
+
+func checkProfileName(name string) error {
+	if !profileNamePattern.MatchString(name) {
+		return fmt.Errorf("DOC_CONFIG_PROFILE: invalid profile name %q (use letters, digits, '-' and '_')", name)
+	}
+	return nil
+}
+
+// ActiveProfile returns the profile in use, DefaultProfile when none was
+// selected. A name in the active-profile file that CreateProfile would
+// reject is an error, so the file cannot point outside the profiles dir.
+func ActiveProfile() (string, error) {
+	blob, err := os.ReadFile(activeProfilePath())
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return DefaultProfile, nil
+		}
+		return "", err
+	}
+	name := strings.TrimSpace(string(blob))
+	if name == "" {
+		return DefaultProfile, nil
+	}
+	if err := checkProfileName(name); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// ResolveConfigPath returns explicit when set, otherwise the config file of
+// the active profile. A named profile whose file is gone is an error rather
+// than a silently recreated default config.
+func ResolveConfigPath(explicit string) (string, error) {
+	if explicit != "" {
+		return explicit, nil
+	}
+	name, err := ActiveProfile()
+	if err != nil {
+		return "", err
+	}
+	path := ProfilePath(name)
+	if name != DefaultProfile {
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("DOC_CONFIG_PROFILE: active profile %q has no config at %s; run 'skillpm config profile use default'", name, path)
+		}
+	}
+	return path, nil
+}
+
+// ListProfiles returns the default profile followed by every named profile,
+// sorted.
+func ListProfiles() ([]string, error) {
+	entries, err := os.ReadDir(ProfilesDir())
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), ".toml")
+		if ok && !e.IsDir() && profileNamePattern.MatchString(name) && name != DefaultProfile {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return append([]string{DefaultProfile}, names...), nil
+}
+
+// CreateProfile writes cfg as the config of a new named profile.
+func CreateProfile(name string, cfg Config) (string, error) {
+	if err := checkProfileName(name); err != nil {
+		return "", err
+	}
+	if name == DefaultProfile {
+		return "", fmt.Errorf("DOC_CONFIG_PROFILE: %q is reserved for %s", DefaultProfile, DefaultConfigPath())
+	}
+	path := ProfilePath(name)
+	if _, err := os.Stat(path); err == nil {
+		return "", fmt.Errorf("DOC_CONFIG_PROFILE: profile %q already exists", name)
+	}
+	if err := Save(path, cfg); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// UseProfile makes name the active profile; DefaultProfile switches back
+// to DefaultConfigPath.
+func UseProfile(name string) error {
+	if name == DefaultProfile {
+		if err := os.Remove(activeProfilePath()); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	if err := checkProfileName(name); err != nil {
+		return err
+	}
+	if _, err := os.Stat(ProfilePath(name)); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("DOC_CONFIG_PROFILE: profile %q does not exist; create it with 'skillpm config profile create %s'", name, name)
+		}
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(activeProfilePath()), 0o755); err != nil {
+		return err
+	}
+	return fsutil.AtomicWrite(activeProfilePath(), []byte(name+"\n"), 0o644)
+}
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestProfilesCreateUseAndList(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	path, err := ResolveConfigPath("")
+	if err != nil || path != DefaultConfigPath() {
+		t.Fatalf("expected default config path without a profile, got %q err=%v", path, err)
+	}
+
+	work := DefaultConfig()
+	work.Sources = append(work.Sources, SourceConfig{Name: "work", Kind: "git", URL: "https://example.com/skills.git", TrustTier: "review"})
+	created, err := CreateProfile("work", work)
+	if err != nil {
+		t.Fatalf("create profile failed: %v", err)
+	}
+	if created != filepath.Join(home, ".skillpm", "profiles", "work.toml") {
+		t.Fatalf("unexpected profile path %q", created)
+	}
+	if _, err := CreateProfile("work", work); err == nil || !strings.HasPrefix(err.Error(), "DOC_CONFIG_PROFILE:") {
+		t.Fatalf("expected duplicate profile error, got %v", err)
+	}
+	if _, err := CreateProfile("../escape", work); err == nil {
+		t.Fatalf("expected invalid profile name to be rejected")
+	}
+	if err := UseProfile("missing"); err == nil || !strings.HasPrefix(err.Error(), "DOC_CONFIG_PROFILE:") {
+		t.Fatalf("expected missing profile error, got %v", err)
+	}
+
+	if err := UseProfile("work"); err != nil {
+		t.Fatalf("use profile failed: %v", err)
+	}
+	if active, _ := ActiveProfile(); active != "work" {
+		t.Fatalf("expected work active, got %q", active)
+	}
+	path, err = ResolveConfigPath("")
+	if err != nil || path != created {
+		t.Fatalf("expected active profile path, got %q err=%v", path, err)
+	}
+	if explicit, _ := ResolveConfigPath("/tmp/other.toml"); explicit != "/tmp/other.toml" {
+		t.Fatalf("expected explicit path to win, got %q", explicit)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("load profile failed: %v", err)
+	}
+	if _, ok := FindSource(cfg, "work"); !ok {
+		t.Fatalf("expected profile config to keep its sources")
+	}
+
+	names, err := ListProfiles()
+	if err != nil || strings.Join(names, ",") != "default,work" {
+		t.Fatalf("unexpected profiles %v err=%v", names, err)
+	}
+
+	if err := UseProfile(DefaultProfile); err != nil {
+		t.Fatalf("switch back failed: %v", err)
+	}
+	if path, _ := ResolveConfigPath(""); path != DefaultConfigPath() {
+		t.Fatalf("expected default path after switching back, got %q", path)
+	}
+}
+
+func TestActiveProfileRejectsInvalidName(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	if err := os.MkdirAll(filepath.Dir(activeProfilePath()), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(activeProfilePath(), []byte("../escape\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ActiveProfile(); err == nil || !strings.HasPrefix(err.Error(), "DOC_CONFIG_PROFILE:") {
+		t.Fatalf("expected an invalid active profile rejected, got %v", err)
+	}
+	if _, err := ResolveConfigPath(""); err == nil {
+		t.Fatal("expected config path resolution to fail on an invalid active profile")
+	}
+}
@@ -62,28 +62,15 @@ func FindProjectRoot(startDir string) (string, bool) {
 }
 
 // ResolveScope determines the effective scope based on an explicit flag and CWD.
-// If explicit is non-empty, it is validated and returned.
-// Otherwise, auto-detection walks up from cwd looking for a project manifest.
+// If explicit is non-empty, it is validated and returned. Otherwise the
+// nearest .skillpm/scope.lock pins the scope, and failing that auto-detection
+// walks up from cwd looking for a project manifest. See DecideScope.
 func ResolveScope(explicit string, cwd string) (Scope, string, error) {
-	switch Scope(explicit) {
-	case ScopeGlobal:
-		return ScopeGlobal, "", nil
-	case ScopeProject:
-		root, found := FindProjectRoot(cwd)
-		if !found {
-			return "", "", fmt.Errorf("PRJ_NO_MANIFEST: no project manifest found; run 'skillpm init' first")
-		}
-		return ScopeProject, root, nil
-	case "":
-		// auto-detect
-		root, found := FindProjectRoot(cwd)
-		if found {
-			return ScopeProject, root, nil
-		}
-		return ScopeGlobal, "", nil
-	default:
-		return "", "", fmt.Errorf("PRJ_INVALID_SCOPE: invalid scope %q; use 'global' or 'project'", explicit)
+	d, err := DecideScope(explicit, cwd)
+	if err != nil {
+		return "", "", err
 	}
+	return d.Scope, d.ProjectRoot, nil
 }
 
 // LoadProjectManifest loads .skillpm/skills.toml from the given project root.
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseReviewInterval parses a source review interval. Besides Go
+// durations it accepts whole days such as "90d".
+func ParseReviewInterval(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("invalid review interval %q", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("invalid review interval %q", s)
+	}
+	return d, nil
+}
+
+// ReviewDue reports whether src has a review interval and has not been
+// reviewed within it. Sources never reviewed are due immediately.
+func ReviewDue(src SourceConfig, now time.Time) bool {
+	if src.ReviewInterval == "" {
+		return false
+	}
+	interval, err := ParseReviewInterval(src.ReviewInterval)
+	if err != nil {
+		return false
+	}
+	last, err := time.Parse(time.RFC3339, src.LastReviewed)
+	if err != nil {
+		return true
+	}
+	return now.Sub(last) > interval
+}
+
+// SourcesDueForReview returns the names of sources whose review is due,
+// sorted as configured.
+func SourcesDueForReview(cfg Config, now time.Time) []string {
+	var due []string
+	for _, src := range cfg.Sources {
+		if ReviewDue(src, now) {
+			due = append(due, src.Name)
+		}
+	}
+	return due
+}
+
+// MarkSourceReviewed records that the named source was re-audited at now.
+func MarkSourceReviewed(cfg *Config, name string, now time.Time) (SourceConfig, error) {
+	src, ok := FindSource(*cfg, name)
+	if !ok {
+		return SourceConfig{}, fmt.Errorf("SRC_CONFIG_SOURCE: source %q not found", name)
+	}
+	src.LastReviewed = now.UTC().Format(time.RFC3339)
+	if err := ReplaceSource(cfg, src); err != nil {
+		return SourceConfig{}, err
+	}
+	return src, nil
+}
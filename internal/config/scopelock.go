@@ -0,0 +1,93 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+const scopeLockFile = "scope.lock"
+
+// ScopeLock pins the default scope for the directory tree below the
+// .skillpm directory that holds it.
+type ScopeLock struct {
+	Scope Scope `toml:"scope"`
+}
+
+// ScopeDecision is the effective scope and why it was chosen.
+type ScopeDecision struct {
+	Scope       Scope  `json:"scope"`
+	ProjectRoot string `json:"projectRoot,omitempty"`
+	Reason      string `json:"reason"`
+	LockPath    string `json:"lockPath,omitempty"`
+}
+
+// ScopeLockPath returns the path to scope.lock for a directory.
+func ScopeLockPath(dir string) string {
+	return filepath.Join(dir, projectDir, scopeLockFile)
+}
+
+// FindScopeLock walks up from startDir looking for .skillpm/scope.lock and
+// returns the path and contents of the nearest one. path is empty when
+// there is none.
+func FindScopeLock(startDir string) (string, ScopeLock, error) {
+	dir, err := filepath.Abs(startDir)
+	if err != nil {
+		return "", ScopeLock{}, nil
+	}
+	for i := 0; i < maxAncestorSearch; i++ {
+		path := ScopeLockPath(dir)
+		if data, err := os.ReadFile(path); err == nil {
+			var lock ScopeLock
+			if err := toml.Unmarshal(data, &lock); err != nil {
+				return "", ScopeLock{}, fmt.Errorf("PRJ_SCOPE_LOCK: %s: %w", path, err)
+			}
+			if lock.Scope != ScopeGlobal && lock.Scope != ScopeProject {
+				return "", ScopeLock{}, fmt.Errorf("PRJ_SCOPE_LOCK: %s: invalid scope %q; use 'global' or 'project'", path, lock.Scope)
+			}
+			return path, lock, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+	return "", ScopeLock{}, nil
+}
+
+// DecideScope resolves the effective scope like ResolveScope and records
+// the reason: an explicit --scope wins, then the nearest scope.lock, then
+// auto-detection of a project manifest.
+func DecideScope(explicit string, cwd string) (ScopeDecision, error) {
+	if explicit != "" {
+		if Scope(explicit) != ScopeGlobal && Scope(explicit) != ScopeProject {
+			return ScopeDecision{}, fmt.Errorf("PRJ_INVALID_SCOPE: invalid scope %q; use 'global' or 'project'", explicit)
+		}
+		return decide(Scope(explicit), cwd, "set by --scope", "")
+	}
+	lockPath, lock, err := FindScopeLock(cwd)
+	if err != nil {
+		return ScopeDecision{}, err
+	}
+	if lockPath != "" {
+		return decide(lock.Scope, cwd, "pinned by "+lockPath, lockPath)
+	}
+	if root, found := FindProjectRoot(cwd); found {
+		return ScopeDecision{Scope: ScopeProject, ProjectRoot: root, Reason: "auto-detected project manifest at " + ProjectManifestPath(root)}, nil
+	}
+	return ScopeDecision{Scope: ScopeGlobal, Reason: "auto-detected: no project manifest above " + cwd}, nil
+}
+
+func decide(scope Scope, cwd, reason, lockPath string) (ScopeDecision, error) {
+	if scope == ScopeGlobal {
+		return ScopeDecision{Scope: ScopeGlobal, Reason: reason, LockPath: lockPath}, nil
+	}
+	root, found := FindProjectRoot(cwd)
+	if !found {
+		return ScopeDecision{}, fmt.Errorf("PRJ_NO_MANIFEST: no project manifest found; run 'skillpm init' first")
+	}
+	return ScopeDecision{Scope: ScopeProject, ProjectRoot: root, Reason: reason, LockPath: lockPath}, nil
+}
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDecideScopeHonorsScopeLock(t *testing.T) {
+	repo := t.TempDir()
+	nested := filepath.Join(repo, "packages", "app")
+	for _, dir := range []string{filepath.Join(repo, ".skillpm"), filepath.Join(nested, ".skillpm")} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(ProjectManifestPath(nested), []byte("version = 1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := DecideScope("", nested)
+	if err != nil || d.Scope != ScopeProject || d.ProjectRoot != nested || !strings.Contains(d.Reason, "auto-detected") {
+		t.Fatalf("expected auto-detected project scope, got %+v (%v)", d, err)
+	}
+
+	lockPath := ScopeLockPath(repo)
+	if err := os.WriteFile(lockPath, []byte("scope = \"global\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	d, err = DecideScope("", nested)
+	if err != nil || d.Scope != ScopeGlobal || d.LockPath != lockPath || d.ProjectRoot != "" {
+		t.Fatalf("expected scope.lock to pin global, got %+v (%v)", d, err)
+	}
+	if scope, _, err := ResolveScope("", nested); err != nil || scope != ScopeGlobal {
+		t.Fatalf("expected ResolveScope to honor scope.lock, got %q (%v)", scope, err)
+	}
+
+	d, err = DecideScope("project", nested)
+	if err != nil || d.Scope != ScopeProject || d.Reason != "set by --scope" {
+		t.Fatalf("expected --scope to override scope.lock, got %+v (%v)", d, err)
+	}
+
+	if err := os.WriteFile(lockPath, []byte("scope = \"project\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := DecideScope("", repo); err == nil || !strings.Contains(err.Error(), "PRJ_NO_MANIFEST") {
+		t.Fatalf("expected project lock without a manifest to fail, got %v", err)
+	}
+
+	if err := os.WriteFile(lockPath, []byte("scope = \"team\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := DecideScope("", nested); err == nil || !strings.Contains(err.Error(), "PRJ_SCOPE_LOCK") {
+		t.Fatalf("expected invalid scope.lock to fail, got %v", err)
+	}
+}
@@ -2,18 +2,26 @@ package config
 
 // Config is the frozen v1 global schema.
 type Config struct {
-	Version  int             `toml:"version"`
-	Sync     SyncConfig      `toml:"sync"`
-	Security SecurityConfig  `toml:"security"`
-	Storage  StorageConfig   `toml:"storage"`
-	Logging  LoggingConfig   `toml:"logging"`
-	Sources  []SourceConfig  `toml:"sources"`
-	Adapters []AdapterConfig `toml:"adapters"`
+	Version    int              `toml:"version"`
+	Sync       SyncConfig       `toml:"sync"`
+	Security   SecurityConfig   `toml:"security"`
+	Storage    StorageConfig    `toml:"storage"`
+	Logging    LoggingConfig    `toml:"logging"`
+	Search     SearchConfig     `toml:"search,omitempty"`
+	Resolution ResolutionConfig `toml:"resolution,omitempty"`
+	Notify     NotifyConfig     `toml:"notify,omitempty"`
+	Metrics    MetricsConfig    `toml:"metrics,omitempty"`
+	Defaults   DefaultsConfig   `toml:"defaults,omitempty"`
+	Sources    []SourceConfig   `toml:"sources"`
+	Adapters   []AdapterConfig  `toml:"adapters"`
 }
 
 type SyncConfig struct {
 	Mode     string `toml:"mode" json:"mode"`
 	Interval string `toml:"interval" json:"interval"`
+	// History appends every sync run's summary to sync-history.jsonl in
+	// the state root.
+	History bool `toml:"history,omitempty" json:"history,omitempty"`
 }
 
 type SecurityConfig struct {
@@ -30,6 +38,51 @@ type ScanConfig struct {
 
 type StorageConfig struct {
 	Root string `toml:"root"`
+	// MaxDirNameLength caps installed directory names before they are
+	// replaced by hashed names. 0 selects the platform default, negative
+	// values disable hashing.
+	MaxDirNameLength int `toml:"max_dir_name_length,omitempty"`
+}
+
+type SearchConfig struct {
+	// CacheTTL is how long search results are reused, as a duration such
+	// as "10m". Empty selects the default; "0" disables the cache.
+	CacheTTL string `toml:"cache_ttl,omitempty"`
+}
+
+type ResolutionConfig struct {
+	// PreferTierOrder ranks trust tiers for bare skill names found in
+	// several sources, most preferred first. Empty means trusted, review,
+	// untrusted.
+	PreferTierOrder []string `toml:"prefer_tier_order,omitempty"`
+}
+
+type NotifyConfig struct {
+	// WebhookURL receives a POST of the sync --json summary after every
+	// sync that is not a dry run. Empty disables notifications.
+	WebhookURL string `toml:"webhook_url,omitempty"`
+	// On selects which outcomes notify: "attention" (blocked,
+	// changed-with-risk, failed or refused, the default) or "always".
+	On string `toml:"on,omitempty"`
+}
+
+type MetricsConfig struct {
+	// Enabled counts installs, upgrades, syncs and injects in metrics.json
+	// in the state root. The counters never leave the machine.
+	Enabled bool `toml:"enabled,omitempty"`
+}
+
+// DefaultsConfig holds values commands use for flags that are not given on
+// the command line. An explicit flag always wins.
+type DefaultsConfig struct {
+	// Lockfile is the lockfile used in global scope when --lockfile is not
+	// set. Project scope keeps the lockfile next to the manifest. "~/" is
+	// expanded.
+	Lockfile string `toml:"lockfile,omitempty"`
+	// InstallMaxSeverity is install's --max-severity when the flag is not
+	// set: scan findings up to it are allowed and those above it refused,
+	// whether or not --force is given.
+	InstallMaxSeverity string `toml:"install_max_severity,omitempty"`
 }
 
 type LoggingConfig struct {
@@ -51,12 +104,28 @@ type SourceConfig struct {
 	APIVersion     string   `toml:"api_version,omitempty" json:"apiVersion,omitempty"`
 	CachedRegistry string   `toml:"cached_registry,omitempty" json:"cachedRegistry,omitempty"`
 	MinCLIVersion  string   `toml:"min_cli_version,omitempty" json:"minCliVersion,omitempty"`
+	// ReviewInterval is how often the source should be re-audited, as a
+	// duration such as "2160h" or "90d". Empty disables review reminders.
+	ReviewInterval string `toml:"review_interval,omitempty" json:"reviewInterval,omitempty"`
+	// LastReviewed is the RFC 3339 time of the last `source review`.
+	LastReviewed string `toml:"last_reviewed,omitempty" json:"lastReviewed,omitempty"`
+	// PublicKey is the base64 ed25519 key that verifies the SKILL.md.sig
+	// files published by this source.
+	PublicKey string `toml:"public_key,omitempty" json:"publicKey,omitempty"`
+	// RequireSignatures refuses to install skills from this source unless
+	// their signature verifies against PublicKey.
+	RequireSignatures bool `toml:"require_signatures,omitempty" json:"requireSignatures,omitempty"`
+	// MirrorURLs are tried in order when cloning or fetching URL fails.
+	MirrorURLs []string `toml:"mirror_urls,omitempty" json:"mirrorUrls,omitempty"`
 }
 
 type AdapterConfig struct {
 	Name    string `toml:"name" json:"name"`
 	Enabled bool   `toml:"enabled" json:"enabled"`
 	Scope   string `toml:"scope" json:"scope"`
+	// Dir overrides the global skills directory the agent reads from, for
+	// agents relocated away from their default home. "~/" is expanded.
+	Dir string `toml:"dir,omitempty" json:"dir,omitempty"`
 }
 
 // BundleEntry defines a named group of skills that can be installed together.
@@ -1,8 +1,18 @@
 package config
 
 import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
 	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
 )
 
 var allowedTrustTiers = map[string]struct{}{
@@ -15,63 +25,296 @@ var allowedSourceKinds = map[string]struct{}{
 	"git":     {},
 	"clawhub": {},
 	"dir":     {},
+	"archive": {},
+}
+
+var allowedBlockSeverities = map[string]struct{}{
+	"critical": {},
+	"high":     {},
+	"medium":   {},
+	"low":      {},
+	"info":     {},
+}
+
+var allowedNotifyOn = map[string]struct{}{
+	"":          {},
+	"attention": {},
+	"always":    {},
 }
 
+var allowedAdapterScopes = map[string]struct{}{
+	"":        {},
+	"global":  {},
+	"project": {},
+}
+
+// ValidationIssue is one problem found in a config document, tied to the
+// offending key (for example "sources[1].trust_tier").
+type ValidationIssue struct {
+	Code    string `json:"code"`
+	Key     string `json:"key"`
+	Message string `json:"message"`
+	// Advisory issues are reported by Check but do not fail Load or Save.
+	Advisory bool `json:"advisory,omitempty"`
+}
+
+func (i ValidationIssue) String() string {
+	if i.Key == "" {
+		return fmt.Sprintf("%s (%s)", i.Message, i.Code)
+	}
+	return fmt.Sprintf("%s: %s (%s)", i.Key, i.Message, i.Code)
+}
+
+// Validate returns the first non-advisory issue found by Check.
 func Validate(cfg Config) error {
+	for _, issue := range Check(cfg) {
+		if !issue.Advisory {
+			return fmt.Errorf("%s: %s", issue.Code, issue.Message)
+		}
+	}
+	return nil
+}
+
+// Check reports every semantic problem in cfg instead of stopping at the
+// first one. Advisory issues flag values that load fine but are likely
+// mistakes, such as unknown adapter names or an unparseable sync interval.
+func Check(cfg Config) []ValidationIssue {
+	var issues []ValidationIssue
+	add := func(code, key, format string, args ...any) {
+		issues = append(issues, ValidationIssue{Code: code, Key: key, Message: fmt.Sprintf(format, args...)})
+	}
+	advise := func(code, key, format string, args ...any) {
+		issues = append(issues, ValidationIssue{Code: code, Key: key, Message: fmt.Sprintf(format, args...), Advisory: true})
+	}
+
 	if cfg.Version != SchemaVersion {
-		return fmt.Errorf("DOC_CONFIG_VERSION: unsupported version %d", cfg.Version)
+		add("DOC_CONFIG_VERSION", "version", "unsupported version %d", cfg.Version)
 	}
 	if cfg.Sync.Mode == "" || cfg.Sync.Interval == "" {
-		return fmt.Errorf("DOC_CONFIG_SYNC: missing sync mode/interval")
+		add("DOC_CONFIG_SYNC", "sync", "missing sync mode/interval")
+	} else if _, err := time.ParseDuration(cfg.Sync.Interval); err != nil {
+		advise("DOC_CONFIG_SYNC", "sync.interval", "interval %q is not a duration such as \"6h\"", cfg.Sync.Interval)
 	}
 	if cfg.Security.Profile == "" {
-		return fmt.Errorf("SEC_CONFIG_SECURITY: missing security profile")
+		add("SEC_CONFIG_SECURITY", "security.profile", "missing security profile")
+	}
+	if sev := cfg.Security.Scan.BlockSeverity; sev != "" {
+		if _, ok := allowedBlockSeverities[strings.ToLower(sev)]; !ok {
+			advise("SEC_CONFIG_SCAN", "security.scan.block_severity", "unknown severity %q (falls back to \"high\")", sev)
+		}
+	}
+	if lf := cfg.Defaults.Lockfile; lf != "" && !strings.HasPrefix(lf, "~/") && !filepath.IsAbs(lf) {
+		add("DOC_CONFIG_DEFAULTS", "defaults.lockfile", "lockfile %q must be an absolute path or start with ~/", lf)
+	}
+	if sev := cfg.Defaults.InstallMaxSeverity; sev != "" {
+		if _, ok := allowedBlockSeverities[strings.ToLower(sev)]; !ok {
+			add("DOC_CONFIG_DEFAULTS", "defaults.install_max_severity", "unknown severity %q; use info, low, medium, high or critical", sev)
+		}
+	}
+	if ttl := cfg.Search.CacheTTL; ttl != "" {
+		if d, err := time.ParseDuration(ttl); err != nil || d < 0 {
+			advise("DOC_CONFIG_SEARCH", "search.cache_ttl", "cache ttl %q is not a duration such as \"10m\" (using the default)", ttl)
+		}
+	}
+	seenTiers := map[string]struct{}{}
+	for i, tier := range cfg.Resolution.PreferTierOrder {
+		key := fmt.Sprintf("resolution.prefer_tier_order[%d]", i)
+		if _, ok := allowedTrustTiers[tier]; !ok {
+			add("SEC_CONFIG_TRUST", key, "invalid trust tier %q", tier)
+		} else if _, ok := seenTiers[tier]; ok {
+			add("SEC_CONFIG_TRUST", key, "duplicate trust tier %q", tier)
+		}
+		seenTiers[tier] = struct{}{}
+	}
+	if url := cfg.Notify.WebhookURL; url != "" && !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		add("DOC_CONFIG_NOTIFY", "notify.webhook_url", "webhook url %q must be http or https", url)
+	}
+	if _, ok := allowedNotifyOn[cfg.Notify.On]; !ok {
+		advise("DOC_CONFIG_NOTIFY", "notify.on", "unknown value %q (want attention or always)", cfg.Notify.On)
 	}
 	if cfg.Storage.Root == "" {
-		return fmt.Errorf("DOC_CONFIG_STORAGE: missing storage root")
+		add("DOC_CONFIG_STORAGE", "storage.root", "missing storage root")
 	}
 	if cfg.Logging.Level == "" || cfg.Logging.Format == "" {
-		return fmt.Errorf("DOC_CONFIG_LOGGING: missing logging level/format")
+		add("DOC_CONFIG_LOGGING", "logging", "missing logging level/format")
 	}
 
+	checkSources(cfg.Sources, add)
+	checkAdapters(cfg.Adapters, add, advise)
+
+	return issues
+}
+
+type issueFunc func(code, key, format string, args ...any)
+
+func checkSources(sources []SourceConfig, add issueFunc) {
 	names := map[string]struct{}{}
-	for i := range cfg.Sources {
-		s := &cfg.Sources[i]
+	for i := range sources {
+		s := &sources[i]
+		key := fmt.Sprintf("sources[%d]", i)
 		if s.Name == "" {
-			return fmt.Errorf("SRC_CONFIG_SOURCE: source name is required")
-		}
-		if _, ok := names[s.Name]; ok {
-			return fmt.Errorf("SRC_CONFIG_SOURCE: duplicate source name %q", s.Name)
+			add("SRC_CONFIG_SOURCE", key+".name", "source name is required")
+		} else if _, ok := names[s.Name]; ok {
+			add("SRC_CONFIG_SOURCE", key+".name", "duplicate source name %q", s.Name)
 		}
 		names[s.Name] = struct{}{}
 		if _, ok := allowedSourceKinds[s.Kind]; !ok {
-			return fmt.Errorf("SRC_CONFIG_SOURCE: unsupported source kind %q", s.Kind)
+			add("SRC_CONFIG_SOURCE", key+".kind", "unsupported source kind %q", s.Kind)
 		}
 		if _, ok := allowedTrustTiers[s.TrustTier]; !ok {
-			return fmt.Errorf("SEC_CONFIG_TRUST: invalid trust tier %q", s.TrustTier)
+			add("SEC_CONFIG_TRUST", key+".trust_tier", "invalid trust tier %q", s.TrustTier)
+		}
+		if s.ReviewInterval != "" {
+			if _, err := ParseReviewInterval(s.ReviewInterval); err != nil {
+				add("SRC_CONFIG_SOURCE", key+".review_interval", "review interval %q is not a duration such as \"90d\"", s.ReviewInterval)
+			}
+		}
+		if s.LastReviewed != "" {
+			if _, err := time.Parse(time.RFC3339, s.LastReviewed); err != nil {
+				add("SRC_CONFIG_SOURCE", key+".last_reviewed", "last reviewed %q is not an RFC 3339 time", s.LastReviewed)
+			}
+		}
+		if s.PublicKey != "" {
+			if raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s.PublicKey)); err != nil || len(raw) != ed25519.PublicKeySize {
+				add("SEC_CONFIG_KEY", key+".public_key", "public key is not a base64 ed25519 key")
+			}
+		} else if s.RequireSignatures {
+			add("SEC_CONFIG_KEY", key+".public_key", "source %q requires signatures but has no public key", s.Name)
+		}
+		for j, m := range s.MirrorURLs {
+			if strings.TrimSpace(m) == "" {
+				add("SRC_CONFIG_SOURCE", fmt.Sprintf("%s.mirror_urls[%d]", key, j), "mirror url is empty")
+			}
+		}
+		if len(s.MirrorURLs) > 0 && s.Kind != "git" {
+			add("SRC_CONFIG_SOURCE", key+".mirror_urls", "mirrors are only supported for git sources")
 		}
 		switch s.Kind {
 		case "git":
 			if s.URL == "" {
-				return fmt.Errorf("SRC_CONFIG_SOURCE: git source %q missing url", s.Name)
+				add("SRC_CONFIG_SOURCE", key+".url", "git source %q missing url", s.Name)
 			}
 		case "dir":
 			if s.URL == "" {
-				return fmt.Errorf("SRC_CONFIG_SOURCE: dir source %q missing path", s.Name)
+				add("SRC_CONFIG_SOURCE", key+".url", "dir source %q missing path", s.Name)
+			}
+		case "archive":
+			if s.URL == "" {
+				add("SRC_CONFIG_SOURCE", key+".url", "archive source %q missing url", s.Name)
 			}
 		}
 	}
+}
 
+func checkAdapters(adapters []AdapterConfig, add, advise issueFunc) {
+	known := map[string]struct{}{}
+	for _, a := range DefaultConfig().Adapters {
+		known[a.Name] = struct{}{}
+	}
 	adapterNames := map[string]struct{}{}
-	for _, a := range cfg.Adapters {
+	for i, a := range adapters {
+		key := fmt.Sprintf("adapters[%d]", i)
 		if strings.TrimSpace(a.Name) == "" {
-			return fmt.Errorf("ADP_CONFIG_ADAPTER: adapter name is required")
+			add("ADP_CONFIG_ADAPTER", key+".name", "adapter name is required")
+			continue
 		}
 		if _, ok := adapterNames[a.Name]; ok {
-			return fmt.Errorf("ADP_CONFIG_ADAPTER: duplicate adapter %q", a.Name)
+			add("ADP_CONFIG_ADAPTER", key+".name", "duplicate adapter %q", a.Name)
 		}
 		adapterNames[a.Name] = struct{}{}
+		if _, ok := known[a.Name]; !ok {
+			advise("ADP_CONFIG_ADAPTER", key+".name", "unknown adapter %q", a.Name)
+		}
+		if _, ok := allowedAdapterScopes[a.Scope]; !ok {
+			advise("ADP_CONFIG_ADAPTER", key+".scope", "invalid scope %q (want global or project)", a.Scope)
+		}
+		if a.Dir != "" && a.Dir != "~" && !strings.HasPrefix(a.Dir, "~/") && !filepath.IsAbs(a.Dir) {
+			add("ADP_CONFIG_ADAPTER", key+".dir", "dir %q must be an absolute path or start with ~/", a.Dir)
+		}
 	}
+}
 
-	return nil
+// CheckFile parses the config at path and reports every problem in it:
+// keys the schema does not know, followed by the issues found by Check.
+// Syntax errors are returned as a DOC_CONFIG_PARSE error with the line and
+// column of the problem.
+func CheckFile(path string) ([]ValidationIssue, error) {
+	if path == "" {
+		path = DefaultConfigPath()
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return CheckData(path, data)
+}
+
+// CheckData is CheckFile for config content that is not on disk yet; path
+// only labels parse errors.
+func CheckData(path string, data []byte) ([]ValidationIssue, error) {
+	var cfg Config
+	if err := toml.Unmarshal(data, &cfg); err != nil {
+		return nil, parseError(path, err)
+	}
+	issues := unknownKeys(data, &Config{})
+	return append(issues, Check(Normalize(cfg))...), nil
+}
+
+// CheckManifestData reports every problem in project manifest content:
+// unknown keys, skill entries without a ref, and invalid sources or
+// adapters.
+func CheckManifestData(path string, data []byte) ([]ValidationIssue, error) {
+	var m ProjectManifest
+	if err := toml.Unmarshal(data, &m); err != nil {
+		return nil, parseError(path, err)
+	}
+	issues := unknownKeys(data, &ProjectManifest{})
+	add := func(code, key, format string, args ...any) {
+		issues = append(issues, ValidationIssue{Code: code, Key: key, Message: fmt.Sprintf(format, args...)})
+	}
+	advise := func(code, key, format string, args ...any) {
+		issues = append(issues, ValidationIssue{Code: code, Key: key, Message: fmt.Sprintf(format, args...), Advisory: true})
+	}
+	if m.Version != 0 && m.Version != SchemaVersion {
+		add("PRJ_MANIFEST_VERSION", "version", "unsupported version %d", m.Version)
+	}
+	for i, entry := range m.Skills {
+		if strings.TrimSpace(entry.Ref) == "" {
+			add("PRJ_MANIFEST_SKILL", fmt.Sprintf("skills[%d].ref", i), "skill ref is required")
+		}
+	}
+	checkSources(m.Sources, add)
+	checkAdapters(m.Adapters, add, advise)
+	return issues, nil
+}
+
+// unknownKeys reports keys in data that v's schema does not define.
+func unknownKeys(data []byte, v any) []ValidationIssue {
+	var issues []ValidationIssue
+	dec := toml.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	var missing *toml.StrictMissingError
+	if err := dec.Decode(v); errors.As(err, &missing) {
+		for _, e := range missing.Errors {
+			row, _ := e.Position()
+			issues = append(issues, ValidationIssue{
+				Code:     "DOC_CONFIG_KEY",
+				Key:      strings.Join(e.Key(), "."),
+				Message:  fmt.Sprintf("unknown key (line %d)", row),
+				Advisory: true,
+			})
+		}
+	}
+	sort.SliceStable(issues, func(i, j int) bool { return issues[i].Key < issues[j].Key })
+	return issues
+}
+
+// parseError wraps a TOML decode error with the file position it points at.
+func parseError(path string, err error) error {
+	var decErr *toml.DecodeError
+	if errors.As(err, &decErr) {
+		row, col := decErr.Position()
+		return fmt.Errorf("DOC_CONFIG_PARSE: %s:%d:%d: %w", path, row, col, err)
+	}
+	return fmt.Errorf("DOC_CONFIG_PARSE: %w", err)
 }
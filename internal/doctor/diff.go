@@ -0,0 +1,88 @@
+package doctor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// CheckChange is a check whose status differs between two reports. Before
+// is empty for a check only the later report has, After for one it lacks.
+// Kind is "fixed" when a problem went away, "broke" when one appeared, and
+// "changed" otherwise (for example error to warn).
+type CheckChange struct {
+	Name    string      `json:"name"`
+	Kind    string      `json:"kind"`
+	Before  CheckStatus `json:"before,omitempty"`
+	After   CheckStatus `json:"after,omitempty"`
+	Message string      `json:"message,omitempty"`
+}
+
+// ReportDiff compares two doctor reports check by check, matching checks
+// by name.
+type ReportDiff struct {
+	HealthyBefore bool          `json:"healthyBefore"`
+	HealthyAfter  bool          `json:"healthyAfter"`
+	Changes       []CheckChange `json:"changes"`
+	Fixed         []string      `json:"fixed"`
+	Broke         []string      `json:"broke"`
+	Unchanged     int           `json:"unchanged"`
+}
+
+// isProblem reports whether a status still needs attention after the run.
+func isProblem(s CheckStatus) bool {
+	return s == StatusWarn || s == StatusError
+}
+
+// DiffReports reports which checks changed status between before and
+// after, in the order after lists them, followed by checks after dropped.
+func DiffReports(before, after Report) ReportDiff {
+	d := ReportDiff{HealthyBefore: before.Healthy, HealthyAfter: after.Healthy, Changes: []CheckChange{}, Fixed: []string{}, Broke: []string{}}
+	prev := map[string]CheckResult{}
+	for _, c := range before.Checks {
+		prev[c.Name] = c
+	}
+	seen := map[string]struct{}{}
+	for _, c := range after.Checks {
+		seen[c.Name] = struct{}{}
+		old, ok := prev[c.Name]
+		if ok && old.Status == c.Status {
+			d.Unchanged++
+			continue
+		}
+		change := CheckChange{Name: c.Name, Before: old.Status, After: c.Status, Message: c.Message}
+		switch {
+		case isProblem(c.Status) && (!ok || !isProblem(old.Status)):
+			change.Kind = "broke"
+			d.Broke = append(d.Broke, c.Name)
+		case !isProblem(c.Status) && ok && isProblem(old.Status):
+			change.Kind = "fixed"
+			d.Fixed = append(d.Fixed, c.Name)
+		default:
+			change.Kind = "changed"
+		}
+		d.Changes = append(d.Changes, change)
+	}
+	for _, c := range before.Checks {
+		if _, ok := seen[c.Name]; !ok {
+			d.Changes = append(d.Changes, CheckChange{Name: c.Name, Kind: "changed", Before: c.Status, Message: "check not in the later report"})
+		}
+	}
+	return d
+}
+
+// ReadReport loads a report saved from `doctor --json`.
+func ReadReport(path string) (Report, error) {
+	blob, err := os.ReadFile(path)
+	if err != nil {
+		return Report{}, fmt.Errorf("DOC_DIFF: %w", err)
+	}
+	var r Report
+	if err := json.Unmarshal(blob, &r); err != nil {
+		return Report{}, fmt.Errorf("DOC_DIFF: %s is not a doctor --json report: %w", path, err)
+	}
+	if r.Checks == nil {
+		return Report{}, fmt.Errorf("DOC_DIFF: %s has no checks; save it with skillpm doctor --json", path)
+	}
+	return r, nil
+}
@@ -0,0 +1,61 @@
+package doctor
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDiffReportsFixedAndBroke(t *testing.T) {
+	before := Report{Healthy: false, Checks: []CheckResult{
+		{Name: "config", Status: StatusOK},
+		{Name: "injections", Status: StatusError, Message: "stale ref"},
+		{Name: "lockfile", Status: StatusOK},
+		{Name: "deprecated", Status: StatusError},
+		{Name: "legacy", Status: StatusOK},
+	}}
+	after := Report{Healthy: false, Checks: []CheckResult{
+		{Name: "config", Status: StatusOK},
+		{Name: "injections", Status: StatusFixed, Message: "cleared stale ref"},
+		{Name: "lockfile", Status: StatusWarn, Message: "lockfile stale"},
+		{Name: "deprecated", Status: StatusWarn},
+		{Name: "source-review", Status: StatusWarn},
+	}}
+	d := DiffReports(before, after)
+	if strings.Join(d.Fixed, ",") != "injections" {
+		t.Fatalf("expected injections fixed, got %v", d.Fixed)
+	}
+	if strings.Join(d.Broke, ",") != "lockfile,source-review" {
+		t.Fatalf("expected lockfile and source-review broke, got %v", d.Broke)
+	}
+	if d.Unchanged != 1 || len(d.Changes) != 5 {
+		t.Fatalf("unexpected diff: %+v", d)
+	}
+	if c := d.Changes[2]; c.Name != "deprecated" || c.Kind != "changed" {
+		t.Fatalf("expected error to warn to be a plain change, got %+v", c)
+	}
+	if c := d.Changes[4]; c.Name != "legacy" || c.After != "" {
+		t.Fatalf("expected dropped check last, got %+v", c)
+	}
+}
+
+func TestReadReportRejectsNonReports(t *testing.T) {
+	dir := t.TempDir()
+	good := filepath.Join(dir, "good.json")
+	blob, _ := json.Marshal(Report{Healthy: true, Checks: []CheckResult{{Name: "config", Status: StatusOK}}})
+	if err := os.WriteFile(good, blob, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if r, err := ReadReport(good); err != nil || len(r.Checks) != 1 {
+		t.Fatalf("expected report to load, got %+v, %v", r, err)
+	}
+	bad := filepath.Join(dir, "bad.json")
+	if err := os.WriteFile(bad, []byte(`{"installs": 3}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadReport(bad); err == nil || !strings.HasPrefix(err.Error(), "DOC_DIFF") {
+		t.Fatalf("expected DOC_DIFF for a non-report, got %v", err)
+	}
+}
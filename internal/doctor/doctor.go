@@ -7,10 +7,12 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"time"
 
 	"skillpm/internal/adapter"
 	"skillpm/internal/config"
 	"skillpm/internal/fsutil"
+	"skillpm/internal/source"
 	"skillpm/internal/store"
 	"skillpm/pkg/adapterapi"
 )
@@ -31,6 +33,20 @@ type CheckResult struct {
 	Status  CheckStatus `json:"status"`
 	Message string      `json:"message"`
 	Fix     string      `json:"fix,omitempty"`
+	// FixActions is Fix for automation: one entry per fix, in the same order.
+	FixActions []FixAction `json:"fixActions,omitempty"`
+	// DurationMs is the check's wall-clock time, set only when profiling.
+	DurationMs float64 `json:"durationMs,omitempty"`
+}
+
+// FixAction is one fix a check applied or would apply, in machine-readable
+// form. Type names the action, e.g. "remove-ghost" or "restore-agent-file";
+// the other fields say what it acts on.
+type FixAction struct {
+	Type   string `json:"type"`
+	Target string `json:"target,omitempty"`
+	Agent  string `json:"agent,omitempty"`
+	Skill  string `json:"skill,omitempty"`
 }
 
 // Report is the aggregate diagnostic output.
@@ -41,6 +57,8 @@ type Report struct {
 	Fixed    int           `json:"fixed"`
 	Warnings int           `json:"warnings"`
 	Errors   int           `json:"errors"`
+	// DryRun is set when the fixes counted in Fixed were not applied.
+	DryRun bool `json:"dryRun,omitempty"`
 }
 
 // Service holds the dependencies needed by the doctor checks.
@@ -51,35 +69,135 @@ type Service struct {
 	Runtime     *adapter.Runtime
 	Scope       config.Scope
 	ProjectRoot string
+
+	// ReinstallMissing makes the injections check try to reinstall skills
+	// that are injected but no longer installed, instead of clearing them.
+	ReinstallMissing bool
+	// Reinstall installs the given refs and returns those that succeeded.
+	Reinstall func(ctx context.Context, refs []string) []string
+	// RepairFromLock treats the lockfile as the source of truth and
+	// rebuilds installed state to match it before the other checks run.
+	RepairFromLock bool
+	// Uninstall removes the given installed refs and returns those removed.
+	Uninstall func(ctx context.Context, refs []string) []string
+	// ReportOnly detects drift without applying any fix. Checks that would
+	// have fixed something report StatusWarn instead of StatusFixed.
+	ReportOnly bool
+	// DryRun skips every fix like ReportOnly, but reports the fixes a real
+	// run would apply as StatusFixed so the report's counts match one.
+	DryRun bool
+	// UpstreamSkill returns the current SKILL.md of an installed skill from
+	// its source's local cache, so deprecations published after install are
+	// noticed without a network round trip.
+	UpstreamSkill func(rec store.InstalledSkill) (string, bool)
+	// Profile records how long each check takes in CheckResult.DurationMs.
+	Profile bool
+}
+
+// check is one diagnostic step. deps names the checks whose fixes it relies
+// on; checks that are not part of the run (such as lock-repair without
+// RepairFromLock) are ignored.
+type check struct {
+	name string
+	deps []string
+	run  func(st store.State, stateErr error) CheckResult
+}
+
+// checks declares every check of a run with its dependencies.
+func (s *Service) checks() []check {
+	cs := []check{
+		{name: "config", run: func(store.State, error) CheckResult { return s.checkConfig() }},
+		{name: "state", run: func(_ store.State, stateErr error) CheckResult { return s.checkState(stateErr) }},
+	}
+	if s.RepairFromLock {
+		cs = append(cs, check{name: "lock-repair", deps: []string{"state"}, run: s.checkRepairFromLock})
+	}
+	return append(cs,
+		check{name: "installed-dirs", deps: []string{"state", "lock-repair"}, run: s.checkInstalledDirs},
+		// Injections reference installed skills, and reinstalling missing
+		// ones adds installed records.
+		check{name: "injections", deps: []string{"installed-dirs"}, run: s.checkInjections},
+		check{name: "adapter-state", deps: []string{"injections"}, run: s.checkAdapterState},
+		check{name: "agent-skills", deps: []string{"adapter-state"}, run: s.checkAgentSkills},
+		check{name: "lockfile", deps: []string{"installed-dirs", "injections"}, run: s.checkLockfile},
+		check{name: "deprecated", deps: []string{"installed-dirs"}, run: s.checkDeprecated},
+		check{name: "source-review", deps: []string{"config"}, run: func(store.State, error) CheckResult { return s.checkSourceReview() }},
+	)
 }
 
-// Run executes all checks in dependency order and returns a report.
+// orderChecks sorts checks so that each runs after its dependencies,
+// keeping the declared order otherwise.
+func orderChecks(cs []check) ([]check, error) {
+	present := map[string]struct{}{}
+	for _, c := range cs {
+		present[c.name] = struct{}{}
+	}
+	done := map[string]struct{}{}
+	ordered := make([]check, 0, len(cs))
+	for len(ordered) < len(cs) {
+		progressed := false
+		for _, c := range cs {
+			if _, ok := done[c.name]; ok {
+				continue
+			}
+			ready := true
+			for _, dep := range c.deps {
+				if _, ok := present[dep]; !ok {
+					continue
+				}
+				if _, ok := done[dep]; !ok {
+					ready = false
+					break
+				}
+			}
+			if !ready {
+				continue
+			}
+			ordered = append(ordered, c)
+			done[c.name] = struct{}{}
+			progressed = true
+			break
+		}
+		if !progressed {
+			var stuck []string
+			for _, c := range cs {
+				if _, ok := done[c.name]; !ok {
+					stuck = append(stuck, c.name)
+				}
+			}
+			return nil, fmt.Errorf("DOC_CHECK_ORDER: dependency cycle between checks %s", strings.Join(stuck, ", "))
+		}
+	}
+	return ordered, nil
+}
+
+// Run executes all checks in dependency order and returns a report. State
+// is re-read after every check that did not come back clean, so each check
+// sees the repairs of the checks it depends on and a single run converges.
 func (s *Service) Run(_ context.Context) Report {
-	// Load state once for all checks that need it.
+	ordered, err := orderChecks(s.checks())
+	if err != nil {
+		return Report{Scope: string(s.Scope), Checks: []CheckResult{{Name: "doctor", Status: StatusError, Message: err.Error()}}, Errors: 1}
+	}
 	st, stateErr := store.LoadState(s.StateRoot)
-
-	checks := []CheckResult{
-		s.checkConfig(),
-		s.checkState(stateErr),
+	var checks []CheckResult
+	for _, c := range ordered {
+		start := time.Now()
+		r := c.run(st, stateErr)
+		if s.Profile {
+			r.DurationMs = float64(time.Since(start).Microseconds()) / 1000
+		}
+		checks = append(checks, r)
+		if r.Status != StatusOK || stateErr != nil {
+			st, stateErr = store.LoadState(s.StateRoot)
+		}
 	}
-	if stateErr != nil {
-		// Re-load after potential state reset in checkState.
-		st, stateErr = store.LoadState(s.StateRoot)
-	}
-	checks = append(checks, s.checkInstalledDirs(st, stateErr))
-	// Re-load after checks that mutate and save state, so subsequent
-	// checks see the updated version (e.g., ghost removals, stale refs).
-	st, stateErr = store.LoadState(s.StateRoot)
-	checks = append(checks, s.checkInjections(st, stateErr))
-	st, stateErr = store.LoadState(s.StateRoot)
-	checks = append(checks, s.checkAdapterState(st, stateErr))
-	checks = append(checks, s.checkAgentSkills(st, stateErr))
-	checks = append(checks, s.checkLockfile(st, stateErr))
 
 	rpt := Report{
 		Healthy: true,
 		Scope:   string(s.Scope),
 		Checks:  checks,
+		DryRun:  s.DryRun,
 	}
 	for _, c := range checks {
 		switch c.Status {
@@ -95,6 +213,14 @@ func (s *Service) Run(_ context.Context) Report {
 	return rpt
 }
 
+// Slowest returns the report's checks ordered by duration, slowest first.
+// Checks with equal durations keep their run order.
+func (r Report) Slowest() []CheckResult {
+	out := append([]CheckResult(nil), r.Checks...)
+	sort.SliceStable(out, func(i, j int) bool { return out[i].DurationMs > out[j].DurationMs })
+	return out
+}
+
 // --- check 1: config ---
 
 func (s *Service) checkConfig() CheckResult {
@@ -102,13 +228,19 @@ func (s *Service) checkConfig() CheckResult {
 
 	// Try loading; if missing, Ensure will create default.
 	_, err := config.Load(s.ConfigPath)
+	if err != nil && s.readOnly() {
+		var fixes fixList
+		fixes.add("created default config", FixAction{Type: "create-config", Target: s.ConfigPath})
+		return s.repaired(name, "config valid", fixes)
+	}
 	if err != nil {
 		cfg, ensureErr := config.Ensure(s.ConfigPath)
 		if ensureErr != nil {
 			return CheckResult{Name: name, Status: StatusError, Message: ensureErr.Error()}
 		}
 		// Auto-enable detected adapters on fresh config.
-		fix := "created default config"
+		var fixes fixList
+		fixes.add("created default config", FixAction{Type: "create-config", Target: s.ConfigPath})
 		detected := adapter.DetectAvailable()
 		var enabled []string
 		for _, d := range detected {
@@ -121,9 +253,9 @@ func (s *Service) checkConfig() CheckResult {
 			if saveErr := config.Save(s.ConfigPath, cfg); saveErr != nil {
 				return CheckResult{Name: name, Status: StatusError, Message: saveErr.Error()}
 			}
-			fix += fmt.Sprintf("; enabled adapters: %s", strings.Join(enabled, ", "))
+			fixes.add(fmt.Sprintf("enabled adapters: %s", strings.Join(enabled, ", ")), enableActions(enabled)...)
 		}
-		return CheckResult{Name: name, Status: StatusFixed, Message: "config valid", Fix: fix}
+		return fixes.result(name, StatusFixed, "config valid")
 	}
 
 	// Config exists — check if detected adapters need enabling.
@@ -146,15 +278,15 @@ func (s *Service) checkConfig() CheckResult {
 		}
 	}
 	if len(newlyEnabled) > 0 {
+		var fixes fixList
+		fixes.add("enabled adapters: "+strings.Join(newlyEnabled, ", "), enableActions(newlyEnabled)...)
+		if s.readOnly() {
+			return s.repaired(name, "config valid", fixes)
+		}
 		if saveErr := config.Save(s.ConfigPath, cfg); saveErr != nil {
 			return CheckResult{Name: name, Status: StatusError, Message: saveErr.Error()}
 		}
-		return CheckResult{
-			Name:    name,
-			Status:  StatusFixed,
-			Message: "config valid",
-			Fix:     fmt.Sprintf("enabled adapters: %s", strings.Join(newlyEnabled, ", ")),
-		}
+		return fixes.result(name, StatusFixed, "config valid")
 	}
 
 	return CheckResult{Name: name, Status: StatusOK, Message: "config valid"}
@@ -167,6 +299,11 @@ func (s *Service) checkState(stateErr error) CheckResult {
 	if stateErr == nil {
 		return CheckResult{Name: name, Status: StatusOK, Message: "state valid"}
 	}
+	var fixes fixList
+	fixes.add("reset corrupt state", FixAction{Type: "reset-state", Target: store.StatePath(s.StateRoot)})
+	if s.readOnly() {
+		return s.repaired(name, "state valid", fixes)
+	}
 	// Reset to empty state. Ensure directory layout exists since SaveState no longer does.
 	if err := store.EnsureLayout(s.StateRoot); err != nil {
 		return CheckResult{Name: name, Status: StatusError, Message: err.Error()}
@@ -175,7 +312,7 @@ func (s *Service) checkState(stateErr error) CheckResult {
 	if saveErr := store.SaveState(s.StateRoot, empty); saveErr != nil {
 		return CheckResult{Name: name, Status: StatusError, Message: saveErr.Error()}
 	}
-	return CheckResult{Name: name, Status: StatusFixed, Message: "state valid", Fix: "reset corrupt state"}
+	return fixes.result(name, StatusFixed, "state valid")
 }
 
 // --- check 3: installed-dirs ---
@@ -189,10 +326,13 @@ func (s *Service) checkInstalledDirs(st store.State, stateErr error) CheckResult
 	installedRoot := store.InstalledRoot(s.StateRoot)
 
 	// Build set of dirs that should exist based on state.
+	// Both naming schemes are accepted so that changing
+	// storage.max_dir_name_length never turns live installs into orphans.
 	expectedDirs := map[string]struct{}{}
 	for _, rec := range st.Installed {
-		dirName := store.InstalledDirName(rec.SkillRef, rec.ResolvedVersion)
-		expectedDirs[dirName] = struct{}{}
+		for _, dirName := range store.InstalledDirNames(rec.SkillRef, rec.ResolvedVersion) {
+			expectedDirs[dirName] = struct{}{}
+		}
 	}
 
 	// Check for orphan dirs (on disk but not in state).
@@ -212,8 +352,14 @@ func (s *Service) checkInstalledDirs(st store.State, stateErr error) CheckResult
 	// Check for ghost entries (in state but dir missing).
 	var ghosts []string
 	for _, rec := range st.Installed {
-		dirName := store.InstalledDirName(rec.SkillRef, rec.ResolvedVersion)
-		if _, ok := diskDirs[dirName]; !ok {
+		found := false
+		for _, dirName := range store.InstalledDirNames(rec.SkillRef, rec.ResolvedVersion) {
+			if _, ok := diskDirs[dirName]; ok {
+				found = true
+				break
+			}
+		}
+		if !found {
 			ghosts = append(ghosts, rec.SkillRef)
 		}
 	}
@@ -222,25 +368,25 @@ func (s *Service) checkInstalledDirs(st store.State, stateErr error) CheckResult
 		return CheckResult{Name: name, Status: StatusOK, Message: "installed dirs reconciled"}
 	}
 
-	var fixes []string
+	var fixes fixList
 	for _, o := range orphans {
-		_ = os.RemoveAll(filepath.Join(installedRoot, o))
-		fixes = append(fixes, "removed orphan dir: "+o)
+		if !s.readOnly() {
+			_ = os.RemoveAll(filepath.Join(installedRoot, o))
+		}
+		fixes.add("removed orphan dir: "+o, FixAction{Type: "remove-orphan-dir", Target: o})
 	}
 	for _, g := range ghosts {
 		store.RemoveInstalled(&st, g)
-		fixes = append(fixes, "removed ghost state entry: "+g)
+		fixes.add("removed ghost state entry: "+g, FixAction{Type: "remove-ghost", Target: g})
+	}
+	if s.readOnly() {
+		return s.repaired(name, "installed dirs reconciled", fixes)
 	}
 	if len(ghosts) > 0 {
 		_ = store.SaveState(s.StateRoot, st)
 	}
 
-	return CheckResult{
-		Name:    name,
-		Status:  StatusFixed,
-		Message: "installed dirs reconciled",
-		Fix:     strings.Join(fixes, "; "),
-	}
+	return fixes.result(name, StatusFixed, "installed dirs reconciled")
 }
 
 // --- check 4: injections ---
@@ -256,8 +402,39 @@ func (s *Service) checkInjections(st store.State, stateErr error) CheckResult {
 		installedSet[rec.SkillRef] = struct{}{}
 	}
 
-	changed := false
-	var fixes []string
+	var fixes fixList
+	if s.ReinstallMissing && s.Reinstall != nil && !s.readOnly() {
+		var missing []string
+		seen := map[string]struct{}{}
+		for _, inj := range st.Injections {
+			for _, ref := range inj.Skills {
+				if _, ok := installedSet[ref]; ok {
+					continue
+				}
+				if _, ok := seen[ref]; ok {
+					continue
+				}
+				seen[ref] = struct{}{}
+				missing = append(missing, ref)
+			}
+		}
+		if len(missing) > 0 {
+			sort.Strings(missing)
+			for _, ref := range s.Reinstall(context.Background(), missing) {
+				installedSet[ref] = struct{}{}
+				fixes.add("reinstalled missing skill "+ref, FixAction{Type: "reinstall-skill", Target: ref})
+			}
+			// Reinstalling saved new installed records; pick them up so
+			// the injection fix below does not overwrite them.
+			reloaded, err := store.LoadState(s.StateRoot)
+			if err != nil {
+				return CheckResult{Name: name, Status: StatusError, Message: err.Error()}
+			}
+			st = reloaded
+		}
+	}
+
+	changed := !fixes.empty()
 	var kept []store.InjectionState
 	for _, inj := range st.Injections {
 		var valid []string
@@ -265,12 +442,12 @@ func (s *Service) checkInjections(st store.State, stateErr error) CheckResult {
 			if _, ok := installedSet[ref]; ok {
 				valid = append(valid, ref)
 			} else {
-				fixes = append(fixes, fmt.Sprintf("removed stale ref %s from %s", ref, inj.Agent))
+				fixes.add(fmt.Sprintf("removed stale ref %s from %s", ref, inj.Agent), FixAction{Type: "remove-stale-injection", Agent: inj.Agent, Target: ref})
 				changed = true
 			}
 		}
 		if len(valid) == 0 {
-			fixes = append(fixes, fmt.Sprintf("removed empty agent entry: %s", inj.Agent))
+			fixes.add(fmt.Sprintf("removed empty agent entry: %s", inj.Agent), FixAction{Type: "remove-agent-entry", Agent: inj.Agent})
 			changed = true
 			continue
 		}
@@ -282,15 +459,13 @@ func (s *Service) checkInjections(st store.State, stateErr error) CheckResult {
 		return CheckResult{Name: name, Status: StatusOK, Message: "injection refs valid"}
 	}
 
+	if s.readOnly() {
+		return s.repaired(name, "injection refs valid", fixes)
+	}
 	st.Injections = kept
 	_ = store.SaveState(s.StateRoot, st)
 
-	return CheckResult{
-		Name:    name,
-		Status:  StatusFixed,
-		Message: "injection refs valid",
-		Fix:     strings.Join(fixes, "; "),
-	}
+	return fixes.result(name, StatusFixed, "injection refs valid")
 }
 
 // --- check 5: adapter-state ---
@@ -305,9 +480,14 @@ func (s *Service) checkAdapterState(st store.State, stateErr error) CheckResult
 	}
 
 	ctx := context.Background()
+	var fixes fixList
 	scope := string(s.Scope)
-	var fixes []string
 	for _, inj := range st.Injections {
+		// The runtime is bound to this scope's layout; records from another
+		// scope are reconciled by doctor runs in that scope.
+		if inj.EffectiveScope(scope) != scope {
+			continue
+		}
 		adp, aErr := s.Runtime.Get(inj.Agent)
 		if aErr != nil {
 			continue
@@ -319,23 +499,24 @@ func (s *Service) checkAdapterState(st store.State, stateErr error) CheckResult
 		if skillSetsEqual(inj.Skills, listed.Skills) {
 			continue
 		}
+		fixes.add(fmt.Sprintf("%s: synced injected.toml", inj.Agent), FixAction{Type: "sync-agent-state", Agent: inj.Agent})
+		if s.readOnly() {
+			continue
+		}
 		// Re-inject to reconcile: remove all, then inject what state says.
 		_, _ = adp.Remove(ctx, adapterapi.RemoveRequest{Scope: scope})
 		if len(inj.Skills) > 0 {
 			_, _ = adp.Inject(ctx, adapterapi.InjectRequest{SkillRefs: inj.Skills, Scope: scope})
 		}
-		fixes = append(fixes, fmt.Sprintf("%s: synced injected.toml", inj.Agent))
 	}
 
-	if len(fixes) == 0 {
+	if fixes.empty() {
 		return CheckResult{Name: name, Status: StatusOK, Message: "adapter state synced"}
 	}
-	return CheckResult{
-		Name:    name,
-		Status:  StatusFixed,
-		Message: "adapter state synced",
-		Fix:     strings.Join(fixes, "; "),
+	if s.readOnly() {
+		return s.repaired(name, "adapter state synced", fixes)
 	}
+	return fixes.result(name, StatusFixed, "adapter state synced")
 }
 
 // --- check 6: agent-skills ---
@@ -346,15 +527,15 @@ func (s *Service) checkAgentSkills(st store.State, stateErr error) CheckResult {
 		return CheckResult{Name: name, Status: StatusError, Message: stateErr.Error()}
 	}
 
-	var projectRoot string
-	if s.Scope == config.ScopeProject {
-		projectRoot = s.ProjectRoot
-	}
-
-	var fixes []string
+	var fixes fixList
 
 	for _, inj := range st.Injections {
-		skillsDir := adapter.AgentSkillsDirForScope(inj.Agent, projectRoot)
+		// Restore into the directory of the scope the skill was injected in.
+		var projectRoot string
+		if inj.EffectiveScope(string(s.Scope)) == string(config.ScopeProject) {
+			projectRoot = s.ProjectRoot
+		}
+		skillsDir := s.Runtime.SkillsDirForScope(inj.Agent, projectRoot)
 		for _, ref := range inj.Skills {
 			skillName := adapter.ExtractSkillName(ref)
 			destDir := filepath.Join(skillsDir, skillName)
@@ -365,21 +546,24 @@ func (s *Service) checkAgentSkills(st store.State, stateErr error) CheckResult {
 			if srcDir == "" {
 				continue
 			}
+			restore := FixAction{Type: "restore-agent-file", Agent: inj.Agent, Skill: skillName, Target: ref}
+			if s.readOnly() {
+				fixes.add(fmt.Sprintf("restored %s for %s", skillName, inj.Agent), restore)
+				continue
+			}
 			if cpErr := fsutil.CopyDir(srcDir, destDir); cpErr == nil {
-				fixes = append(fixes, fmt.Sprintf("restored %s for %s", skillName, inj.Agent))
+				fixes.add(fmt.Sprintf("restored %s for %s", skillName, inj.Agent), restore)
 			}
 		}
 	}
 
-	if len(fixes) == 0 {
+	if fixes.empty() {
 		return CheckResult{Name: name, Status: StatusOK, Message: "agent skill files present"}
 	}
-	return CheckResult{
-		Name:    name,
-		Status:  StatusFixed,
-		Message: "agent skill files present",
-		Fix:     strings.Join(fixes, "; "),
+	if s.readOnly() {
+		return s.repaired(name, "agent skill files present", fixes)
 	}
+	return fixes.result(name, StatusFixed, "agent skill files present")
 }
 
 // --- check 7: lockfile ---
@@ -407,7 +591,7 @@ func (s *Service) checkLockfile(st store.State, stateErr error) CheckResult {
 		lockRefs[ls.SkillRef] = struct{}{}
 	}
 
-	var fixes []string
+	var fixes fixList
 	changed := false
 
 	// Remove stale lock entries (in lock but not in state).
@@ -416,7 +600,7 @@ func (s *Service) checkLockfile(st store.State, stateErr error) CheckResult {
 		if _, ok := stateRefs[ls.SkillRef]; ok {
 			kept = append(kept, ls)
 		} else {
-			fixes = append(fixes, "removed stale lock entry: "+ls.SkillRef)
+			fixes.add("removed stale lock entry: "+ls.SkillRef, FixAction{Type: "remove-lock-entry", Target: ls.SkillRef})
 			changed = true
 		}
 	}
@@ -431,7 +615,7 @@ func (s *Service) checkLockfile(st store.State, stateErr error) CheckResult {
 				Checksum:        rec.Checksum,
 				SourceRef:       rec.SourceRef,
 			})
-			fixes = append(fixes, "added missing lock entry: "+ref)
+			fixes.add("added missing lock entry: "+ref, FixAction{Type: "add-lock-entry", Target: ref})
 			changed = true
 		}
 	}
@@ -441,19 +625,120 @@ func (s *Service) checkLockfile(st store.State, stateErr error) CheckResult {
 		return CheckResult{Name: name, Status: StatusOK, Message: fmt.Sprintf("%d lock entries verified", count)}
 	}
 
+	if s.readOnly() {
+		return s.repaired(name, fmt.Sprintf("%d lock entries verified", len(lock.Skills)), fixes)
+	}
 	if saveErr := store.SaveLockfile(s.LockPath, lock); saveErr != nil {
 		return CheckResult{Name: name, Status: StatusError, Message: saveErr.Error()}
 	}
-	return CheckResult{
-		Name:    name,
-		Status:  StatusFixed,
-		Message: fmt.Sprintf("%d lock entries verified", len(lock.Skills)),
-		Fix:     strings.Join(fixes, "; "),
+	return fixes.result(name, StatusFixed, fmt.Sprintf("%d lock entries verified", len(lock.Skills)))
+}
+
+// --- check 8: deprecated ---
+
+// checkDeprecated warns about installed skills marked deprecated, either in
+// the record written at install time or in the source's cached SKILL.md.
+func (s *Service) checkDeprecated(st store.State, stateErr error) CheckResult {
+	name := "deprecated"
+	if stateErr != nil {
+		return CheckResult{Name: name, Status: StatusError, Message: stateErr.Error()}
+	}
+	var found []string
+	for _, rec := range st.Installed {
+		deprecated, supersededBy := rec.Deprecated, rec.SupersededBy
+		if s.UpstreamSkill != nil {
+			if content, ok := s.UpstreamSkill(rec); ok {
+				deprecated, supersededBy = source.ParseDeprecation(content)
+			}
+		}
+		if !deprecated {
+			continue
+		}
+		if supersededBy != "" {
+			found = append(found, fmt.Sprintf("%s (use %s)", rec.SkillRef, supersededBy))
+		} else {
+			found = append(found, rec.SkillRef)
+		}
+	}
+	if len(found) == 0 {
+		return CheckResult{Name: name, Status: StatusOK, Message: "no deprecated skills installed"}
+	}
+	sort.Strings(found)
+	return CheckResult{Name: name, Status: StatusWarn, Message: fmt.Sprintf("%d deprecated skill(s) installed: %s", len(found), strings.Join(found, ", "))}
+}
+
+// --- check 9: source-review ---
+
+// checkSourceReview warns about sources whose review_interval has lapsed
+// since their last `source review`.
+func (s *Service) checkSourceReview() CheckResult {
+	name := "source-review"
+	cfg, err := config.Load(s.ConfigPath)
+	if err != nil {
+		return CheckResult{Name: name, Status: StatusError, Message: err.Error()}
+	}
+	due := config.SourcesDueForReview(cfg, time.Now())
+	if len(due) == 0 {
+		return CheckResult{Name: name, Status: StatusOK, Message: "no source reviews due"}
+	}
+	var fixes fixList
+	actions := make([]FixAction, 0, len(due))
+	for _, src := range due {
+		actions = append(actions, FixAction{Type: "review-source", Target: src})
 	}
+	fixes.add("re-audit, then run 'skillpm source review <name>'", actions...)
+	return fixes.result(name, StatusWarn, fmt.Sprintf("%d source(s) due for review: %s", len(due), strings.Join(due, ", ")))
 }
 
 // --- helpers ---
 
+// readOnly reports whether checks must leave everything as they found it.
+func (s *Service) readOnly() bool {
+	return s.ReportOnly || s.DryRun
+}
+
+// repaired reports fixes that a check applied, or, in report-only mode,
+// the fixes it would have applied as a warning. In dry-run mode they are
+// reported as fixed but marked as not applied.
+func (s *Service) repaired(name, message string, fixes fixList) CheckResult {
+	res := fixes.result(name, StatusFixed, message)
+	if s.DryRun {
+		res.Fix = "not applied (dry run): " + res.Fix
+	} else if s.ReportOnly {
+		res.Status = StatusWarn
+		res.Fix = "not applied (report only): " + res.Fix
+	}
+	return res
+}
+
+// fixList collects a check's fixes, each as the text shown to people and
+// the actions reported to automation.
+type fixList struct {
+	texts   []string
+	actions []FixAction
+}
+
+func (f *fixList) add(text string, actions ...FixAction) {
+	f.texts = append(f.texts, text)
+	f.actions = append(f.actions, actions...)
+}
+
+func (f fixList) empty() bool { return len(f.texts) == 0 }
+
+// result returns a check result whose Fix and FixActions hold the fixes.
+func (f fixList) result(name string, status CheckStatus, message string) CheckResult {
+	return CheckResult{Name: name, Status: status, Message: message, Fix: strings.Join(f.texts, "; "), FixActions: f.actions}
+}
+
+// enableActions returns one enable-adapter action per adapter.
+func enableActions(adapters []string) []FixAction {
+	out := make([]FixAction, 0, len(adapters))
+	for _, name := range adapters {
+		out = append(out, FixAction{Type: "enable-adapter", Agent: name})
+	}
+	return out
+}
+
 func skillSetsEqual(a, b []string) bool {
 	if len(a) != len(b) {
 		return false
@@ -4,6 +4,8 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -169,7 +171,7 @@ func TestCheckInstalledDirs_OK(t *testing.T) {
 	}}
 	saveState(t, stateRoot, st)
 	// Create matching dir.
-	dirName := store.InstalledDirName("hub/demo", "1.0.0")
+	dirName := store.InstalledDirName("hub/demo", "1.0.0", 0)
 	if err := os.MkdirAll(filepath.Join(store.InstalledRoot(stateRoot), dirName), 0o755); err != nil {
 		t.Fatal(err)
 	}
@@ -196,6 +198,10 @@ func TestCheckInstalledDirs_Ghost(t *testing.T) {
 	if r.Status != StatusFixed {
 		t.Fatalf("expected fixed, got %s", r.Status)
 	}
+	want := []FixAction{{Type: "remove-ghost", Target: "hub/ghost"}}
+	if !reflect.DeepEqual(r.FixActions, want) {
+		t.Fatalf("expected fix actions %+v, got %+v", want, r.FixActions)
+	}
 	// State should no longer have the ghost entry.
 	reloaded, _ := store.LoadState(stateRoot)
 	if len(reloaded.Installed) != 0 {
@@ -203,6 +209,27 @@ func TestCheckInstalledDirs_Ghost(t *testing.T) {
 	}
 }
 
+func TestCheckInstalledDirs_HashedNameIsNotOrphan(t *testing.T) {
+	_, cfgPath, stateRoot := setupTestEnv(t)
+	cfg := config.DefaultConfig()
+	saveConfig(t, cfgPath, cfg)
+	ref := "hub/teams/platform/tooling/deep-skill"
+	saveState(t, stateRoot, store.State{Version: store.StateVersion, Installed: []store.InstalledSkill{
+		{SkillRef: ref, ResolvedVersion: "1.0.0", Source: "hub", Skill: "deep-skill", Checksum: "abc", SourceRef: "abc"},
+	}})
+	// Dir was written under the hashed scheme, e.g. before the threshold changed.
+	hashed := store.InstalledDirNames(ref, "1.0.0")[1]
+	if err := os.MkdirAll(filepath.Join(store.InstalledRoot(stateRoot), hashed), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	svc := newService(t, cfgPath, stateRoot, "", "", config.ScopeGlobal)
+	st, stateErr := loadTestState(t, stateRoot)
+	r := svc.checkInstalledDirs(st, stateErr)
+	if r.Status != StatusOK {
+		t.Fatalf("expected ok for hashed dir, got %s: %s", r.Status, r.Message)
+	}
+}
+
 func TestCheckInstalledDirs_Orphan(t *testing.T) {
 	_, cfgPath, stateRoot := setupTestEnv(t)
 	cfg := config.DefaultConfig()
@@ -275,6 +302,46 @@ func TestCheckInjections_StaleRef(t *testing.T) {
 	}
 }
 
+func TestCheckInjections_ReinstallMissingKeepsInjection(t *testing.T) {
+	_, cfgPath, stateRoot := setupTestEnv(t)
+	saveConfig(t, cfgPath, config.DefaultConfig())
+	saveState(t, stateRoot, store.State{
+		Version: store.StateVersion,
+		Injections: []store.InjectionState{
+			{Agent: "claude", Skills: []string{"hub/back", "hub/lost"}},
+		},
+	})
+	svc := newService(t, cfgPath, stateRoot, "", "", config.ScopeGlobal)
+	svc.ReinstallMissing = true
+	var asked []string
+	svc.Reinstall = func(_ context.Context, refs []string) []string {
+		asked = refs
+		// Simulate a successful reinstall of hub/back only.
+		st, _ := store.LoadState(stateRoot)
+		store.UpsertInstalled(&st, store.InstalledSkill{SkillRef: "hub/back", ResolvedVersion: "1.0.0"})
+		saveState(t, stateRoot, st)
+		return []string{"hub/back"}
+	}
+	loadedSt, loadErr := loadTestState(t, stateRoot)
+	r := svc.checkInjections(loadedSt, loadErr)
+	if r.Status != StatusFixed {
+		t.Fatalf("expected fixed, got %s", r.Status)
+	}
+	if len(asked) != 2 {
+		t.Fatalf("expected reinstall of both missing refs, got %v", asked)
+	}
+	if !strings.Contains(r.Fix, "reinstalled missing skill hub/back") || !strings.Contains(r.Fix, "removed stale ref hub/lost") {
+		t.Fatalf("unexpected fix message: %s", r.Fix)
+	}
+	reloaded, _ := store.LoadState(stateRoot)
+	if len(reloaded.Installed) != 1 {
+		t.Fatalf("expected reinstalled record to survive, got %+v", reloaded.Installed)
+	}
+	if len(reloaded.Injections) != 1 || len(reloaded.Injections[0].Skills) != 1 || reloaded.Injections[0].Skills[0] != "hub/back" {
+		t.Fatalf("expected injection of hub/back to be kept, got %+v", reloaded.Injections)
+	}
+}
+
 func TestCheckInjections_PartialStale(t *testing.T) {
 	_, cfgPath, stateRoot := setupTestEnv(t)
 	cfg := config.DefaultConfig()
@@ -357,7 +424,7 @@ func TestCheckAdapterState_Drift(t *testing.T) {
 	saveState(t, stateRoot, st)
 
 	// Create installed dir with a skill file.
-	dirName := store.InstalledDirName("hub/demo", "1.0.0")
+	dirName := store.InstalledDirName("hub/demo", "1.0.0", 0)
 	skillDir := filepath.Join(store.InstalledRoot(stateRoot), dirName)
 	if err := os.MkdirAll(skillDir, 0o755); err != nil {
 		t.Fatal(err)
@@ -422,7 +489,7 @@ func TestCheckAgentSkills_FixedMissing(t *testing.T) {
 	saveConfig(t, cfgPath, cfg)
 
 	// Installed dir has the skill.
-	dirName := store.InstalledDirName("hub/demo", "1.0.0")
+	dirName := store.InstalledDirName("hub/demo", "1.0.0", 0)
 	skillSrc := filepath.Join(store.InstalledRoot(stateRoot), dirName)
 	if err := os.MkdirAll(skillSrc, 0o755); err != nil {
 		t.Fatal(err)
@@ -456,6 +523,42 @@ func TestCheckAgentSkills_FixedMissing(t *testing.T) {
 	}
 }
 
+func TestCheckAgentSkills_UsesDirOverride(t *testing.T) {
+	home, cfgPath, stateRoot := setupTestEnv(t)
+	custom := filepath.Join(home, "relocated", "claude-skills")
+	cfg := config.DefaultConfig()
+	cfg.Adapters = []config.AdapterConfig{{Name: "claude", Enabled: true, Scope: "global", Dir: custom}}
+	saveConfig(t, cfgPath, cfg)
+
+	skillSrc := filepath.Join(store.InstalledRoot(stateRoot), store.InstalledDirName("hub/demo", "1.0.0", 0))
+	if err := os.MkdirAll(skillSrc, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(skillSrc, "SKILL.md"), []byte("# demo"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	saveState(t, stateRoot, store.State{
+		Version:    store.StateVersion,
+		Installed:  []store.InstalledSkill{{SkillRef: "hub/demo", ResolvedVersion: "1.0.0", Source: "hub", Skill: "demo"}},
+		Injections: []store.InjectionState{{Agent: "claude", Skills: []string{"hub/demo"}}},
+	})
+
+	svc := newService(t, cfgPath, stateRoot, "", "", config.ScopeGlobal)
+	loadedSt, loadErr := loadTestState(t, stateRoot)
+	if r := svc.checkAgentSkills(loadedSt, loadErr); r.Status != StatusFixed {
+		t.Fatalf("expected fixed, got %s", r.Status)
+	}
+	if _, err := os.Stat(filepath.Join(custom, "demo", "SKILL.md")); err != nil {
+		t.Fatalf("expected skill restored into the override dir: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(home, ".claude", "skills", "demo")); !os.IsNotExist(err) {
+		t.Fatalf("expected default dir untouched, got %v", err)
+	}
+	if r := svc.checkAgentSkills(loadedSt, loadErr); r.Status != StatusOK {
+		t.Fatalf("expected ok on second run, got %s", r.Status)
+	}
+}
+
 // --- check 7: lockfile ---
 
 func TestCheckLockfile_OK(t *testing.T) {
@@ -578,7 +681,7 @@ func TestRunIdempotent(t *testing.T) {
 	cfg.Adapters = []config.AdapterConfig{{Name: "claude", Enabled: true, Scope: "global"}}
 	saveConfig(t, cfgPath, cfg)
 
-	dirName := store.InstalledDirName("hub/demo", "1.0.0")
+	dirName := store.InstalledDirName("hub/demo", "1.0.0", 0)
 	skillDir := filepath.Join(store.InstalledRoot(stateRoot), dirName)
 	if err := os.MkdirAll(skillDir, 0o755); err != nil {
 		t.Fatal(err)
@@ -695,3 +798,207 @@ func TestRunIdempotent_FixThenClean(t *testing.T) {
 		}
 	}
 }
+
+func TestRunDryRunCountsFixesWithoutApplying(t *testing.T) {
+	_, cfgPath, stateRoot := setupTestEnv(t)
+	lockPath := filepath.Join(stateRoot, "skills.lock")
+	saveConfig(t, cfgPath, config.DefaultConfig())
+	saveState(t, stateRoot, store.State{Version: store.StateVersion})
+	orphanDir := filepath.Join(store.InstalledRoot(stateRoot), "orphan_skill@v0.0.0")
+	if err := os.MkdirAll(orphanDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	svc := newService(t, cfgPath, stateRoot, lockPath, "", config.ScopeGlobal)
+	svc.DryRun = true
+	r1 := svc.Run(context.Background())
+	if !r1.DryRun || r1.Fixed == 0 {
+		t.Fatalf("expected dry-run fixes to be counted, got %+v", r1)
+	}
+	for _, c := range r1.Checks {
+		if c.Status == StatusFixed && !strings.HasPrefix(c.Fix, "not applied (dry run): ") {
+			t.Errorf("check %s fix not marked as a dry run: %s", c.Name, c.Fix)
+		}
+	}
+	if _, err := os.Stat(orphanDir); err != nil {
+		t.Fatalf("dry run removed the orphan dir: %v", err)
+	}
+	if r2 := svc.Run(context.Background()); r2.Fixed != r1.Fixed {
+		t.Fatalf("expected a second dry run to find the same %d fixes, got %d", r1.Fixed, r2.Fixed)
+	}
+}
+
+func TestRunProfileRecordsDurations(t *testing.T) {
+	_, cfgPath, stateRoot := setupTestEnv(t)
+	lockPath := filepath.Join(stateRoot, "skills.lock")
+	saveConfig(t, cfgPath, config.DefaultConfig())
+	saveState(t, stateRoot, store.State{Version: store.StateVersion})
+
+	svc := newService(t, cfgPath, stateRoot, lockPath, "", config.ScopeGlobal)
+	for _, c := range svc.Run(context.Background()).Checks {
+		if c.DurationMs != 0 {
+			t.Fatalf("check %s timed without --profile: %v", c.Name, c.DurationMs)
+		}
+	}
+
+	svc.Profile = true
+	rpt := svc.Run(context.Background())
+	var total float64
+	for _, c := range rpt.Checks {
+		total += c.DurationMs
+	}
+	if total <= 0 {
+		t.Fatalf("expected profiled durations, got %+v", rpt.Checks)
+	}
+	slowest := rpt.Slowest()
+	if len(slowest) != len(rpt.Checks) {
+		t.Fatalf("slowest has %d checks, want %d", len(slowest), len(rpt.Checks))
+	}
+	for i := 1; i < len(slowest); i++ {
+		if slowest[i].DurationMs > slowest[i-1].DurationMs {
+			t.Fatalf("slowest not sorted: %+v", slowest)
+		}
+	}
+}
+
+func TestCheckDeprecated_RecordAndUpstream(t *testing.T) {
+	_, cfgPath, stateRoot := setupTestEnv(t)
+	saveConfig(t, cfgPath, config.DefaultConfig())
+	saveState(t, stateRoot, store.State{
+		Version: store.StateVersion,
+		Installed: []store.InstalledSkill{
+			{SkillRef: "hub/old", Skill: "old", Deprecated: true, SupersededBy: "hub/new"},
+			{SkillRef: "hub/fine", Skill: "fine"},
+		},
+	})
+	svc := newService(t, cfgPath, stateRoot, "", "", config.ScopeGlobal)
+
+	st, stErr := loadTestState(t, stateRoot)
+	r := svc.checkDeprecated(st, stErr)
+	if r.Status != StatusWarn || !strings.Contains(r.Message, "hub/old (use hub/new)") || strings.Contains(r.Message, "hub/fine") {
+		t.Fatalf("expected warning for the deprecated record, got %+v", r)
+	}
+
+	// The cached upstream SKILL.md wins over the record written at install.
+	svc.UpstreamSkill = func(rec store.InstalledSkill) (string, bool) {
+		if rec.Skill == "fine" {
+			return "---\ndeprecated: true\n---\n# Fine\n", true
+		}
+		return "# Old, revived\n", true
+	}
+	r = svc.checkDeprecated(st, stErr)
+	if r.Status != StatusWarn || !strings.Contains(r.Message, "1 deprecated skill(s) installed: hub/fine") {
+		t.Fatalf("expected upstream deprecation to be reported, got %+v", r)
+	}
+}
+
+func TestCheckSourceReview_WarnsWhenIntervalLapsed(t *testing.T) {
+	_, cfgPath, stateRoot := setupTestEnv(t)
+	cfg := config.DefaultConfig()
+	cfg.Sources[0].ReviewInterval = "30d"
+	cfg.Sources[0].LastReviewed = time.Now().Add(-31 * 24 * time.Hour).UTC().Format(time.RFC3339)
+	cfg.Sources[1].ReviewInterval = "30d"
+	cfg.Sources[1].LastReviewed = time.Now().UTC().Format(time.RFC3339)
+	saveConfig(t, cfgPath, cfg)
+	svc := newService(t, cfgPath, stateRoot, "", "", config.ScopeGlobal)
+
+	r := svc.checkSourceReview()
+	if r.Status != StatusWarn || r.Message != "1 source(s) due for review: "+cfg.Sources[0].Name {
+		t.Fatalf("expected one overdue source, got %+v", r)
+	}
+
+	if _, err := config.MarkSourceReviewed(&cfg, cfg.Sources[0].Name, time.Now()); err != nil {
+		t.Fatalf("mark reviewed: %v", err)
+	}
+	saveConfig(t, cfgPath, cfg)
+	if r := svc.checkSourceReview(); r.Status != StatusOK {
+		t.Fatalf("expected ok after review, got %+v", r)
+	}
+}
+
+func TestOrderChecksRunsDependenciesFirst(t *testing.T) {
+	noop := func(store.State, error) CheckResult { return CheckResult{} }
+	ordered, err := orderChecks([]check{
+		{name: "c", deps: []string{"b"}, run: noop},
+		{name: "a", run: noop},
+		{name: "b", deps: []string{"a", "absent"}, run: noop},
+	})
+	if err != nil {
+		t.Fatalf("order: %v", err)
+	}
+	var names []string
+	for _, c := range ordered {
+		names = append(names, c.name)
+	}
+	if strings.Join(names, ",") != "a,b,c" {
+		t.Fatalf("unexpected order %v", names)
+	}
+	if _, err := orderChecks([]check{{name: "x", deps: []string{"y"}, run: noop}, {name: "y", deps: []string{"x"}, run: noop}}); err == nil || !strings.Contains(err.Error(), "DOC_CHECK_ORDER") {
+		t.Fatalf("expected cycle error, got %v", err)
+	}
+}
+
+func TestRunConvergesInOnePass(t *testing.T) {
+	home, cfgPath, stateRoot := setupTestEnv(t)
+	lockPath := filepath.Join(stateRoot, "skills.lock")
+	claudeDir := filepath.Join(home, ".claude")
+	cfg := config.DefaultConfig()
+	cfg.Adapters = []config.AdapterConfig{{Name: "claude", Enabled: true, Scope: "global"}}
+	saveConfig(t, cfgPath, cfg)
+
+	// hub/demo is installed but missing from the agent and the lockfile.
+	skillDir := filepath.Join(store.InstalledRoot(stateRoot), store.InstalledDirName("hub/demo", "1.0.0", 0))
+	if err := os.MkdirAll(skillDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte("# demo"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// An orphan dir on disk, and hub/ghost recorded, injected and locked
+	// without its dir: fixing one exposes drift in the next.
+	if err := os.MkdirAll(filepath.Join(store.InstalledRoot(stateRoot), "orphan_skill@v0.0.0"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	saveState(t, stateRoot, store.State{
+		Version: store.StateVersion,
+		Installed: []store.InstalledSkill{
+			{SkillRef: "hub/demo", ResolvedVersion: "1.0.0", Source: "hub", Skill: "demo", Checksum: "abc", SourceRef: "hub@main"},
+			{SkillRef: "hub/ghost", ResolvedVersion: "1.0.0", Source: "hub", Skill: "ghost", Checksum: "def", SourceRef: "hub@main"},
+		},
+		Injections: []store.InjectionState{
+			{Agent: "claude", Skills: []string{"hub/demo", "hub/ghost"}, UpdatedAt: time.Now()},
+		},
+	})
+	adapterDir := filepath.Join(claudeDir, "skillpm")
+	if err := os.MkdirAll(adapterDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	blob, _ := toml.Marshal(struct {
+		Skills []string `toml:"skills"`
+	}{Skills: []string{"hub/ghost"}})
+	if err := os.WriteFile(filepath.Join(adapterDir, "injected.toml"), blob, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SaveLockfile(lockPath, store.Lockfile{Version: store.LockVersion, Skills: []store.LockSkill{
+		{SkillRef: "hub/ghost", ResolvedVersion: "1.0.0", Checksum: "def", SourceRef: "hub@main"},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+
+	r1 := newService(t, cfgPath, stateRoot, lockPath, "", config.ScopeGlobal).Run(context.Background())
+	if r1.Errors != 0 || r1.Fixed == 0 {
+		t.Fatalf("expected fixes without errors, got %+v", r1)
+	}
+
+	verify := newService(t, cfgPath, stateRoot, lockPath, "", config.ScopeGlobal)
+	verify.ReportOnly = true
+	r2 := verify.Run(context.Background())
+	for _, c := range r2.Checks {
+		if c.Status != StatusOK {
+			t.Errorf("after one run, check %s is %s: %s %s", c.Name, c.Status, c.Message, c.Fix)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(claudeDir, "skills", "demo", "SKILL.md")); err != nil {
+		t.Fatalf("expected demo restored for claude: %v", err)
+	}
+}
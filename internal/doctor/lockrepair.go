@@ -0,0 +1,145 @@
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"skillpm/internal/fsutil"
+	"skillpm/internal/store"
+)
+
+// checkRepairFromLock makes installed state match the lockfile: locked
+// skills that are missing, at another version, or without a directory are
+// reinstalled, and installed skills absent from the lock are removed. The
+// state file is backed up before anything changes.
+func (s *Service) checkRepairFromLock(st store.State, stateErr error) CheckResult {
+	name := "lock-repair"
+	if stateErr != nil {
+		return CheckResult{Name: name, Status: StatusError, Message: stateErr.Error()}
+	}
+	if _, err := os.Stat(s.LockPath); err != nil {
+		return CheckResult{Name: name, Status: StatusError, Message: fmt.Sprintf("lockfile not found: %s", s.LockPath)}
+	}
+	lock, err := store.LoadLockfile(s.LockPath)
+	if err != nil {
+		return CheckResult{Name: name, Status: StatusError, Message: err.Error()}
+	}
+	if s.Reinstall == nil || s.Uninstall == nil {
+		return CheckResult{Name: name, Status: StatusError, Message: "lock repair is not available"}
+	}
+
+	installed := map[string]store.InstalledSkill{}
+	for _, rec := range st.Installed {
+		installed[rec.SkillRef] = rec
+	}
+	locked := map[string]store.LockSkill{}
+	var reinstall []string
+	for _, entry := range lock.Skills {
+		locked[entry.SkillRef] = entry
+		rec, ok := installed[entry.SkillRef]
+		if ok && rec.ResolvedVersion == entry.ResolvedVersion && store.FindInstalledDir(s.StateRoot, entry.SkillRef) != "" {
+			continue
+		}
+		reinstall = append(reinstall, entry.SkillRef)
+	}
+	var remove []string
+	for ref := range installed {
+		if _, ok := locked[ref]; !ok {
+			remove = append(remove, ref)
+		}
+	}
+	sort.Strings(reinstall)
+	sort.Strings(remove)
+
+	if len(reinstall) == 0 && len(remove) == 0 {
+		return CheckResult{Name: name, Status: StatusOK, Message: fmt.Sprintf("state matches %d lock entries", len(lock.Skills))}
+	}
+
+	var fixes fixList
+	if s.readOnly() {
+		for _, ref := range reinstall {
+			fixes.add("reinstall "+ref, FixAction{Type: "reinstall-skill", Target: ref})
+		}
+		for _, ref := range remove {
+			fixes.add("remove "+ref, FixAction{Type: "uninstall-skill", Target: ref})
+		}
+		return s.repaired(name, "state rebuilt from lockfile", fixes)
+	}
+
+	backup, err := backupState(s.StateRoot)
+	if err != nil {
+		return CheckResult{Name: name, Status: StatusError, Message: fmt.Sprintf("state backup failed: %v", err)}
+	}
+	if backup != "" {
+		fixes.add("backed up state to "+backup, FixAction{Type: "backup-state", Target: backup})
+	}
+
+	ctx := context.Background()
+	if len(remove) > 0 {
+		for _, ref := range s.Uninstall(ctx, remove) {
+			fixes.add("removed unlocked skill "+ref, FixAction{Type: "uninstall-skill", Target: ref})
+		}
+	}
+
+	var failed []string
+	if len(reinstall) > 0 {
+		done := map[string]struct{}{}
+		for _, ref := range s.Reinstall(ctx, reinstall) {
+			done[ref] = struct{}{}
+			fixes.add("reinstalled "+ref+"@"+locked[ref].ResolvedVersion, FixAction{Type: "reinstall-skill", Target: ref})
+		}
+		for _, ref := range reinstall {
+			if _, ok := done[ref]; !ok {
+				failed = append(failed, ref)
+			}
+		}
+	}
+
+	// Verify reinstalled content against the checksums the lock was
+	// committed with.
+	after, err := store.LoadState(s.StateRoot)
+	if err != nil {
+		return CheckResult{Name: name, Status: StatusError, Message: err.Error()}
+	}
+	var mismatched []string
+	for _, rec := range after.Installed {
+		entry, ok := locked[rec.SkillRef]
+		if ok && entry.Checksum != "" && rec.Checksum != entry.Checksum {
+			mismatched = append(mismatched, rec.SkillRef)
+		}
+	}
+
+	if len(failed) > 0 || len(mismatched) > 0 {
+		msg := "lock repair incomplete"
+		if len(failed) > 0 {
+			msg += fmt.Sprintf("; could not reinstall: %s", strings.Join(failed, ", "))
+		}
+		if len(mismatched) > 0 {
+			msg += fmt.Sprintf("; checksum differs from lock: %s", strings.Join(mismatched, ", "))
+		}
+		return fixes.result(name, StatusError, msg)
+	}
+	return s.repaired(name, "state rebuilt from lockfile", fixes)
+}
+
+// backupState copies state.toml next to itself with a timestamp suffix and
+// returns the backup path, or "" when there is no state file yet.
+func backupState(root string) (string, error) {
+	path := store.StatePath(root)
+	blob, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	backup := fmt.Sprintf("%s.bak-%d", path, time.Now().UnixNano())
+	if err := fsutil.AtomicWrite(backup, blob, 0o644); err != nil {
+		return "", err
+	}
+	return backup, nil
+}
@@ -0,0 +1,97 @@
+package doctor
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"skillpm/internal/config"
+	"skillpm/internal/store"
+)
+
+func setupLockRepair(t *testing.T) (*Service, string) {
+	t.Helper()
+	_, cfgPath, stateRoot := setupTestEnv(t)
+	saveConfig(t, cfgPath, config.DefaultConfig())
+	saveState(t, stateRoot, store.State{
+		Version: store.StateVersion,
+		Installed: []store.InstalledSkill{
+			{SkillRef: "hub/b", ResolvedVersion: "1.0.0", Checksum: "old"},
+			{SkillRef: "hub/extra", ResolvedVersion: "1.0.0"},
+		},
+	})
+	lockPath := filepath.Join(t.TempDir(), "skills.lock")
+	lock := store.Lockfile{Version: store.LockVersion, Skills: []store.LockSkill{
+		{SkillRef: "hub/a", ResolvedVersion: "1.0.0", Checksum: "ca", SourceRef: "hub@1.0.0"},
+		{SkillRef: "hub/b", ResolvedVersion: "2.0.0", Checksum: "cb", SourceRef: "hub@2.0.0"},
+	}}
+	if err := store.SaveLockfile(lockPath, lock); err != nil {
+		t.Fatalf("save lockfile: %v", err)
+	}
+	svc := newService(t, cfgPath, stateRoot, lockPath, "", config.ScopeGlobal)
+	svc.RepairFromLock = true
+	return svc, stateRoot
+}
+
+func TestCheckRepairFromLock_RebuildsStateFromLock(t *testing.T) {
+	svc, stateRoot := setupLockRepair(t)
+	var reinstalled, removed []string
+	svc.Reinstall = func(_ context.Context, refs []string) []string {
+		reinstalled = refs
+		st, _ := store.LoadState(stateRoot)
+		for _, ref := range refs {
+			checksum := map[string]string{"hub/a": "ca", "hub/b": "cb"}[ref]
+			store.UpsertInstalled(&st, store.InstalledSkill{SkillRef: ref, ResolvedVersion: "locked", Checksum: checksum})
+		}
+		saveState(t, stateRoot, st)
+		return refs
+	}
+	svc.Uninstall = func(_ context.Context, refs []string) []string {
+		removed = refs
+		st, _ := store.LoadState(stateRoot)
+		for _, ref := range refs {
+			store.RemoveInstalled(&st, ref)
+		}
+		saveState(t, stateRoot, st)
+		return refs
+	}
+
+	st, stErr := loadTestState(t, stateRoot)
+	r := svc.checkRepairFromLock(st, stErr)
+	if r.Status != StatusFixed {
+		t.Fatalf("expected fixed, got %s: %s", r.Status, r.Message)
+	}
+	if strings.Join(reinstalled, ",") != "hub/a,hub/b" || strings.Join(removed, ",") != "hub/extra" {
+		t.Fatalf("unexpected repair plan: reinstall=%v remove=%v", reinstalled, removed)
+	}
+	if !strings.Contains(r.Fix, "backed up state to ") {
+		t.Fatalf("expected state backup in fix, got %s", r.Fix)
+	}
+	backups, _ := filepath.Glob(store.StatePath(stateRoot) + ".bak-*")
+	if len(backups) != 1 {
+		t.Fatalf("expected one state backup, got %v", backups)
+	}
+}
+
+func TestCheckRepairFromLock_ReportOnlyChangesNothing(t *testing.T) {
+	svc, stateRoot := setupLockRepair(t)
+	svc.ReportOnly = true
+	svc.Reinstall = func(context.Context, []string) []string { t.Fatal("reinstall called in report-only mode"); return nil }
+	svc.Uninstall = func(context.Context, []string) []string { t.Fatal("uninstall called in report-only mode"); return nil }
+
+	st, stErr := loadTestState(t, stateRoot)
+	r := svc.checkRepairFromLock(st, stErr)
+	if r.Status != StatusWarn || !strings.Contains(r.Fix, "reinstall hub/a") || !strings.Contains(r.Fix, "remove hub/extra") {
+		t.Fatalf("expected report-only plan, got %+v", r)
+	}
+}
+
+func TestCheckRepairFromLock_RequiresLockfile(t *testing.T) {
+	svc, stateRoot := setupLockRepair(t)
+	svc.LockPath = filepath.Join(t.TempDir(), "missing.lock")
+	st, stErr := loadTestState(t, stateRoot)
+	if r := svc.checkRepairFromLock(st, stErr); r.Status != StatusError {
+		t.Fatalf("expected error without lockfile, got %+v", r)
+	}
+}
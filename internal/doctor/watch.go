@@ -0,0 +1,45 @@
+package doctor
+
+import (
+	"context"
+	"time"
+)
+
+// Watch runs the diagnostics every interval until ctx is cancelled and calls
+// onChange with the first report and with every report whose check outcomes
+// differ from the previous one.
+func (s *Service) Watch(ctx context.Context, interval time.Duration, onChange func(Report)) error {
+	if interval <= 0 {
+		interval = 5 * time.Minute
+	}
+	var prev *Report
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		rpt := s.Run(ctx)
+		if prev == nil || !sameOutcome(*prev, rpt) {
+			onChange(rpt)
+		}
+		prev = &rpt
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// sameOutcome reports whether two reports have identical per-check results.
+func sameOutcome(a, b Report) bool {
+	if len(a.Checks) != len(b.Checks) {
+		return false
+	}
+	for i := range a.Checks {
+		// FixActions follow from Fix, so they need no comparing.
+		x, y := a.Checks[i], b.Checks[i]
+		if x.Name != y.Name || x.Status != y.Status || x.Message != y.Message || x.Fix != y.Fix || x.DurationMs != y.DurationMs {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,71 @@
+package doctor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"skillpm/internal/config"
+	"skillpm/internal/store"
+)
+
+func TestSameOutcome(t *testing.T) {
+	a := Report{Checks: []CheckResult{{Name: "state", Status: StatusOK, Message: "state valid"}}}
+	b := Report{Checks: []CheckResult{{Name: "state", Status: StatusOK, Message: "state valid"}}}
+	if !sameOutcome(a, b) {
+		t.Fatal("expected identical reports to match")
+	}
+	b.Checks[0].Status = StatusFixed
+	if sameOutcome(a, b) {
+		t.Fatal("expected status change to be detected")
+	}
+	if sameOutcome(a, Report{}) {
+		t.Fatal("expected differing check counts to be detected")
+	}
+}
+
+func TestWatchReportsOnlyChanges(t *testing.T) {
+	_, cfgPath, stateRoot := setupTestEnv(t)
+	saveConfig(t, cfgPath, config.DefaultConfig())
+	saveState(t, stateRoot, store.State{Version: store.StateVersion})
+	svc := newService(t, cfgPath, stateRoot, "", "", config.ScopeGlobal)
+	svc.ReportOnly = true
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var reports []Report
+	runs := 0
+	done := make(chan error, 1)
+	go func() {
+		done <- svc.Watch(ctx, 10*time.Millisecond, func(r Report) {
+			reports = append(reports, r)
+			runs++
+			if runs == 1 {
+				// Introduce drift; report-only mode leaves it in place.
+				_ = os.MkdirAll(filepath.Join(store.InstalledRoot(stateRoot), "orphan@1.0.0"), 0o755)
+			}
+			if runs == 2 {
+				cancel()
+			}
+		})
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("watch returned error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("watch did not stop after cancel")
+	}
+	if len(reports) != 2 {
+		t.Fatalf("expected 2 change reports, got %d", len(reports))
+	}
+	if reports[1].Warnings == 0 {
+		t.Fatalf("expected orphan drift to be reported as warning, got %+v", reports[1].Checks)
+	}
+	if _, err := os.Stat(filepath.Join(store.InstalledRoot(stateRoot), "orphan@1.0.0")); err != nil {
+		t.Fatalf("report-only watch must not remove orphan: %v", err)
+	}
+}
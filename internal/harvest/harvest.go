@@ -6,10 +6,15 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"time"
+	"unicode"
 
 	"skillpm/internal/adapter"
 	"skillpm/internal/importer"
+	"skillpm/internal/security"
+	"skillpm/internal/source"
 	"skillpm/internal/store"
 	"skillpm/pkg/adapterapi"
 )
@@ -17,15 +22,52 @@ import (
 type Service struct {
 	Runtime   *adapter.Runtime
 	StateRoot string
+	// Scanner, when set, scans every valid harvested skill with the same
+	// rules applied to installs.
+	Scanner *security.Scanner
 }
 
+// InboxEntry is one skill found in an agent's skills directory. SourcePath
+// is Path with symlinks resolved, and SuggestedRef is the <source>/<skill>
+// ref the skill would get once promoted, derived from the agent name and
+// the skill's folder name.
 type InboxEntry struct {
-	Agent     string    `json:"agent"`
-	Path      string    `json:"path"`
-	SkillName string    `json:"skillName"`
-	Valid     bool      `json:"valid"`
-	Reason    string    `json:"reason,omitempty"`
-	CreatedAt time.Time `json:"createdAt"`
+	Agent        string    `json:"agent"`
+	Path         string    `json:"path"`
+	SkillName    string    `json:"skillName"`
+	SourcePath   string    `json:"sourcePath"`
+	SuggestedRef string    `json:"suggestedRef"`
+	Valid        bool      `json:"valid"`
+	Reason       string    `json:"reason,omitempty"`
+	Findings     *Findings `json:"findings,omitempty"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+// Findings summarizes the security scan of a harvested skill. Status is
+// "clean", "findings", or "skipped" when the scanner is disabled.
+type Findings struct {
+	Status      string   `json:"status"`
+	Count       int      `json:"count"`
+	MaxSeverity string   `json:"maxSeverity,omitempty"`
+	Rules       []string `json:"rules,omitempty"`
+}
+
+// Blocked reports whether the findings include a critical one.
+func (f *Findings) Blocked() bool {
+	return f != nil && f.MaxSeverity == security.SeverityCritical.String()
+}
+
+// CheckPromote applies the install safety bar to an inbox entry before it
+// is promoted: invalid skills are always refused, and skills with critical
+// findings are refused unless force is set.
+func CheckPromote(entry InboxEntry, force bool) error {
+	if !entry.Valid {
+		return fmt.Errorf("HRV_PROMOTE_INVALID: %s from %s failed validation: %s", entry.SkillName, entry.Agent, entry.Reason)
+	}
+	if entry.Findings.Blocked() && !force {
+		return fmt.Errorf("HRV_PROMOTE_BLOCKED: %s from %s has critical findings (%v); use --force to proceed", entry.SkillName, entry.Agent, entry.Findings.Rules)
+	}
+	return nil
 }
 
 func (s *Service) Harvest(ctx context.Context, agentName string) ([]InboxEntry, string, error) {
@@ -42,12 +84,20 @@ func (s *Service) Harvest(ctx context.Context, agentName string) ([]InboxEntry,
 	}
 	entries := make([]InboxEntry, 0, len(res.Candidates))
 	for _, c := range res.Candidates {
-		entry := InboxEntry{Agent: agentName, Path: c.Path, SkillName: c.Name, CreatedAt: time.Now().UTC()}
+		entry := InboxEntry{
+			Agent:        agentName,
+			Path:         c.Path,
+			SkillName:    c.Name,
+			SourcePath:   sourcePath(c.Path),
+			SuggestedRef: SuggestRef(agentName, c.Path),
+			CreatedAt:    time.Now().UTC(),
+		}
 		if _, err := importer.ValidateSkillDir(c.Path); err != nil {
 			entry.Valid = false
 			entry.Reason = err.Error()
 		} else {
 			entry.Valid = true
+			entry.Findings = s.scan(ctx, agentName, c.Name, c.Path)
 		}
 		entries = append(entries, entry)
 	}
@@ -58,6 +108,78 @@ func (s *Service) Harvest(ctx context.Context, agentName string) ([]InboxEntry,
 	return entries, path, nil
 }
 
+// sourcePath resolves dir to an absolute path without symlinks, falling
+// back to dir when it cannot be resolved.
+func sourcePath(dir string) string {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return dir
+	}
+	if real, err := filepath.EvalSymlinks(abs); err == nil {
+		return real
+	}
+	return abs
+}
+
+// SuggestRef derives a managed ref for a harvested skill directory from
+// the agent it came from and its folder name, lowercased with spaces and
+// characters that are not valid in refs replaced by dashes.
+func SuggestRef(agentName, dir string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsSpace(r), r == '@', r == '/', r == '\\', r == ':':
+			return '-'
+		}
+		return unicode.ToLower(r)
+	}, filepath.Base(dir))
+	name = strings.Trim(name, "-")
+	if name == "" || name == "." {
+		name = "skill"
+	}
+	return strings.ToLower(agentName) + "/" + name
+}
+
+// scan runs the security scanner over a harvested skill directory.
+func (s *Service) scan(ctx context.Context, agentName, name, dir string) *Findings {
+	if s.Scanner == nil {
+		return &Findings{Status: "skipped"}
+	}
+	content, err := readSkillDir(dir)
+	if err != nil {
+		return &Findings{Status: "skipped"}
+	}
+	content.SkillRef = agentName + "/" + name
+	content.Source = agentName
+	report := s.Scanner.Scan(ctx, []security.SkillContent{content})
+	if len(report.Findings) == 0 {
+		return &Findings{Status: "clean"}
+	}
+	out := &Findings{Status: "findings", Count: len(report.Findings), MaxSeverity: report.MaxSeverity().String()}
+	seen := map[string]struct{}{}
+	for _, f := range report.Findings {
+		if _, ok := seen[f.RuleID]; !ok {
+			seen[f.RuleID] = struct{}{}
+			out.Rules = append(out.Rules, f.RuleID)
+		}
+	}
+	sort.Strings(out.Rules)
+	return out
+}
+
+// readSkillDir loads SKILL.md and the ancillary files of a skill directory
+// for scanning.
+func readSkillDir(dir string) (security.SkillContent, error) {
+	skill, err := os.ReadFile(filepath.Join(dir, "SKILL.md"))
+	if err != nil {
+		return security.SkillContent{}, err
+	}
+	files, err := source.ReadSkillFiles(dir)
+	if err != nil {
+		return security.SkillContent{}, err
+	}
+	return security.SkillContent{Content: string(skill), Files: files}, nil
+}
+
 func (s *Service) persistInbox(entries []InboxEntry) (string, error) {
 	if err := store.EnsureLayout(s.StateRoot); err != nil {
 		return "", err
@@ -3,15 +3,19 @@ package installer
 import (
 	"context"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"runtime"
 	"sort"
 	"strings"
 	"time"
 
 	"skillpm/internal/audit"
+	"skillpm/internal/config"
 	"skillpm/internal/resolver"
 	"skillpm/internal/security"
+	"skillpm/internal/source"
 	"skillpm/internal/store"
 )
 
@@ -19,6 +23,32 @@ type Service struct {
 	Root     string
 	Security *security.Engine
 	Audit    *audit.Logger
+	// VerifySignatures requires every skill to carry a SKILL.md.sig that
+	// verifies against its source's public key, not just skills from
+	// sources with require_signatures set.
+	VerifySignatures bool
+	// Platform is the GOOS that skills are installed for: skills declaring
+	// other platforms are refused and files tagged for other platforms are
+	// left out. Empty means the running platform.
+	Platform string
+	// MaxDirNameLength is the longest installed directory name used before
+	// falling back to a hashed name; <= 0 disables hashing.
+	MaxDirNameLength int
+}
+
+func (s *Service) platform() string {
+	if s.Platform != "" {
+		return s.Platform
+	}
+	return runtime.GOOS
+}
+
+// signedChecksum is the checksum a skill's signature covers: SKILL.md and
+// every ancillary file except the signature itself.
+func signedChecksum(item resolver.ResolvedSkill) string {
+	files := maps.Clone(item.Files)
+	delete(files, security.SignatureFile)
+	return source.ComputeChecksum([]byte(item.Content), files)
 }
 
 func (s *Service) Install(_ context.Context, skills []resolver.ResolvedSkill, lockPath string, force bool) ([]store.InstalledSkill, error) {
@@ -28,6 +58,16 @@ func (s *Service) Install(_ context.Context, skills []resolver.ResolvedSkill, lo
 	if s.Audit != nil {
 		_ = s.Audit.Log(audit.Event{Operation: "install", Phase: "start", Status: "ok", Message: fmt.Sprintf("skills=%d", len(skills))})
 	}
+	platform := s.platform()
+	for _, item := range skills {
+		if !source.SupportsPlatform(item.Platforms, platform) {
+			return nil, fmt.Errorf("INS_PLATFORM_UNSUPPORTED: skill %q supports %s, not %s; use --platform to install for another platform", item.SkillRef, strings.Join(item.Platforms, ", "), platform)
+		}
+		required := s.VerifySignatures || item.SignatureRequired
+		if err := security.VerifySkillSignature(item.SkillRef, signedChecksum(item), item.Files[security.SignatureFile], item.PublicKey, required); err != nil {
+			return nil, err
+		}
+	}
 	state, err := store.LoadState(s.Root)
 	if err != nil {
 		return nil, err
@@ -67,7 +107,7 @@ func (s *Service) Install(_ context.Context, skills []resolver.ResolvedSkill, lo
 			}
 		}
 
-		safeName := store.InstalledDirName(item.SkillRef, item.ResolvedVersion)
+		safeName := store.InstalledDirName(item.SkillRef, item.ResolvedVersion, s.MaxDirNameLength)
 		stagedDir := filepath.Join(stage, safeName)
 		finalDir := filepath.Join(store.InstalledRoot(s.Root), safeName)
 
@@ -90,8 +130,15 @@ func (s *Service) Install(_ context.Context, skills []resolver.ResolvedSkill, lo
 			rollback()
 			return nil, fmt.Errorf("INS_STAGE_WRITE: %w", err)
 		}
-		// Write ancillary files
+		// Write ancillary files. A skill that declares platforms has its
+		// files tagged for the other ones left out; without a declaration
+		// a "_<goos>" suffix is just part of the name.
+		filtered := false
 		for relPath, fileContent := range item.Files {
+			if tag := source.FilePlatform(relPath); len(item.Platforms) > 0 && tag != "" && tag != platform {
+				filtered = true
+				continue
+			}
 			destPath := filepath.Join(stagedDir, filepath.FromSlash(relPath))
 			if err := os.MkdirAll(filepath.Dir(destPath), 0o755); err != nil {
 				rollback()
@@ -122,11 +169,10 @@ func (s *Service) Install(_ context.Context, skills []resolver.ResolvedSkill, lo
 		committed = append(committed, finalDir)
 
 		// Clean up old version directories for this skill ref
-		prefix := store.InstalledDirPrefix(item.SkillRef)
 		entries, _ := os.ReadDir(store.InstalledRoot(s.Root))
 		for _, e := range entries {
 			ePath := filepath.Join(store.InstalledRoot(s.Root), e.Name())
-			if strings.HasPrefix(e.Name(), prefix) && ePath != finalDir {
+			if store.IsInstalledDirFor(e.Name(), item.SkillRef) && ePath != finalDir {
 				_ = os.RemoveAll(ePath)
 			}
 		}
@@ -139,10 +185,17 @@ func (s *Service) Install(_ context.Context, skills []resolver.ResolvedSkill, lo
 			Checksum:         item.Checksum,
 			SourceRef:        item.SourceRef,
 			InstalledAt:      time.Now().UTC(),
+			ResolvedCommit:   item.Commit,
+			InstalledBy:      config.Version,
 			TrustTier:        item.TrustTier,
 			IsSuspicious:     item.IsSuspicious,
 			IsMalwareBlocked: item.IsMalwareBlocked,
 			Deps:             item.Deps,
+			Deprecated:       item.Deprecated,
+			SupersededBy:     item.SupersededBy,
+		}
+		if filtered {
+			rec.Platform = platform
 		}
 		installed = append(installed, rec)
 		store.UpsertInstalled(&state, rec)
@@ -196,10 +249,9 @@ func (s *Service) Uninstall(_ context.Context, skillRefs []string, lockPath stri
 			continue
 		}
 		store.RemoveLock(&lock, skillRef)
-		prefix := store.InstalledDirPrefix(skillRef)
 		entries, _ := os.ReadDir(store.InstalledRoot(s.Root))
 		for _, e := range entries {
-			if strings.HasPrefix(e.Name(), prefix) {
+			if store.IsInstalledDirFor(e.Name(), skillRef) {
 				_ = os.RemoveAll(filepath.Join(store.InstalledRoot(s.Root), e.Name()))
 			}
 		}
@@ -6,11 +6,15 @@ import (
 	"fmt"
 	"net/url"
 	"path/filepath"
+	"sort"
 	"strings"
+	"time"
+	"unicode"
 
 	"skillpm/internal/config"
 	"skillpm/internal/source"
 	"skillpm/internal/store"
+	"skillpm/internal/workpool"
 )
 
 type ParsedRef struct {
@@ -31,33 +35,50 @@ type ResolvedSkill struct {
 	Content          string
 	Files            map[string]string
 	SourceRef        string
+	Commit           string
 	ResolverHash     string
 	TrustTier        string
 	IsSuspicious     bool
 	IsMalwareBlocked bool
 	Deps             []string // dependency skill refs
+	Deprecated       bool
+	SupersededBy     string
+	// Platforms are the GOOS values the skill declares support for; empty
+	// means every platform.
+	Platforms []string
+	// PublicKey and SignatureRequired carry the source's signing policy
+	// so the installer can verify SKILL.md.sig.
+	PublicKey         string
+	SignatureRequired bool
+	// Attempts is how many fetches it took to resolve the skill.
+	Attempts int
 }
 
 type Service struct {
 	Sources *source.Manager
+	// Retries is how many times a transient fetch failure is retried
+	// before giving up; RetryBackoff is the first delay, doubled after
+	// each retry (default 500ms).
+	Retries      int
+	RetryBackoff time.Duration
 }
 
 func parseURLRef(raw string) (ParsedRef, error) {
 	u, err := url.Parse(raw)
 	if err != nil {
-		return ParsedRef{}, err
+		return ParsedRef{}, fmt.Errorf("RES_PARSE_URL: %w", err)
 	}
 	if u.Host == "clawhub.ai" || u.Host == "www.clawhub.ai" {
 		path := strings.TrimPrefix(u.Path, "/")
 		if path == "" {
-			return ParsedRef{}, fmt.Errorf("INS_REF_PARSE: invalid clawhub URL %q", raw)
+			return ParsedRef{}, fmt.Errorf("RES_PARSE_URL: invalid clawhub URL %q", raw)
 		}
 		return ParsedRef{Source: "clawhub", Skill: path}, nil
 	}
 	if u.Host == "github.com" || u.Host == "www.github.com" {
 		parts := strings.Split(strings.TrimPrefix(u.Path, "/"), "/")
 		if len(parts) < 2 {
-			return ParsedRef{}, fmt.Errorf("INS_REF_PARSE: invalid github repo URL %q", raw)
+			return ParsedRef{}, fmt.Errorf("RES_PARSE_URL: invalid github repo URL %q", raw)
 		}
 		org := parts[0]
 		repo := parts[1]
@@ -90,12 +111,12 @@ func parseURLRef(raw string) (ParsedRef, error) {
 func parseGenericURLRef(u *url.URL, raw string) (ParsedRef, error) {
 	parts := strings.Split(strings.TrimPrefix(u.Path, "/"), "/")
 	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
-		return ParsedRef{}, fmt.Errorf("INS_REF_PARSE: URL must have at least org/repo: %q", raw)
+		return ParsedRef{}, fmt.Errorf("RES_PARSE_URL: URL must have at least org/repo: %q", raw)
 	}
 
 	repoEnd, branch, skillPath := parseGenericGitPath(parts)
 	if repoEnd < 2 {
-		return ParsedRef{}, fmt.Errorf("INS_REF_PARSE: URL must have at least org/repo: %q", raw)
+		return ParsedRef{}, fmt.Errorf("RES_PARSE_URL: URL must have at least org/repo: %q", raw)
 	}
 
 	repoSegments := make([]string, repoEnd)
@@ -166,10 +187,13 @@ func stripTrailingFile(skillPath string) string {
 	return skillPath
 }
 
+// ParseRef parses a <source>/<skill>[@constraint] reference or a skill URL.
+// Each class of malformed input fails with its own RES_PARSE_* code so the
+// message can say exactly what is wrong.
 func ParseRef(raw string) (ParsedRef, error) {
 	in := strings.TrimSpace(raw)
 	if in == "" {
-		return ParsedRef{}, fmt.Errorf("INS_REF_PARSE: empty skill reference")
+		return ParsedRef{}, fmt.Errorf("RES_PARSE_EMPTY: empty skill reference")
 	}
 	parts := strings.SplitN(in, "@", 2)
 	left := parts[0]
@@ -185,99 +209,324 @@ func ParseRef(raw string) (ParsedRef, error) {
 		pr.Constraint = constraint
 		return pr, nil
 	}
+	if strings.Contains(constraint, "@") {
+		return ParsedRef{}, fmt.Errorf("RES_PARSE_AT: %q has more than one '@'; use <source>/<skill>@<constraint>", raw)
+	}
+	if len(parts) == 2 && constraint == "" {
+		return ParsedRef{}, fmt.Errorf("RES_PARSE_CONSTRAINT: %q has an empty constraint after '@'; drop the '@' or add a version", raw)
+	}
+	if strings.ContainsFunc(left, unicode.IsSpace) {
+		return ParsedRef{}, fmt.Errorf("RES_PARSE_SPACE: %q contains whitespace in the source or skill name", raw)
+	}
 	seg := strings.SplitN(left, "/", 2)
-	if len(seg) != 2 || strings.TrimSpace(seg[0]) == "" || strings.TrimSpace(seg[1]) == "" {
-		return ParsedRef{}, fmt.Errorf("INS_REF_PARSE: expected <source>/<skill>[@constraint] or URL, got %q", raw)
+	if len(seg) != 2 {
+		return ParsedRef{}, fmt.Errorf("RES_PARSE_FORMAT: expected <source>/<skill>[@constraint] or URL, got %q", raw)
+	}
+	if seg[0] == "" {
+		return ParsedRef{}, fmt.Errorf("RES_PARSE_SOURCE: %q has an empty source before '/'", raw)
+	}
+	if seg[1] == "" {
+		return ParsedRef{}, fmt.Errorf("RES_PARSE_SKILL: %q has an empty skill after %q", raw, seg[0]+"/")
+	}
+	if strings.HasSuffix(seg[1], "/") {
+		return ParsedRef{}, fmt.Errorf("RES_PARSE_SLASH: %q has a trailing '/'; use %q", raw, seg[0]+"/"+strings.TrimRight(seg[1], "/"))
+	}
+	if strings.Contains(seg[1], "//") || strings.HasPrefix(seg[1], "/") {
+		return ParsedRef{}, fmt.Errorf("RES_PARSE_SLASH: %q has an empty path segment ('//')", raw)
 	}
 	return ParsedRef{Source: seg[0], Skill: seg[1], Constraint: constraint}, nil
 }
 
+// ParseBareRef reports whether raw is a bare skill name without a source,
+// such as "pdf" or "pdf@^1", and splits it into name and constraint.
+func ParseBareRef(raw string) (name, constraint string, ok bool) {
+	in := strings.TrimSpace(raw)
+	if in == "" || strings.HasPrefix(in, "http://") || strings.HasPrefix(in, "https://") {
+		return "", "", false
+	}
+	parts := strings.SplitN(in, "@", 2)
+	name = strings.TrimSpace(parts[0])
+	if name == "" || strings.Contains(name, "/") {
+		return "", "", false
+	}
+	if len(parts) == 2 {
+		constraint = strings.TrimSpace(parts[1])
+	}
+	return name, constraint, true
+}
+
+// defaultTierOrder is the bare-ref preference when the config sets none.
+var defaultTierOrder = []string{"trusted", "review", "untrusted"}
+
+// TrustTierRank orders sources for bare-ref fallback and deduplication by
+// their position in order (most preferred first); tiers not listed rank
+// last.
+func TrustTierRank(order []string, tier string) int {
+	if len(order) == 0 {
+		order = defaultTierOrder
+	}
+	for i, t := range order {
+		if t == tier {
+			return i
+		}
+	}
+	return len(order)
+}
+
+// resolveBare tries every configured source for a bare skill name, in
+// resolution.prefer_tier_order (most trusted first by default) and by name
+// within a tier. The first tier with a match wins; several matches within
+// that tier are ambiguous.
+func (s *Service) resolveBare(ctx context.Context, cfg config.Config, name, constraint string, lock store.Lockfile) (ResolvedSkill, error) {
+	order := cfg.Resolution.PreferTierOrder
+	sources := append([]config.SourceConfig{}, cfg.Sources...)
+	sort.SliceStable(sources, func(i, j int) bool {
+		ri, rj := TrustTierRank(order, sources[i].TrustTier), TrustTierRank(order, sources[j].TrustTier)
+		if ri != rj {
+			return ri < rj
+		}
+		return sources[i].Name < sources[j].Name
+	})
+
+	var tried []string
+	var matches []ResolvedSkill
+	matchTier := -1
+	for _, src := range sources {
+		rank := TrustTierRank(order, src.TrustTier)
+		if matchTier >= 0 && rank != matchTier {
+			break
+		}
+		tried = append(tried, src.Name)
+		c := constraint
+		if c == "" || strings.EqualFold(c, "latest") {
+			if entry, ok := store.FindLock(lock, src.Name+"/"+name); ok {
+				c = entry.ResolvedVersion
+			}
+		}
+		r, attempts, err := s.fetch(ctx, src, source.ResolveRequest{Skill: name, Constraint: c})
+		if source.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return ResolvedSkill{}, err
+		}
+		matchTier = rank
+		m := toResolvedSkill(r, src)
+		m.Attempts = attempts
+		matches = append(matches, m)
+	}
+	switch len(matches) {
+	case 0:
+		return ResolvedSkill{}, fmt.Errorf("RES_NOT_FOUND_ANY: skill %q not found in any source (tried %s)", name, strings.Join(tried, ", "))
+	case 1:
+		return matches[0], nil
+	default:
+		var refs, options []string
+		for _, m := range matches {
+			refs = append(refs, m.SkillRef)
+			options = append(options, withConstraint(m.SkillRef, constraint))
+		}
+		return ResolvedSkill{}, &ChoiceError{
+			Options: options,
+			Err:     fmt.Errorf("RES_AMBIGUOUS: skill %q found in several sources with the same trust tier: %s; use <source>/%s or --source", name, strings.Join(refs, ", "), name),
+		}
+	}
+}
+
+// ChoiceError reports a ref that matches several skills: a bare name found
+// in more than one source, or a source path that is a directory of skills.
+// Options lists refs that each resolve to exactly one skill, so a caller can
+// let the user pick and retry.
+type ChoiceError struct {
+	Ref     string
+	Options []string
+	Err     error
+}
+
+func (e *ChoiceError) Error() string { return e.Err.Error() }
+func (e *ChoiceError) Unwrap() error { return e.Err }
+
+func withConstraint(ref, constraint string) string {
+	if constraint == "" {
+		return ref
+	}
+	return ref + "@" + constraint
+}
+
+func toResolvedSkill(r source.ResolveResult, src config.SourceConfig) ResolvedSkill {
+	deprecated, supersededBy := source.ParseDeprecation(r.Content)
+	return ResolvedSkill{
+		SkillRef:          r.SkillRef,
+		Source:            r.Source,
+		Skill:             r.Skill,
+		ResolvedVersion:   r.ResolvedVersion,
+		Checksum:          r.Checksum,
+		Content:           r.Content,
+		Files:             r.Files,
+		SourceRef:         r.SourceRef,
+		Commit:            r.Commit,
+		ResolverHash:      r.ResolverHash,
+		TrustTier:         src.TrustTier,
+		IsSuspicious:      r.Moderation.IsSuspicious,
+		IsMalwareBlocked:  r.Moderation.IsMalwareBlocked,
+		Deprecated:        deprecated,
+		SupersededBy:      supersededBy,
+		Platforms:         source.ParsePlatforms(r.Content),
+		PublicKey:         src.PublicKey,
+		SignatureRequired: src.RequireSignatures,
+	}
+}
+
 func (s *Service) ResolveMany(ctx context.Context, cfg config.Config, refs []string, lock store.Lockfile) ([]ResolvedSkill, error) {
 	if s == nil || s.Sources == nil {
 		return nil, fmt.Errorf("SRC_RESOLVE: source manager not configured")
 	}
 	out := make([]ResolvedSkill, 0, len(refs))
 	for _, raw := range refs {
-		pr, err := ParseRef(raw)
+		rs, err := s.resolveRef(ctx, cfg, raw, lock)
 		if err != nil {
 			return nil, err
 		}
-		src, ok := config.FindSource(cfg, pr.Source)
-		if !ok {
-			if pr.IsURL {
-				src = config.SourceConfig{
-					Name:      pr.Source,
-					Kind:      "git",
-					URL:       pr.URL,
-					Branch:    pr.Branch,
-					ScanPaths: []string{".", "skills"},
-					TrustTier: "review",
-				}
-			} else {
-				return nil, fmt.Errorf("SRC_RESOLVE: source %q not found", pr.Source)
-			}
+		out = append(out, rs...)
+	}
+	return out, nil
+}
+
+// ResolveManyConcurrent resolves refs like ResolveMany, with up to workers
+// sources resolving at once. Refs of one source resolve one at a time, in
+// order, so a source's cache is never touched by two resolutions at once;
+// bare refs, which may search every source, resolve after the others. The
+// result keeps the order of refs.
+func (s *Service) ResolveManyConcurrent(ctx context.Context, cfg config.Config, refs []string, lock store.Lockfile, workers int) ([]ResolvedSkill, error) {
+	if s == nil || s.Sources == nil {
+		return nil, fmt.Errorf("SRC_RESOLVE: source manager not configured")
+	}
+	var order []string
+	groups := map[string][]int{}
+	var serial []int
+	for i, raw := range refs {
+		if _, _, ok := ParseBareRef(raw); ok {
+			serial = append(serial, i)
+			continue
 		}
+		pr, err := ParseRef(raw)
+		if err != nil {
+			serial = append(serial, i)
+			continue
+		}
+		if _, ok := groups[pr.Source]; !ok {
+			order = append(order, pr.Source)
+		}
+		groups[pr.Source] = append(groups[pr.Source], i)
+	}
 
-		skillRef := pr.Source + "/" + pr.Skill
-		if pr.Constraint == "" || strings.EqualFold(pr.Constraint, "latest") {
-			if entry, ok := findLock(lock, skillRef); ok {
-				pr.Constraint = entry.ResolvedVersion
+	resolved := make([][]ResolvedSkill, len(refs))
+	errs := make([]error, len(refs))
+	poolErrs := workpool.Run(ctx, len(order), workers, func(g int) error {
+		for _, i := range groups[order[g]] {
+			if err := ctx.Err(); err != nil {
+				errs[i] = err
+				continue
 			}
+			resolved[i], errs[i] = s.resolveRef(ctx, cfg, refs[i], lock)
 		}
-
-		resolved, err := s.Sources.Resolve(ctx, src, source.ResolveRequest{Skill: pr.Skill, Constraint: pr.Constraint})
+		return nil
+	})
+	for g, err := range poolErrs {
 		if err != nil {
-			// If the URL path is a scan-path directory containing skills,
-			// expand into individual skill resolutions.
-			var scanErr *source.ScanPathError
-			if errors.As(err, &scanErr) && pr.IsURL {
-				for _, skillName := range scanErr.AvailableSkills {
-					r, rErr := s.Sources.Resolve(ctx, src, source.ResolveRequest{Skill: skillName, Constraint: pr.Constraint})
-					if rErr != nil {
-						return nil, rErr
-					}
-					out = append(out, ResolvedSkill{
-						SkillRef:         r.SkillRef,
-						Source:           r.Source,
-						Skill:            r.Skill,
-						ResolvedVersion:  r.ResolvedVersion,
-						Checksum:         r.Checksum,
-						Content:          r.Content,
-						Files:            r.Files,
-						SourceRef:        r.SourceRef,
-						ResolverHash:     r.ResolverHash,
-						TrustTier:        src.TrustTier,
-						IsSuspicious:     r.Moderation.IsSuspicious,
-						IsMalwareBlocked: r.Moderation.IsMalwareBlocked,
-					})
-				}
-				continue
+			// The group never started; its refs report the cancellation.
+			for _, i := range groups[order[g]] {
+				errs[i] = err
 			}
-			return nil, err
 		}
-		out = append(out, ResolvedSkill{
-			SkillRef:         resolved.SkillRef,
-			Source:           resolved.Source,
-			Skill:            resolved.Skill,
-			ResolvedVersion:  resolved.ResolvedVersion,
-			Checksum:         resolved.Checksum,
-			Content:          resolved.Content,
-			Files:            resolved.Files,
-			SourceRef:        resolved.SourceRef,
-			ResolverHash:     resolved.ResolverHash,
-			TrustTier:        src.TrustTier,
-			IsSuspicious:     resolved.Moderation.IsSuspicious,
-			IsMalwareBlocked: resolved.Moderation.IsMalwareBlocked,
-		})
+	}
+	for _, i := range serial {
+		if err := ctx.Err(); err != nil {
+			errs[i] = err
+			continue
+		}
+		resolved[i], errs[i] = s.resolveRef(ctx, cfg, refs[i], lock)
+	}
+
+	out := make([]ResolvedSkill, 0, len(refs))
+	for i := range refs {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		out = append(out, resolved[i]...)
 	}
 	return out, nil
 }
 
-func findLock(lock store.Lockfile, skillRef string) (store.LockSkill, bool) {
-	for _, s := range lock.Skills {
-		if s.SkillRef == skillRef {
-			return s, true
+// resolveRef resolves one ref. A URL ref naming a directory of skills
+// resolves to every skill in it.
+func (s *Service) resolveRef(ctx context.Context, cfg config.Config, raw string, lock store.Lockfile) ([]ResolvedSkill, error) {
+	if name, constraint, ok := ParseBareRef(raw); ok {
+		r, err := s.resolveBare(ctx, cfg, name, constraint, lock)
+		if err != nil {
+			var choiceErr *ChoiceError
+			if errors.As(err, &choiceErr) {
+				choiceErr.Ref = raw
+			}
+			return nil, err
+		}
+		return []ResolvedSkill{r}, nil
+	}
+	pr, err := ParseRef(raw)
+	if err != nil {
+		return nil, err
+	}
+	src, ok := config.FindSource(cfg, pr.Source)
+	if !ok {
+		if pr.IsURL {
+			src = config.SourceConfig{
+				Name:      pr.Source,
+				Kind:      "git",
+				URL:       pr.URL,
+				Branch:    pr.Branch,
+				ScanPaths: []string{".", "skills"},
+				TrustTier: "review",
+			}
+		} else {
+			return nil, fmt.Errorf("SRC_RESOLVE: source %q not found", pr.Source)
+		}
+	}
+
+	skillRef := pr.Source + "/" + pr.Skill
+	if pr.Constraint == "" || strings.EqualFold(pr.Constraint, "latest") {
+		if entry, ok := store.FindLock(lock, skillRef); ok {
+			pr.Constraint = entry.ResolvedVersion
+		}
+	}
+
+	resolved, attempts, err := s.fetch(ctx, src, source.ResolveRequest{Skill: pr.Skill, Constraint: pr.Constraint})
+	if err != nil {
+		// If the URL path is a scan-path directory containing skills,
+		// expand into individual skill resolutions.
+		var scanErr *source.ScanPathError
+		if errors.As(err, &scanErr) && pr.IsURL {
+			var out []ResolvedSkill
+			for _, skillName := range scanErr.AvailableSkills {
+				r, n, rErr := s.fetch(ctx, src, source.ResolveRequest{Skill: skillName, Constraint: pr.Constraint})
+				if rErr != nil {
+					return nil, rErr
+				}
+				rs := toResolvedSkill(r, src)
+				rs.Attempts = n
+				out = append(out, rs)
+			}
+			return out, nil
+		}
+		if errors.As(err, &scanErr) {
+			options := make([]string, len(scanErr.AvailableSkills))
+			for i, skillName := range scanErr.AvailableSkills {
+				options[i] = withConstraint(pr.Source+"/"+skillName, pr.Constraint)
+			}
+			return nil, &ChoiceError{Ref: raw, Options: options, Err: err}
 		}
+		return nil, err
 	}
-	return store.LockSkill{}, false
+	rs := toResolvedSkill(resolved, src)
+	rs.Attempts = attempts
+	return []ResolvedSkill{rs}, nil
 }
@@ -3,6 +3,8 @@ package resolver
 import (
 	"context"
 	"net/http"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"skillpm/internal/config"
@@ -66,6 +68,40 @@ func TestParseRef(t *testing.T) {
 	}
 }
 
+func TestParseRefMalformedCodes(t *testing.T) {
+	tests := []struct {
+		in   string
+		code string
+	}{
+		{"", "RES_PARSE_EMPTY"},
+		{"   ", "RES_PARSE_EMPTY"},
+		{"badref", "RES_PARSE_FORMAT"},
+		{"/pdf", "RES_PARSE_SOURCE"},
+		{"/pdf@1.0.0", "RES_PARSE_SOURCE"},
+		{"anthropic/", "RES_PARSE_SKILL"},
+		{"anthropic/@1.0.0", "RES_PARSE_SKILL"},
+		{"anthropic/pdf@1.0.0@2.0.0", "RES_PARSE_AT"},
+		{"anthropic/pdf@@1.0.0", "RES_PARSE_AT"},
+		{"anthropic/pdf@", "RES_PARSE_CONSTRAINT"},
+		{"anthropic/pdf/", "RES_PARSE_SLASH"},
+		{"anthropic/pdf//", "RES_PARSE_SLASH"},
+		{"anthropic//pdf", "RES_PARSE_SLASH"},
+		{"anthropic/a//b", "RES_PARSE_SLASH"},
+		{"anthropic/my skill", "RES_PARSE_SPACE"},
+		{"anthropic /pdf", "RES_PARSE_SPACE"},
+		{"https://gitlab.com/only-one", "RES_PARSE_URL"},
+		{"https://clawhub.ai/", "RES_PARSE_URL"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			_, err := ParseRef(tt.in)
+			if err == nil || !strings.HasPrefix(err.Error(), tt.code+":") {
+				t.Fatalf("ParseRef(%q) error = %v, want %s", tt.in, err, tt.code)
+			}
+		})
+	}
+}
+
 func TestResolveManyUsesLockVersionWhenConstraintMissing(t *testing.T) {
 	cfg := config.DefaultConfig()
 	svc := &Service{Sources: source.NewManager(http.DefaultClient, t.TempDir(), false)}
@@ -89,3 +125,57 @@ func TestResolveManyUsesLockVersionWhenConstraintMissing(t *testing.T) {
 		t.Fatalf("expected locked version, got %q", resolved[0].ResolvedVersion)
 	}
 }
+
+func TestParseBareRef(t *testing.T) {
+	cases := []struct {
+		in         string
+		name       string
+		constraint string
+		ok         bool
+	}{
+		{in: "pdf", name: "pdf", ok: true},
+		{in: "pdf@^1", name: "pdf", constraint: "^1", ok: true},
+		{in: "anthropic/pdf", ok: false},
+		{in: "https://github.com/org/repo", ok: false},
+		{in: "  ", ok: false},
+	}
+	for _, tc := range cases {
+		name, constraint, ok := ParseBareRef(tc.in)
+		if ok != tc.ok || name != tc.name || constraint != tc.constraint {
+			t.Fatalf("ParseBareRef(%q) = %q, %q, %v", tc.in, name, constraint, ok)
+		}
+	}
+}
+
+func TestTrustTierRankOrdersTrustedFirst(t *testing.T) {
+	if !(TrustTierRank(nil, "trusted") < TrustTierRank(nil, "review") && TrustTierRank(nil, "review") < TrustTierRank(nil, "untrusted")) {
+		t.Fatal("expected trusted < review < untrusted")
+	}
+}
+
+func TestTrustTierRankFollowsConfiguredOrder(t *testing.T) {
+	order := []string{"review", "trusted"}
+	if !(TrustTierRank(order, "review") < TrustTierRank(order, "trusted") && TrustTierRank(order, "trusted") < TrustTierRank(order, "untrusted")) {
+		t.Fatal("expected review < trusted < unlisted untrusted")
+	}
+}
+
+func TestResolveBareSkipsOnlyMissingSkills(t *testing.T) {
+	repo := newLocalSkillRepo(t, "pdf")
+	svc := &Service{Sources: source.NewManager(http.DefaultClient, t.TempDir(), true)}
+	local := config.SourceConfig{Name: "local", Kind: "git", URL: "file://" + repo, ScanPaths: []string{"skills"}, TrustTier: "review"}
+	other := config.SourceConfig{Name: "other", Kind: "git", URL: "file://" + repo, ScanPaths: []string{"skills"}, TrustTier: "trusted"}
+	ctx := context.Background()
+
+	cfg := config.Config{Sources: []config.SourceConfig{local, other}}
+	if _, err := svc.ResolveMany(ctx, cfg, []string{"missing"}, store.Lockfile{}); err == nil || !strings.HasPrefix(err.Error(), "RES_NOT_FOUND_ANY:") {
+		t.Fatalf("expected RES_NOT_FOUND_ANY when no source has the skill, got %v", err)
+	}
+
+	gone := config.SourceConfig{Name: "gone", Kind: "git", URL: "file://" + filepath.Join(t.TempDir(), "missing.git"), TrustTier: "review"}
+	cfg = config.Config{Sources: []config.SourceConfig{gone, local}}
+	_, err := svc.ResolveMany(ctx, cfg, []string{"pdf"}, store.Lockfile{})
+	if err == nil || !strings.HasPrefix(err.Error(), "SRC_GIT_UPDATE:") {
+		t.Fatalf("expected the failing source's error, not a skip, got %v", err)
+	}
+}
@@ -0,0 +1,20 @@
+package resolver
+
+import (
+	"context"
+
+	"skillpm/internal/config"
+	"skillpm/internal/source"
+)
+
+// fetch resolves req from src, retrying transient failures up to s.Retries
+// times with exponential backoff. It returns the number of attempts made.
+func (s *Service) fetch(ctx context.Context, src config.SourceConfig, req source.ResolveRequest) (source.ResolveResult, int, error) {
+	var r source.ResolveResult
+	attempts, err := source.Retry(ctx, s.Retries, s.RetryBackoff, func() error {
+		var err error
+		r, err = s.Sources.Resolve(ctx, src, req)
+		return err
+	})
+	return r, attempts, err
+}
@@ -0,0 +1,97 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"skillpm/internal/config"
+	"skillpm/internal/source"
+	"skillpm/internal/store"
+)
+
+func TestResolveManyRetriesOnlyTransientFailures(t *testing.T) {
+	repo := newLocalSkillRepo(t, "pdf")
+	svc := &Service{Sources: source.NewManager(http.DefaultClient, t.TempDir(), true), Retries: 2, RetryBackoff: time.Millisecond}
+	cfg := config.Config{Sources: []config.SourceConfig{
+		{Name: "down", Kind: "git", URL: "http://127.0.0.1:1/skills.git", TrustTier: "review"},
+		{Name: "gone", Kind: "git", URL: "file://" + filepath.Join(t.TempDir(), "missing.git"), TrustTier: "review"},
+		{Name: "local", Kind: "git", URL: "file://" + repo, ScanPaths: []string{"skills"}, TrustTier: "review"},
+	}}
+	ctx := context.Background()
+
+	_, err := svc.ResolveMany(ctx, cfg, []string{"down/pdf"}, store.Lockfile{})
+	if err == nil || !strings.Contains(err.Error(), "after 3 attempts") {
+		t.Fatalf("expected a clone failure after 3 attempts, got %v", err)
+	}
+	_, err = svc.ResolveMany(ctx, cfg, []string{"gone/pdf"}, store.Lockfile{})
+	if err == nil || strings.Contains(err.Error(), "attempts") {
+		t.Fatalf("expected a missing repository to fail without retries, got %v", err)
+	}
+	resolved, err := svc.ResolveMany(ctx, cfg, []string{"local/pdf"}, store.Lockfile{})
+	if err != nil || resolved[0].Attempts != 1 {
+		t.Fatalf("expected local/pdf to resolve on the first attempt, got %+v, %v", resolved, err)
+	}
+	_, err = svc.ResolveMany(ctx, cfg, []string{"local/missing"}, store.Lockfile{})
+	if err == nil || strings.Contains(err.Error(), "attempts") {
+		t.Fatalf("expected a missing skill to fail without retries, got %v", err)
+	}
+}
+
+func TestResolveManyConcurrentKeepsRefOrder(t *testing.T) {
+	repo := newLocalSkillRepo(t, "pdf", "docx")
+	svc := &Service{Sources: source.NewManager(http.DefaultClient, t.TempDir(), true)}
+	cfg := config.Config{Sources: []config.SourceConfig{
+		{Name: "a", Kind: "git", URL: "file://" + repo, ScanPaths: []string{"skills"}, TrustTier: "review"},
+		{Name: "b", Kind: "git", URL: "file://" + repo, ScanPaths: []string{"skills"}, TrustTier: "review"},
+	}}
+	refs := []string{"b/pdf", "a/docx", "b/docx", "a/pdf"}
+	resolved, err := svc.ResolveManyConcurrent(context.Background(), cfg, refs, store.Lockfile{}, 2)
+	if err != nil {
+		t.Fatalf("resolve failed: %v", err)
+	}
+	if len(resolved) != len(refs) {
+		t.Fatalf("expected %d skills, got %+v", len(refs), resolved)
+	}
+	for i, ref := range refs {
+		if resolved[i].SkillRef != ref {
+			t.Fatalf("result %d is %s, want %s", i, resolved[i].SkillRef, ref)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := svc.ResolveManyConcurrent(ctx, cfg, refs, store.Lockfile{}, 2); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected a cancelled context to stop resolution, got %v", err)
+	}
+}
+
+// newLocalSkillRepo commits a skills/<name>/SKILL.md per name to a new git
+// repository and returns its path.
+func newLocalSkillRepo(t *testing.T, names ...string) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available on PATH")
+	}
+	repo := t.TempDir()
+	for _, name := range names {
+		if err := os.MkdirAll(filepath.Join(repo, "skills", name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(repo, "skills", name, "SKILL.md"), []byte("# "+name+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, args := range [][]string{{"init", "-b", "main"}, {"add", "-A"}, {"-c", "user.name=test", "-c", "user.email=test@test.com", "commit", "-m", "initial"}} {
+		if out, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+	return repo
+}
@@ -3,7 +3,10 @@ package security
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
+	"text/tabwriter"
 	"time"
 
 	"skillpm/internal/config"
@@ -37,6 +40,17 @@ func (s Severity) String() string {
 	}
 }
 
+// MarshalText encodes the severity by name, so JSON output reads
+// "critical" rather than 4.
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s *Severity) UnmarshalText(text []byte) error {
+	*s = ParseSeverity(string(text))
+	return nil
+}
+
 // ParseSeverity converts a severity string to its typed value.
 func ParseSeverity(s string) Severity {
 	switch strings.ToLower(strings.TrimSpace(s)) {
@@ -64,8 +78,21 @@ type Finding struct {
 	Line        int      `json:"line,omitempty"`
 	Pattern     string   `json:"pattern,omitempty"`
 	Description string   `json:"description"`
+	TrustTier   string   `json:"trustTier,omitempty"`
 }
 
+// trustedSkipRules are the rules not run against skills from trusted
+// sources: size anomalies there are taken as deliberate.
+var trustedSkipRules = map[string]bool{"SCAN_SIZE_ANOMALY": true}
+
+// untrustedMaxSeverity is the highest severity allowed from an untrusted
+// source, even with --force or a higher --max-severity.
+const untrustedMaxSeverity = SeverityMedium
+
+// trustTierMatrix explains blocks caused by untrustedMaxSeverity.
+const trustTierMatrix = "untrusted sources allow at most medium findings even with --force " +
+	"(trusted: size checks skipped; review: --force and --max-severity apply; untrusted: capped at medium)"
+
 // ScanReport aggregates all findings across all skills.
 type ScanReport struct {
 	Skills    []string      `json:"skills"`
@@ -116,6 +143,25 @@ type Scanner struct {
 	rules         []Rule
 	disabledRules map[string]bool
 	blockSeverity Severity
+	maxSeverity   *Severity
+}
+
+// SetMaxSeverity makes Enforce gate scans with EnforceThreshold at max,
+// whether or not force is set.
+func (s *Scanner) SetMaxSeverity(max Severity) {
+	s.maxSeverity = &max
+}
+
+// Gate describes the severity gate Enforce applies for force, for audit
+// records: "max-severity=<sev>", "force", or "block-severity=<sev>".
+func (s *Scanner) Gate(force bool) string {
+	switch {
+	case s.maxSeverity != nil:
+		return "max-severity=" + s.maxSeverity.String()
+	case force:
+		return "force"
+	}
+	return "block-severity=" + s.blockSeverity.String()
 }
 
 // NewScanner creates a scanner with built-in rules.
@@ -141,11 +187,13 @@ func (s *Scanner) Scan(ctx context.Context, skills []SkillContent) ScanReport {
 	for _, skill := range skills {
 		report.Skills = append(report.Skills, skill.SkillRef)
 		for _, rule := range s.rules {
-			if s.disabledRules[rule.ID()] {
+			if s.disabledRules[rule.ID()] || (skill.TrustTier == "trusted" && trustedSkipRules[rule.ID()]) {
 				continue
 			}
-			findings := rule.Scan(ctx, skill)
-			report.Findings = append(report.Findings, findings...)
+			for _, f := range rule.Scan(ctx, skill) {
+				f.TrustTier = skill.TrustTier
+				report.Findings = append(report.Findings, f)
+			}
 		}
 	}
 	report.Duration = time.Since(start)
@@ -153,21 +201,93 @@ func (s *Scanner) Scan(ctx context.Context, skills []SkillContent) ScanReport {
 }
 
 // Enforce checks the report against policy and returns an error if blocked.
-// force=true allows medium severity through but never bypasses critical.
+// A max severity set with SetMaxSeverity takes precedence over force: it is
+// EnforceThreshold at that severity and force is ignored. Otherwise force
+// is a shortcut for a critical threshold.
+// Blocked errors are *BlockedError and carry the report.
 func (s *Scanner) Enforce(report ScanReport, force bool) error {
+	if s.maxSeverity != nil {
+		return s.EnforceThreshold(report, *s.maxSeverity)
+	}
+	if force {
+		return s.EnforceThreshold(report, SeverityCritical)
+	}
 	max := report.MaxSeverity()
 	if max == SeverityCritical {
-		return fmt.Errorf("SEC_SCAN_CRITICAL: %s", formatFindings(report, SeverityCritical))
+		return &BlockedError{Report: report, msg: fmt.Sprintf("SEC_SCAN_CRITICAL: %s", formatFindings(report, SeverityCritical))}
 	}
-	if max >= s.blockSeverity && !force {
-		return fmt.Errorf("SEC_SCAN_BLOCKED: %s; use --force to proceed", formatFindings(report, s.blockSeverity))
+	if max >= s.blockSeverity {
+		return &BlockedError{Report: report, msg: fmt.Sprintf("SEC_SCAN_BLOCKED: %s; use --force to proceed", formatFindings(report, s.blockSeverity))}
 	}
-	if max >= SeverityMedium && !force {
-		return fmt.Errorf("SEC_SCAN_BLOCKED: %s; use --force to proceed", formatFindings(report, SeverityMedium))
+	if max >= SeverityMedium {
+		return &BlockedError{Report: report, msg: fmt.Sprintf("SEC_SCAN_BLOCKED: %s; use --force to proceed", formatFindings(report, SeverityMedium))}
 	}
 	return nil
 }
 
+// EnforceThreshold allows findings up to and including max and blocks the
+// rest. Critical findings are blocked whatever max is, and findings from
+// untrusted sources are blocked above medium.
+func (s *Scanner) EnforceThreshold(report ScanReport, max Severity) error {
+	worst := report.MaxSeverity()
+	if worst == SeverityCritical {
+		return &BlockedError{Report: report, msg: fmt.Sprintf("SEC_SCAN_CRITICAL: %s", formatFindings(report, SeverityCritical))}
+	}
+	if worst > max {
+		return &BlockedError{Report: report, msg: fmt.Sprintf("SEC_SCAN_BLOCKED: %s; above max severity %s, which --force does not override", formatFindings(report, max+1), max)}
+	}
+	untrusted := ScanReport{}
+	for _, f := range report.Findings {
+		if f.TrustTier == "untrusted" {
+			untrusted.Findings = append(untrusted.Findings, f)
+		}
+	}
+	if untrusted.MaxSeverity() > untrustedMaxSeverity {
+		return &BlockedError{Report: report, msg: fmt.Sprintf("SEC_SCAN_BLOCKED: %s; %s", formatFindings(untrusted, untrustedMaxSeverity+1), trustTierMatrix)}
+	}
+	return nil
+}
+
+// BlockedError is a scan that Enforce refused. It keeps the full report so
+// the caller can explain every finding, not just the summary in the message.
+type BlockedError struct {
+	Report ScanReport
+	msg    string
+}
+
+func (e *BlockedError) Error() string { return e.msg }
+
+// FormatFindingsTable renders every finding of report as a table with one
+// row per finding: skill, severity, rule, file, line and description.
+func FormatFindingsTable(report ScanReport) string {
+	if len(report.Findings) == 0 {
+		return ""
+	}
+	findings := append([]Finding(nil), report.Findings...)
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].SkillRef != findings[j].SkillRef {
+			return findings[i].SkillRef < findings[j].SkillRef
+		}
+		return findings[i].Severity > findings[j].Severity
+	})
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "SKILL\tSEVERITY\tRULE\tFILE\tLINE\tDESCRIPTION")
+	for _, f := range findings {
+		line := "-"
+		if f.Line > 0 {
+			line = strconv.Itoa(f.Line)
+		}
+		file := f.File
+		if file == "" {
+			file = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", f.SkillRef, strings.ToUpper(f.Severity.String()), f.RuleID, file, line, f.Description)
+	}
+	_ = w.Flush()
+	return b.String()
+}
+
 // FormatReport returns a human-readable summary of scan findings.
 func FormatReport(report ScanReport) string {
 	if len(report.Findings) == 0 {
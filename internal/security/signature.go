@@ -0,0 +1,54 @@
+package security
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// SignatureFile is the detached signature published next to SKILL.md: the
+// base64 ed25519 signature of the skill's "sha256:..." checksum, computed
+// over SKILL.md and every other file except the signature itself.
+const SignatureFile = "SKILL.md.sig"
+
+// VerifySkillSignature checks sigB64 over checksum with the source's base64
+// ed25519 public key. A missing signature or key fails only when required.
+func VerifySkillSignature(skillRef, checksum, sigB64, keyB64 string, required bool) error {
+	sigB64 = strings.TrimSpace(sigB64)
+	if keyB64 == "" {
+		if required {
+			return fmt.Errorf("SEC_SKILL_UNSIGNED: %s: source has no public_key to verify signatures with", skillRef)
+		}
+		return nil
+	}
+	if sigB64 == "" {
+		if required {
+			return fmt.Errorf("SEC_SKILL_UNSIGNED: %s: no %s found next to SKILL.md", skillRef, SignatureFile)
+		}
+		return nil
+	}
+	key, err := decodePublicKey(keyB64)
+	if err != nil {
+		return fmt.Errorf("SEC_SKILL_BADSIG: %s: %v", skillRef, err)
+	}
+	sig, err := base64.StdEncoding.DecodeString(sigB64)
+	if err != nil {
+		return fmt.Errorf("SEC_SKILL_BADSIG: %s: invalid signature encoding", skillRef)
+	}
+	if !ed25519.Verify(key, []byte(checksum), sig) {
+		return fmt.Errorf("SEC_SKILL_BADSIG: %s: signature verification failed", skillRef)
+	}
+	return nil
+}
+
+func decodePublicKey(keyB64 string) (ed25519.PublicKey, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(keyB64))
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key encoding")
+	}
+	if len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid public key size")
+	}
+	return ed25519.PublicKey(key), nil
+}
@@ -0,0 +1,50 @@
+package security
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func TestVerifySkillSignature(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	key := base64.StdEncoding.EncodeToString(pub)
+	checksum := "sha256:5f0c8b0c2f3a9e1d7b6a4c3e2d1f0a9b8c7d6e5f4a3b2c1d0e9f8a7b6c5d4e3f"
+	sig := base64.StdEncoding.EncodeToString(ed25519.Sign(priv, []byte(checksum)))
+
+	cases := []struct {
+		name     string
+		checksum string
+		sig      string
+		key      string
+		required bool
+		code     string
+	}{
+		{name: "valid", checksum: checksum, sig: sig + "\n", key: key, required: true},
+		{name: "unsigned optional", checksum: checksum, key: key},
+		{name: "no key optional", checksum: checksum, sig: sig},
+		{name: "unsigned required", checksum: checksum, key: key, required: true, code: "SEC_SKILL_UNSIGNED"},
+		{name: "no key required", checksum: checksum, sig: sig, required: true, code: "SEC_SKILL_UNSIGNED"},
+		{name: "tampered", checksum: "sha256:0000", sig: sig, key: key, code: "SEC_SKILL_BADSIG"},
+		{name: "garbage signature", checksum: checksum, sig: "not base64!", key: key, code: "SEC_SKILL_BADSIG"},
+		{name: "short key", checksum: checksum, sig: sig, key: "AAAA", code: "SEC_SKILL_BADSIG"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := VerifySkillSignature("hub/pdf", tc.checksum, tc.sig, tc.key, tc.required)
+			if tc.code == "" {
+				if err != nil {
+					t.Fatalf("expected success, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.HasPrefix(err.Error(), tc.code+":") {
+				t.Fatalf("expected %s, got %v", tc.code, err)
+			}
+		})
+	}
+}
@@ -15,6 +15,8 @@ import (
 	"runtime"
 	"strings"
 	"time"
+
+	"golang.org/x/mod/semver"
 )
 
 type Manifest struct {
@@ -32,6 +34,16 @@ type Result struct {
 	Updated    bool   `json:"updated"`
 }
 
+// Check is the release a channel offers, as seen by Service.Check.
+type Check struct {
+	Channel           string `json:"channel"`
+	CurrentVersion    string `json:"currentVersion"`
+	AvailableVersion  string `json:"availableVersion"`
+	UpdateAvailable   bool   `json:"updateAvailable"`
+	Signed            bool   `json:"signed"`
+	SignatureRequired bool   `json:"signatureRequired"`
+}
+
 type Service struct {
 	client *http.Client
 }
@@ -78,6 +90,48 @@ func (s *Service) Update(ctx context.Context, channel string, requireSignatures
 	return Result{Channel: channel, Version: manifest.Version, Executable: exe, Updated: true}, nil
 }
 
+// Check fetches the channel's release manifest and compares it with
+// currentVersion without downloading or replacing anything. A current
+// version that is not semver (such as a dev build) is treated as older than
+// any release that differs from it.
+func (s *Service) Check(ctx context.Context, channel, currentVersion string, requireSignatures bool) (Check, error) {
+	if channel == "" {
+		channel = "stable"
+	}
+	manifest, err := s.fetchManifest(ctx, resolveManifestURL(channel))
+	if err != nil {
+		return Check{}, err
+	}
+	if manifest.Version == "" {
+		return Check{}, fmt.Errorf("SEC_SELF_UPDATE_MANIFEST: manifest has no version")
+	}
+	return Check{
+		Channel:           channel,
+		CurrentVersion:    currentVersion,
+		AvailableVersion:  manifest.Version,
+		UpdateAvailable:   newerVersion(manifest.Version, currentVersion),
+		Signed:            manifest.Signature != "" && manifest.PublicKey != "",
+		SignatureRequired: requireSignatures,
+	}, nil
+}
+
+// newerVersion reports whether available should replace current.
+func newerVersion(available, current string) bool {
+	a, c := canonicalVersion(available), canonicalVersion(current)
+	if semver.IsValid(a) && semver.IsValid(c) {
+		return semver.Compare(a, c) > 0
+	}
+	return a != c
+}
+
+func canonicalVersion(v string) string {
+	v = strings.TrimSpace(v)
+	if v != "" && !strings.HasPrefix(v, "v") {
+		v = "v" + v
+	}
+	return v
+}
+
 func resolveManifestURL(channel string) string {
 	if channel == "" {
 		channel = "stable"
@@ -152,3 +152,40 @@ func TestResolveManifestURL(t *testing.T) {
 		t.Errorf("resolveManifestURL override failed; got %q", got)
 	}
 }
+
+func TestCheckReportsUpdateWithoutDownloading(t *testing.T) {
+	downloaded := false
+	mux := http.NewServeMux()
+	mux.HandleFunc("/bin", func(w http.ResponseWriter, r *http.Request) {
+		downloaded = true
+	})
+	mux.HandleFunc("/manifest", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(Manifest{Version: "1.2.3", URL: "/bin", Checksum: "abc"})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	target := filepath.Join(t.TempDir(), "skillpm")
+	t.Setenv("SKILLPM_SELF_UPDATE_TARGET", target)
+	t.Setenv("SKILLPM_UPDATE_MANIFEST_URL", server.URL+"/manifest")
+
+	svc := New(server.Client())
+	check, err := svc.Check(context.Background(), "", "1.2.0", true)
+	if err != nil {
+		t.Fatalf("check failed: %v", err)
+	}
+	if !check.UpdateAvailable || check.AvailableVersion != "1.2.3" || check.Channel != "stable" || check.Signed || !check.SignatureRequired {
+		t.Fatalf("unexpected check: %+v", check)
+	}
+	if check, _ := svc.Check(context.Background(), "stable", "v1.2.3", false); check.UpdateAvailable {
+		t.Fatalf("expected the same version to be up to date, got %+v", check)
+	}
+	if check, _ := svc.Check(context.Background(), "stable", "dev", false); !check.UpdateAvailable {
+		t.Fatalf("expected a dev build to see the release as an update, got %+v", check)
+	}
+	if downloaded {
+		t.Fatal("check must not download the binary")
+	}
+	if _, err := os.Stat(target); !os.IsNotExist(err) {
+		t.Fatalf("check must not write the executable, stat err = %v", err)
+	}
+}
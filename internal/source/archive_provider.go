@@ -0,0 +1,410 @@
+package source
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"skillpm/internal/config"
+)
+
+// archiveMarker is written into an unpacked archive once extraction has
+// finished; it holds the sha256 checksum of the extracted tree.
+const archiveMarker = ".skillpm-archive"
+
+const (
+	maxArchiveSize  = 100 << 20 // 100MB download
+	maxArchiveEntry = 10 << 20  // 10MB per extracted file
+	// maxArchiveExpanded and maxArchiveEntries cap the whole extracted
+	// tree, since a small compressed download can unpack to far more.
+	maxArchiveExpanded = 500 << 20 // 500MB extracted in total
+	maxArchiveEntries  = 10000
+)
+
+// archiveProvider serves skills from a .tar.gz (or plain .tar) release
+// archive. Update downloads the archive and unpacks it into a per-source
+// cache directory laid out like a git clone, so skills are found under the
+// source's scan paths the same way.
+type archiveProvider struct {
+	cacheRoot string
+	client    *http.Client
+}
+
+func (p *archiveProvider) Update(ctx context.Context, src config.SourceConfig) (UpdateResult, error) {
+	if src.URL == "" {
+		return UpdateResult{}, fmt.Errorf("SRC_ARCHIVE_UPDATE: source %q missing url", src.Name)
+	}
+	blob, err := p.download(ctx, src.URL)
+	if err != nil {
+		return UpdateResult{}, err
+	}
+	if err := verifyArchiveDigest(src.URL, blob); err != nil {
+		return UpdateResult{}, err
+	}
+	if err := os.MkdirAll(p.cacheRoot, 0o755); err != nil {
+		return UpdateResult{}, fmt.Errorf("SRC_ARCHIVE_UPDATE: %w", err)
+	}
+	tmp, err := os.MkdirTemp(p.cacheRoot, ".unpack-*")
+	if err != nil {
+		return UpdateResult{}, fmt.Errorf("SRC_ARCHIVE_UPDATE: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	if err := extractArchive(blob, tmp); err != nil {
+		return UpdateResult{}, err
+	}
+	root, err := stripWrapperDir(tmp)
+	if err != nil {
+		return UpdateResult{}, fmt.Errorf("SRC_ARCHIVE_UPDATE: %w", err)
+	}
+	sum, err := treeChecksum(root)
+	if err != nil {
+		return UpdateResult{}, fmt.Errorf("SRC_ARCHIVE_UPDATE: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, archiveMarker), []byte(sum+"\n"), 0o644); err != nil {
+		return UpdateResult{}, fmt.Errorf("SRC_ARCHIVE_UPDATE: %w", err)
+	}
+	cacheDir := p.cacheDir(src)
+	if err := os.RemoveAll(cacheDir); err != nil {
+		return UpdateResult{}, fmt.Errorf("SRC_ARCHIVE_UPDATE: %w", err)
+	}
+	if err := os.Rename(root, cacheDir); err != nil {
+		return UpdateResult{}, fmt.Errorf("SRC_ARCHIVE_UPDATE: %w", err)
+	}
+	return UpdateResult{Source: src, Note: "archive source unpacked (" + sum + ")"}, nil
+}
+
+func (p *archiveProvider) Search(_ context.Context, src config.SourceConfig, query string) ([]SearchResult, error) {
+	cacheDir := p.cacheDir(src)
+	if _, ok := archiveChecksum(cacheDir); !ok {
+		return nil, fmt.Errorf("SRC_ARCHIVE_SEARCH: source %q not unpacked; run 'skillpm source update %s' first", src.Name, src.Name)
+	}
+	return searchSkillTree(cacheDir, src, query, nil), nil
+}
+
+// SearchPattern is like Search but matches a regex or glob pattern against
+// each skill's name, slug and description.
+func (p *archiveProvider) SearchPattern(_ context.Context, src config.SourceConfig, pattern *Pattern) ([]SearchResult, error) {
+	cacheDir := p.cacheDir(src)
+	if _, ok := archiveChecksum(cacheDir); !ok {
+		return nil, fmt.Errorf("SRC_ARCHIVE_SEARCH: source %q not unpacked; run 'skillpm source update %s' first", src.Name, src.Name)
+	}
+	return searchSkillTree(cacheDir, src, pattern.Query, pattern), nil
+}
+
+func (p *archiveProvider) Resolve(ctx context.Context, src config.SourceConfig, req ResolveRequest) (ResolveResult, error) {
+	if req.Skill == "" {
+		return ResolveResult{}, fmt.Errorf("SRC_ARCHIVE_RESOLVE: empty skill")
+	}
+	cacheDir := p.cacheDir(src)
+	sum, ok := archiveChecksum(cacheDir)
+	if !ok {
+		if _, err := p.Update(ctx, src); err != nil {
+			return ResolveResult{}, err
+		}
+		sum, _ = archiveChecksum(cacheDir)
+	}
+
+	skillDir, err := findSkillDir(cacheDir, src.ScanPaths, req.Skill)
+	if err != nil {
+		if available := listSkillsInDir(cacheDir, src.ScanPaths, req.Skill); len(available) > 0 {
+			return ResolveResult{}, &ScanPathError{Path: req.Skill, AvailableSkills: available}
+		}
+		return ResolveResult{}, err
+	}
+	contentBytes, err := os.ReadFile(filepath.Join(skillDir, "SKILL.md"))
+	if err != nil {
+		return ResolveResult{}, fmt.Errorf("SRC_ARCHIVE_RESOLVE: reading SKILL.md: %w", err)
+	}
+	files, err := ReadSkillFiles(skillDir)
+	if err != nil {
+		return ResolveResult{}, fmt.Errorf("SRC_ARCHIVE_RESOLVE: walking skill dir: %w", err)
+	}
+
+	version := req.Constraint
+	if version == "" || strings.EqualFold(version, "latest") {
+		version = "0.0.0+archive." + shortChecksum(sum)
+	}
+	return ResolveResult{
+		SkillRef:        fmt.Sprintf("%s/%s", src.Name, req.Skill),
+		ResolvedVersion: version,
+		Checksum:        ComputeChecksum(contentBytes, files),
+		SourceRef:       fmt.Sprintf("%s@%s", src.URL, version),
+		Source:          src.Name,
+		Skill:           req.Skill,
+		Content:         string(contentBytes),
+		Files:           files,
+	}, nil
+}
+
+// Status reports the unpacked archive's cache path, tree checksum (as its
+// commit), unpack time and skill count. It never touches the network.
+func (p *archiveProvider) Status(_ context.Context, src config.SourceConfig) (SourceStatus, error) {
+	cacheDir := p.cacheDir(src)
+	st := SourceStatus{Source: src, CachePath: cacheDir}
+	sum, ok := archiveChecksum(cacheDir)
+	if !ok {
+		return st, nil
+	}
+	st.Cloned = true
+	st.Commit = sum
+	if info, err := os.Stat(filepath.Join(cacheDir, archiveMarker)); err == nil {
+		t := info.ModTime().UTC()
+		st.LastUpdated = &t
+	}
+	st.SkillCount = len(listSkillsInDir(cacheDir, src.ScanPaths, ""))
+	return st, nil
+}
+
+// CachedSkill returns the SKILL.md of skill from the unpacked archive
+// without downloading it again.
+func (p *archiveProvider) CachedSkill(src config.SourceConfig, skill string) (string, bool) {
+	cacheDir := p.cacheDir(src)
+	if _, ok := archiveChecksum(cacheDir); !ok {
+		return "", false
+	}
+	dir, err := findSkillDir(cacheDir, src.ScanPaths, skill)
+	if err != nil {
+		return "", false
+	}
+	content, err := os.ReadFile(filepath.Join(dir, "SKILL.md"))
+	if err != nil {
+		return "", false
+	}
+	return string(content), true
+}
+
+// cacheDir returns a deterministic cache directory for the source.
+func (p *archiveProvider) cacheDir(src config.SourceConfig) string {
+	h := sha256.Sum256([]byte(src.URL))
+	return filepath.Join(p.cacheRoot, src.Name+"-"+hex.EncodeToString(h[:])[:16])
+}
+
+// download fetches the archive from an http(s) URL, or reads it from a
+// local path or file:// URL.
+func (p *archiveProvider) download(ctx context.Context, rawURL string) ([]byte, error) {
+	loc, _, _ := strings.Cut(rawURL, "#")
+	if !strings.HasPrefix(loc, "http://") && !strings.HasPrefix(loc, "https://") {
+		local, err := config.ExpandPath(strings.TrimPrefix(loc, "file://"))
+		if err != nil {
+			return nil, fmt.Errorf("SRC_ARCHIVE_DOWNLOAD: %w", err)
+		}
+		f, err := os.Open(local)
+		if err != nil {
+			return nil, fmt.Errorf("SRC_ARCHIVE_DOWNLOAD: %w", err)
+		}
+		defer f.Close()
+		return readArchiveLimited(f)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, loc, nil)
+	if err != nil {
+		return nil, fmt.Errorf("SRC_ARCHIVE_DOWNLOAD: %w", err)
+	}
+	client := p.client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, &TransientError{Err: fmt.Errorf("SRC_ARCHIVE_DOWNLOAD: %w", err)}
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		err := fmt.Errorf("SRC_ARCHIVE_DOWNLOAD: %s returned status %d", loc, resp.StatusCode)
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+			return nil, &TransientError{Err: err}
+		}
+		return nil, err
+	}
+	return readArchiveLimited(resp.Body)
+}
+
+func readArchiveLimited(r io.Reader) ([]byte, error) {
+	blob, err := io.ReadAll(io.LimitReader(r, maxArchiveSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("SRC_ARCHIVE_DOWNLOAD: %w", err)
+	}
+	if len(blob) > maxArchiveSize {
+		return nil, fmt.Errorf("SRC_ARCHIVE_DOWNLOAD: archive exceeds %d bytes", maxArchiveSize)
+	}
+	return blob, nil
+}
+
+// verifyArchiveDigest checks the download against a "#sha256=<hex>" suffix
+// on the source URL, when there is one.
+func verifyArchiveDigest(rawURL string, blob []byte) error {
+	_, fragment, ok := strings.Cut(rawURL, "#")
+	if !ok {
+		return nil
+	}
+	want, ok := strings.CutPrefix(fragment, "sha256=")
+	if !ok {
+		return fmt.Errorf("SRC_ARCHIVE_VERIFY: unsupported digest %q; use #sha256=<hex>", fragment)
+	}
+	sum := sha256.Sum256(blob)
+	if got := hex.EncodeToString(sum[:]); !strings.EqualFold(got, want) {
+		return fmt.Errorf("SRC_ARCHIVE_VERIFY: archive sha256 %s does not match expected %s", got, want)
+	}
+	return nil
+}
+
+// extractArchive unpacks a tar or gzip-compressed tar into dir. Entries
+// that would land outside dir fail the whole extraction; links and other
+// special files are skipped.
+func extractArchive(blob []byte, dir string) error {
+	var r io.Reader = bytes.NewReader(blob)
+	if len(blob) >= 2 && blob[0] == 0x1f && blob[1] == 0x8b {
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return fmt.Errorf("SRC_ARCHIVE_VERIFY: %w", err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+	tr := tar.NewReader(r)
+	entries := 0
+	var expanded int64
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("SRC_ARCHIVE_VERIFY: %w", err)
+		}
+		name := path.Clean(strings.TrimPrefix(hdr.Name, "./"))
+		if name == "." {
+			continue
+		}
+		if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return fmt.Errorf("SRC_ARCHIVE_VERIFY: entry %q escapes the archive root", hdr.Name)
+		}
+		if path.Base(name) == archiveMarker {
+			continue
+		}
+		target := filepath.Join(dir, filepath.FromSlash(name))
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0o755); err != nil {
+				return fmt.Errorf("SRC_ARCHIVE_UPDATE: %w", err)
+			}
+		case tar.TypeReg:
+			if hdr.Size > maxArchiveEntry {
+				return fmt.Errorf("SRC_ARCHIVE_VERIFY: entry %q exceeds %d bytes", hdr.Name, maxArchiveEntry)
+			}
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return fmt.Errorf("SRC_ARCHIVE_UPDATE: %w", err)
+			}
+			data, err := io.ReadAll(io.LimitReader(tr, maxArchiveEntry+1))
+			if err != nil {
+				return fmt.Errorf("SRC_ARCHIVE_VERIFY: %w", err)
+			}
+			if len(data) > maxArchiveEntry {
+				return fmt.Errorf("SRC_ARCHIVE_VERIFY: entry %q exceeds %d bytes", hdr.Name, maxArchiveEntry)
+			}
+			if expanded += int64(len(data)); expanded > maxArchiveExpanded {
+				return fmt.Errorf("SRC_ARCHIVE_VERIFY: archive unpacks to more than %d bytes", maxArchiveExpanded)
+			}
+			if err := os.WriteFile(target, data, 0o644); err != nil {
+				return fmt.Errorf("SRC_ARCHIVE_UPDATE: %w", err)
+			}
+		default:
+			continue
+		}
+		if entries++; entries > maxArchiveEntries {
+			return fmt.Errorf("SRC_ARCHIVE_VERIFY: archive has more than %d entries", maxArchiveEntries)
+		}
+	}
+	if entries == 0 {
+		return fmt.Errorf("SRC_ARCHIVE_VERIFY: archive is empty")
+	}
+	return nil
+}
+
+// stripWrapperDir returns the single top-level directory of an extracted
+// archive, as in GitHub release tarballs, or dir itself when the archive
+// has several top-level entries or its only directory is a skill.
+func stripWrapperDir(dir string) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+	if len(entries) != 1 || !entries[0].IsDir() {
+		return dir, nil
+	}
+	wrapper := filepath.Join(dir, entries[0].Name())
+	if _, err := os.Stat(filepath.Join(wrapper, "SKILL.md")); err != nil {
+		return wrapper, nil
+	}
+	return dir, nil
+}
+
+// treeChecksum hashes every file under root outside .git into a "sha256:"
+// checksum. Each file is one length-prefixed record of its slash-separated
+// relative path and content, so moving bytes between a name and the data
+// around it changes the sum.
+func treeChecksum(root string) (string, error) {
+	var rels []string
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if d.IsDir() || d.Name() == archiveMarker {
+			return nil
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		rels = append(rels, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	sort.Strings(rels)
+	h := sha256.New()
+	for _, rel := range rels {
+		data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(rel)))
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(h, "%d:%s%d:", len(rel), rel, len(data))
+		h.Write(data)
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// archiveChecksum returns the tree checksum recorded when cacheDir was
+// unpacked; ok is false when no archive has been unpacked there.
+func archiveChecksum(cacheDir string) (string, bool) {
+	data, err := os.ReadFile(filepath.Join(cacheDir, archiveMarker))
+	if err != nil {
+		return "", false
+	}
+	return strings.TrimSpace(string(data)), true
+}
+
+func shortChecksum(sum string) string {
+	sum = strings.TrimPrefix(sum, "sha256:")
+	if len(sum) > 12 {
+		sum = sum[:12]
+	}
+	return sum
+}
@@ -0,0 +1,150 @@
+package source
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"skillpm/internal/config"
+)
+
+func writeTestArchive(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestArchiveProviderStripsWrapperAndResolves(t *testing.T) {
+	blob := writeTestArchive(t, map[string]string{
+		"skills-v2/skills/docx/SKILL.md":     "# docx\nWrite documents.",
+		"skills-v2/skills/docx/tools/run.sh": "#!/bin/sh\n",
+		"skills-v2/skills/group/a/SKILL.md":  "# a",
+	})
+	archivePath := filepath.Join(t.TempDir(), "skills-v2.tar.gz")
+	if err := os.WriteFile(archivePath, blob, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	p := &archiveProvider{cacheRoot: t.TempDir()}
+	src := config.SourceConfig{Name: "rel", Kind: "archive", URL: archivePath, ScanPaths: []string{"skills"}}
+
+	res, err := p.Resolve(context.Background(), src, ResolveRequest{Skill: "docx"})
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if res.SkillRef != "rel/docx" || !strings.HasPrefix(res.ResolvedVersion, "0.0.0+archive.") {
+		t.Fatalf("unexpected result %+v", res)
+	}
+	if !strings.HasPrefix(res.Checksum, "sha256:") || res.Files["tools/run.sh"] != "#!/bin/sh\n" {
+		t.Fatalf("expected checksum and ancillary files, got %q %v", res.Checksum, res.Files)
+	}
+
+	var scanErr *ScanPathError
+	if _, err := p.Resolve(context.Background(), src, ResolveRequest{Skill: "group"}); !errors.As(err, &scanErr) {
+		t.Fatalf("expected ScanPathError, got %v", err)
+	}
+
+	hits, err := p.Search(context.Background(), src, "documents")
+	if err != nil || len(hits) != 1 || hits[0].Slug != "rel/docx" || hits[0].Match != MatchBody {
+		t.Fatalf("unexpected search %+v, %v", hits, err)
+	}
+
+	st, err := p.Status(context.Background(), src)
+	if err != nil || !st.Cloned || !strings.HasPrefix(st.Commit, "sha256:") || st.SkillCount != 2 {
+		t.Fatalf("unexpected status %+v, %v", st, err)
+	}
+}
+
+func TestArchiveProviderDownloadVerifiesDigest(t *testing.T) {
+	blob := writeTestArchive(t, map[string]string{"demo/SKILL.md": "# demo"})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(blob)
+	}))
+	defer srv.Close()
+	sum := sha256.Sum256(blob)
+	p := &archiveProvider{cacheRoot: t.TempDir(), client: srv.Client()}
+
+	bad := config.SourceConfig{Name: "rel", Kind: "archive", URL: srv.URL + "/skills.tar.gz#sha256=" + strings.Repeat("0", 64)}
+	if _, err := p.Update(context.Background(), bad); err == nil || !strings.HasPrefix(err.Error(), "SRC_ARCHIVE_VERIFY") {
+		t.Fatalf("expected digest mismatch, got %v", err)
+	}
+
+	good := config.SourceConfig{Name: "rel", Kind: "archive", URL: srv.URL + "/skills.tar.gz#sha256=" + hex.EncodeToString(sum[:])}
+	if _, err := p.Update(context.Background(), good); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if content, ok := p.CachedSkill(good, "demo"); !ok || content != "# demo" {
+		t.Fatalf("expected cached demo skill, got %q %v", content, ok)
+	}
+}
+
+func TestArchiveProviderRejectsEscapingEntries(t *testing.T) {
+	blob := writeTestArchive(t, map[string]string{"../evil/SKILL.md": "# evil"})
+	archivePath := filepath.Join(t.TempDir(), "evil.tar.gz")
+	if err := os.WriteFile(archivePath, blob, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	p := &archiveProvider{cacheRoot: t.TempDir()}
+	_, err := p.Update(context.Background(), config.SourceConfig{Name: "evil", Kind: "archive", URL: archivePath})
+	if err == nil || !strings.Contains(err.Error(), "escapes the archive root") {
+		t.Fatalf("expected escaping entry error, got %v", err)
+	}
+}
+
+func TestExtractArchiveCapsEntryCount(t *testing.T) {
+	files := make(map[string]string, maxArchiveEntries+1)
+	for i := 0; i <= maxArchiveEntries; i++ {
+		files[fmt.Sprintf("skills/f%d.md", i)] = "x"
+	}
+	err := extractArchive(writeTestArchive(t, files), t.TempDir())
+	if err == nil || !strings.Contains(err.Error(), "more than") {
+		t.Fatalf("expected the entry cap to fail extraction, got %v", err)
+	}
+}
+
+func TestTreeChecksumSeparatesNameFromContent(t *testing.T) {
+	a, b := t.TempDir(), t.TempDir()
+	if err := os.WriteFile(filepath.Join(a, "ab"), []byte("c"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(b, "a"), []byte("bc"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	sumA, err := treeChecksum(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sumB, err := treeChecksum(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sumA == sumB {
+		t.Fatalf("expected different checksums for ab=c and a=bc, both %s", sumA)
+	}
+}
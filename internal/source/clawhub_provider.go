@@ -133,29 +133,21 @@ func (p *clawHubProvider) Resolve(ctx context.Context, src config.SourceConfig,
 		return ResolveResult{}, err
 	}
 
-	constraint := strings.TrimSpace(req.Constraint)
 	resolverHash := ""
-	resolvedVersion := ""
-	tag := ""
-	if strings.HasPrefix(constraint, "sha256:") {
-		resVersion, hash, err := p.resolveByHash(ctx, base, req.Skill, constraint)
+	resolvedVersion, tag, hash := clawhubConstraint(req.Constraint)
+	if hash != "" {
+		resVersion, resHash, err := p.resolveByHash(ctx, base, req.Skill, hash)
 		if err != nil {
 			return ResolveResult{}, err
 		}
 		resolvedVersion = resVersion
-		resolverHash = hash
-	} else if constraint == "" || strings.EqualFold(constraint, "latest") {
+		resolverHash = resHash
+	} else if resolvedVersion == "" && tag == "" {
 		resVersion, err := p.resolveLatest(ctx, base, req.Skill)
 		if err != nil {
 			return ResolveResult{}, err
 		}
 		resolvedVersion = resVersion
-	} else if strings.HasPrefix(constraint, "tag:") {
-		tag = strings.TrimPrefix(constraint, "tag:")
-	} else if looksLikeVersion(constraint) {
-		resolvedVersion = constraint
-	} else {
-		tag = constraint
 	}
 
 	checksum, resolvedVersionFromDownload, content, files, err := p.downloadChecksum(ctx, base, req.Skill, resolvedVersion, tag)
@@ -300,6 +292,9 @@ func (p *clawHubProvider) resolveLatest(ctx context.Context, base, slug string)
 	if err != nil {
 		return "", err
 	}
+	if status == http.StatusNotFound {
+		return "", &NotFoundError{Err: fmt.Errorf("SRC_RESOLVE: skill %q not found", slug)}
+	}
 	if status != http.StatusOK {
 		return "", fmt.Errorf("SRC_RESOLVE: versions returned status %d", status)
 	}
@@ -323,6 +318,9 @@ func (p *clawHubProvider) downloadChecksum(ctx context.Context, base, slug, vers
 	if err != nil {
 		return "", "", "", nil, err
 	}
+	if status == http.StatusNotFound {
+		return "", "", "", nil, &NotFoundError{Err: fmt.Errorf("SRC_DOWNLOAD: skill %q not found", slug)}
+	}
 	if status != http.StatusOK {
 		return "", "", "", nil, fmt.Errorf("SRC_DOWNLOAD: status %d", status)
 	}
@@ -338,7 +336,7 @@ func (p *clawHubProvider) downloadChecksum(ctx context.Context, base, slug, vers
 		if c, ok := payload["content"].(string); ok {
 			content = c
 			files = parseDownloadFiles(payload["files"])
-			checksum = computeChecksum([]byte(c), files)
+			checksum = ComputeChecksum([]byte(c), files)
 		}
 	} else {
 		// It's just raw content
@@ -589,6 +587,24 @@ func normalizeSemver(v string) string {
 	return v
 }
 
+// clawhubConstraint classifies a constraint as Resolve reads it: a
+// "sha256:" hash, a "tag:" or bare non-version tag, an exact version, or
+// latest (all results empty).
+func clawhubConstraint(constraint string) (version, tag, hash string) {
+	c := strings.TrimSpace(constraint)
+	switch {
+	case c == "" || strings.EqualFold(c, "latest"):
+		return "", "", ""
+	case strings.HasPrefix(c, "sha256:"):
+		return "", "", c
+	case strings.HasPrefix(c, "tag:"):
+		return "", strings.TrimPrefix(c, "tag:"), ""
+	case looksLikeVersion(c):
+		return c, "", ""
+	}
+	return "", c, ""
+}
+
 func looksLikeVersion(v string) bool {
 	if v == "" {
 		return false
@@ -90,7 +90,7 @@ func TestClawHubSearchHandlesRateLimitRetry(t *testing.T) {
 	cfg := config.DefaultConfig()
 	cfg.Sources = []config.SourceConfig{{Name: "clawhub", Kind: "clawhub", Registry: server.URL + "/", TrustTier: "review"}}
 	mgr := NewManager(server.Client(), t.TempDir(), false)
-	results, err := mgr.Search(context.Background(), cfg, "clawhub", "forms")
+	results, err := mgr.Search(context.Background(), cfg, []string{"clawhub"}, "forms")
 	if err != nil {
 		t.Fatalf("search failed: %v", err)
 	}
@@ -0,0 +1,50 @@
+package source
+
+import (
+	"sort"
+	"strings"
+)
+
+// DedupeResults collapses results that are the same skill offered by
+// several sources: the same name and, when both sides have one, the same
+// content hash (otherwise the same description). The result from the
+// source with the lowest rank is kept, with the other sources listed in
+// AlsoIn. Skills that merely share a name are kept apart.
+func DedupeResults(results []SearchResult, rank func(source string) int) []SearchResult {
+	var out []SearchResult
+	for _, r := range results {
+		i := findDuplicate(out, r)
+		if i < 0 {
+			out = append(out, r)
+			continue
+		}
+		kept := out[i]
+		if rank(r.Source) < rank(kept.Source) {
+			r.AlsoIn = append(append([]string(nil), kept.AlsoIn...), kept.Source)
+			kept = r
+		} else {
+			kept.AlsoIn = append(kept.AlsoIn, r.Source)
+		}
+		sort.Strings(kept.AlsoIn)
+		out[i] = kept
+	}
+	return out
+}
+
+func findDuplicate(results []SearchResult, r SearchResult) int {
+	for i, other := range results {
+		if other.Source == r.Source || !strings.EqualFold(other.Name, r.Name) {
+			continue
+		}
+		if other.ContentHash != "" && r.ContentHash != "" {
+			if other.ContentHash == r.ContentHash {
+				return i
+			}
+			continue
+		}
+		if strings.EqualFold(strings.TrimSpace(other.Description), strings.TrimSpace(r.Description)) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,33 @@
+package source
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDedupeResultsPrefersBestRankedSource(t *testing.T) {
+	ranks := map[string]int{"internal": 0, "mirror": 1, "hub": 1}
+	results := []SearchResult{
+		{Source: "mirror", Name: "pdf", ContentHash: "aaa"},
+		{Source: "hub", Name: "pdf", Description: "PDF tools"},
+		{Source: "internal", Name: "pdf", ContentHash: "aaa"},
+		{Source: "hub", Name: "docx", Description: "Word"},
+		{Source: "mirror", Name: "docx", Description: "Word"},
+		{Source: "other", Name: "pdf", ContentHash: "bbb"},
+	}
+	got := DedupeResults(results, func(src string) int {
+		if r, ok := ranks[src]; ok {
+			return r
+		}
+		return 9
+	})
+	want := []SearchResult{
+		{Source: "internal", Name: "pdf", ContentHash: "aaa", AlsoIn: []string{"mirror"}},
+		{Source: "hub", Name: "pdf", Description: "PDF tools"},
+		{Source: "hub", Name: "docx", Description: "Word", AlsoIn: []string{"mirror"}},
+		{Source: "other", Name: "pdf", ContentHash: "bbb"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected dedupe:\n got %+v\nwant %+v", got, want)
+	}
+}
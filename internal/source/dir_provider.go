@@ -0,0 +1,145 @@
+package source
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"skillpm/internal/config"
+)
+
+// dirProvider serves skills straight from a local directory, so edits are
+// picked up without re-adding the source. There is nothing to fetch:
+// Update checks the directory and recomputes its tree checksum, and Resolve
+// reads the live files and versions each skill by a hash of its content.
+type dirProvider struct{}
+
+func (p *dirProvider) Update(_ context.Context, src config.SourceConfig) (UpdateResult, error) {
+	root, err := dirRoot(src)
+	if err != nil {
+		return UpdateResult{}, fmt.Errorf("SRC_DIR_UPDATE: %w", err)
+	}
+	sum, err := treeChecksum(root)
+	if err != nil {
+		return UpdateResult{}, fmt.Errorf("SRC_DIR_UPDATE: %w", err)
+	}
+	return UpdateResult{Source: src, Note: "dir source rescanned (" + sum + ")"}, nil
+}
+
+func (p *dirProvider) Search(_ context.Context, src config.SourceConfig, query string) ([]SearchResult, error) {
+	root, err := dirRoot(src)
+	if err != nil {
+		return nil, fmt.Errorf("SRC_DIR_SEARCH: %w", err)
+	}
+	return searchSkillTree(root, src, query, nil), nil
+}
+
+// SearchPattern is like Search but matches a regex or glob pattern against
+// each skill's name, slug and description.
+func (p *dirProvider) SearchPattern(_ context.Context, src config.SourceConfig, pattern *Pattern) ([]SearchResult, error) {
+	root, err := dirRoot(src)
+	if err != nil {
+		return nil, fmt.Errorf("SRC_DIR_SEARCH: %w", err)
+	}
+	return searchSkillTree(root, src, pattern.Query, pattern), nil
+}
+
+// Resolve reads skill from the directory as it is now. Its version is
+// "0.0.0+dir.<hash>" of the skill's content, so editing a skill changes
+// its version and sync treats the edit as an upgrade. Only the current
+// content can be served, so the constraint is ignored: a locked version
+// that differs, including the 0.0.0+git.<hash> versions older releases
+// recorded for dir sources, is stale and resolves to the current one.
+func (p *dirProvider) Resolve(_ context.Context, src config.SourceConfig, req ResolveRequest) (ResolveResult, error) {
+	if req.Skill == "" {
+		return ResolveResult{}, fmt.Errorf("SRC_DIR_RESOLVE: empty skill")
+	}
+	root, err := dirRoot(src)
+	if err != nil {
+		return ResolveResult{}, fmt.Errorf("SRC_DIR_RESOLVE: %w", err)
+	}
+	skillDir, err := findSkillDir(root, src.ScanPaths, req.Skill)
+	if err != nil {
+		if available := listSkillsInDir(root, src.ScanPaths, req.Skill); len(available) > 0 {
+			return ResolveResult{}, &ScanPathError{Path: req.Skill, AvailableSkills: available}
+		}
+		return ResolveResult{}, err
+	}
+	contentBytes, err := os.ReadFile(filepath.Join(skillDir, "SKILL.md"))
+	if err != nil {
+		return ResolveResult{}, fmt.Errorf("SRC_DIR_RESOLVE: reading SKILL.md: %w", err)
+	}
+	files, err := ReadSkillFiles(skillDir)
+	if err != nil {
+		return ResolveResult{}, fmt.Errorf("SRC_DIR_RESOLVE: walking skill dir: %w", err)
+	}
+
+	checksum := ComputeChecksum(contentBytes, files)
+	version := "0.0.0+dir." + shortChecksum(checksum)
+	return ResolveResult{
+		SkillRef:        fmt.Sprintf("%s/%s", src.Name, req.Skill),
+		ResolvedVersion: version,
+		Checksum:        checksum,
+		SourceRef:       fmt.Sprintf("%s@%s", src.URL, version),
+		Source:          src.Name,
+		Skill:           req.Skill,
+		Content:         string(contentBytes),
+		Files:           files,
+	}, nil
+}
+
+// Status reports the directory as the cache path, its tree checksum as the
+// commit, and its skill count.
+func (p *dirProvider) Status(_ context.Context, src config.SourceConfig) (SourceStatus, error) {
+	root, err := dirRoot(src)
+	st := SourceStatus{Source: src, CachePath: root}
+	if err != nil {
+		return st, nil
+	}
+	sum, err := treeChecksum(root)
+	if err != nil {
+		return st, nil
+	}
+	st.Cloned = true
+	st.Commit = sum
+	st.SkillCount = len(listSkillsInDir(root, src.ScanPaths, ""))
+	return st, nil
+}
+
+// CachedSkill returns the SKILL.md of skill from the directory.
+func (p *dirProvider) CachedSkill(src config.SourceConfig, skill string) (string, bool) {
+	root, err := dirRoot(src)
+	if err != nil {
+		return "", false
+	}
+	dir, err := findSkillDir(root, src.ScanPaths, skill)
+	if err != nil {
+		return "", false
+	}
+	content, err := os.ReadFile(filepath.Join(dir, "SKILL.md"))
+	if err != nil {
+		return "", false
+	}
+	return string(content), true
+}
+
+// dirRoot returns the expanded directory of src, failing when it is not a
+// directory.
+func dirRoot(src config.SourceConfig) (string, error) {
+	if src.URL == "" {
+		return "", fmt.Errorf("source %q missing path", src.Name)
+	}
+	root, err := config.ExpandPath(src.URL)
+	if err != nil {
+		return "", err
+	}
+	info, err := os.Stat(root)
+	if err != nil {
+		return "", fmt.Errorf("source %q: %w", src.Name, err)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("source %q: %s is not a directory", src.Name, root)
+	}
+	return root, nil
+}
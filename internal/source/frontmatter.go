@@ -0,0 +1,60 @@
+package source
+
+import (
+	"strings"
+)
+
+// frontmatterFields returns the top-level fields of the SKILL.md
+// frontmatter in content, keyed by name with values trimmed of whitespace.
+// Both "key: value" and "key = value" forms are accepted; content without
+// a leading "---" block has no fields.
+func frontmatterFields(content string) map[string]string {
+	lines := strings.Split(content, "\n")
+	if len(lines) < 2 || strings.TrimSpace(lines[0]) != "---" {
+		return nil
+	}
+	fields := map[string]string{}
+	for _, line := range lines[1:] {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "---" {
+			break
+		}
+		key, val, ok := strings.Cut(trimmed, ":")
+		if eqKey, eqVal, eqOK := strings.Cut(trimmed, "="); eqOK && (!ok || len(eqKey) < len(key)) {
+			key, val, ok = eqKey, eqVal, true
+		}
+		if !ok {
+			continue
+		}
+		fields[strings.TrimSpace(key)] = strings.TrimSpace(val)
+	}
+	return fields
+}
+
+// ParseDeprecation reads the deprecated and superseded_by fields from
+// SKILL.md frontmatter. A superseded_by entry on its own also marks the
+// skill as deprecated.
+func ParseDeprecation(content string) (deprecated bool, supersededBy string) {
+	fields := frontmatterFields(content)
+	val := strings.Trim(fields["deprecated"], `"'`)
+	deprecated = strings.EqualFold(val, "true") || strings.EqualFold(val, "yes")
+	supersededBy = strings.Trim(fields["superseded_by"], `"'`)
+	if supersededBy != "" {
+		deprecated = true
+	}
+	return deprecated, supersededBy
+}
+
+// ParsePlatforms reads the platforms field from SKILL.md frontmatter, in
+// either "platforms: [linux, darwin]" or `platforms = ["linux", "darwin"]`
+// form. An empty result means the skill supports every platform.
+func ParsePlatforms(content string) []string {
+	var out []string
+	for _, p := range strings.Split(strings.Trim(frontmatterFields(content)["platforms"], "[]"), ",") {
+		p = strings.ToLower(strings.Trim(strings.TrimSpace(p), `"'`))
+		if p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
@@ -0,0 +1,23 @@
+package source
+
+import "testing"
+
+func TestParseDeprecation(t *testing.T) {
+	cases := []struct {
+		content    string
+		deprecated bool
+		superseded string
+	}{
+		{"---\nname: old\ndeprecated: true\nsuperseded_by: hub/new\n---\n# Old\n", true, "hub/new"},
+		{"---\ndeprecated = true\nsuperseded_by = \"hub/new\"\n---\n", true, "hub/new"},
+		{"---\nsuperseded_by: 'hub/new'\n---\n", true, "hub/new"},
+		{"---\ndeprecated: false\n---\ndeprecated: true\n", false, ""},
+		{"# No frontmatter\ndeprecated: true\n", false, ""},
+	}
+	for _, tc := range cases {
+		deprecated, superseded := ParseDeprecation(tc.content)
+		if deprecated != tc.deprecated || superseded != tc.superseded {
+			t.Fatalf("ParseDeprecation(%q) = %v, %q; want %v, %q", tc.content, deprecated, superseded, tc.deprecated, tc.superseded)
+		}
+	}
+}
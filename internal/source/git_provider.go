@@ -2,16 +2,20 @@ package source
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"sort"
 	"strings"
+	"time"
 
 	"skillpm/internal/config"
 )
@@ -31,14 +35,24 @@ func newGitExec(quiet bool) gitExecFunc {
 			cmd.Dir = dir
 		}
 
-		// Show progress natively in the terminal for long-running network commands
+		// Show progress natively in the terminal for long-running network
+		// commands, keeping a copy of stderr to tell network failures apart.
 		if len(args) > 0 && (args[0] == "clone" || args[0] == "fetch") {
+			var stderr bytes.Buffer
+			cmd.Stderr = &stderr
 			if !quiet {
-				cmd.Stderr = os.Stderr
+				cmd.Stderr = io.MultiWriter(os.Stderr, &stderr)
+				if stderrIsTerminal() {
+					cmd.Args = append([]string{"git", args[0], "--progress"}, args[1:]...)
+				}
 			}
 			out, err := cmd.Output()
 			if err != nil {
-				return nil, fmt.Errorf("git %s: %w", strings.Join(args, " "), err)
+				err = fmt.Errorf("git %s: %w", strings.Join(args, " "), err)
+				if isGitNetworkFailure(stderr.String()) {
+					return nil, &TransientError{Err: err}
+				}
+				return nil, err
 			}
 			return out, nil
 		}
@@ -51,6 +65,40 @@ func newGitExec(quiet bool) gitExecFunc {
 	}
 }
 
+// gitNetworkFailures are git stderr fragments, lowercased, for failures to
+// reach or hear back from a remote. Bad credentials, missing repositories
+// and unknown branches exit the same way but are not retried.
+var gitNetworkFailures = []string{
+	"could not resolve host",
+	"failed to connect",
+	"connection timed out",
+	"operation timed out",
+	"connection refused",
+	"connection reset",
+	"early eof",
+	"rpc failed",
+	"unexpected disconnect",
+	"gnutls_handshake",
+	"ssl_error_syscall",
+	"the requested url returned error: 429",
+	"the requested url returned error: 5",
+}
+
+func isGitNetworkFailure(stderr string) bool {
+	stderr = strings.ToLower(stderr)
+	for _, frag := range gitNetworkFailures {
+		if strings.Contains(stderr, frag) {
+			return true
+		}
+	}
+	return false
+}
+
+func stderrIsTerminal() bool {
+	info, err := os.Stderr.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
 func defaultGitExec(ctx context.Context, dir string, args ...string) ([]byte, error) {
 	return newGitExec(false)(ctx, dir, args...)
 }
@@ -59,34 +107,70 @@ func (p *gitProvider) Update(ctx context.Context, src config.SourceConfig) (Upda
 	if src.URL == "" {
 		return UpdateResult{}, fmt.Errorf("SRC_GIT_UPDATE: source %q missing url", src.Name)
 	}
-	branch := src.Branch
-
 	cacheDir := p.repoCacheDir(src)
 	if err := os.MkdirAll(filepath.Dir(cacheDir), 0o755); err != nil {
 		return UpdateResult{}, fmt.Errorf("SRC_GIT_UPDATE: %w", err)
 	}
 
+	urls := append([]string{src.URL}, src.MirrorURLs...)
+	var attempts []string
+	allTransient := true
+	for _, url := range urls {
+		err := p.updateFrom(ctx, cacheDir, src.Branch, url, src.URL)
+		if err == nil {
+			res := UpdateResult{Source: src, Note: "git source updated", URL: url}
+			if url != src.URL {
+				res.Note = "git source updated from mirror " + url
+			}
+			return res, nil
+		}
+		if len(urls) == 1 {
+			return UpdateResult{}, err
+		}
+		attempts = append(attempts, fmt.Sprintf("%s: %v", url, err))
+		allTransient = allTransient && IsTransient(err)
+	}
+	err := fmt.Errorf("SRC_GIT_ALL_MIRRORS_FAILED: source %q: %s", src.Name, strings.Join(attempts, "; "))
+	if allTransient {
+		return UpdateResult{}, &TransientError{Err: err}
+	}
+	return UpdateResult{}, err
+}
+
+// updateFrom clones url into cacheDir, or fetches from it when the cache
+// already holds a clone. The clone's origin stays the primary URL, so a
+// clone taken from a mirror fetches from the primary again next time.
+func (p *gitProvider) updateFrom(ctx context.Context, cacheDir, branch, url, primary string) error {
 	if isGitRepo(cacheDir) {
 		if branch == "" {
 			branch = detectCurrentBranch(p, ctx, cacheDir)
 		}
-		if _, err := p.execGit(ctx, cacheDir, "fetch", "origin", branch, "--depth", "1"); err != nil {
-			return UpdateResult{}, fmt.Errorf("SRC_GIT_UPDATE: fetch failed: %w", err)
+		remote, ref := "origin", "origin/"+branch
+		if url != primary {
+			remote, ref = url, "FETCH_HEAD"
 		}
-		if _, err := p.execGit(ctx, cacheDir, "reset", "--hard", "origin/"+branch); err != nil {
-			return UpdateResult{}, fmt.Errorf("SRC_GIT_UPDATE: reset failed: %w", err)
+		if _, err := p.execGit(ctx, cacheDir, "fetch", remote, branch, "--depth", "1"); err != nil {
+			return fmt.Errorf("SRC_GIT_UPDATE: fetch failed: %w", err)
 		}
-	} else {
-		args := []string{"clone", "--depth", "1", "--single-branch"}
-		if branch != "" {
-			args = append(args, "--branch", branch)
+		if _, err := p.execGit(ctx, cacheDir, "reset", "--hard", ref); err != nil {
+			return fmt.Errorf("SRC_GIT_UPDATE: reset failed: %w", err)
 		}
-		args = append(args, src.URL, cacheDir)
-		if _, err := p.execGit(ctx, "", args...); err != nil {
-			return UpdateResult{}, fmt.Errorf("SRC_GIT_UPDATE: clone failed: %w", err)
+		return nil
+	}
+	args := []string{"clone", "--depth", "1", "--single-branch"}
+	if branch != "" {
+		args = append(args, "--branch", branch)
+	}
+	args = append(args, url, cacheDir)
+	if _, err := p.execGit(ctx, "", args...); err != nil {
+		return fmt.Errorf("SRC_GIT_UPDATE: clone failed: %w", err)
+	}
+	if url != primary {
+		if _, err := p.execGit(ctx, cacheDir, "remote", "set-url", "origin", primary); err != nil {
+			return fmt.Errorf("SRC_GIT_UPDATE: %w", err)
 		}
 	}
-	return UpdateResult{Source: src, Note: "git source updated"}, nil
+	return nil
 }
 
 // detectCurrentBranch reads the current branch from an existing clone.
@@ -107,6 +191,24 @@ func (p *gitProvider) Search(_ context.Context, src config.SourceConfig, query s
 		return nil, fmt.Errorf("SRC_GIT_SEARCH: source %q not cloned; run 'skillpm source update %s' first", src.Name, src.Name)
 	}
 
+	return searchSkillTree(cacheDir, src, query, nil), nil
+}
+
+// SearchPattern is like Search but matches a regex or glob pattern against
+// each skill's name, slug and description.
+func (p *gitProvider) SearchPattern(_ context.Context, src config.SourceConfig, pattern *Pattern) ([]SearchResult, error) {
+	cacheDir := p.repoCacheDir(src)
+	if !isGitRepo(cacheDir) {
+		return nil, fmt.Errorf("SRC_GIT_SEARCH: source %q not cloned; run 'skillpm source update %s' first", src.Name, src.Name)
+	}
+	return searchSkillTree(cacheDir, src, pattern.Query, pattern), nil
+}
+
+// searchSkillTree lists the skills directly under src's scan paths in a
+// local copy of the source, keeping those whose name, description or body
+// matches query, or whose name, slug or description matches pattern when
+// it is set.
+func searchSkillTree(cacheDir string, src config.SourceConfig, query string, pattern *Pattern) []SearchResult {
 	scanPaths := src.ScanPaths
 	if len(scanPaths) == 0 {
 		scanPaths = []string{"."}
@@ -124,23 +226,40 @@ func (p *gitProvider) Search(_ context.Context, src config.SourceConfig, query s
 				continue
 			}
 			skillMdPath := filepath.Join(base, entry.Name(), "SKILL.md")
-			if _, err := os.Stat(skillMdPath); err != nil {
+			content, err := os.ReadFile(skillMdPath)
+			if err != nil {
 				continue
 			}
 			name := entry.Name()
-			if query != "" && !strings.Contains(strings.ToLower(name), strings.ToLower(query)) {
-				continue
-			}
-			results = append(results, SearchResult{
+			res := SearchResult{
 				Source:      src.Name,
 				Slug:        src.Name + "/" + name,
 				Name:        name,
 				Description: readFirstHeading(skillMdPath),
-			})
+			}
+			if pattern != nil {
+				if !pattern.annotate(&res) {
+					continue
+				}
+			} else if query != "" {
+				annotateMatch(&res, query)
+				if res.Match == "" {
+					snippet, ok := MatchSnippet(string(content), query)
+					if !ok {
+						continue
+					}
+					res.Match, res.Snippet = MatchBody, snippet
+				}
+			}
+			deprecated, supersededBy := ParseDeprecation(string(content))
+			sum := sha256.Sum256(content)
+			res.Deprecated, res.SupersededBy = deprecated, supersededBy
+			res.ContentHash = hex.EncodeToString(sum[:])
+			results = append(results, res)
 		}
 	}
 	sort.Slice(results, func(i, j int) bool { return results[i].Slug < results[j].Slug })
-	return results, nil
+	return results
 }
 
 func (p *gitProvider) Resolve(ctx context.Context, src config.SourceConfig, req ResolveRequest) (ResolveResult, error) {
@@ -173,17 +292,56 @@ func (p *gitProvider) Resolve(ctx context.Context, src config.SourceConfig, req
 	}
 	content := string(contentBytes)
 
-	// Walk skill dir for ancillary files
+	files, err := ReadSkillFiles(skillDir)
+	if err != nil {
+		return ResolveResult{}, fmt.Errorf("SRC_GIT_RESOLVE: walking skill dir: %w", err)
+	}
+
+	// Version from git
+	version := req.Constraint
+	if version == "" || strings.EqualFold(version, "latest") {
+		hash, gitErr := p.execGit(ctx, cacheDir, "rev-parse", "--short", "HEAD")
+		if gitErr != nil {
+			version = "0.0.0+git.unknown"
+		} else {
+			version = "0.0.0+git." + strings.TrimSpace(string(hash))
+		}
+	}
+
+	checksum := ComputeChecksum(contentBytes, files)
+
+	var commit string
+	if head, headErr := p.execGit(ctx, cacheDir, "rev-parse", "HEAD"); headErr == nil {
+		commit = strings.TrimSpace(string(head))
+	}
+
+	return ResolveResult{
+		SkillRef:        fmt.Sprintf("%s/%s", src.Name, req.Skill),
+		ResolvedVersion: version,
+		Checksum:        checksum,
+		SourceRef:       fmt.Sprintf("%s@%s", src.URL, version),
+		Commit:          commit,
+		Source:          src.Name,
+		Skill:           req.Skill,
+		Content:         content,
+		Files:           files,
+	}, nil
+}
+
+// ReadSkillFiles reads the ancillary files of the skill in skillDir, keyed
+// by slash-separated path relative to it. Symlinks and other non-regular
+// files, files over 1MB, and files past 10MB in total, are skipped.
+func ReadSkillFiles(skillDir string) (map[string]string, error) {
 	files := map[string]string{}
 	var totalSize int64
 	const maxFileSize = 1 << 20   // 1MB per file
 	const maxTotalSize = 10 << 20 // 10MB total
 
-	err = filepath.WalkDir(skillDir, func(path string, d fs.DirEntry, walkErr error) error {
+	err := filepath.WalkDir(skillDir, func(path string, d fs.DirEntry, walkErr error) error {
 		if walkErr != nil {
 			return nil // skip errors
 		}
-		if d.IsDir() {
+		if d.IsDir() || !d.Type().IsRegular() {
 			return nil
 		}
 		rel, _ := filepath.Rel(skillDir, path)
@@ -208,33 +366,63 @@ func (p *gitProvider) Resolve(ctx context.Context, src config.SourceConfig, req
 		files[filepath.ToSlash(rel)] = string(data)
 		return nil
 	})
+	return files, err
+}
+
+// revision returns the commit the cached clone of src is at, or "" when
+// there is no clone yet.
+func (p *gitProvider) revision(ctx context.Context, src config.SourceConfig) string {
+	cacheDir := p.repoCacheDir(src)
+	if p.execGit == nil || !isGitRepo(cacheDir) {
+		return ""
+	}
+	out, err := p.execGit(ctx, cacheDir, "rev-parse", "HEAD")
 	if err != nil {
-		return ResolveResult{}, fmt.Errorf("SRC_GIT_RESOLVE: walking skill dir: %w", err)
+		return ""
 	}
+	return strings.TrimSpace(string(out))
+}
 
-	// Version from git
-	version := req.Constraint
-	if version == "" || strings.EqualFold(version, "latest") {
-		hash, gitErr := p.execGit(ctx, cacheDir, "rev-parse", "--short", "HEAD")
-		if gitErr != nil {
-			version = "0.0.0+git.unknown"
-		} else {
-			version = "0.0.0+git." + strings.TrimSpace(string(hash))
+// Status reports the cache path, checked-out commit, last update time and
+// skill count of the cached clone. It never touches the network.
+func (p *gitProvider) Status(ctx context.Context, src config.SourceConfig) (SourceStatus, error) {
+	cacheDir := p.repoCacheDir(src)
+	st := SourceStatus{Source: src, CachePath: cacheDir}
+	if !isGitRepo(cacheDir) {
+		return st, nil
+	}
+	st.Cloned = true
+	if p.execGit != nil {
+		if out, err := p.execGit(ctx, cacheDir, "rev-parse", "HEAD"); err == nil {
+			st.Commit = strings.TrimSpace(string(out))
 		}
 	}
+	if t, ok := cacheUpdatedAt(cacheDir); ok {
+		st.LastUpdated = &t
+	}
+	st.SkillCount = len(listSkillsInDir(cacheDir, src.ScanPaths, ""))
+	return st, nil
+}
 
-	checksum := computeChecksum(contentBytes, files)
-
-	return ResolveResult{
-		SkillRef:        fmt.Sprintf("%s/%s", src.Name, req.Skill),
-		ResolvedVersion: version,
-		Checksum:        checksum,
-		SourceRef:       fmt.Sprintf("%s@%s", src.URL, version),
-		Source:          src.Name,
-		Skill:           req.Skill,
-		Content:         content,
-		Files:           files,
-	}, nil
+// cacheUpdatedAt returns the most recent modification time of the git
+// bookkeeping files touched by clone, fetch and reset.
+func cacheUpdatedAt(cacheDir string) (time.Time, bool) {
+	gitDir := filepath.Join(cacheDir, ".git")
+	var latest time.Time
+	for _, name := range []string{"FETCH_HEAD", "ORIG_HEAD", "index", "HEAD"} {
+		info, err := os.Stat(filepath.Join(gitDir, name))
+		if err == nil && info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	if latest.IsZero() {
+		info, err := os.Stat(gitDir)
+		if err != nil {
+			return time.Time{}, false
+		}
+		latest = info.ModTime()
+	}
+	return latest.UTC(), true
 }
 
 // repoCacheDir returns a deterministic cache directory for the source.
@@ -244,12 +432,46 @@ func (p *gitProvider) repoCacheDir(src config.SourceConfig) string {
 	return filepath.Join(p.cacheRoot, src.Name+"-"+short)
 }
 
+// CachedSkill returns the SKILL.md of skill from the cached clone without
+// fetching; ok is false when the source is not cloned or lacks the skill.
+func (p *gitProvider) CachedSkill(src config.SourceConfig, skill string) (string, bool) {
+	cacheDir := p.repoCacheDir(src)
+	if !isGitRepo(cacheDir) {
+		return "", false
+	}
+	dir, err := findSkillDir(cacheDir, src.ScanPaths, skill)
+	if err != nil {
+		return "", false
+	}
+	content, err := os.ReadFile(filepath.Join(dir, "SKILL.md"))
+	if err != nil {
+		return "", false
+	}
+	return string(content), true
+}
+
 // isGitRepo checks whether the directory contains a .git dir.
 func isGitRepo(dir string) bool {
 	info, err := os.Stat(filepath.Join(dir, ".git"))
 	return err == nil && info.IsDir()
 }
 
+// NotFoundError is returned by Resolve when the source has no skill of the
+// requested name, as opposed to failing to look.
+type NotFoundError struct {
+	Err error
+}
+
+func (e *NotFoundError) Error() string { return e.Err.Error() }
+
+func (e *NotFoundError) Unwrap() error { return e.Err }
+
+// IsNotFound reports whether err carries a NotFoundError.
+func IsNotFound(err error) bool {
+	var notFound *NotFoundError
+	return errors.As(err, &notFound)
+}
+
 // ScanPathError is returned when a skill path is actually a directory
 // containing multiple skills (a scan path), not a single skill.
 type ScanPathError struct {
@@ -276,7 +498,7 @@ func findSkillDir(cacheDir string, scanPaths []string, skill string) (string, er
 			return candidate, nil
 		}
 	}
-	return "", fmt.Errorf("SRC_GIT_RESOLVE: skill %q not found in scan paths %v", skill, scanPaths)
+	return "", &NotFoundError{Err: fmt.Errorf("SRC_GIT_RESOLVE: skill %q not found in scan paths %v", skill, scanPaths)}
 }
 
 // listSkillsInDir walks the directory at {cacheDir}/{scanPath}/{prefix} and
@@ -311,8 +533,9 @@ func listSkillsInDir(cacheDir string, scanPaths []string, prefix string) []strin
 	return skills
 }
 
-// computeChecksum creates a deterministic SHA256 over SKILL.md content and all ancillary files.
-func computeChecksum(content []byte, files map[string]string) string {
+// ComputeChecksum creates a deterministic SHA256 over SKILL.md content and all ancillary files.
+// The installed copy of a skill hashes to the same value, which store checks rely on.
+func ComputeChecksum(content []byte, files map[string]string) string {
 	h := sha256.New()
 	h.Write(content)
 	// Sort keys for determinism.
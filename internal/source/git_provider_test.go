@@ -3,10 +3,13 @@ package source
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"skillpm/internal/config"
 )
@@ -114,6 +117,40 @@ func TestGitProviderUpdateFetchesWhenCacheExists(t *testing.T) {
 	}
 }
 
+func TestGitProviderUpdateFallsBackToMirror(t *testing.T) {
+	mirror := setupBareRepo(t, map[string]map[string]string{
+		"docx": {"SKILL.md": "# docx\nDocx skill"},
+	})
+	p := &gitProvider{cacheRoot: t.TempDir(), execGit: newGitExec(true)}
+	src := testSourceConfig("test", "file://"+filepath.Join(t.TempDir(), "missing.git"))
+	src.MirrorURLs = []string{mirror}
+	ctx := context.Background()
+
+	for _, step := range []string{"clone", "fetch"} {
+		res, err := p.Update(ctx, src)
+		if err != nil {
+			t.Fatalf("%s via mirror failed: %v", step, err)
+		}
+		if res.URL != mirror || !strings.Contains(res.Note, "mirror") {
+			t.Fatalf("%s: expected update recorded from the mirror, got %+v", step, res)
+		}
+	}
+	out, err := p.execGit(ctx, p.repoCacheDir(src), "remote", "get-url", "origin")
+	if err != nil || strings.TrimSpace(string(out)) != src.URL {
+		t.Fatalf("expected origin to stay the primary url, got %q (%v)", out, err)
+	}
+	if _, err := p.Resolve(ctx, src, ResolveRequest{Skill: "docx"}); err != nil {
+		t.Fatalf("resolve after mirror update failed: %v", err)
+	}
+
+	src.MirrorURLs = []string{"file://" + filepath.Join(t.TempDir(), "also-missing.git")}
+	p.cacheRoot = t.TempDir()
+	_, err = p.Update(ctx, src)
+	if err == nil || !strings.HasPrefix(err.Error(), "SRC_GIT_ALL_MIRRORS_FAILED") || !strings.Contains(err.Error(), "also-missing.git") {
+		t.Fatalf("expected SRC_GIT_ALL_MIRRORS_FAILED naming every attempt, got %v", err)
+	}
+}
+
 func TestGitProviderUpdateErrorOnEmptyURL(t *testing.T) {
 	p := &gitProvider{cacheRoot: t.TempDir(), execGit: defaultGitExec}
 	src := testSourceConfig("test", "")
@@ -400,15 +437,15 @@ func TestListSkillsInDirFindsNestedSkills(t *testing.T) {
 	}
 }
 
-// Verify computeChecksum is deterministic.
+// Verify ComputeChecksum is deterministic.
 func TestComputeChecksumDeterministic(t *testing.T) {
 	content := []byte("# skill\nContent")
 	files := map[string]string{
 		"b.txt": "BBB",
 		"a.txt": "AAA",
 	}
-	c1 := computeChecksum(content, files)
-	c2 := computeChecksum(content, files)
+	c1 := ComputeChecksum(content, files)
+	c2 := ComputeChecksum(content, files)
 	if c1 != c2 {
 		t.Fatalf("checksum not deterministic: %q != %q", c1, c2)
 	}
@@ -601,3 +638,167 @@ func TestScanPathErrorMessage(t *testing.T) {
 		t.Fatalf("expected '2 skill(s)' in error message, got %q", msg)
 	}
 }
+
+func TestGitProviderStatusNotCloned(t *testing.T) {
+	p := &gitProvider{cacheRoot: t.TempDir(), execGit: nil}
+	src := testSourceConfig("test", "https://github.com/test/skills.git")
+
+	st, err := p.Status(context.Background(), src)
+	if err != nil {
+		t.Fatalf("status failed: %v", err)
+	}
+	if st.Cloned || st.CachePath != p.repoCacheDir(src) || st.LastUpdated != nil {
+		t.Fatalf("unexpected status for missing cache: %+v", st)
+	}
+}
+
+func TestGitProviderStatusReportsCacheDetails(t *testing.T) {
+	var calls []string
+	p := &gitProvider{
+		cacheRoot: t.TempDir(),
+		execGit:   mockGitExec(&calls, map[string]string{"rev-parse HEAD": "abc123\n"}, nil),
+	}
+	src := testSourceConfig("test", "https://github.com/test/skills.git")
+	setupFakeCache(t, p.repoCacheDir(src), map[string]map[string]string{
+		"docx": {"SKILL.md": "# docx"},
+		"pdf":  {"SKILL.md": "# pdf"},
+	})
+
+	st, err := p.Status(context.Background(), src)
+	if err != nil {
+		t.Fatalf("status failed: %v", err)
+	}
+	if !st.Cloned || st.Commit != "abc123" || st.SkillCount != 2 || st.LastUpdated == nil {
+		t.Fatalf("unexpected status: %+v", st)
+	}
+}
+
+func TestManagerStatusCoversAllSources(t *testing.T) {
+	m := NewManager(nil, t.TempDir(), true)
+	cfg := config.Config{Sources: []config.SourceConfig{
+		testSourceConfig("zeta", "https://github.com/test/zeta.git"),
+		{Name: "hub", Kind: "clawhub", Registry: "https://clawhub.ai/"},
+	}}
+
+	statuses, err := m.Status(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("status failed: %v", err)
+	}
+	if len(statuses) != 2 || statuses[0].Source.Name != "hub" || statuses[1].Source.Name != "zeta" {
+		t.Fatalf("unexpected statuses: %+v", statuses)
+	}
+	if statuses[0].CachePath != "" {
+		t.Fatalf("registry source should report no cache, got %q", statuses[0].CachePath)
+	}
+	if statuses[1].CachePath == "" || statuses[1].Cloned {
+		t.Fatalf("git source should report an uncloned cache path, got %+v", statuses[1])
+	}
+}
+
+// updateStub fails sources named "bad" and holds every other source until
+// its context is cancelled.
+type updateStub struct {
+	Provider
+	started atomic.Int32
+}
+
+func (p *updateStub) Update(ctx context.Context, src config.SourceConfig) (UpdateResult, error) {
+	p.started.Add(1)
+	if src.Name == "bad" {
+		return UpdateResult{}, fmt.Errorf("SRC_GIT_UPDATE: clone failed")
+	}
+	<-ctx.Done()
+	return UpdateResult{}, ctx.Err()
+}
+
+func TestManagerUpdateStopsAtFirstFailure(t *testing.T) {
+	stub := &updateStub{}
+	m := NewManager(nil, t.TempDir(), true)
+	m.providers["git"] = stub
+	cfg := config.Config{Sources: []config.SourceConfig{
+		testSourceConfig("slow", "https://github.com/test/slow.git"),
+		testSourceConfig("bad", "https://github.com/test/bad.git"),
+		testSourceConfig("later", "https://github.com/test/later.git"),
+	}}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := m.UpdateConcurrent(context.Background(), &cfg, 2)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err == nil || !strings.HasPrefix(err.Error(), "SRC_GIT_UPDATE") {
+			t.Fatalf("expected the failing source's error, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("update kept waiting on other sources after one failed")
+	}
+	if n := stub.started.Load(); n != 2 {
+		t.Fatalf("expected the source after the failure to stay unstarted, %d started", n)
+	}
+}
+
+func TestManagerResolveMemoizesPerCommit(t *testing.T) {
+	commit := "aaa111"
+	p := &gitProvider{
+		cacheRoot: t.TempDir(),
+		execGit: func(ctx context.Context, dir string, args ...string) ([]byte, error) {
+			return []byte(commit + "\n"), nil
+		},
+	}
+	m := NewManager(nil, t.TempDir(), true)
+	m.providers["git"] = p
+	src := testSourceConfig("test", "https://github.com/test/skills.git")
+	skillPath := filepath.Join(p.repoCacheDir(src), "skills", "docx", "SKILL.md")
+	setupFakeCache(t, p.repoCacheDir(src), map[string]map[string]string{"docx": {"SKILL.md": "# docx v1"}})
+	ctx := context.Background()
+
+	first, err := m.Resolve(ctx, src, ResolveRequest{Skill: "docx"})
+	if err != nil {
+		t.Fatalf("resolve failed: %v", err)
+	}
+	if err := os.WriteFile(skillPath, []byte("# docx v2"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	again, err := m.Resolve(ctx, src, ResolveRequest{Skill: "docx"})
+	if err != nil {
+		t.Fatalf("resolve failed: %v", err)
+	}
+	if first.Content != "# docx v1" || again.Content != first.Content {
+		t.Fatalf("expected the same commit to be served from memory, got %q then %q", first.Content, again.Content)
+	}
+
+	commit = "bbb222"
+	updated, err := m.Resolve(ctx, src, ResolveRequest{Skill: "docx"})
+	if err != nil {
+		t.Fatalf("resolve failed: %v", err)
+	}
+	if updated.Content != "# docx v2" || updated.Commit != "bbb222" {
+		t.Fatalf("expected a new commit to re-read the source, got %+v", updated)
+	}
+}
+
+func TestReadSkillFilesSkipsSymlinks(t *testing.T) {
+	dir := t.TempDir()
+	outside := filepath.Join(t.TempDir(), "secret")
+	if err := os.WriteFile(outside, []byte("secret"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte("# skill"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "notes.md"), []byte("notes"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(outside, filepath.Join(dir, "link.md")); err != nil {
+		t.Fatal(err)
+	}
+	files, err := ReadSkillFiles(dir)
+	if err != nil {
+		t.Fatalf("read skill files failed: %v", err)
+	}
+	if len(files) != 1 || files["notes.md"] != "notes" {
+		t.Fatalf("expected only notes.md, got %v", files)
+	}
+}
@@ -6,8 +6,12 @@ import (
 	"net/http"
 	"path/filepath"
 	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	"skillpm/internal/config"
+	"skillpm/internal/workpool"
 )
 
 type Provider interface {
@@ -21,8 +25,42 @@ type Publisher interface {
 	Publish(ctx context.Context, src config.SourceConfig, req PublishRequest) (PublishResult, error)
 }
 
+// StatusReporter is an optional interface for sources that keep local cache
+// state worth reporting.
+type StatusReporter interface {
+	Status(ctx context.Context, src config.SourceConfig) (SourceStatus, error)
+}
+
+// CacheReader is an optional interface for sources that can read a skill's
+// SKILL.md from a local cache without touching the network.
+type CacheReader interface {
+	CachedSkill(src config.SourceConfig, skill string) (string, bool)
+}
+
+// PatternSearcher is an optional interface for sources that can match a
+// regex or glob pattern against their skills locally. Other sources are
+// searched by the pattern's longest literal and their results filtered.
+type PatternSearcher interface {
+	SearchPattern(ctx context.Context, src config.SourceConfig, pattern *Pattern) ([]SearchResult, error)
+}
+
 type Manager struct {
 	providers map[string]Provider
+
+	// SearchCacheTTL is how long search results are reused per source and
+	// query; 0 disables the search cache.
+	SearchCacheTTL  time.Duration
+	searchCacheRoot string
+
+	// Retries is how many times a transient source update failure is
+	// retried; RetryBackoff is the first delay, doubled after each retry.
+	Retries      int
+	RetryBackoff time.Duration
+
+	// resolved memoizes resolutions for the life of the manager; see
+	// cachedResolve.
+	resolveMu sync.Mutex
+	resolved  map[resolveKey]ResolveResult
 }
 
 func NewManager(httpClient *http.Client, stateRoot string, quiet bool) *Manager {
@@ -34,9 +72,11 @@ func NewManager(httpClient *http.Client, stateRoot string, quiet bool) *Manager
 	return &Manager{
 		providers: map[string]Provider{
 			"git":     gitProv,
-			"dir":     gitProv,
+			"dir":     &dirProvider{},
 			"clawhub": &clawHubProvider{client: httpClient},
+			"archive": &archiveProvider{cacheRoot: filepath.Join(stateRoot, "cache", "archive"), client: httpClient},
 		},
+		searchCacheRoot: filepath.Join(stateRoot, "cache", "search"),
 	}
 }
 
@@ -49,6 +89,18 @@ func (m *Manager) provider(kind string) (Provider, error) {
 }
 
 func (m *Manager) Update(ctx context.Context, cfg *config.Config, name string) ([]UpdateResult, error) {
+	return m.update(ctx, cfg, name, 1)
+}
+
+// UpdateConcurrent updates every source like Update, with up to workers
+// sources updating at once. Results are applied to cfg in source order after
+// all updates finish. The first failure cancels the updates in flight, and
+// sources not yet started when it fails or ctx is cancelled are skipped.
+func (m *Manager) UpdateConcurrent(ctx context.Context, cfg *config.Config, workers int) ([]UpdateResult, error) {
+	return m.update(ctx, cfg, "", workers)
+}
+
+func (m *Manager) update(ctx context.Context, cfg *config.Config, name string, workers int) ([]UpdateResult, error) {
 	if cfg == nil {
 		return nil, fmt.Errorf("SRC_UPDATE: nil config")
 	}
@@ -63,15 +115,42 @@ func (m *Manager) Update(ctx context.Context, cfg *config.Config, name string) (
 		targets = append(targets, s)
 	}
 
-	results := make([]UpdateResult, 0, len(targets))
-	for _, src := range targets {
+	providers := make([]Provider, len(targets))
+	for i, src := range targets {
 		provider, err := m.provider(src.Kind)
 		if err != nil {
 			return nil, err
 		}
-		res, err := provider.Update(ctx, src)
+		providers[i] = provider
+	}
+	// The first failure fails the whole update, so it cancels the sources
+	// still in flight and leaves the rest unstarted.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var failOnce sync.Once
+	var failed error
+	updated := make([]UpdateResult, len(targets))
+	errs := workpool.Run(ctx, len(targets), workers, func(i int) error {
+		_, err := Retry(ctx, m.Retries, m.RetryBackoff, func() error {
+			res, err := providers[i].Update(ctx, targets[i])
+			updated[i] = res
+			return err
+		})
 		if err != nil {
-			return nil, err
+			failOnce.Do(func() {
+				failed = err
+				cancel()
+			})
+		}
+		return err
+	})
+	if failed != nil {
+		return nil, failed
+	}
+	results := make([]UpdateResult, 0, len(targets))
+	for i, res := range updated {
+		if errs[i] != nil {
+			return nil, errs[i]
 		}
 		results = append(results, res)
 		_ = config.ReplaceSource(cfg, res.Source)
@@ -80,18 +159,56 @@ func (m *Manager) Update(ctx context.Context, cfg *config.Config, name string) (
 	return results, nil
 }
 
-func (m *Manager) Search(ctx context.Context, cfg config.Config, sourceName string, query string) ([]SearchResult, error) {
+// Search queries the named sources, or all sources when sourceNames is
+// empty. Results are served from the search cache while fresh; see
+// SearchRefresh to bypass it.
+func (m *Manager) Search(ctx context.Context, cfg config.Config, sourceNames []string, query string) ([]SearchResult, error) {
+	return m.search(ctx, cfg, sourceNames, query, nil, false)
+}
+
+// SearchRefresh is like Search but always queries the sources, refreshing
+// the cached results.
+func (m *Manager) SearchRefresh(ctx context.Context, cfg config.Config, sourceNames []string, query string) ([]SearchResult, error) {
+	return m.search(ctx, cfg, sourceNames, query, nil, true)
+}
+
+// SearchPattern is like Search, or SearchRefresh when refresh is set, but
+// matches query as a regex or glob according to mode. An invalid pattern
+// fails before any source is queried.
+func (m *Manager) SearchPattern(ctx context.Context, cfg config.Config, sourceNames []string, query string, mode SearchMode, refresh bool) ([]SearchResult, error) {
+	if query == "" {
+		return nil, fmt.Errorf("SRC_SEARCH: query is required")
+	}
+	pattern, err := CompilePattern(query, mode)
+	if err != nil {
+		return nil, err
+	}
+	return m.search(ctx, cfg, sourceNames, query, pattern, refresh)
+}
+
+func (m *Manager) search(ctx context.Context, cfg config.Config, sourceNames []string, query string, pattern *Pattern, refresh bool) ([]SearchResult, error) {
 	if query == "" {
 		return nil, fmt.Errorf("SRC_SEARCH: query is required")
 	}
+	cacheKey := query
+	if pattern != nil {
+		cacheKey = string(pattern.Mode) + ":" + query
+	}
 	var sources []config.SourceConfig
-	if sourceName != "" {
-		s, ok := config.FindSource(cfg, sourceName)
+	seen := map[string]struct{}{}
+	for _, name := range sourceNames {
+		name = strings.TrimSpace(name)
+		if _, ok := seen[name]; ok || name == "" {
+			continue
+		}
+		seen[name] = struct{}{}
+		s, ok := config.FindSource(cfg, name)
 		if !ok {
-			return nil, fmt.Errorf("SRC_SEARCH: source %q not found", sourceName)
+			return nil, fmt.Errorf("SRC_SEARCH: source %q not found", name)
 		}
 		sources = append(sources, s)
-	} else {
+	}
+	if len(seen) == 0 {
 		sources = cfg.Sources
 	}
 
@@ -101,12 +218,35 @@ func (m *Manager) Search(ctx context.Context, cfg config.Config, sourceName stri
 		if err != nil {
 			return nil, err
 		}
-		items, err := provider.Search(ctx, src, query)
+		if m.SearchCacheTTL <= 0 {
+			items, err := searchProvider(ctx, provider, src, query, pattern)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, items...)
+			continue
+		}
+		now := time.Now()
+		commit := m.sourceRevision(ctx, provider, src)
+		if !refresh {
+			if items, ok := m.cachedSearch(src, cacheKey, commit, now); ok {
+				out = append(out, items...)
+				continue
+			}
+		}
+		items, err := searchProvider(ctx, provider, src, query, pattern)
 		if err != nil {
 			return nil, err
 		}
+		// A search may clone the source; record the revision it ran against.
+		m.storeSearch(src, cacheKey, m.sourceRevision(ctx, provider, src), now, items)
 		out = append(out, items...)
 	}
+	if pattern == nil {
+		for i := range out {
+			annotateMatch(&out[i], query)
+		}
+	}
 	sort.Slice(out, func(i, j int) bool {
 		if out[i].Source == out[j].Source {
 			return out[i].Slug < out[j].Slug
@@ -116,12 +256,90 @@ func (m *Manager) Search(ctx context.Context, cfg config.Config, sourceName stri
 	return out, nil
 }
 
+// searchProvider runs one source's search. Without PatternSearcher support,
+// a pattern search asks the source for the pattern's longest literal and
+// keeps the results the pattern matches.
+func searchProvider(ctx context.Context, provider Provider, src config.SourceConfig, query string, pattern *Pattern) ([]SearchResult, error) {
+	if pattern == nil {
+		return provider.Search(ctx, src, query)
+	}
+	if ps, ok := provider.(PatternSearcher); ok {
+		return ps.SearchPattern(ctx, src, pattern)
+	}
+	literal := pattern.literal()
+	if literal == "" {
+		return nil, fmt.Errorf("SRC_SEARCH: source %q cannot search by %s %q; include some plain text in the pattern", src.Name, pattern.Mode, query)
+	}
+	items, err := provider.Search(ctx, src, literal)
+	if err != nil {
+		return nil, err
+	}
+	var out []SearchResult
+	for _, item := range items {
+		item.Match, item.Snippet = "", ""
+		if pattern.annotate(&item) {
+			out = append(out, item)
+		}
+	}
+	return out, nil
+}
+
+// Status reports cache details for every configured source, sorted by name.
+// Sources whose provider keeps no local cache report only their config.
+func (m *Manager) Status(ctx context.Context, cfg config.Config) ([]SourceStatus, error) {
+	out := make([]SourceStatus, 0, len(cfg.Sources))
+	for _, src := range cfg.Sources {
+		provider, err := m.provider(src.Kind)
+		if err != nil {
+			return nil, err
+		}
+		reporter, ok := provider.(StatusReporter)
+		if !ok {
+			out = append(out, SourceStatus{Source: src})
+			continue
+		}
+		st, err := reporter.Status(ctx, src)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, st)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Source.Name < out[j].Source.Name })
+	return out, nil
+}
+
+// Resolve resolves a skill from src. Resolutions from sources with a local
+// cache are memoized per source commit, skill and constraint, so resolving
+// the same skill again in one run does not re-read the source.
 func (m *Manager) Resolve(ctx context.Context, src config.SourceConfig, req ResolveRequest) (ResolveResult, error) {
 	provider, err := m.provider(src.Kind)
 	if err != nil {
 		return ResolveResult{}, err
 	}
-	return provider.Resolve(ctx, src, req)
+	key, res, ok := m.cachedResolve(ctx, provider, src, req)
+	if ok {
+		return res, nil
+	}
+	res, err = provider.Resolve(ctx, src, req)
+	if err != nil {
+		return ResolveResult{}, err
+	}
+	m.rememberResolve(key, res)
+	return res, nil
+}
+
+// CachedSkill returns the cached SKILL.md of skill in src, if the source
+// keeps a local cache that has it.
+func (m *Manager) CachedSkill(src config.SourceConfig, skill string) (string, bool) {
+	provider, err := m.provider(src.Kind)
+	if err != nil {
+		return "", false
+	}
+	reader, ok := provider.(CacheReader)
+	if !ok {
+		return "", false
+	}
+	return reader.CachedSkill(src, skill)
 }
 
 func (m *Manager) Publish(ctx context.Context, src config.SourceConfig, req PublishRequest) (PublishResult, error) {
@@ -1,17 +1,45 @@
 package source
 
-import "skillpm/internal/config"
+import (
+	"time"
+
+	"skillpm/internal/config"
+)
 
 type UpdateResult struct {
 	Source config.SourceConfig `json:"source"`
 	Note   string              `json:"note"`
+	// URL is the URL the update succeeded from, when the source has
+	// mirrors to choose between.
+	URL string `json:"url,omitempty"`
+}
+
+// SourceStatus describes the local cache state of a source.
+type SourceStatus struct {
+	Source      config.SourceConfig `json:"source"`
+	CachePath   string              `json:"cachePath,omitempty"`
+	Cloned      bool                `json:"cloned"`
+	Commit      string              `json:"commit,omitempty"`
+	LastUpdated *time.Time          `json:"lastUpdated,omitempty"`
+	SkillCount  int                 `json:"skillCount"`
 }
 
 type SearchResult struct {
-	Source      string `json:"source"`
-	Slug        string `json:"slug"`
-	Name        string `json:"name"`
-	Description string `json:"description,omitempty"`
+	Source       string `json:"source"`
+	Slug         string `json:"slug"`
+	Name         string `json:"name"`
+	Description  string `json:"description,omitempty"`
+	Deprecated   bool   `json:"deprecated,omitempty"`
+	SupersededBy string `json:"supersededBy,omitempty"`
+	// ContentHash is the sha256 of SKILL.md, when the provider has it.
+	ContentHash string `json:"contentHash,omitempty"`
+	// AlsoIn lists other sources providing the same skill, set by
+	// DedupeResults.
+	AlsoIn []string `json:"alsoIn,omitempty"`
+	// Match is where the query was found (name, description or body) and
+	// Snippet the text around it, when known.
+	Match   string `json:"match,omitempty"`
+	Snippet string `json:"snippet,omitempty"`
 }
 
 type ResolveRequest struct {
@@ -24,6 +52,7 @@ type ResolveResult struct {
 	ResolvedVersion string
 	Checksum        string
 	SourceRef       string
+	Commit          string // full commit hash, for git-backed sources
 	Source          string
 	Skill           string
 	Content         string
@@ -0,0 +1,145 @@
+package source
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+)
+
+// SearchMode selects how a search query is matched against skills.
+type SearchMode string
+
+const (
+	// SearchSubstring matches the query as a case-insensitive substring.
+	SearchSubstring SearchMode = ""
+	// SearchRegex matches the query as a case-insensitive regular expression
+	// anywhere in the text.
+	SearchRegex SearchMode = "regex"
+	// SearchGlob matches the query as a case-insensitive glob against the
+	// whole text, with path.Match syntax.
+	SearchGlob SearchMode = "glob"
+)
+
+// Pattern is a compiled regex or glob search query.
+type Pattern struct {
+	Query string
+	Mode  SearchMode
+	re    *regexp.Regexp
+}
+
+// CompilePattern compiles query for mode, failing with SRC_SEARCH_REGEX or
+// SRC_SEARCH_GLOB when the pattern is invalid.
+func CompilePattern(query string, mode SearchMode) (*Pattern, error) {
+	p := &Pattern{Query: query, Mode: mode}
+	switch mode {
+	case SearchRegex:
+		re, err := regexp.Compile("(?i)" + query)
+		if err != nil {
+			return nil, fmt.Errorf("SRC_SEARCH_REGEX: invalid regex %q: %v", query, err)
+		}
+		p.re = re
+	case SearchGlob:
+		if _, err := path.Match(strings.ToLower(query), ""); err != nil {
+			return nil, fmt.Errorf("SRC_SEARCH_GLOB: invalid glob %q: %v", query, err)
+		}
+	default:
+		return nil, fmt.Errorf("SRC_SEARCH: unsupported search mode %q", mode)
+	}
+	return p, nil
+}
+
+// Match reports whether text matches the pattern.
+func (p *Pattern) Match(text string) bool {
+	if p.re != nil {
+		return p.re.MatchString(text)
+	}
+	ok, _ := path.Match(strings.ToLower(p.Query), strings.ToLower(text))
+	return ok
+}
+
+// annotate records which of res's slug, name or description matched,
+// reporting false when none did.
+func (p *Pattern) annotate(res *SearchResult) bool {
+	switch {
+	case p.Match(res.Name):
+		res.Match, res.Snippet = MatchName, res.Name
+	case p.Match(res.Slug):
+		res.Match, res.Snippet = MatchName, res.Slug
+	case res.Description != "" && p.Match(res.Description):
+		res.Match, res.Snippet = MatchDescription, res.Description
+	default:
+		return false
+	}
+	return true
+}
+
+// literal returns the longest run of plain text every match must contain,
+// for sources that can only search by substring; "" when there is none.
+func (p *Pattern) literal() string {
+	if p.Mode == SearchGlob {
+		return globLiteral(p.Query)
+	}
+	re, err := syntax.Parse(p.Query, syntax.Perl)
+	if err != nil {
+		return ""
+	}
+	return requiredLiteral(re.Simplify())
+}
+
+// globLiteral returns the longest run of plain text in a path.Match
+// pattern. Wildcards end a run, character classes are skipped whole since
+// they match one of their runes rather than all of them, and escaped runes
+// count as plain text.
+func globLiteral(query string) string {
+	var longest string
+	var run strings.Builder
+	flush := func() {
+		if run.Len() > len(longest) {
+			longest = run.String()
+		}
+		run.Reset()
+	}
+	rs := []rune(query)
+	for i := 0; i < len(rs); i++ {
+		switch rs[i] {
+		case '*', '?':
+			flush()
+		case '[':
+			flush()
+			for i++; i < len(rs) && rs[i] != ']'; i++ {
+				if rs[i] == '\\' {
+					i++
+				}
+			}
+		case '\\':
+			if i+1 < len(rs) {
+				i++
+				run.WriteRune(rs[i])
+			}
+		default:
+			run.WriteRune(rs[i])
+		}
+	}
+	flush()
+	return longest
+}
+
+func requiredLiteral(re *syntax.Regexp) string {
+	switch re.Op {
+	case syntax.OpLiteral:
+		return string(re.Rune)
+	case syntax.OpCapture:
+		return requiredLiteral(re.Sub[0])
+	case syntax.OpConcat:
+		longest := ""
+		for _, sub := range re.Sub {
+			if lit := requiredLiteral(sub); len(lit) > len(longest) {
+				longest = lit
+			}
+		}
+		return longest
+	}
+	return ""
+}
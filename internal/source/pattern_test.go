@@ -0,0 +1,73 @@
+package source
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"skillpm/internal/config"
+)
+
+func TestCompilePatternRejectsInvalidRegexAndGlob(t *testing.T) {
+	if _, err := CompilePattern("pdf(", SearchRegex); err == nil || !strings.HasPrefix(err.Error(), "SRC_SEARCH_REGEX") {
+		t.Fatalf("expected SRC_SEARCH_REGEX, got %v", err)
+	}
+	if _, err := CompilePattern("pdf[", SearchGlob); err == nil || !strings.HasPrefix(err.Error(), "SRC_SEARCH_GLOB") {
+		t.Fatalf("expected SRC_SEARCH_GLOB, got %v", err)
+	}
+}
+
+func TestPatternLiteral(t *testing.T) {
+	cases := []struct {
+		query string
+		mode  SearchMode
+		want  string
+	}{
+		{"pdf.*form", SearchRegex, "form"},
+		{"^(forms)$", SearchRegex, "forms"},
+		{"pdf|docx", SearchRegex, ""},
+		{"data/*-tools", SearchGlob, "-tools"},
+		{"[abcdef]x", SearchGlob, "x"},
+		{"pdf[!0-9]*", SearchGlob, "pdf"},
+		{`a\*bc*`, SearchGlob, "a*bc"},
+	}
+	for _, tc := range cases {
+		p, err := CompilePattern(tc.query, tc.mode)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.query, err)
+		}
+		if got := p.literal(); got != tc.want {
+			t.Errorf("literal(%q) = %q, want %q", tc.query, got, tc.want)
+		}
+	}
+}
+
+func TestManagerSearchPatternMatchesNamesAndDescriptions(t *testing.T) {
+	m := NewManager(nil, t.TempDir(), true)
+	src := testSourceConfig("data", "https://github.com/test/skills.git")
+	setupFakeCache(t, m.providers["git"].(*gitProvider).repoCacheDir(src), map[string]map[string]string{
+		"pdf-forms": {"SKILL.md": "# Fill PDF forms"},
+		"slides":    {"SKILL.md": "# Slides\npdf export form"},
+	})
+	cfg := config.Config{Sources: []config.SourceConfig{src}}
+
+	res, err := m.SearchPattern(context.Background(), cfg, nil, "pdf.*form", SearchRegex, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 1 || res[0].Name != "pdf-forms" || res[0].Match != MatchName {
+		t.Fatalf("expected only pdf-forms to match by name, got %+v", res)
+	}
+
+	res, err = m.SearchPattern(context.Background(), cfg, nil, "data/s*", SearchGlob, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 1 || res[0].Name != "slides" {
+		t.Fatalf("expected the glob to match data/slides, got %+v", res)
+	}
+
+	if _, err := m.SearchPattern(context.Background(), cfg, []string{"missing"}, "(", SearchRegex, false); err == nil || !strings.HasPrefix(err.Error(), "SRC_SEARCH_REGEX") {
+		t.Fatalf("expected the regex error before sources are looked up, got %v", err)
+	}
+}
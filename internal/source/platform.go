@@ -0,0 +1,53 @@
+package source
+
+import (
+	"path"
+	"strings"
+)
+
+// knownPlatforms are the GOOS values a skill can declare or tag files with.
+// js and wasip1 are left out: no agent runs there, and "_js" is a common
+// file name suffix that would otherwise read as a platform tag.
+var knownPlatforms = map[string]struct{}{
+	"aix": {}, "android": {}, "darwin": {}, "dragonfly": {}, "freebsd": {},
+	"illumos": {}, "ios": {}, "linux": {}, "netbsd": {},
+	"openbsd": {}, "plan9": {}, "solaris": {}, "windows": {},
+}
+
+// IsKnownPlatform reports whether name is a GOOS value skills can target.
+func IsKnownPlatform(name string) bool {
+	_, ok := knownPlatforms[name]
+	return ok
+}
+
+// FilePlatform returns the platform an ancillary file is tagged for, using
+// Go's file naming convention: a "_<goos>" suffix on the file name before
+// the extension, as in scripts/setup_windows.ps1. Untagged files return "".
+func FilePlatform(relPath string) string {
+	base := path.Base(relPath)
+	if ext := path.Ext(base); ext != base {
+		base = strings.TrimSuffix(base, ext)
+	}
+	i := strings.LastIndex(base, "_")
+	if i <= 0 {
+		return ""
+	}
+	if tag := base[i+1:]; IsKnownPlatform(tag) {
+		return tag
+	}
+	return ""
+}
+
+// SupportsPlatform reports whether a skill declaring platforms can run on
+// platform. No declaration means every platform.
+func SupportsPlatform(platforms []string, platform string) bool {
+	if len(platforms) == 0 {
+		return true
+	}
+	for _, p := range platforms {
+		if p == platform {
+			return true
+		}
+	}
+	return false
+}
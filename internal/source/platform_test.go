@@ -0,0 +1,40 @@
+package source
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParsePlatforms(t *testing.T) {
+	cases := []struct {
+		content string
+		want    string
+	}{
+		{"---\nname: tool\nplatforms: [linux, darwin]\n---\n# Tool\n", "linux,darwin"},
+		{"---\nplatforms = [\"Linux\", \"windows\"]\n---\n", "linux,windows"},
+		{"---\nname: any\n---\nplatforms: [linux]\n", ""},
+		{"# No frontmatter\n", ""},
+	}
+	for _, tc := range cases {
+		if got := strings.Join(ParsePlatforms(tc.content), ","); got != tc.want {
+			t.Fatalf("ParsePlatforms(%q) = %q; want %q", tc.content, got, tc.want)
+		}
+	}
+}
+
+func TestFilePlatform(t *testing.T) {
+	cases := map[string]string{
+		"scripts/setup_linux.sh":    "linux",
+		"scripts/setup_windows.ps1": "windows",
+		"bin/run_darwin":            "darwin",
+		"scripts/setup.sh":          "",
+		"docs/read_me.md":           "",
+		"_linux.sh":                 "",
+		"scripts/build_js.sh":       "",
+	}
+	for path, want := range cases {
+		if got := FilePlatform(path); got != want {
+			t.Fatalf("FilePlatform(%q) = %q; want %q", path, got, want)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package source
+
+import (
+	"context"
+	"strings"
+
+	"skillpm/internal/config"
+)
+
+// revisionReporter is implemented by providers that can cheaply report the
+// commit their local cache of a source is at.
+type revisionReporter interface {
+	revision(ctx context.Context, src config.SourceConfig) string
+}
+
+// resolveKey identifies one resolution of a skill at a source commit.
+type resolveKey struct {
+	source, url, commit, skill, constraint string
+}
+
+// cachedResolve returns a memoized resolution, keyed on the commit the
+// source's cache is at now so a source update in the same run misses.
+func (m *Manager) cachedResolve(ctx context.Context, provider Provider, src config.SourceConfig, req ResolveRequest) (resolveKey, ResolveResult, bool) {
+	reporter, ok := provider.(revisionReporter)
+	if !ok {
+		return resolveKey{}, ResolveResult{}, false
+	}
+	commit := reporter.revision(ctx, src)
+	if commit == "" {
+		return resolveKey{}, ResolveResult{}, false
+	}
+	key := resolveKey{source: src.Name, url: src.URL, commit: commit, skill: req.Skill, constraint: strings.ToLower(req.Constraint)}
+	m.resolveMu.Lock()
+	defer m.resolveMu.Unlock()
+	res, ok := m.resolved[key]
+	if !ok {
+		return key, ResolveResult{}, false
+	}
+	return key, cloneResolveResult(res), true
+}
+
+// rememberResolve memoizes res under key if res was read at key's commit.
+func (m *Manager) rememberResolve(key resolveKey, res ResolveResult) {
+	if key.commit == "" || res.Commit != key.commit {
+		return
+	}
+	m.resolveMu.Lock()
+	defer m.resolveMu.Unlock()
+	if m.resolved == nil {
+		m.resolved = map[resolveKey]ResolveResult{}
+	}
+	m.resolved[key] = cloneResolveResult(res)
+}
+
+// cloneResolveResult copies res so callers can change its files without
+// touching the memoized copy.
+func cloneResolveResult(res ResolveResult) ResolveResult {
+	if res.Files != nil {
+		files := make(map[string]string, len(res.Files))
+		for k, v := range res.Files {
+			files[k] = v
+		}
+		res.Files = files
+	}
+	return res
+}
@@ -0,0 +1,56 @@
+package source
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const defaultRetryBackoff = 500 * time.Millisecond
+
+// TransientError marks a failure that may succeed on retry: the remote
+// could not be reached or did not answer. Providers wrap only such
+// failures, so bad credentials, unknown branches and missing skills are
+// never retried. Clawhub retries its own requests and does not wrap.
+type TransientError struct {
+	Err error
+}
+
+func (e *TransientError) Error() string { return e.Err.Error() }
+
+func (e *TransientError) Unwrap() error { return e.Err }
+
+// IsTransient reports whether err carries a TransientError.
+func IsTransient(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var transient *TransientError
+	return errors.As(err, &transient)
+}
+
+// Retry calls fn until it succeeds, fails with a non-transient error or has
+// been retried retries times, waiting backoff before the first retry and
+// doubling it after each. It returns the number of attempts made; a failure
+// after more than one attempt says how many there were.
+func Retry(ctx context.Context, retries int, backoff time.Duration, fn func() error) (int, error) {
+	if backoff <= 0 {
+		backoff = defaultRetryBackoff
+	}
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt > retries || !IsTransient(err) {
+			if err != nil && attempt > 1 {
+				err = fmt.Errorf("%w (after %d attempts)", err, attempt)
+			}
+			return attempt, err
+		}
+		select {
+		case <-ctx.Done():
+			return attempt, err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
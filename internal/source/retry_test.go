@@ -0,0 +1,55 @@
+package source
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestIsTransient(t *testing.T) {
+	network := &TransientError{Err: errors.New("git clone: exit status 128")}
+	cases := map[error]bool{
+		fmt.Errorf("SRC_GIT_UPDATE: clone failed: %w", network):           true,
+		fmt.Errorf("SRC_GIT_UPDATE: clone failed: exit status 128"):       false,
+		fmt.Errorf("SRC_HTTP: dial tcp: connection refused"):              false,
+		&TransientError{Err: fmt.Errorf("wrapped: %w", context.Canceled)}: false,
+		errors.New("SEC_SCAN_BLOCKED: critical finding"):                  false,
+	}
+	for err, want := range cases {
+		if got := IsTransient(err); got != want {
+			t.Fatalf("IsTransient(%v) = %v, want %v", err, got, want)
+		}
+	}
+}
+
+func TestIsGitNetworkFailure(t *testing.T) {
+	cases := map[string]bool{
+		"fatal: unable to access 'https://x/': Could not resolve host: x":               true,
+		"fatal: unable to access 'http://127.0.0.1:1/': Failed to connect to 127.0.0.1": true,
+		"fatal: early EOF": true,
+		"fatal: Authentication failed for 'https://x/'":          false,
+		"fatal: Remote branch nope not found in upstream origin": false,
+		"fatal: repository 'https://x/' not found":               false,
+	}
+	for stderr, want := range cases {
+		if got := isGitNetworkFailure(stderr); got != want {
+			t.Fatalf("isGitNetworkFailure(%q) = %v, want %v", stderr, got, want)
+		}
+	}
+}
+
+func TestRetryStopsOnPermanentFailure(t *testing.T) {
+	calls := 0
+	attempts, err := Retry(context.Background(), 3, time.Millisecond, func() error {
+		calls++
+		if calls < 3 {
+			return &TransientError{Err: errors.New("SRC_ARCHIVE_DOWNLOAD: status 503")}
+		}
+		return errors.New("SRC_ARCHIVE_DOWNLOAD: status 404")
+	})
+	if attempts != 3 || err == nil || err.Error() != "SRC_ARCHIVE_DOWNLOAD: status 404 (after 3 attempts)" {
+		t.Fatalf("expected the permanent failure on attempt 3, got %d: %v", attempts, err)
+	}
+}
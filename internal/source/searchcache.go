@@ -0,0 +1,71 @@
+package source
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"skillpm/internal/config"
+	"skillpm/internal/fsutil"
+)
+
+// searchCacheEntry is one cached (source, query) search result set.
+type searchCacheEntry struct {
+	Source   string         `json:"source"`
+	Query    string         `json:"query"`
+	Commit   string         `json:"commit,omitempty"`
+	CachedAt time.Time      `json:"cachedAt"`
+	Results  []SearchResult `json:"results"`
+}
+
+func (m *Manager) searchCachePath(src config.SourceConfig, query string) string {
+	sum := sha256.Sum256([]byte(src.Name + "\x00" + query))
+	return filepath.Join(m.searchCacheRoot, hex.EncodeToString(sum[:8])+".json")
+}
+
+// sourceRevision returns the cached commit of sources that keep a local
+// clone, so cache entries written before a source update are not reused.
+func (m *Manager) sourceRevision(ctx context.Context, provider Provider, src config.SourceConfig) string {
+	reporter, ok := provider.(StatusReporter)
+	if !ok {
+		return ""
+	}
+	st, err := reporter.Status(ctx, src)
+	if err != nil {
+		return ""
+	}
+	return st.Commit
+}
+
+func (m *Manager) cachedSearch(src config.SourceConfig, query, commit string, now time.Time) ([]SearchResult, bool) {
+	blob, err := os.ReadFile(m.searchCachePath(src, query))
+	if err != nil {
+		return nil, false
+	}
+	var entry searchCacheEntry
+	if err := json.Unmarshal(blob, &entry); err != nil {
+		return nil, false
+	}
+	if entry.Source != src.Name || entry.Query != query || entry.Commit != commit {
+		return nil, false
+	}
+	if now.Sub(entry.CachedAt) > m.SearchCacheTTL {
+		return nil, false
+	}
+	return entry.Results, true
+}
+
+func (m *Manager) storeSearch(src config.SourceConfig, query, commit string, now time.Time, results []SearchResult) {
+	if err := os.MkdirAll(m.searchCacheRoot, 0o755); err != nil {
+		return
+	}
+	blob, err := json.Marshal(searchCacheEntry{Source: src.Name, Query: query, Commit: commit, CachedAt: now.UTC(), Results: results})
+	if err != nil {
+		return
+	}
+	_ = fsutil.AtomicWrite(m.searchCachePath(src, query), blob, 0o644)
+}
@@ -0,0 +1,108 @@
+package source
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"skillpm/internal/config"
+)
+
+// countingProvider counts searches and reports a settable cache commit.
+type countingProvider struct {
+	searches int
+	commit   string
+}
+
+func (p *countingProvider) Update(context.Context, config.SourceConfig) (UpdateResult, error) {
+	return UpdateResult{}, nil
+}
+
+func (p *countingProvider) Search(_ context.Context, src config.SourceConfig, query string) ([]SearchResult, error) {
+	p.searches++
+	return []SearchResult{{Source: src.Name, Slug: query, Name: query}}, nil
+}
+
+func (p *countingProvider) Resolve(context.Context, config.SourceConfig, ResolveRequest) (ResolveResult, error) {
+	return ResolveResult{}, nil
+}
+
+func (p *countingProvider) Status(_ context.Context, src config.SourceConfig) (SourceStatus, error) {
+	return SourceStatus{Source: src, Commit: p.commit}, nil
+}
+
+func newCountingManager(t *testing.T, ttl time.Duration) (*Manager, *countingProvider, config.Config) {
+	t.Helper()
+	m := NewManager(nil, t.TempDir(), true)
+	prov := &countingProvider{commit: "aaa"}
+	m.providers["git"] = prov
+	m.SearchCacheTTL = ttl
+	cfg := config.Config{Sources: []config.SourceConfig{{Name: "local", Kind: "git"}}}
+	return m, prov, cfg
+}
+
+func TestSearchServesCachedResultsWithinTTL(t *testing.T) {
+	m, prov, cfg := newCountingManager(t, time.Hour)
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		res, err := m.Search(ctx, cfg, nil, "pdf")
+		if err != nil {
+			t.Fatalf("search failed: %v", err)
+		}
+		if len(res) != 1 || res[0].Slug != "pdf" {
+			t.Fatalf("unexpected results: %+v", res)
+		}
+	}
+	if prov.searches != 1 {
+		t.Fatalf("expected one provider search, got %d", prov.searches)
+	}
+
+	if _, err := m.SearchRefresh(ctx, cfg, nil, "pdf"); err != nil {
+		t.Fatalf("refresh failed: %v", err)
+	}
+	if prov.searches != 2 {
+		t.Fatalf("expected refresh to bypass the cache, got %d searches", prov.searches)
+	}
+
+	prov.commit = "bbb"
+	if _, err := m.Search(ctx, cfg, nil, "pdf"); err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if prov.searches != 3 {
+		t.Fatalf("expected a new commit to invalidate the cache, got %d searches", prov.searches)
+	}
+}
+
+func TestSearchCacheDisabledOrExpired(t *testing.T) {
+	for _, ttl := range []time.Duration{0, time.Nanosecond} {
+		m, prov, cfg := newCountingManager(t, ttl)
+		for i := 0; i < 2; i++ {
+			if _, err := m.Search(context.Background(), cfg, nil, "pdf"); err != nil {
+				t.Fatalf("search failed: %v", err)
+			}
+		}
+		if prov.searches != 2 {
+			t.Fatalf("ttl %v: expected no cache hits, got %d searches", ttl, prov.searches)
+		}
+	}
+}
+
+func TestSearchSubsetOfSources(t *testing.T) {
+	m, _, cfg := newCountingManager(t, 0)
+	cfg.Sources = []config.SourceConfig{{Name: "hub", Kind: "git"}, {Name: "local", Kind: "git"}, {Name: "mirror", Kind: "git"}}
+	ctx := context.Background()
+
+	res, err := m.Search(ctx, cfg, []string{"mirror", "local", "mirror"}, "pdf")
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if len(res) != 2 || res[0].Source != "local" || res[1].Source != "mirror" {
+		t.Fatalf("expected results from local and mirror only, got %+v", res)
+	}
+	if res, _ := m.Search(ctx, cfg, nil, "pdf"); len(res) != 3 {
+		t.Fatalf("expected every source without names, got %+v", res)
+	}
+	if _, err := m.Search(ctx, cfg, []string{"local", "nope"}, "pdf"); err == nil {
+		t.Fatal("expected unknown source to fail")
+	}
+}
@@ -0,0 +1,80 @@
+package source
+
+import (
+	"strings"
+	"unicode"
+)
+
+// snippetWidth is roughly how many characters of context a search snippet
+// keeps around the match.
+const snippetWidth = 80
+
+// Search match locations reported in SearchResult.Match.
+const (
+	MatchName        = "name"
+	MatchDescription = "description"
+	MatchBody        = "body"
+)
+
+// MatchSnippet returns the line of text holding the first case-insensitive
+// occurrence of query, cut to about snippetWidth characters around it with
+// "…" marking the cuts. ok is false when text does not contain query.
+func MatchSnippet(text, query string) (string, bool) {
+	q := []rune(strings.ToLower(strings.TrimSpace(query)))
+	if len(q) == 0 {
+		return "", false
+	}
+	for _, line := range strings.Split(text, "\n") {
+		runes := []rune(line)
+		at := indexFold(runes, q)
+		if at < 0 {
+			continue
+		}
+		start, end := 0, len(runes)
+		if end-start > snippetWidth {
+			start = max(0, at-(snippetWidth-len(q))/2)
+			end = min(len(runes), start+snippetWidth)
+			start = max(0, end-snippetWidth)
+		}
+		snippet := strings.TrimSpace(string(runes[start:end]))
+		if start > 0 {
+			snippet = "…" + snippet
+		}
+		if end < len(runes) {
+			snippet += "…"
+		}
+		return snippet, true
+	}
+	return "", false
+}
+
+// indexFold is the rune index of the lower-case needle in s, ignoring case,
+// or -1.
+func indexFold(s, needle []rune) int {
+	for i := 0; i+len(needle) <= len(s); i++ {
+		match := true
+		for j, r := range needle {
+			if unicode.ToLower(s[i+j]) != r {
+				match = false
+				break
+			}
+		}
+		if match {
+			return i
+		}
+	}
+	return -1
+}
+
+// annotateMatch fills in where query matched a result that the provider did
+// not annotate, checking the name and then the description.
+func annotateMatch(res *SearchResult, query string) {
+	if res.Match != "" {
+		return
+	}
+	if snippet, ok := MatchSnippet(res.Name, query); ok {
+		res.Match, res.Snippet = MatchName, snippet
+	} else if snippet, ok := MatchSnippet(res.Description, query); ok {
+		res.Match, res.Snippet = MatchDescription, snippet
+	}
+}
@@ -0,0 +1,48 @@
+package source
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestMatchSnippet(t *testing.T) {
+	if got, ok := MatchSnippet("# Forms\nFill PDF forms quickly.\n", "pdf"); !ok || got != "Fill PDF forms quickly." {
+		t.Fatalf("expected the matching line, got %q, %v", got, ok)
+	}
+	long := strings.Repeat("a", 100) + " needle " + strings.Repeat("b", 100)
+	got, ok := MatchSnippet(long, "NEEDLE")
+	if !ok || !strings.Contains(got, "needle") || !strings.HasPrefix(got, "…") || !strings.HasSuffix(got, "…") {
+		t.Fatalf("expected a cut snippet around the match, got %q", got)
+	}
+	if len([]rune(got)) > snippetWidth+2 {
+		t.Fatalf("snippet too long: %d runes", len([]rune(got)))
+	}
+	if _, ok := MatchSnippet("nothing here", "pdf"); ok {
+		t.Fatal("expected no match")
+	}
+}
+
+func TestGitProviderSearchMatchesBodyWithSnippet(t *testing.T) {
+	p := &gitProvider{cacheRoot: t.TempDir()}
+	src := testSourceConfig("test", "https://github.com/test/skills.git")
+	setupFakeCache(t, p.repoCacheDir(src), map[string]map[string]string{
+		"forms":  {"SKILL.md": "# forms\nFill in PDF forms."},
+		"pdf":    {"SKILL.md": "# pdf\nRead documents."},
+		"slides": {"SKILL.md": "# slides\nMake decks."},
+	})
+
+	results, err := p.Search(context.Background(), src, "pdf")
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected a name match and a body match, got %+v", results)
+	}
+	if results[0].Name != "forms" || results[0].Match != MatchBody || results[0].Snippet != "Fill in PDF forms." {
+		t.Fatalf("unexpected body match: %+v", results[0])
+	}
+	if results[1].Name != "pdf" || results[1].Match != MatchName {
+		t.Fatalf("unexpected name match: %+v", results[1])
+	}
+}
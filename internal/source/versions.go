@@ -0,0 +1,160 @@
+package source
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"golang.org/x/mod/semver"
+
+	"skillpm/internal/config"
+)
+
+// VersionLister is an optional interface for sources that can list the
+// published versions of a skill without fetching its content, and preview
+// which of them a constraint resolves to by the same rules as Resolve.
+type VersionLister interface {
+	Versions(ctx context.Context, src config.SourceConfig, skill string) ([]string, error)
+	SelectVersion(ctx context.Context, src config.SourceConfig, skill string, versions []string, constraint string) (selected, reason string, err error)
+}
+
+// Versions lists the versions src publishes for skill, newest first.
+func (m *Manager) Versions(ctx context.Context, src config.SourceConfig, skill string) ([]string, error) {
+	lister, err := m.versionLister(src)
+	if err != nil {
+		return nil, err
+	}
+	versions, err := lister.Versions(ctx, src, skill)
+	if err != nil {
+		return nil, err
+	}
+	SortVersions(versions)
+	return versions, nil
+}
+
+// SelectVersion previews which of versions resolving skill with constraint
+// would install, and why. Selected is empty when the choice is only made
+// at install time, e.g. for a registry tag; an SRC_VERSION_NONE error means
+// the constraint cannot be satisfied.
+func (m *Manager) SelectVersion(ctx context.Context, src config.SourceConfig, skill string, versions []string, constraint string) (string, string, error) {
+	lister, err := m.versionLister(src)
+	if err != nil {
+		return "", "", err
+	}
+	return lister.SelectVersion(ctx, src, skill, versions, strings.TrimSpace(constraint))
+}
+
+func (m *Manager) versionLister(src config.SourceConfig) (VersionLister, error) {
+	provider, err := m.provider(src.Kind)
+	if err != nil {
+		return nil, err
+	}
+	lister, ok := provider.(VersionLister)
+	if !ok {
+		return nil, fmt.Errorf("SRC_VERSIONS: source kind %q cannot list versions", src.Kind)
+	}
+	return lister, nil
+}
+
+// Versions reports the one version a git source serves: the
+// "0.0.0+git.<commit>" pseudo-version Resolve gives the commit it would
+// install. That is the cached clone's HEAD, or the remote branch head when
+// the source has not been cloned yet.
+func (p *gitProvider) Versions(ctx context.Context, src config.SourceConfig, skill string) ([]string, error) {
+	if skill == "" {
+		return nil, fmt.Errorf("SRC_VERSIONS: empty skill")
+	}
+	if cacheDir := p.repoCacheDir(src); isGitRepo(cacheDir) {
+		out, err := p.execGit(ctx, cacheDir, "rev-parse", "--short", "HEAD")
+		if err != nil {
+			return nil, fmt.Errorf("SRC_VERSIONS: reading HEAD of %s: %w", src.Name, err)
+		}
+		return []string{"0.0.0+git." + strings.TrimSpace(string(out))}, nil
+	}
+	ref := "HEAD"
+	if src.Branch != "" {
+		ref = "refs/heads/" + src.Branch
+	}
+	out, err := p.execGit(ctx, "", "ls-remote", src.URL, ref)
+	if err != nil {
+		return nil, fmt.Errorf("SRC_VERSIONS: reading %s of %s: %w", ref, src.Name, err)
+	}
+	fields := strings.Fields(string(out))
+	if len(fields) < 2 || len(fields[0]) < 7 {
+		return nil, fmt.Errorf("SRC_VERSIONS: %s not found in %s", ref, src.Name)
+	}
+	return []string{"0.0.0+git." + fields[0][:7]}, nil
+}
+
+// SelectVersion mirrors Resolve: latest installs the checked-out commit,
+// and any other constraint installs that same commit labelled with the
+// constraint, since git sources do not check out tags.
+func (p *gitProvider) SelectVersion(_ context.Context, _ config.SourceConfig, _ string, versions []string, constraint string) (string, string, error) {
+	if constraint == "" || strings.EqualFold(constraint, "latest") {
+		if len(versions) == 0 {
+			return "", "", fmt.Errorf("SRC_VERSION_NONE: no commit to install")
+		}
+		return versions[0], "checked-out commit", nil
+	}
+	return constraint, "git sources install the checked-out commit under the requested version", nil
+}
+
+func (p *clawHubProvider) Versions(ctx context.Context, src config.SourceConfig, skill string) ([]string, error) {
+	if skill == "" {
+		return nil, fmt.Errorf("SRC_VERSIONS: empty skill")
+	}
+	status, body, err := p.getJSONWithFallback(ctx, resolvedRegistry(src), "/api/v1/skills/"+escapeSlugPath(skill)+"/versions", nil)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("SRC_VERSIONS: versions returned status %d", status)
+	}
+	return parseVersions(body), nil
+}
+
+// SelectVersion applies Resolve's reading of constraint (see
+// clawhubConstraint). A sha256: hash is looked up with the registry's
+// resolve endpoint; a tag is only resolved by the registry at download.
+func (p *clawHubProvider) SelectVersion(ctx context.Context, src config.SourceConfig, skill string, versions []string, constraint string) (string, string, error) {
+	version, tag, hash := clawhubConstraint(constraint)
+	switch {
+	case hash != "":
+		resolved, _, err := p.resolveByHash(ctx, resolvedRegistry(src), skill, hash)
+		if err != nil {
+			return "", "", err
+		}
+		return resolved, "registry resolves the hash", nil
+	case version != "":
+		for _, v := range versions {
+			if v == version {
+				return v, "exact version", nil
+			}
+		}
+		return "", "", fmt.Errorf("SRC_VERSION_NONE: version %q is not published", version)
+	case tag != "":
+		return "", fmt.Sprintf("tag %q is resolved by the registry at install time", tag), nil
+	}
+	if len(versions) == 0 {
+		return "", "", fmt.Errorf("SRC_VERSION_NONE: no versions published")
+	}
+	return chooseLatest(append([]string(nil), versions...)), "newest published version", nil
+}
+
+// SortVersions orders versions newest first. Semantic versions come first
+// in descending order; other tags follow in lexical order.
+func SortVersions(versions []string) {
+	sort.SliceStable(versions, func(i, j int) bool {
+		vi, vj := normalizeSemver(versions[i]), normalizeSemver(versions[j])
+		switch {
+		case vi != "" && vj != "":
+			return semver.Compare(vi, vj) > 0
+		case vi != "" || vj != "":
+			return vi != ""
+		default:
+			return versions[i] < versions[j]
+		}
+	})
+}
@@ -0,0 +1,57 @@
+package source
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"skillpm/internal/config"
+)
+
+func TestSortVersionsNewestFirst(t *testing.T) {
+	versions := []string{"1.2.0", "stable", "v2.0.0", "1.10.0", "2.0.0-rc.1", "beta"}
+	SortVersions(versions)
+	want := "v2.0.0,2.0.0-rc.1,1.10.0,1.2.0,beta,stable"
+	if got := strings.Join(versions, ","); got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
+
+func TestClawhubSelectVersionFollowsResolve(t *testing.T) {
+	p := &clawHubProvider{}
+	src := config.SourceConfig{Name: "hub", Kind: "clawhub"}
+	versions := []string{"1.1.0", "1.2.0", "2.0.0"}
+	cases := []struct {
+		constraint string
+		want       string
+	}{
+		{"", "2.0.0"},
+		{"latest", "2.0.0"},
+		{"1.2.0", "1.2.0"},
+		{"tag:stable", ""},
+		{"stable", ""},
+	}
+	for _, tc := range cases {
+		got, reason, err := p.SelectVersion(context.Background(), src, "pdf", versions, tc.constraint)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", tc.constraint, err)
+		}
+		if got != tc.want || reason == "" {
+			t.Fatalf("%q: got %q (%s), want %q", tc.constraint, got, reason, tc.want)
+		}
+	}
+	if _, _, err := p.SelectVersion(context.Background(), src, "pdf", versions, "3.0.0"); err == nil || !strings.HasPrefix(err.Error(), "SRC_VERSION_NONE:") {
+		t.Fatalf("expected SRC_VERSION_NONE for an unpublished version, got %v", err)
+	}
+}
+
+func TestGitSelectVersionIsCheckedOutCommit(t *testing.T) {
+	p := &gitProvider{}
+	versions := []string{"0.0.0+git.abc1234"}
+	if got, _, err := p.SelectVersion(context.Background(), config.SourceConfig{}, "pdf", versions, ""); err != nil || got != versions[0] {
+		t.Fatalf("expected latest to select the checked-out commit, got %q, %v", got, err)
+	}
+	if got, _, err := p.SelectVersion(context.Background(), config.SourceConfig{}, "pdf", versions, "1.2.0"); err != nil || got != "1.2.0" {
+		t.Fatalf("expected an explicit constraint to label the commit, got %q, %v", got, err)
+	}
+}
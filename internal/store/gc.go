@@ -0,0 +1,74 @@
+package store
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// GCResult reports the outcome of an installed-dir garbage collection pass.
+type GCResult struct {
+	Removed        []string `json:"removed"`
+	ReclaimedBytes int64    `json:"reclaimedBytes"`
+	DryRun         bool     `json:"dryRun"`
+}
+
+// CollectGarbage removes directories under the installed root that are not
+// referenced by any installed record in state. With dryRun set, nothing is
+// deleted and the result lists what would be removed.
+func CollectGarbage(root string, dryRun bool) (GCResult, error) {
+	st, err := LoadState(root)
+	if err != nil {
+		return GCResult{}, err
+	}
+	referenced := make(map[string]struct{}, len(st.Installed))
+	for _, rec := range st.Installed {
+		for _, name := range InstalledDirNames(rec.SkillRef, rec.ResolvedVersion) {
+			referenced[name] = struct{}{}
+		}
+	}
+
+	res := GCResult{Removed: []string{}, DryRun: dryRun}
+	entries, err := os.ReadDir(InstalledRoot(root))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return res, nil
+		}
+		return GCResult{}, err
+	}
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		if _, ok := referenced[e.Name()]; ok {
+			continue
+		}
+		path := filepath.Join(InstalledRoot(root), e.Name())
+		size := dirSize(path)
+		if !dryRun {
+			if err := os.RemoveAll(path); err != nil {
+				return GCResult{}, err
+			}
+		}
+		res.Removed = append(res.Removed, e.Name())
+		res.ReclaimedBytes += size
+	}
+	sort.Strings(res.Removed)
+	return res, nil
+}
+
+// dirSize returns the total size in bytes of regular files under path.
+func dirSize(path string) int64 {
+	var total int64
+	_ = filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if info, infoErr := d.Info(); infoErr == nil && info.Mode().IsRegular() {
+			total += info.Size()
+		}
+		return nil
+	})
+	return total
+}
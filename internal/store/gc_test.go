@@ -0,0 +1,70 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCollectGarbageRemovesOnlyUnreferencedDirs(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "state")
+	if err := EnsureLayout(root); err != nil {
+		t.Fatalf("ensure layout failed: %v", err)
+	}
+	st := State{Version: StateVersion}
+	UpsertInstalled(&st, InstalledSkill{SkillRef: "hub/keep", ResolvedVersion: "1.0.0"})
+	UpsertInstalled(&st, InstalledSkill{SkillRef: "hub/keep2", ResolvedVersion: "2.0.0"})
+	if err := SaveState(root, st); err != nil {
+		t.Fatalf("save state failed: %v", err)
+	}
+	for _, name := range []string{
+		InstalledDirName("hub/keep", "1.0.0", 0),
+		InstalledDirName("hub/keep2", "2.0.0", 0),
+		InstalledDirName("hub/keep", "0.9.0", 0),
+		InstalledDirName("hub/gone", "1.0.0", 0),
+	} {
+		dir := filepath.Join(InstalledRoot(root), name)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("mkdir failed: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte("12345"), 0o644); err != nil {
+			t.Fatalf("write failed: %v", err)
+		}
+	}
+
+	dry, err := CollectGarbage(root, true)
+	if err != nil {
+		t.Fatalf("dry-run gc failed: %v", err)
+	}
+	if len(dry.Removed) != 2 || dry.ReclaimedBytes != 10 || !dry.DryRun {
+		t.Fatalf("unexpected dry-run result: %+v", dry)
+	}
+	if _, err := os.Stat(filepath.Join(InstalledRoot(root), InstalledDirName("hub/gone", "1.0.0", 0))); err != nil {
+		t.Fatalf("dry-run must not remove directories: %v", err)
+	}
+
+	res, err := CollectGarbage(root, false)
+	if err != nil {
+		t.Fatalf("gc failed: %v", err)
+	}
+	if len(res.Removed) != 2 || res.ReclaimedBytes != 10 {
+		t.Fatalf("unexpected gc result: %+v", res)
+	}
+	entries, err := os.ReadDir(InstalledRoot(root))
+	if err != nil {
+		t.Fatalf("read installed root failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 referenced dirs to remain, got %d", len(entries))
+	}
+}
+
+func TestCollectGarbageMissingInstalledRoot(t *testing.T) {
+	res, err := CollectGarbage(filepath.Join(t.TempDir(), "state"), false)
+	if err != nil {
+		t.Fatalf("gc failed: %v", err)
+	}
+	if len(res.Removed) != 0 || res.ReclaimedBytes != 0 {
+		t.Fatalf("expected empty result, got %+v", res)
+	}
+}
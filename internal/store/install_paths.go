@@ -1,20 +1,49 @@
 package store
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"os"
 	"path/filepath"
 	"strings"
 )
 
+// hashedDirPrefix marks installed directories that use the shortened,
+// hash-based naming scheme. The skill ref and version stay recoverable from
+// the metadata.toml written into every installed directory.
+const hashedDirPrefix = "h-"
+
 // InstalledDirName returns the sanitized on-disk directory name for an
-// installed skill artifact.
-func InstalledDirName(skillRef, resolvedVersion string) string {
-	return sanitizeInstalledName(skillRef) + "@" + sanitizeInstalledName(resolvedVersion)
+// installed skill artifact. Names longer than maxLen are replaced with a
+// fixed-length hashed name; maxLen <= 0 disables hashing.
+func InstalledDirName(skillRef, resolvedVersion string, maxLen int) string {
+	name := naturalInstalledDirName(skillRef, resolvedVersion)
+	if maxLen > 0 && len(name) > maxLen {
+		return hashedInstalledDirName(skillRef, resolvedVersion)
+	}
+	return name
 }
 
-// InstalledDirPath returns the absolute path to an installed skill artifact.
-func InstalledDirPath(root, skillRef, resolvedVersion string) string {
-	return filepath.Join(InstalledRoot(root), InstalledDirName(skillRef, resolvedVersion))
+// InstalledDirNames returns every directory name an installed artifact may
+// use on disk, covering both the natural and the hashed naming schemes.
+func InstalledDirNames(skillRef, resolvedVersion string) []string {
+	return []string{
+		naturalInstalledDirName(skillRef, resolvedVersion),
+		hashedInstalledDirName(skillRef, resolvedVersion),
+	}
+}
+
+// IsInstalledDirFor reports whether the installed directory name belongs to
+// any version of skillRef under either naming scheme.
+func IsInstalledDirFor(name, skillRef string) bool {
+	return strings.HasPrefix(name, InstalledDirPrefix(skillRef)) ||
+		strings.HasPrefix(name, hashedInstalledDirPrefix(skillRef))
+}
+
+// InstalledDirPath returns the absolute path to an installed skill
+// artifact, named as InstalledDirName does for maxLen.
+func InstalledDirPath(root, skillRef, resolvedVersion string, maxLen int) string {
+	return filepath.Join(InstalledRoot(root), InstalledDirName(skillRef, resolvedVersion, maxLen))
 }
 
 // InstalledDirPrefix returns the sanitized directory prefix for all installed
@@ -30,9 +59,8 @@ func FindInstalledDir(root, skillRef string) string {
 		return ""
 	}
 
-	prefix := InstalledDirPrefix(skillRef)
 	for _, entry := range entries {
-		if strings.HasPrefix(entry.Name(), prefix) {
+		if IsInstalledDirFor(entry.Name(), skillRef) {
 			return filepath.Join(InstalledRoot(root), entry.Name())
 		}
 	}
@@ -48,3 +76,20 @@ func sanitizeInstalledName(v string) string {
 	}
 	return out
 }
+
+func naturalInstalledDirName(skillRef, resolvedVersion string) string {
+	return sanitizeInstalledName(skillRef) + "@" + sanitizeInstalledName(resolvedVersion)
+}
+
+func hashedInstalledDirName(skillRef, resolvedVersion string) string {
+	return hashedInstalledDirPrefix(skillRef) + shortNameHash(resolvedVersion)
+}
+
+func hashedInstalledDirPrefix(skillRef string) string {
+	return hashedDirPrefix + shortNameHash(skillRef) + "@"
+}
+
+func shortNameHash(v string) string {
+	sum := sha256.Sum256([]byte(v))
+	return hex.EncodeToString(sum[:])[:12]
+}
@@ -12,6 +12,8 @@ import (
 
 const LockVersion = 1
 
+// LoadLockfile reads the lockfile at path, returning an empty lockfile when
+// it does not exist.
 func LoadLockfile(path string) (Lockfile, error) {
 	blob, err := os.ReadFile(path)
 	if err != nil {
@@ -20,6 +22,23 @@ func LoadLockfile(path string) (Lockfile, error) {
 		}
 		return Lockfile{}, err
 	}
+	return DecodeLockfile(blob)
+}
+
+// SaveLockfile writes lock to path.
+func SaveLockfile(path string, lock Lockfile) error {
+	blob, err := EncodeLockfile(lock)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return fsutil.AtomicWrite(path, blob, 0o644)
+}
+
+// DecodeLockfile parses and validates an encoded lockfile.
+func DecodeLockfile(blob []byte) (Lockfile, error) {
 	var lock Lockfile
 	if err := toml.Unmarshal(blob, &lock); err != nil {
 		return Lockfile{}, fmt.Errorf("DOC_LOCK_PARSE: %w", err)
@@ -46,25 +65,36 @@ func LoadLockfile(path string) (Lockfile, error) {
 	return lock, nil
 }
 
-func SaveLockfile(path string, lock Lockfile) error {
+// EncodeLockfile stamps the lock version and encodes lock with its skills
+// sorted by ref.
+func EncodeLockfile(lock Lockfile) ([]byte, error) {
 	lock.Version = LockVersion
 	sort.Slice(lock.Skills, func(i, j int) bool {
 		return lock.Skills[i].SkillRef < lock.Skills[j].SkillRef
 	})
 	blob, err := toml.Marshal(lock)
 	if err != nil {
-		return fmt.Errorf("DOC_LOCK_ENCODE: %w", err)
+		return nil, fmt.Errorf("DOC_LOCK_ENCODE: %w", err)
 	}
-	parent := filepath.Dir(path)
-	if err := os.MkdirAll(parent, 0o755); err != nil {
-		return err
+	return blob, nil
+}
+
+// FindLock returns the lock entry for skillRef.
+func FindLock(lock Lockfile, skillRef string) (LockSkill, bool) {
+	for _, s := range lock.Skills {
+		if s.SkillRef == skillRef {
+			return s, true
+		}
 	}
-	return fsutil.AtomicWrite(path, blob, 0o644)
+	return LockSkill{}, false
 }
 
+// UpsertLock adds rec to lock or replaces the entry for its skill. A pin on
+// the replaced entry is kept.
 func UpsertLock(lock *Lockfile, rec LockSkill) {
 	for i := range lock.Skills {
 		if lock.Skills[i].SkillRef == rec.SkillRef {
+			rec.Pinned = rec.Pinned || lock.Skills[i].Pinned
 			lock.Skills[i] = rec
 			return
 		}
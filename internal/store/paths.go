@@ -26,6 +26,16 @@ func AuditPath(root string) string {
 	return filepath.Join(root, "audit.log")
 }
 
+// SyncHistoryPath is the append-only log of recorded sync runs.
+func SyncHistoryPath(root string) string {
+	return filepath.Join(root, "sync-history.jsonl")
+}
+
+// MetricsPath holds the local operation counters.
+func MetricsPath(root string) string {
+	return filepath.Join(root, "metrics.json")
+}
+
 func AdapterStateRoot(root string) string {
 	return filepath.Join(root, "adapters")
 }
@@ -52,6 +52,9 @@ func SaveState(root string, st State) error {
 		return st.Installed[i].SkillRef < st.Installed[j].SkillRef
 	})
 	sort.Slice(st.Injections, func(i, j int) bool {
+		if st.Injections[i].Agent == st.Injections[j].Agent {
+			return st.Injections[i].Scope < st.Injections[j].Scope
+		}
 		return st.Injections[i].Agent < st.Injections[j].Agent
 	})
 	blob, err := toml.Marshal(st)
@@ -81,12 +84,35 @@ func RemoveInstalled(st *State, skillRef string) bool {
 	return false
 }
 
+// SetInjection records the injected skills for an agent in one scope,
+// leaving the agent's records in other scopes untouched. A legacy entry
+// without a scope is taken over by the first scoped write for its agent.
 func SetInjection(st *State, in InjectionState) {
+	legacy := -1
 	for i := range st.Injections {
-		if st.Injections[i].Agent == in.Agent {
+		if st.Injections[i].Agent != in.Agent {
+			continue
+		}
+		if st.Injections[i].Scope == in.Scope {
 			st.Injections[i] = in
 			return
 		}
+		if st.Injections[i].Scope == "" {
+			legacy = i
+		}
+	}
+	if legacy >= 0 {
+		st.Injections[legacy] = in
+		return
 	}
 	st.Injections = append(st.Injections, in)
 }
+
+// EffectiveScope returns the injection's scope, or def for legacy entries
+// recorded without one.
+func (in InjectionState) EffectiveScope(def string) string {
+	if in.Scope == "" {
+		return def
+	}
+	return in.Scope
+}
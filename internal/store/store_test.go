@@ -151,7 +151,7 @@ func TestLoadLockfileInvalidTOMLReturnsParseError(t *testing.T) {
 }
 
 func TestInstalledDirNameSanitizesRefAndVersion(t *testing.T) {
-	got := InstalledDirName("anthropic/skill-creator", "2026.03.20@build 1")
+	got := InstalledDirName("anthropic/skill-creator", "2026.03.20@build 1", 0)
 	want := "anthropic_skill-creator@2026.03.20_build-1"
 	if got != want {
 		t.Fatalf("InstalledDirName() = %q, want %q", got, want)
@@ -160,7 +160,7 @@ func TestInstalledDirNameSanitizesRefAndVersion(t *testing.T) {
 
 func TestInstalledDirPathUsesSanitizedDirectoryName(t *testing.T) {
 	root := t.TempDir()
-	got := InstalledDirPath(root, "anthropic/skill-creator", "v1.0.0@beta")
+	got := InstalledDirPath(root, "anthropic/skill-creator", "v1.0.0@beta", 0)
 	want := filepath.Join(InstalledRoot(root), "anthropic_skill-creator@v1.0.0_beta")
 	if got != want {
 		t.Fatalf("InstalledDirPath() = %q, want %q", got, want)
@@ -173,11 +173,11 @@ func TestFindInstalledDirMatchesSanitizedRefPrefix(t *testing.T) {
 		t.Fatalf("ensure layout failed: %v", err)
 	}
 
-	match := filepath.Join(InstalledRoot(root), InstalledDirName("anthropic/skill-creator", "1.0.0"))
+	match := filepath.Join(InstalledRoot(root), InstalledDirName("anthropic/skill-creator", "1.0.0", 0))
 	if err := os.MkdirAll(match, 0o755); err != nil {
 		t.Fatalf("mkdir installed dir failed: %v", err)
 	}
-	other := filepath.Join(InstalledRoot(root), InstalledDirName("anthropic/other-skill", "1.0.0"))
+	other := filepath.Join(InstalledRoot(root), InstalledDirName("anthropic/other-skill", "1.0.0", 0))
 	if err := os.MkdirAll(other, 0o755); err != nil {
 		t.Fatalf("mkdir other dir failed: %v", err)
 	}
@@ -187,3 +187,60 @@ func TestFindInstalledDirMatchesSanitizedRefPrefix(t *testing.T) {
 		t.Fatalf("FindInstalledDir() = %q, want %q", got, match)
 	}
 }
+
+func TestInstalledDirNameHashesLongNamesAboveThreshold(t *testing.T) {
+	ref := "monorepo/teams/platform/tooling/skills/deep-skill"
+	name := InstalledDirName(ref, "0.0.0+git.0123456789abcdef", 32)
+	if !strings.HasPrefix(name, hashedDirPrefix) || len(name) > 32 {
+		t.Fatalf("expected short hashed name, got %q", name)
+	}
+	if !IsInstalledDirFor(name, ref) {
+		t.Fatalf("expected hashed name %q to match ref %q", name, ref)
+	}
+	if IsInstalledDirFor(name, "monorepo/other") {
+		t.Fatalf("hashed name %q should not match unrelated ref", name)
+	}
+	if short := InstalledDirName("a/b", "1.0.0", 32); short != "a_b@1.0.0" {
+		t.Fatalf("expected natural name below threshold, got %q", short)
+	}
+	names := InstalledDirNames(ref, "0.0.0+git.0123456789abcdef")
+	if len(names) != 2 || names[1] != name {
+		t.Fatalf("expected both naming schemes, got %v", names)
+	}
+}
+
+func TestFindInstalledDirMatchesHashedName(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "state")
+	ref := "monorepo/teams/platform/tooling/skills/deep-skill"
+	dir := InstalledDirPath(root, ref, "1.0.0", 32)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatalf("mkdir failed: %v", err)
+	}
+	if got := FindInstalledDir(root, ref); got != dir {
+		t.Fatalf("expected %q, got %q", dir, got)
+	}
+}
+
+func TestSetInjectionKeepsScopesSeparate(t *testing.T) {
+	st := State{Version: StateVersion}
+	SetInjection(&st, InjectionState{Agent: "claude", Scope: "global", Skills: []string{"hub/a"}})
+	SetInjection(&st, InjectionState{Agent: "claude", Scope: "project", Skills: []string{"hub/b"}})
+	if len(st.Injections) != 2 {
+		t.Fatalf("expected separate global and project records, got %+v", st.Injections)
+	}
+	SetInjection(&st, InjectionState{Agent: "claude", Scope: "project", Skills: []string{"hub/c"}})
+	if len(st.Injections) != 2 || st.Injections[0].Skills[0] != "hub/a" || st.Injections[1].Skills[0] != "hub/c" {
+		t.Fatalf("expected project record replaced only, got %+v", st.Injections)
+	}
+}
+
+func TestSetInjectionAdoptsLegacyRecord(t *testing.T) {
+	st := State{Version: StateVersion, Injections: []InjectionState{{Agent: "claude", Skills: []string{"hub/a"}}}}
+	if got := st.Injections[0].EffectiveScope("global"); got != "global" {
+		t.Fatalf("expected legacy record to default to global, got %q", got)
+	}
+	SetInjection(&st, InjectionState{Agent: "claude", Scope: "global", Skills: []string{"hub/b"}})
+	if len(st.Injections) != 1 || st.Injections[0].Scope != "global" {
+		t.Fatalf("expected legacy record to be migrated in place, got %+v", st.Injections)
+	}
+}
@@ -11,21 +11,37 @@ type State struct {
 }
 
 type InstalledSkill struct {
-	SkillRef         string    `toml:"skill_ref" json:"skillRef"`
-	Source           string    `toml:"source" json:"source"`
-	Skill            string    `toml:"skill" json:"skill"`
-	ResolvedVersion  string    `toml:"resolved_version" json:"resolvedVersion"`
-	Checksum         string    `toml:"checksum" json:"checksum"`
-	SourceRef        string    `toml:"source_ref" json:"sourceRef"`
-	InstalledAt      time.Time `toml:"installed_at" json:"installedAt"`
-	TrustTier        string    `toml:"trust_tier" json:"trustTier"`
-	IsSuspicious     bool      `toml:"is_suspicious,omitempty" json:"isSuspicious,omitempty"`
-	IsMalwareBlocked bool      `toml:"is_malware_blocked,omitempty" json:"isMalwareBlocked,omitempty"`
-	Deps             []string  `toml:"deps,omitempty" json:"deps,omitempty"`
+	SkillRef        string    `toml:"skill_ref" json:"skillRef"`
+	Source          string    `toml:"source" json:"source"`
+	Skill           string    `toml:"skill" json:"skill"`
+	ResolvedVersion string    `toml:"resolved_version" json:"resolvedVersion"`
+	Checksum        string    `toml:"checksum" json:"checksum"`
+	SourceRef       string    `toml:"source_ref" json:"sourceRef"`
+	InstalledAt     time.Time `toml:"installed_at" json:"installedAt"`
+	// ResolvedCommit is the full source commit the skill was read from, when
+	// the source is a git repository.
+	ResolvedCommit string `toml:"resolved_commit,omitempty" json:"resolvedCommit,omitempty"`
+	// InstalledBy is the skillpm version that wrote the record.
+	InstalledBy      string   `toml:"installed_by,omitempty" json:"installedBy,omitempty"`
+	TrustTier        string   `toml:"trust_tier" json:"trustTier"`
+	IsSuspicious     bool     `toml:"is_suspicious,omitempty" json:"isSuspicious,omitempty"`
+	IsMalwareBlocked bool     `toml:"is_malware_blocked,omitempty" json:"isMalwareBlocked,omitempty"`
+	Deps             []string `toml:"deps,omitempty" json:"deps,omitempty"`
+	// Deprecated and SupersededBy mirror the skill's SKILL.md frontmatter
+	// at install time.
+	Deprecated   bool   `toml:"deprecated,omitempty" json:"deprecated,omitempty"`
+	SupersededBy string `toml:"superseded_by,omitempty" json:"supersededBy,omitempty"`
+	// Platform is set when files tagged for other platforms were left out
+	// at install time, so the installed content is a subset of what the
+	// checksum covers.
+	Platform string `toml:"platform,omitempty" json:"platform,omitempty"`
 }
 
 type InjectionState struct {
-	Agent     string    `toml:"agent"`
+	Agent string `toml:"agent"`
+	// Scope is the scope ("global" or "project") the skills were injected
+	// in. Entries written before scope tracking leave it empty.
+	Scope     string    `toml:"scope,omitempty"`
 	Skills    []string  `toml:"skills"`
 	UpdatedAt time.Time `toml:"updated_at"`
 }
@@ -42,4 +58,6 @@ type LockSkill struct {
 	SourceRef       string            `toml:"sourceRef"`
 	Metadata        map[string]string `toml:"metadata,omitempty"`
 	Deps            []string          `toml:"deps,omitempty" json:"deps,omitempty"`
+	// Pinned skills are left out of upgrades that do not name them.
+	Pinned bool `toml:"pinned,omitempty" json:"pinned,omitempty"`
 }
@@ -0,0 +1,682 @@
+package sync
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SummarySchemaVersion is the schemaVersion of a Summary.
+const SummarySchemaVersion = "v1"
+
+// Summary is the structured form of a Report printed by sync --json, kept in
+// the sync history and posted to the notify webhook.
+type Summary struct {
+	SchemaVersion       string            `json:"schemaVersion"`
+	UpdatedSources      []string          `json:"updatedSources"`
+	UpgradedSkills      []string          `json:"upgradedSkills"`
+	RemovedSkills       []string          `json:"removedSkills,omitempty"`
+	Reinjected          []string          `json:"reinjectedAgents"`
+	SkippedReinjects    []string          `json:"skippedReinjects"`
+	FailedReinjects     []string          `json:"failedReinjects"`
+	SkippedDetails      []ReinjectIssue   `json:"skippedReinjectDetails"`
+	FailedDetails       []ReinjectIssue   `json:"failedReinjectDetails"`
+	DryRun              bool              `json:"dryRun"`
+	StrictMode          bool              `json:"strictMode"`
+	StrictStatus        string            `json:"strictStatus"`
+	StrictFailureReason string            `json:"strictFailureReason"`
+	Mode                string            `json:"mode"`
+	Outcome             string            `json:"outcome"`
+	ProgressStatus      string            `json:"progressStatus"`
+	ProgressClass       string            `json:"progressClass"`
+	ProgressHotspot     string            `json:"progressHotspot"`
+	ProgressFocus       string            `json:"progressFocus"`
+	ProgressTarget      string            `json:"progressTarget"`
+	ProgressSignal      string            `json:"progressSignal"`
+	ActionBreakdown     string            `json:"actionBreakdown"`
+	NextAction          string            `json:"nextAction"`
+	PrimaryAction       string            `json:"primaryAction"`
+	ExecutionPriority   string            `json:"executionPriority"`
+	FollowUpGate        string            `json:"followUpGate"`
+	NextStepHint        string            `json:"nextStepHint"`
+	RecommendedCommand  string            `json:"recommendedCommand"`
+	RecommendedCommands []string          `json:"recommendedCommands"`
+	RecommendedAgent    string            `json:"recommendedAgent"`
+	SummaryLine         string            `json:"summaryLine"`
+	NoopReason          string            `json:"noopReason"`
+	RiskStatus          string            `json:"riskStatus"`
+	RiskLevel           string            `json:"riskLevel"`
+	RiskClass           string            `json:"riskClass"`
+	RiskBreakdown       string            `json:"riskBreakdown"`
+	RiskInjectCommands  []string          `json:"riskInjectCommands"`
+	RiskHotspot         string            `json:"riskHotspot"`
+	RiskAgents          []string          `json:"riskAgents"`
+	RiskAgentsTotal     int               `json:"riskAgentsTotal"`
+	HasProgress         bool              `json:"hasProgress"`
+	HasRisk             bool              `json:"hasRisk"`
+	CanProceed          bool              `json:"canProceed"`
+	NextBatchReady      bool              `json:"nextBatchReady"`
+	NextBatchBlocker    string            `json:"nextBatchBlocker"`
+	ActionCounts        SummaryCounts     `json:"actionCounts"`
+	RiskCounts          SummaryRiskCounts `json:"riskCounts"`
+	TopSamples          SummaryTopSamples `json:"topSamples"`
+}
+
+type SummaryCounts struct {
+	Sources       int `json:"sources"`
+	Upgrades      int `json:"upgrades"`
+	Reinjected    int `json:"reinjected"`
+	Skipped       int `json:"skipped"`
+	Failed        int `json:"failed"`
+	ProgressTotal int `json:"progressTotal"`
+	RiskTotal     int `json:"riskTotal"`
+	Total         int `json:"total"`
+}
+
+type SummaryRiskCounts struct {
+	Skipped int `json:"skipped"`
+	Failed  int `json:"failed"`
+	Total   int `json:"total"`
+}
+
+type SummaryTopSamples struct {
+	Sources    SummarySample `json:"sources"`
+	Upgrades   SummarySample `json:"upgrades"`
+	Reinjected SummarySample `json:"reinjected"`
+	Skipped    SummarySample `json:"skipped"`
+	Failed     SummarySample `json:"failed"`
+}
+
+type SummarySample struct {
+	Items     []string `json:"items"`
+	Remaining int      `json:"remaining"`
+}
+
+// Summary builds the structured summary of r; strict records whether the
+// run was in strict mode.
+func (r Report) Summary(strict bool) Summary {
+	return buildSummary(r, strict)
+}
+
+func buildSummary(report Report, strictMode bool) Summary {
+	progressTotal := totalSyncProgressActions(report)
+	riskTotal := totalSyncIssues(report)
+	riskAgents := syncRiskAgents(report)
+	return Summary{
+		SchemaVersion:       SummarySchemaVersion,
+		UpdatedSources:      sortedStringSlice(report.UpdatedSources),
+		UpgradedSkills:      sortedStringSlice(report.UpgradedSkills),
+		RemovedSkills:       sortedStringSlice(report.RemovedSkills),
+		Reinjected:          sortedStringSlice(report.Reinjected),
+		SkippedReinjects:    sortedStringSlice(report.SkippedReinjects),
+		FailedReinjects:     sortedStringSlice(report.FailedReinjects),
+		SkippedDetails:      reinjectIssueSlice(report.SkippedReinjectDetails),
+		FailedDetails:       reinjectIssueSlice(report.FailedReinjectDetails),
+		DryRun:              report.DryRun,
+		StrictMode:          strictMode,
+		StrictStatus:        syncStrictStatus(strictMode),
+		StrictFailureReason: syncStrictFailureReason(report, strictMode),
+		Mode:                syncMode(report),
+		Outcome:             syncOutcome(report),
+		ProgressStatus:      syncProgressStatus(report),
+		ProgressClass:       syncProgressClass(report),
+		ProgressHotspot:     syncProgressHotspot(report),
+		ProgressFocus:       syncProgressFocus(report),
+		ProgressTarget:      syncProgressTarget(report),
+		ProgressSignal:      syncProgressSignal(report),
+		ActionBreakdown:     syncActionBreakdown(report),
+		NextAction:          syncNextAction(report),
+		PrimaryAction:       syncPrimaryAction(report),
+		ExecutionPriority:   syncExecutionPriority(report),
+		FollowUpGate:        syncFollowUpGate(report),
+		NextStepHint:        syncNextStepHint(report),
+		RecommendedCommand:  syncRecommendedCommand(report),
+		RecommendedCommands: syncRecommendedCommands(report),
+		RecommendedAgent:    syncRecommendedAgent(report),
+		SummaryLine:         syncSummaryLine(report),
+		NoopReason:          syncNoopReason(report),
+		RiskStatus:          syncRiskStatus(report),
+		RiskLevel:           syncRiskLevel(report),
+		RiskClass:           syncRiskClass(report),
+		RiskBreakdown:       syncRiskBreakdown(report),
+		RiskInjectCommands:  syncRiskInjectCommands(report),
+		RiskHotspot:         syncRiskHotspot(report),
+		RiskAgents:          riskAgents,
+		RiskAgentsTotal:     len(riskAgents),
+		HasProgress:         progressTotal > 0,
+		HasRisk:             riskTotal > 0,
+		CanProceed:          riskTotal == 0,
+		NextBatchReady:      syncNextBatchReady(report),
+		NextBatchBlocker:    syncNextBatchBlocker(report),
+		ActionCounts: SummaryCounts{
+			Sources:       len(report.UpdatedSources),
+			Upgrades:      len(report.UpgradedSkills),
+			Reinjected:    len(report.Reinjected),
+			Skipped:       len(report.SkippedReinjects),
+			Failed:        len(report.FailedReinjects),
+			ProgressTotal: progressTotal,
+			RiskTotal:     riskTotal,
+			Total:         progressTotal + riskTotal,
+		},
+		RiskCounts: SummaryRiskCounts{
+			Skipped: len(report.SkippedReinjects),
+			Failed:  len(report.FailedReinjects),
+			Total:   riskTotal,
+		},
+		TopSamples: SummaryTopSamples{
+			Sources:    topSample(report.UpdatedSources, 3),
+			Upgrades:   topSample(report.UpgradedSkills, 3),
+			Reinjected: topSample(report.Reinjected, 3),
+			Skipped:    topSample(report.SkippedReinjects, 3),
+			Failed:     topSample(report.FailedReinjects, 3),
+		},
+	}
+}
+
+func topSample(items []string, limit int) SummarySample {
+	if limit <= 0 {
+		limit = 1
+	}
+	sorted := sortedStringSlice(items)
+	if len(sorted) <= limit {
+		return SummarySample{Items: sorted}
+	}
+	return SummarySample{
+		Items:     sorted[:limit],
+		Remaining: len(sorted) - limit,
+	}
+}
+
+func stableStringSlice(items []string) []string {
+	out := make([]string, len(items))
+	copy(out, items)
+	return out
+}
+
+func sortedStringSlice(items []string) []string {
+	sorted := stableStringSlice(items)
+	sort.Strings(sorted)
+	return sorted
+}
+
+func syncMode(report Report) string {
+	if report.DryRun {
+		return "dry-run"
+	}
+	return "apply"
+}
+
+func syncStrictStatus(strict bool) string {
+	if strict {
+		return "enabled"
+	}
+	return "disabled"
+}
+
+func syncStrictFailureReason(report Report, strictMode bool) string {
+	if !strictMode {
+		return "strict-disabled"
+	}
+	failed := len(report.FailedReinjects)
+	skipped := len(report.SkippedReinjects)
+	if failed > 0 && skipped > 0 {
+		return "risk-present-mixed"
+	}
+	if failed > 0 {
+		return "risk-present-failed"
+	}
+	if skipped > 0 {
+		return "risk-present-skipped"
+	}
+	return "none"
+}
+
+func syncNextBatchReady(report Report) bool {
+	return !report.DryRun && totalSyncIssues(report) == 0
+}
+
+func syncNextBatchBlocker(report Report) string {
+	if syncNextBatchReady(report) {
+		return "none"
+	}
+	if totalSyncIssues(report) > 0 {
+		return "risk-present"
+	}
+	if report.DryRun {
+		return "dry-run-mode"
+	}
+	return "unknown"
+}
+
+func totalSyncActions(report Report) int {
+	return totalSyncProgressActions(report) + totalSyncIssues(report)
+}
+
+func totalSyncProgressActions(report Report) int {
+	return report.Changes()
+}
+
+func totalSyncIssues(report Report) int {
+	return len(report.SkippedReinjects) + len(report.FailedReinjects)
+}
+
+func syncProgressStatus(report Report) string {
+	if totalSyncProgressActions(report) > 0 {
+		return "progress-made"
+	}
+	return "no-progress"
+}
+
+func syncProgressClass(report Report) string {
+	hasSourceUpdates := len(report.UpdatedSources) > 0
+	hasUpgrades := len(report.UpgradedSkills) > 0
+	hasReinjections := len(report.Reinjected) > 0
+
+	switch {
+	case hasReinjections:
+		return "reinjection"
+	case hasUpgrades:
+		return "upgrade"
+	case hasSourceUpdates:
+		return "source-refresh"
+	default:
+		return "none"
+	}
+}
+
+func syncActionBreakdown(report Report) string {
+	return fmt.Sprintf("sources=%d upgrades=%d reinjected=%d skipped=%d failed=%d", len(report.UpdatedSources), len(report.UpgradedSkills), len(report.Reinjected), len(report.SkippedReinjects), len(report.FailedReinjects))
+}
+
+func syncProgressHotspot(report Report) string {
+	if len(report.UpgradedSkills) > 0 {
+		return sortedStringSlice(report.UpgradedSkills)[0]
+	}
+	if len(report.Reinjected) > 0 {
+		return sortedStringSlice(report.Reinjected)[0]
+	}
+	if len(report.UpdatedSources) > 0 {
+		return sortedStringSlice(report.UpdatedSources)[0]
+	}
+	return "none"
+}
+
+func syncProgressFocus(report Report) string {
+	if len(report.Reinjected) > 0 {
+		return sortedStringSlice(report.Reinjected)[0]
+	}
+	if len(report.UpgradedSkills) > 0 {
+		return sortedStringSlice(report.UpgradedSkills)[0]
+	}
+	if len(report.UpdatedSources) > 0 {
+		return sortedStringSlice(report.UpdatedSources)[0]
+	}
+	return "none"
+}
+
+func syncProgressTarget(report Report) string {
+	if totalSyncProgressActions(report) == 0 {
+		return "none"
+	}
+	if syncProgressClass(report) == "reinjection" {
+		return syncProgressFocus(report)
+	}
+	return syncProgressHotspot(report)
+}
+
+func syncProgressSignal(report Report) string {
+	if totalSyncProgressActions(report) == 0 {
+		return "none"
+	}
+	return fmt.Sprintf("%s:%s", syncProgressClass(report), syncProgressTarget(report))
+}
+
+func syncOutcome(report Report) string {
+	return report.Outcome()
+}
+
+func syncNextAction(report Report) string {
+	switch syncOutcome(report) {
+	case "noop":
+		if report.DryRun {
+			return "plan-next-iteration"
+		}
+		return "monitor"
+	case "blocked":
+		if len(report.FailedReinjects) > 0 {
+			if report.DryRun {
+				return "resolve-failures-then-apply"
+			}
+			return "resolve-reinjection-failures"
+		}
+		if report.DryRun {
+			return "resolve-skips-then-apply"
+		}
+		return "resolve-reinjection-skips"
+	case "changed-with-risk":
+		if len(report.FailedReinjects) > 0 {
+			if report.DryRun {
+				return "resolve-failures-then-apply-plan"
+			}
+			return "review-failed-risk-items"
+		}
+		if report.DryRun {
+			return "resolve-skips-then-apply-plan"
+		}
+		return "review-skipped-risk-items"
+	default:
+		if report.DryRun {
+			return "apply-plan"
+		}
+		return "verify-and-continue"
+	}
+}
+
+func syncPrimaryAction(report Report) string {
+	switch syncOutcome(report) {
+	case "noop":
+		if report.DryRun {
+			return "No changes detected; queue the next iteration to keep momentum."
+		}
+		return "No changes detected; keep monitoring and retry on the next cycle."
+	case "blocked":
+		if report.DryRun {
+			return "Sync plan is blocked by reinjection risk; resolve skipped/failed agents before applying changes."
+		}
+		return "Reinjection is blocked; resolve skipped/failed agents first before adding new work."
+	case "changed-with-risk":
+		if len(report.FailedReinjects) > 0 {
+			if report.DryRun {
+				return "Sync plan includes progress with failed reinjections; clear failures before applying this iteration."
+			}
+			return "Progress landed with failed reinjections; fix failures before expanding scope."
+		}
+		if report.DryRun {
+			return "Sync plan includes progress with skipped reinjections; clear skips before applying this iteration."
+		}
+		return "Progress landed with skipped reinjections; clear skips before expanding scope."
+	default:
+		if report.DryRun {
+			return "Apply this sync plan to convert planned progress into committed state."
+		}
+		return "Progress is applied and clear; move directly to the next feature increment."
+	}
+}
+
+func syncExecutionPriority(report Report) string {
+	if totalSyncIssues(report) > 0 {
+		if len(report.FailedReinjects) > 0 {
+			return "stabilize-failures"
+		}
+		return "stabilize-risks"
+	}
+	if totalSyncProgressActions(report) > 0 {
+		if report.DryRun {
+			return "apply-feature-iteration"
+		}
+		return "feature-iteration"
+	}
+	if report.DryRun {
+		return "plan-feature-iteration"
+	}
+	return "monitor-next-cycle"
+}
+
+func syncFollowUpGate(report Report) string {
+	if totalSyncIssues(report) > 0 {
+		return "blocked-by-risk"
+	}
+	if totalSyncProgressActions(report) > 0 {
+		if report.DryRun {
+			return "ready-to-apply"
+		}
+		return "ready-for-next-iteration"
+	}
+	if report.DryRun {
+		return "plan-next-iteration"
+	}
+	return "monitor-next-cycle"
+}
+
+func syncNextStepHint(report Report) string {
+	if totalSyncIssues(report) > 0 {
+		if len(report.FailedReinjects) > 0 {
+			return "reinject-failed-agents"
+		}
+		return "reinject-skipped-agents"
+	}
+	if report.DryRun {
+		if totalSyncProgressActions(report) > 0 {
+			return "apply-sync-plan"
+		}
+		return "queue-feature-iteration"
+	}
+	if totalSyncProgressActions(report) > 0 {
+		return "start-next-feature-iteration"
+	}
+	return "wait-next-sync-cycle"
+}
+
+func syncRecommendedCommand(report Report) string {
+	switch syncOutcome(report) {
+	case "noop":
+		if report.DryRun {
+			return "skillpm sync"
+		}
+		return "skillpm sync --dry-run"
+	case "blocked", "changed-with-risk":
+		if totalSyncIssues(report) == 0 {
+			if report.DryRun {
+				return "skillpm sync"
+			}
+			return "skillpm sync --dry-run"
+		}
+		agent := syncRecommendedAgent(report)
+		if agent != "" && agent != "none" {
+			return fmt.Sprintf("skillpm inject --agent %s <skill-ref>", agent)
+		}
+		return "skillpm inject --agent <agent> <skill-ref>"
+	default:
+		if report.DryRun {
+			return "skillpm sync"
+		}
+		return "skillpm source list"
+	}
+}
+
+func syncRecommendedCommands(report Report) []string {
+	commands := []string{syncRecommendedCommand(report)}
+	hasIssues := totalSyncIssues(report) > 0
+	hasProgress := totalSyncProgressActions(report) > 0
+	if hasIssues {
+		commands = append(commands, syncRiskInjectCommands(report)...)
+		commands = append(commands, "skillpm source list")
+		if hasProgress && !report.DryRun {
+			commands = append(commands, "go test ./...")
+		}
+		commands = append(commands, "skillpm sync --dry-run")
+		if report.DryRun {
+			commands = append(commands, "skillpm sync")
+			if hasProgress {
+				commands = append(commands, "go test ./...")
+			}
+		}
+		return uniqueNonEmpty(commands)
+	}
+	if hasProgress {
+		commands = append(commands, "skillpm source list", "go test ./...", "skillpm sync --dry-run")
+		return uniqueNonEmpty(commands)
+	}
+	commands = append(commands, "skillpm source list")
+	return uniqueNonEmpty(commands)
+}
+
+func syncRiskInjectCommands(report Report) []string {
+	agents := syncRiskAgents(report)
+	commands := make([]string, 0, len(agents))
+	for _, agent := range agents {
+		commands = append(commands, fmt.Sprintf("skillpm inject --agent %s <skill-ref>", agent))
+	}
+	return commands
+}
+
+func uniqueNonEmpty(items []string) []string {
+	seen := make(map[string]struct{}, len(items))
+	out := make([]string, 0, len(items))
+	for _, item := range items {
+		trimmed := strings.TrimSpace(item)
+		if trimmed == "" {
+			continue
+		}
+		if _, ok := seen[trimmed]; ok {
+			continue
+		}
+		seen[trimmed] = struct{}{}
+		out = append(out, trimmed)
+	}
+	return out
+}
+
+// reinjectIssueSlice keeps JSON output stable by rendering nil as [].
+func reinjectIssueSlice(issues []ReinjectIssue) []ReinjectIssue {
+	if issues == nil {
+		return []ReinjectIssue{}
+	}
+	return issues
+}
+
+func syncRecommendedAgent(report Report) string {
+	for _, agent := range reinjectIssueAgents(report.FailedReinjectDetails, report.FailedReinjects) {
+		if agent != "" {
+			return agent
+		}
+	}
+	for _, agent := range reinjectIssueAgents(report.SkippedReinjectDetails, report.SkippedReinjects) {
+		if agent != "" {
+			return agent
+		}
+	}
+	return "none"
+}
+
+// reinjectIssueAgents returns the sorted agent names of skipped or failed
+// reinjections, read from the structured details when the report has them
+// and parsed from the legacy strings otherwise.
+func reinjectIssueAgents(details []ReinjectIssue, legacy []string) []string {
+	agents := make([]string, 0, len(legacy))
+	if len(details) > 0 {
+		for _, d := range details {
+			agents = append(agents, d.Agent)
+		}
+	} else {
+		for _, item := range legacy {
+			agents = append(agents, riskAgentName(item))
+		}
+	}
+	sort.Strings(agents)
+	return agents
+}
+
+func riskAgentName(item string) string {
+	agent := strings.TrimSpace(item)
+	if agent == "" {
+		return ""
+	}
+	if idx := strings.Index(agent, ":"); idx >= 0 {
+		agent = strings.TrimSpace(agent[:idx])
+	}
+	if idx := strings.Index(agent, " "); idx >= 0 {
+		agent = strings.TrimSpace(agent[:idx])
+	}
+	if idx := strings.Index(agent, "("); idx >= 0 {
+		agent = strings.TrimSpace(agent[:idx])
+	}
+	return agent
+}
+
+func syncSummaryLine(report Report) string {
+	return fmt.Sprintf("outcome=%s progress=%d risk=%d mode=%s", syncOutcome(report), totalSyncProgressActions(report), totalSyncIssues(report), syncMode(report))
+}
+
+func syncNoopReason(report Report) string {
+	if syncOutcome(report) != "noop" {
+		return "not-applicable"
+	}
+	if report.DryRun {
+		return "dry-run detected no source/upgrade/reinjection deltas"
+	}
+	return "no source updates, skill upgrades, or reinjection changes detected"
+}
+
+func syncRiskBreakdown(report Report) string {
+	return fmt.Sprintf("skipped=%d failed=%d", len(report.SkippedReinjects), len(report.FailedReinjects))
+}
+
+func syncRiskStatus(report Report) string {
+	if totalSyncIssues(report) > 0 {
+		return "attention-needed"
+	}
+	return "clear"
+}
+
+func syncRiskLevel(report Report) string {
+	if len(report.FailedReinjects) > 0 {
+		return "high"
+	}
+	if len(report.SkippedReinjects) > 0 {
+		return "medium"
+	}
+	return "none"
+}
+
+func syncRiskClass(report Report) string {
+	failed := len(report.FailedReinjects) > 0
+	skipped := len(report.SkippedReinjects) > 0
+	switch {
+	case failed && skipped:
+		return "mixed"
+	case failed:
+		return "failed-only"
+	case skipped:
+		return "skipped-only"
+	default:
+		return "none"
+	}
+}
+
+func syncRiskHotspot(report Report) string {
+	if len(report.FailedReinjects) > 0 {
+		return sortedStringSlice(report.FailedReinjects)[0]
+	}
+	if len(report.SkippedReinjects) > 0 {
+		return sortedStringSlice(report.SkippedReinjects)[0]
+	}
+	return "none"
+}
+
+func syncRiskAgents(report Report) []string {
+	agents := make([]string, 0, len(report.FailedReinjects)+len(report.SkippedReinjects))
+	seen := map[string]struct{}{}
+	for _, agent := range reinjectIssueAgents(report.FailedReinjectDetails, report.FailedReinjects) {
+		if agent == "" {
+			continue
+		}
+		if _, ok := seen[agent]; ok {
+			continue
+		}
+		seen[agent] = struct{}{}
+		agents = append(agents, agent)
+	}
+	for _, agent := range reinjectIssueAgents(report.SkippedReinjectDetails, report.SkippedReinjects) {
+		if agent == "" {
+			continue
+		}
+		if _, ok := seen[agent]; ok {
+			continue
+		}
+		seen[agent] = struct{}{}
+		agents = append(agents, agent)
+	}
+	sort.Strings(agents)
+	return agents
+}
@@ -0,0 +1,572 @@
+package sync
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSummarySortsOutputArrays(t *testing.T) {
+	report := Report{
+		UpdatedSources:   []string{"zeta", "alpha"},
+		UpgradedSkills:   []string{"b/skill", "a/skill"},
+		Reinjected:       []string{"ghost-b", "ghost-a"},
+		SkippedReinjects: []string{"skip-b", "skip-a"},
+		FailedReinjects:  []string{"fail-b", "fail-a"},
+	}
+	summary := buildSummary(report, false)
+
+	assertSorted := func(name string, got []string, want []string) {
+		t.Helper()
+		if strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Fatalf("expected %s sorted as %v, got %v", name, want, got)
+		}
+	}
+
+	assertSorted("updatedSources", summary.UpdatedSources, []string{"alpha", "zeta"})
+	assertSorted("upgradedSkills", summary.UpgradedSkills, []string{"a/skill", "b/skill"})
+	assertSorted("reinjectedAgents", summary.Reinjected, []string{"ghost-a", "ghost-b"})
+	assertSorted("skippedReinjects", summary.SkippedReinjects, []string{"skip-a", "skip-b"})
+	assertSorted("failedReinjects", summary.FailedReinjects, []string{"fail-a", "fail-b"})
+	assertSorted("riskAgents", summary.RiskAgents, []string{"fail-a", "fail-b", "skip-a", "skip-b"})
+	if summary.RiskAgentsTotal != 4 {
+		t.Fatalf("expected riskAgentsTotal=4, got %d", summary.RiskAgentsTotal)
+	}
+	if summary.RecommendedAgent != "fail-a" {
+		t.Fatalf("expected recommended agent fail-a, got %q", summary.RecommendedAgent)
+	}
+}
+
+func TestSummarySkippedRiskClassification(t *testing.T) {
+	report := Report{
+		UpdatedSources:   []string{"local"},
+		SkippedReinjects: []string{"ghost"},
+	}
+	summary := buildSummary(report, false)
+
+	if summary.Outcome != "changed-with-risk" {
+		t.Fatalf("expected changed-with-risk outcome, got %q", summary.Outcome)
+	}
+	if summary.RiskClass != "skipped-only" {
+		t.Fatalf("expected skipped-only risk class, got %q", summary.RiskClass)
+	}
+	if summary.PrimaryAction != "Progress landed with skipped reinjections; clear skips before expanding scope." {
+		t.Fatalf("unexpected primary action for skipped-only risk: %q", summary.PrimaryAction)
+	}
+	if summary.NextAction != "review-skipped-risk-items" {
+		t.Fatalf("expected review-skipped-risk-items next action, got %q", summary.NextAction)
+	}
+	if summary.NextStepHint != "reinject-skipped-agents" {
+		t.Fatalf("expected reinject-skipped-agents next step hint, got %q", summary.NextStepHint)
+	}
+	if summary.RecommendedCommand != "skillpm inject --agent ghost <skill-ref>" {
+		t.Fatalf("unexpected recommended command for skipped-only risk: %q", summary.RecommendedCommand)
+	}
+}
+
+func TestSyncNextStepHint(t *testing.T) {
+	tests := []struct {
+		name   string
+		report Report
+		want   string
+	}{
+		{name: "failed risk", report: Report{FailedReinjects: []string{"ghost (boom)"}}, want: "reinject-failed-agents"},
+		{name: "skipped risk", report: Report{SkippedReinjects: []string{"ghost"}}, want: "reinject-skipped-agents"},
+		{name: "dry-run progress", report: Report{DryRun: true, UpdatedSources: []string{"local"}}, want: "apply-sync-plan"},
+		{name: "dry-run noop", report: Report{DryRun: true}, want: "queue-feature-iteration"},
+		{name: "apply progress", report: Report{UpgradedSkills: []string{"local/forms"}}, want: "start-next-feature-iteration"},
+		{name: "apply noop", report: Report{}, want: "wait-next-sync-cycle"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := syncNextStepHint(tc.report); got != tc.want {
+				t.Fatalf("expected next step hint %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestSyncProgressClassPriorityAndHotspot(t *testing.T) {
+	tests := []struct {
+		name    string
+		report  Report
+		class   string
+		hotspot string
+		focus   string
+		target  string
+		signal  string
+	}{
+		{
+			name:    "source refresh only",
+			report:  Report{UpdatedSources: []string{"beta", "alpha"}},
+			class:   "source-refresh",
+			hotspot: "alpha",
+			focus:   "alpha",
+			target:  "alpha",
+			signal:  "source-refresh:alpha",
+		},
+		{
+			name:    "upgrade takes priority over source",
+			report:  Report{UpdatedSources: []string{"alpha"}, UpgradedSkills: []string{"zeta/skill", "beta/skill"}},
+			class:   "upgrade",
+			hotspot: "beta/skill",
+			focus:   "beta/skill",
+			target:  "beta/skill",
+			signal:  "upgrade:beta/skill",
+		},
+		{
+			name:    "reinjection class with upgrade hotspot precedence",
+			report:  Report{UpdatedSources: []string{"alpha"}, UpgradedSkills: []string{"beta/skill"}, Reinjected: []string{"agent-z", "agent-a"}},
+			class:   "reinjection",
+			hotspot: "beta/skill",
+			focus:   "agent-a",
+			target:  "agent-a",
+			signal:  "reinjection:agent-a",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := syncProgressClass(tc.report); got != tc.class {
+				t.Fatalf("expected progress class %q, got %q", tc.class, got)
+			}
+			if got := syncProgressHotspot(tc.report); got != tc.hotspot {
+				t.Fatalf("expected progress hotspot %q, got %q", tc.hotspot, got)
+			}
+			if got := syncProgressFocus(tc.report); got != tc.focus {
+				t.Fatalf("expected progress focus %q, got %q", tc.focus, got)
+			}
+			if got := syncProgressTarget(tc.report); got != tc.target {
+				t.Fatalf("expected progress target %q, got %q", tc.target, got)
+			}
+			if got := syncProgressSignal(tc.report); got != tc.signal {
+				t.Fatalf("expected progress signal %q, got %q", tc.signal, got)
+			}
+		})
+	}
+}
+
+func TestSyncRiskAgentsPrefersStructuredDetails(t *testing.T) {
+	report := Report{
+		FailedReinjects:       []string{"odd:name (ADP_NOT_SUPPORTED: x)"},
+		FailedReinjectDetails: []ReinjectIssue{{Agent: "odd:name", Code: "ADP_NOT_SUPPORTED", Message: "ADP_NOT_SUPPORTED: x"}},
+		SkippedReinjects:      []string{"ghost"},
+	}
+	if got := syncRiskAgents(report); !reflect.DeepEqual(got, []string{"ghost", "odd:name"}) {
+		t.Fatalf("unexpected risk agents: %v", got)
+	}
+	if got := syncRecommendedAgent(report); got != "odd:name" {
+		t.Fatalf("expected structured agent name, got %q", got)
+	}
+}
+
+func TestSyncRiskInjectCommands(t *testing.T) {
+	report := Report{
+		FailedReinjects:  []string{"zeta (boom)", "alpha: timeout"},
+		SkippedReinjects: []string{"alpha", "ghost"},
+	}
+	got := syncRiskInjectCommands(report)
+	want := []string{
+		"skillpm inject --agent alpha <skill-ref>",
+		"skillpm inject --agent ghost <skill-ref>",
+		"skillpm inject --agent zeta <skill-ref>",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected risk inject commands: %v", got)
+	}
+}
+
+func TestSyncStrictFailureReason(t *testing.T) {
+	tests := []struct {
+		name   string
+		report Report
+		strict bool
+		want   string
+	}{
+		{name: "strict disabled", report: Report{}, strict: false, want: "strict-disabled"},
+		{name: "strict enabled no risk", report: Report{}, strict: true, want: "none"},
+		{name: "strict enabled skipped only", report: Report{SkippedReinjects: []string{"agent-a"}}, strict: true, want: "risk-present-skipped"},
+		{name: "strict enabled failed only", report: Report{FailedReinjects: []string{"agent-b"}}, strict: true, want: "risk-present-failed"},
+		{name: "strict enabled mixed risk", report: Report{SkippedReinjects: []string{"agent-a"}, FailedReinjects: []string{"agent-b"}}, strict: true, want: "risk-present-mixed"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := syncStrictFailureReason(tc.report, tc.strict); got != tc.want {
+				t.Fatalf("expected strict failure reason %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestSyncStrictStatus(t *testing.T) {
+	if got := syncStrictStatus(true); got != "enabled" {
+		t.Fatalf("expected enabled strict status, got %q", got)
+	}
+	if got := syncStrictStatus(false); got != "disabled" {
+		t.Fatalf("expected disabled strict status, got %q", got)
+	}
+}
+
+func TestTopSampleSortsAndFallsBackToLimitOne(t *testing.T) {
+	got := topSample([]string{"z", "a", "m"}, 0)
+	if !reflect.DeepEqual(got.Items, []string{"a"}) {
+		t.Fatalf("expected fallback single sorted item, got %+v", got.Items)
+	}
+	if got.Remaining != 2 {
+		t.Fatalf("expected remaining=2, got %d", got.Remaining)
+	}
+
+	full := topSample([]string{"b", "a"}, 3)
+	if !reflect.DeepEqual(full.Items, []string{"a", "b"}) || full.Remaining != 0 {
+		t.Fatalf("expected full sorted sample with remaining 0, got %+v", full)
+	}
+}
+
+func TestTotalSyncActions(t *testing.T) {
+	report := syncReportFixture()
+	if got := totalSyncActions(report); got != 7 {
+		t.Fatalf("expected total actions 7, got %d", got)
+	}
+	if got := totalSyncProgressActions(report); got != 4 {
+		t.Fatalf("expected total progress actions 4, got %d", got)
+	}
+	if got := totalSyncIssues(report); got != 3 {
+		t.Fatalf("expected total issues 3, got %d", got)
+	}
+	if got := syncActionBreakdown(report); got != "sources=2 upgrades=1 reinjected=1 skipped=1 failed=2" {
+		t.Fatalf("unexpected action breakdown: %q", got)
+	}
+	if got := syncOutcome(report); got != "changed-with-risk" {
+		t.Fatalf("unexpected action outcome: %q", got)
+	}
+	if got := syncProgressStatus(report); got != "progress-made" {
+		t.Fatalf("unexpected progress status: %q", got)
+	}
+	if got := syncProgressClass(report); got != "reinjection" {
+		t.Fatalf("unexpected progress class: %q", got)
+	}
+	if got := syncProgressHotspot(report); got != "c" {
+		t.Fatalf("unexpected progress hotspot: %q", got)
+	}
+	if got := syncProgressTarget(report); got != "d" {
+		t.Fatalf("unexpected progress target: %q", got)
+	}
+	if got := syncProgressSignal(report); got != "reinjection:d" {
+		t.Fatalf("unexpected progress signal: %q", got)
+	}
+	if got := syncPrimaryAction(report); got != "Progress landed with failed reinjections; fix failures before expanding scope." {
+		t.Fatalf("unexpected primary action: %q", got)
+	}
+	if got := syncNextAction(report); got != "review-failed-risk-items" {
+		t.Fatalf("unexpected next action: %q", got)
+	}
+	if got := syncExecutionPriority(report); got != "stabilize-failures" {
+		t.Fatalf("unexpected execution priority: %q", got)
+	}
+	if got := syncFollowUpGate(report); got != "blocked-by-risk" {
+		t.Fatalf("unexpected follow-up gate: %q", got)
+	}
+	if got := syncRecommendedCommand(report); got != "skillpm inject --agent f <skill-ref>" {
+		t.Fatalf("unexpected recommended command: %q", got)
+	}
+	if got := syncRecommendedAgent(report); got != "f" {
+		t.Fatalf("unexpected recommended agent: %q", got)
+	}
+	if got := syncSummaryLine(report); got != "outcome=changed-with-risk progress=4 risk=3 mode=apply" {
+		t.Fatalf("unexpected summary line: %q", got)
+	}
+	if got := syncNoopReason(report); got != "not-applicable" {
+		t.Fatalf("unexpected non-noop reason marker: %q", got)
+	}
+	if got := syncRiskBreakdown(report); got != "skipped=1 failed=2" {
+		t.Fatalf("unexpected risk breakdown: %q", got)
+	}
+	if got := syncRiskStatus(report); got != "attention-needed" {
+		t.Fatalf("unexpected risk status: %q", got)
+	}
+	if got := syncRiskLevel(report); got != "high" {
+		t.Fatalf("unexpected risk level: %q", got)
+	}
+	if got := syncRiskClass(report); got != "mixed" {
+		t.Fatalf("unexpected risk class: %q", got)
+	}
+	if got := syncRiskHotspot(report); got != "f" {
+		t.Fatalf("unexpected risk hotspot: %q", got)
+	}
+	if got := syncRiskAgents(report); !reflect.DeepEqual(got, []string{"e", "f", "g"}) {
+		t.Fatalf("unexpected risk agents: %v", got)
+	}
+
+	empty := syncReportFixtureEmpty()
+	if got := totalSyncActions(empty); got != 0 {
+		t.Fatalf("expected empty total actions 0, got %d", got)
+	}
+	if got := totalSyncProgressActions(empty); got != 0 {
+		t.Fatalf("expected empty progress actions 0, got %d", got)
+	}
+	if got := totalSyncIssues(empty); got != 0 {
+		t.Fatalf("expected empty total issues 0, got %d", got)
+	}
+	if got := syncActionBreakdown(empty); got != "sources=0 upgrades=0 reinjected=0 skipped=0 failed=0" {
+		t.Fatalf("unexpected empty action breakdown: %q", got)
+	}
+	if got := syncOutcome(empty); got != "noop" {
+		t.Fatalf("unexpected empty action outcome: %q", got)
+	}
+	if got := syncProgressStatus(empty); got != "no-progress" {
+		t.Fatalf("unexpected empty progress status: %q", got)
+	}
+	if got := syncProgressClass(empty); got != "none" {
+		t.Fatalf("unexpected empty progress class: %q", got)
+	}
+	if got := syncProgressHotspot(empty); got != "none" {
+		t.Fatalf("unexpected empty progress hotspot: %q", got)
+	}
+	if got := syncProgressTarget(empty); got != "none" {
+		t.Fatalf("unexpected empty progress target: %q", got)
+	}
+	if got := syncProgressSignal(empty); got != "none" {
+		t.Fatalf("unexpected empty progress signal: %q", got)
+	}
+	if got := syncPrimaryAction(empty); got != "No changes detected; keep monitoring and retry on the next cycle." {
+		t.Fatalf("unexpected empty primary action: %q", got)
+	}
+	if got := syncExecutionPriority(empty); got != "monitor-next-cycle" {
+		t.Fatalf("unexpected empty execution priority: %q", got)
+	}
+	if got := syncFollowUpGate(empty); got != "monitor-next-cycle" {
+		t.Fatalf("unexpected empty follow-up gate: %q", got)
+	}
+	if got := syncRecommendedCommand(empty); got != "skillpm sync --dry-run" {
+		t.Fatalf("unexpected empty recommended command: %q", got)
+	}
+	if got := syncRecommendedCommands(empty); !reflect.DeepEqual(got, []string{"skillpm sync --dry-run", "skillpm source list"}) {
+		t.Fatalf("unexpected empty recommended commands: %v", got)
+	}
+	if got := syncRecommendedAgent(empty); got != "none" {
+		t.Fatalf("unexpected empty recommended agent: %q", got)
+	}
+	if got := syncSummaryLine(empty); got != "outcome=noop progress=0 risk=0 mode=apply" {
+		t.Fatalf("unexpected empty summary line: %q", got)
+	}
+	if got := syncNoopReason(empty); got != "no source updates, skill upgrades, or reinjection changes detected" {
+		t.Fatalf("unexpected empty noop reason: %q", got)
+	}
+
+	emptyDryRun := Report{DryRun: true}
+	if got := syncExecutionPriority(emptyDryRun); got != "plan-feature-iteration" {
+		t.Fatalf("unexpected empty dry-run execution priority: %q", got)
+	}
+	if got := syncFollowUpGate(emptyDryRun); got != "plan-next-iteration" {
+		t.Fatalf("unexpected empty dry-run follow-up gate: %q", got)
+	}
+	if got := syncRecommendedCommand(emptyDryRun); got != "skillpm sync" {
+		t.Fatalf("unexpected empty dry-run recommended command: %q", got)
+	}
+	if got := syncRecommendedCommands(emptyDryRun); !reflect.DeepEqual(got, []string{"skillpm sync", "skillpm source list"}) {
+		t.Fatalf("unexpected empty dry-run recommended commands: %v", got)
+	}
+	if got := syncSummaryLine(emptyDryRun); got != "outcome=noop progress=0 risk=0 mode=dry-run" {
+		t.Fatalf("unexpected empty dry-run summary line: %q", got)
+	}
+	if got := syncProgressTarget(emptyDryRun); got != "none" {
+		t.Fatalf("unexpected empty dry-run progress target: %q", got)
+	}
+	if got := syncProgressSignal(emptyDryRun); got != "none" {
+		t.Fatalf("unexpected empty dry-run progress signal: %q", got)
+	}
+	if got := syncNoopReason(emptyDryRun); got != "dry-run detected no source/upgrade/reinjection deltas" {
+		t.Fatalf("unexpected empty dry-run noop reason: %q", got)
+	}
+	if got := syncRiskBreakdown(empty); got != "skipped=0 failed=0" {
+		t.Fatalf("unexpected empty risk breakdown: %q", got)
+	}
+	if got := syncRiskStatus(empty); got != "clear" {
+		t.Fatalf("unexpected empty risk status: %q", got)
+	}
+	if got := syncRiskLevel(empty); got != "none" {
+		t.Fatalf("unexpected empty risk level: %q", got)
+	}
+	if got := syncRiskClass(empty); got != "none" {
+		t.Fatalf("unexpected empty risk class: %q", got)
+	}
+	if got := syncRiskHotspot(empty); got != "none" {
+		t.Fatalf("unexpected empty risk hotspot: %q", got)
+	}
+	if got := syncRiskAgents(empty); len(got) != 0 {
+		t.Fatalf("unexpected empty risk agents: %v", got)
+	}
+	if got := syncFollowUpGate(empty); got != "monitor-next-cycle" {
+		t.Fatalf("unexpected empty follow-up gate: %q", got)
+	}
+	if got := syncExecutionPriority(empty); got != "monitor-next-cycle" {
+		t.Fatalf("unexpected empty execution priority: %q", got)
+	}
+	if got := syncFollowUpGate(emptyDryRun); got != "plan-next-iteration" {
+		t.Fatalf("unexpected empty dry-run follow-up gate: %q", got)
+	}
+	if got := syncExecutionPriority(emptyDryRun); got != "plan-feature-iteration" {
+		t.Fatalf("unexpected empty dry-run execution priority: %q", got)
+	}
+
+	blocked := Report{SkippedReinjects: []string{"ghost"}}
+	if got := totalSyncActions(blocked); got != 1 {
+		t.Fatalf("expected blocked total actions 1, got %d", got)
+	}
+	if got := totalSyncProgressActions(blocked); got != 0 {
+		t.Fatalf("expected blocked progress actions 0, got %d", got)
+	}
+	if got := totalSyncIssues(blocked); got != 1 {
+		t.Fatalf("expected blocked issues 1, got %d", got)
+	}
+	if got := syncOutcome(blocked); got != "blocked" {
+		t.Fatalf("unexpected blocked action outcome: %q", got)
+	}
+	if got := syncPrimaryAction(blocked); got != "Reinjection is blocked; resolve skipped/failed agents first before adding new work." {
+		t.Fatalf("unexpected blocked primary action: %q", got)
+	}
+	if got := syncNextAction(blocked); got != "resolve-reinjection-skips" {
+		t.Fatalf("unexpected blocked next action: %q", got)
+	}
+	if got := syncRecommendedCommand(blocked); got != "skillpm inject --agent ghost <skill-ref>" {
+		t.Fatalf("unexpected blocked recommended command: %q", got)
+	}
+	if got := syncRecommendedAgent(blocked); got != "ghost" {
+		t.Fatalf("unexpected blocked recommended agent: %q", got)
+	}
+	blockedDryRun := Report{DryRun: true, SkippedReinjects: []string{"ghost"}}
+	if got := syncPrimaryAction(blockedDryRun); got != "Sync plan is blocked by reinjection risk; resolve skipped/failed agents before applying changes." {
+		t.Fatalf("unexpected blocked dry-run primary action: %q", got)
+	}
+	if got := syncNextAction(blockedDryRun); got != "resolve-skips-then-apply" {
+		t.Fatalf("unexpected blocked dry-run next action: %q", got)
+	}
+	if got := syncRecommendedCommand(blockedDryRun); got != "skillpm inject --agent ghost <skill-ref>" {
+		t.Fatalf("unexpected blocked dry-run recommended command: %q", got)
+	}
+	if got := syncRecommendedCommands(blockedDryRun); !reflect.DeepEqual(got, []string{"skillpm inject --agent ghost <skill-ref>", "skillpm source list", "skillpm sync --dry-run", "skillpm sync"}) {
+		t.Fatalf("unexpected blocked dry-run recommended commands: %v", got)
+	}
+	if got := syncExecutionPriority(blocked); got != "stabilize-risks" {
+		t.Fatalf("unexpected blocked execution priority: %q", got)
+	}
+	if got := syncFollowUpGate(blocked); got != "blocked-by-risk" {
+		t.Fatalf("unexpected blocked follow-up gate: %q", got)
+	}
+	if got := syncProgressHotspot(blocked); got != "none" {
+		t.Fatalf("unexpected blocked progress hotspot: %q", got)
+	}
+	if got := syncRiskLevel(blocked); got != "medium" {
+		t.Fatalf("unexpected blocked risk level: %q", got)
+	}
+	if got := syncRiskClass(blocked); got != "skipped-only" {
+		t.Fatalf("unexpected blocked risk class: %q", got)
+	}
+	if got := syncRiskHotspot(blocked); got != "ghost" {
+		t.Fatalf("unexpected blocked risk hotspot: %q", got)
+	}
+
+	changedWithRiskDryRun := Report{DryRun: true, UpgradedSkills: []string{"local/forms"}, FailedReinjects: []string{"ghost (boom)"}}
+	if got := syncPrimaryAction(changedWithRiskDryRun); got != "Sync plan includes progress with failed reinjections; clear failures before applying this iteration." {
+		t.Fatalf("unexpected changed-with-risk dry-run primary action: %q", got)
+	}
+	if got := syncNextAction(changedWithRiskDryRun); got != "resolve-failures-then-apply-plan" {
+		t.Fatalf("unexpected changed-with-risk dry-run next action: %q", got)
+	}
+	if got := syncRiskClass(changedWithRiskDryRun); got != "failed-only" {
+		t.Fatalf("unexpected changed-with-risk dry-run risk class: %q", got)
+	}
+	if got := syncRecommendedCommand(changedWithRiskDryRun); got != "skillpm inject --agent ghost <skill-ref>" {
+		t.Fatalf("unexpected changed-with-risk dry-run recommended command: %q", got)
+	}
+	if got := syncRecommendedCommands(changedWithRiskDryRun); !reflect.DeepEqual(got, []string{"skillpm inject --agent ghost <skill-ref>", "skillpm source list", "skillpm sync --dry-run", "skillpm sync", "go test ./..."}) {
+		t.Fatalf("unexpected changed-with-risk dry-run recommended commands: %v", got)
+	}
+
+	changedWithSkippedRisk := Report{UpdatedSources: []string{"local"}, SkippedReinjects: []string{"ghost"}}
+	if got := syncOutcome(changedWithSkippedRisk); got != "changed-with-risk" {
+		t.Fatalf("unexpected changed-with-skipped-risk outcome: %q", got)
+	}
+	if got := syncRecommendedCommand(changedWithSkippedRisk); got != "skillpm inject --agent ghost <skill-ref>" {
+		t.Fatalf("unexpected changed-with-skipped-risk recommended command: %q", got)
+	}
+	if got := syncPrimaryAction(changedWithSkippedRisk); got != "Progress landed with skipped reinjections; clear skips before expanding scope." {
+		t.Fatalf("unexpected changed-with-skipped-risk primary action: %q", got)
+	}
+	if got := syncNextAction(changedWithSkippedRisk); got != "review-skipped-risk-items" {
+		t.Fatalf("unexpected changed-with-skipped-risk next action: %q", got)
+	}
+	if got := syncRecommendedCommands(changedWithSkippedRisk); !reflect.DeepEqual(got, []string{"skillpm inject --agent ghost <skill-ref>", "skillpm source list", "go test ./...", "skillpm sync --dry-run"}) {
+		t.Fatalf("unexpected changed-with-skipped-risk recommended commands: %v", got)
+	}
+
+	changedWithSkippedRiskDryRun := Report{DryRun: true, UpdatedSources: []string{"local"}, SkippedReinjects: []string{"ghost"}}
+	if got := syncPrimaryAction(changedWithSkippedRiskDryRun); got != "Sync plan includes progress with skipped reinjections; clear skips before applying this iteration." {
+		t.Fatalf("unexpected changed-with-skipped-risk dry-run primary action: %q", got)
+	}
+
+	changedWithMixedRisk := Report{UpdatedSources: []string{"local"}, FailedReinjects: []string{"zeta (boom)"}, SkippedReinjects: []string{"alpha"}}
+	if got := syncRecommendedAgent(changedWithMixedRisk); got != "zeta" {
+		t.Fatalf("unexpected changed-with-mixed-risk recommended agent: %q", got)
+	}
+	if got := syncRiskAgents(changedWithMixedRisk); !reflect.DeepEqual(got, []string{"alpha", "zeta"}) {
+		t.Fatalf("unexpected changed-with-mixed-risk risk agents: %v", got)
+	}
+	if got := syncRecommendedCommand(changedWithMixedRisk); got != "skillpm inject --agent zeta <skill-ref>" {
+		t.Fatalf("unexpected changed-with-mixed-risk recommended command: %q", got)
+	}
+	if got := syncRecommendedCommands(changedWithMixedRisk); !reflect.DeepEqual(got, []string{"skillpm inject --agent zeta <skill-ref>", "skillpm inject --agent alpha <skill-ref>", "skillpm source list", "go test ./...", "skillpm sync --dry-run"}) {
+		t.Fatalf("unexpected changed-with-mixed-risk recommended commands: %v", got)
+	}
+
+	changedClear := Report{UpdatedSources: []string{"local"}, UpgradedSkills: []string{"local/forms"}, Reinjected: []string{"ghost"}}
+	if got := syncFollowUpGate(changedClear); got != "ready-for-next-iteration" {
+		t.Fatalf("unexpected changed-clear follow-up gate: %q", got)
+	}
+	if got := syncRecommendedCommands(changedClear); !reflect.DeepEqual(got, []string{"skillpm source list", "go test ./...", "skillpm sync --dry-run"}) {
+		t.Fatalf("unexpected changed-clear recommended commands: %v", got)
+	}
+
+	changedClearDryRun := Report{DryRun: true, UpdatedSources: []string{"local"}, UpgradedSkills: []string{"local/forms"}}
+	if got := syncRecommendedCommands(changedClearDryRun); !reflect.DeepEqual(got, []string{"skillpm sync", "skillpm source list", "go test ./...", "skillpm sync --dry-run"}) {
+		t.Fatalf("unexpected changed-clear dry-run recommended commands: %v", got)
+	}
+}
+
+func TestRiskAgentName(t *testing.T) {
+	if got := riskAgentName("ghost: runtime unavailable"); got != "ghost" {
+		t.Fatalf("expected ghost agent, got %q", got)
+	}
+	if got := riskAgentName(" ghost "); got != "ghost" {
+		t.Fatalf("expected trimmed agent, got %q", got)
+	}
+	if got := riskAgentName("ghost (boom)"); got != "ghost" {
+		t.Fatalf("expected parsed agent without error suffix, got %q", got)
+	}
+	if got := riskAgentName("   "); got != "" {
+		t.Fatalf("expected empty agent for blank input, got %q", got)
+	}
+}
+
+func TestUniqueNonEmptyTrimsAndDeduplicates(t *testing.T) {
+	got := uniqueNonEmpty([]string{"  skillpm sync  ", "", "skillpm sync", "go test ./...", "go test ./...", "   "})
+	if !reflect.DeepEqual(got, []string{"skillpm sync", "go test ./..."}) {
+		t.Fatalf("unexpected unique non-empty output: %+v", got)
+	}
+}
+
+func syncReportFixture() Report {
+	return Report{
+		UpdatedSources:   []string{"a", "b"},
+		UpgradedSkills:   []string{"c"},
+		Reinjected:       []string{"d"},
+		SkippedReinjects: []string{"e"},
+		FailedReinjects:  []string{"f", "g"},
+	}
+}
+
+func syncReportFixtureEmpty() Report {
+	return Report{}
+}
@@ -3,9 +3,12 @@ package sync
 import (
 	"context"
 	"fmt"
+	"os"
 	"sort"
+	"time"
 
 	"skillpm/internal/adapter"
+	"skillpm/internal/audit"
 	"skillpm/internal/config"
 	"skillpm/internal/installer"
 	"skillpm/internal/resolver"
@@ -24,17 +27,66 @@ type Service struct {
 	Security    *security.Engine
 	Manifest    *config.ProjectManifest
 	ProjectRoot string
+	Scope       config.Scope
+	// PruneRemoved uninstalls skills that are installed but absent from the
+	// lockfile, and removes them from agents they were injected into.
+	PruneRemoved bool
+	// Concurrency is how many sources are updated and resolved at once;
+	// 0 uses DefaultConcurrency. Installs, state changes and reinjection
+	// always run one at a time.
+	Concurrency int
 }
 
+// DefaultConcurrency is the number of sources sync updates and resolves at
+// once when Service.Concurrency is unset.
+const DefaultConcurrency = 4
+
 type Report struct {
 	UpdatedSources   []string `json:"updatedSources"`
 	UpgradedSkills   []string `json:"upgradedSkills"`
+	RemovedSkills    []string `json:"removedSkills,omitempty"`
 	Reinjected       []string `json:"reinjectedAgents"`
 	SkippedReinjects []string `json:"skippedReinjects,omitempty"`
 	FailedReinjects  []string `json:"failedReinjects,omitempty"`
-	DryRun           bool     `json:"dryRun,omitempty"`
+	// SkippedReinjectDetails and FailedReinjectDetails carry the same entries
+	// as SkippedReinjects and FailedReinjects in structured form.
+	SkippedReinjectDetails []ReinjectIssue `json:"skippedReinjectDetails,omitempty"`
+	FailedReinjectDetails  []ReinjectIssue `json:"failedReinjectDetails,omitempty"`
+	DryRun                 bool            `json:"dryRun,omitempty"`
+}
+
+// Changes counts the source updates, upgrades, removals and reinjections in
+// the report.
+func (r Report) Changes() int {
+	return len(r.UpdatedSources) + len(r.UpgradedSkills) + len(r.RemovedSkills) + len(r.Reinjected)
+}
+
+// Outcome classifies the report as noop, changed, blocked (skipped or
+// failed reinjections and nothing else) or changed-with-risk.
+func (r Report) Outcome() string {
+	issues := len(r.SkippedReinjects) + len(r.FailedReinjects)
+	switch changes := r.Changes(); {
+	case changes == 0 && issues == 0:
+		return "noop"
+	case changes == 0:
+		return "blocked"
+	case issues > 0:
+		return "changed-with-risk"
+	}
+	return "changed"
 }
 
+// ReinjectIssue describes an agent whose reinjection was skipped or failed.
+type ReinjectIssue struct {
+	Agent   string `json:"agent"`
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message"`
+}
+
+// reinjectSkippedCode marks agents skipped because no adapter runtime is
+// available.
+const reinjectSkippedCode = "SYNC_RUNTIME_UNAVAILABLE"
+
 func (s *Service) Run(ctx context.Context, cfg *config.Config, lockPath string, force bool, dryRun bool) (Report, error) {
 	if s.Sources == nil || s.Resolver == nil || s.Installer == nil {
 		return Report{}, fmt.Errorf("SYNC_SETUP: sync dependencies not configured")
@@ -47,7 +99,11 @@ func (s *Service) Run(ctx context.Context, cfg *config.Config, lockPath string,
 		cloned := cloneConfig(*cfg)
 		runCfg = &cloned
 	}
-	updates, err := s.Sources.Update(ctx, runCfg, "")
+	workers := s.Concurrency
+	if workers <= 0 {
+		workers = DefaultConcurrency
+	}
+	updates, err := s.Sources.UpdateConcurrent(ctx, runCfg, workers)
 	if err != nil {
 		return Report{}, err
 	}
@@ -74,7 +130,19 @@ func (s *Service) Run(ctx context.Context, cfg *config.Config, lockPath string,
 			refs = append(refs, rec.SkillRef)
 		}
 	}
-	if len(refs) == 0 {
+	pruned := map[string]struct{}{}
+	if s.PruneRemoved {
+		removed, err := unlockedSkills(st, lockPath)
+		if err != nil {
+			return Report{}, err
+		}
+		for _, ref := range removed {
+			pruned[ref] = struct{}{}
+		}
+		refs = withoutRefs(refs, pruned)
+		report.RemovedSkills = removed
+	}
+	if len(refs) == 0 && len(pruned) == 0 {
 		sort.Strings(report.UpdatedSources)
 		return report, nil
 	}
@@ -85,9 +153,56 @@ func (s *Service) Run(ctx context.Context, cfg *config.Config, lockPath string,
 	if err != nil {
 		return Report{}, err
 	}
-	resolved, err := s.Resolver.ResolveMany(ctx, *runCfg, refs, lock)
-	if err != nil {
-		return Report{}, err
+	var resolved []resolver.ResolvedSkill
+	if len(refs) > 0 {
+		if resolved, err = s.Resolver.ResolveManyConcurrent(ctx, *runCfg, refs, lock, workers); err != nil {
+			return Report{}, err
+		}
+	}
+
+	// Only reinject records belonging to this scope; legacy records without
+	// a scope are assumed to belong to the state they were found in.
+	scope := string(s.Scope)
+	var injections []store.InjectionState
+	for _, inj := range st.Injections {
+		if inj.EffectiveScope(scope) == scope {
+			injections = append(injections, inj)
+		}
+	}
+	detachFailed := map[string]struct{}{}
+	if len(pruned) > 0 && !dryRun {
+		// Pruned skills leave the agents before their files are
+		// uninstalled; one that cannot be detached stays installed.
+		kept := map[string]struct{}{}
+		var trimmed []store.InjectionState
+		for i, inj := range injections {
+			keep := withoutRefs(inj.Skills, pruned)
+			if len(keep) == len(inj.Skills) {
+				continue
+			}
+			dropped := withoutRefs(inj.Skills, toSet(keep))
+			if err := s.detach(ctx, inj.Agent, dropped, scope); err != nil {
+				if s.Runtime != nil {
+					report.addFailedReinject(inj.Agent, err)
+					detachFailed[inj.Agent] = struct{}{}
+				}
+				for _, ref := range dropped {
+					kept[refKey(ref)] = struct{}{}
+				}
+				continue
+			}
+			injections[i].Skills = keep
+			trimmed = append(trimmed, injections[i])
+		}
+		if err := s.saveInjections(trimmed); err != nil {
+			return Report{}, err
+		}
+		report.RemovedSkills = withoutRefs(report.RemovedSkills, kept)
+		if len(report.RemovedSkills) > 0 {
+			if _, err := s.Installer.Uninstall(ctx, report.RemovedSkills, lockPath); err != nil {
+				return Report{}, err
+			}
+		}
 	}
 	upgrades := make([]resolver.ResolvedSkill, 0, len(resolved))
 	seenUpgrades := map[string]struct{}{}
@@ -101,6 +216,15 @@ func (s *Service) Run(ctx context.Context, cfg *config.Config, lockPath string,
 		if s.Security != nil && s.Security.Scanner != nil {
 			contents := resolvedToScanContents(upgrades)
 			scanReport := s.Security.Scanner.Scan(ctx, contents)
+			if s.Installer.Audit != nil {
+				_ = s.Installer.Audit.Log(audit.Event{
+					Operation: "security_scan",
+					Phase:     "complete",
+					Status:    scanReport.MaxSeverity().String(),
+					Message:   fmt.Sprintf("skills=%d findings=%d max_severity=%s", len(upgrades), len(scanReport.Findings), scanReport.MaxSeverity()),
+					Fields:    map[string]string{"gate": s.Security.Scanner.Gate(force)},
+				})
+			}
 			if err := s.Security.Scanner.Enforce(scanReport, force); err != nil {
 				return Report{}, err
 			}
@@ -114,44 +238,161 @@ func (s *Service) Run(ctx context.Context, cfg *config.Config, lockPath string,
 	seenSkipped := map[string]struct{}{}
 	if dryRun {
 		if s.Runtime != nil {
-			for _, inj := range st.Injections {
+			for _, inj := range injections {
 				if _, err := s.Runtime.Get(inj.Agent); err != nil {
-					report.FailedReinjects = append(report.FailedReinjects, fmt.Sprintf("%s (%s)", inj.Agent, err))
+					report.addFailedReinject(inj.Agent, err)
 					continue
 				}
 				appendUnique(&report.Reinjected, seenReinjected, inj.Agent)
 			}
 		} else {
-			for _, inj := range st.Injections {
-				appendUnique(&report.SkippedReinjects, seenSkipped, inj.Agent)
+			for _, inj := range injections {
+				report.addSkippedReinject(seenSkipped, inj.Agent)
 			}
 		}
 	} else if s.Runtime != nil {
-		for _, inj := range st.Injections {
+		for _, inj := range injections {
+			if _, failed := detachFailed[inj.Agent]; failed {
+				continue
+			}
 			adp, err := s.Runtime.Get(inj.Agent)
 			if err != nil {
-				report.FailedReinjects = append(report.FailedReinjects, fmt.Sprintf("%s (%s)", inj.Agent, err))
+				report.addFailedReinject(inj.Agent, err)
 				continue
 			}
-			if _, err := adp.Inject(ctx, adapterapi.InjectRequest{SkillRefs: inj.Skills}); err != nil {
-				report.FailedReinjects = append(report.FailedReinjects, fmt.Sprintf("%s (%s)", inj.Agent, err))
-				continue
+			if len(inj.Skills) > 0 {
+				if _, err := adp.Inject(ctx, adapterapi.InjectRequest{SkillRefs: inj.Skills, Scope: scope}); err != nil {
+					report.addFailedReinject(inj.Agent, err)
+					continue
+				}
 			}
 			appendUnique(&report.Reinjected, seenReinjected, inj.Agent)
 		}
 	} else {
-		for _, inj := range st.Injections {
-			appendUnique(&report.SkippedReinjects, seenSkipped, inj.Agent)
+		for _, inj := range injections {
+			report.addSkippedReinject(seenSkipped, inj.Agent)
 		}
 	}
 	sort.Strings(report.UpdatedSources)
 	sort.Strings(report.UpgradedSkills)
+	sort.Strings(report.RemovedSkills)
 	sort.Strings(report.Reinjected)
 	sort.Strings(report.SkippedReinjects)
 	sort.Strings(report.FailedReinjects)
+	sortReinjectIssues(report.SkippedReinjectDetails)
+	sortReinjectIssues(report.FailedReinjectDetails)
 	return report, nil
 }
 
+// unlockedSkills returns the installed skills that have no entry in the
+// lockfile at lockPath. The lockfile must exist: pruning against a missing
+// one would remove everything.
+func unlockedSkills(st store.State, lockPath string) ([]string, error) {
+	if _, err := os.Stat(lockPath); err != nil {
+		return nil, fmt.Errorf("SYNC_PRUNE_NO_LOCK: --prune-removed needs an existing lockfile: %w", err)
+	}
+	lock, err := store.LoadLockfile(lockPath)
+	if err != nil {
+		return nil, err
+	}
+	var out []string
+	for _, rec := range st.Installed {
+		if _, ok := store.FindLock(lock, rec.SkillRef); !ok {
+			out = append(out, rec.SkillRef)
+		}
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+// withoutRefs drops refs (with or without a constraint) whose skill is in
+// drop.
+func withoutRefs(refs []string, drop map[string]struct{}) []string {
+	out := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		if _, ok := drop[refKey(ref)]; !ok {
+			out = append(out, ref)
+		}
+	}
+	return out
+}
+
+// refKey is the source/skill key of ref that withoutRefs matches on.
+func refKey(ref string) string {
+	if parsed, err := resolver.ParseRef(ref); err == nil {
+		return parsed.Source + "/" + parsed.Skill
+	}
+	return ref
+}
+
+func toSet(items []string) map[string]struct{} {
+	out := make(map[string]struct{}, len(items))
+	for _, item := range items {
+		out[item] = struct{}{}
+	}
+	return out
+}
+
+// detach removes refs from agent. Without an adapter runtime nothing can
+// be removed, so the refs stay injected.
+func (s *Service) detach(ctx context.Context, agent string, refs []string, scope string) error {
+	if s.Runtime == nil {
+		return fmt.Errorf("%s: no adapter runtime", reinjectSkippedCode)
+	}
+	adp, err := s.Runtime.Get(agent)
+	if err != nil {
+		return err
+	}
+	_, err = adp.Remove(ctx, adapterapi.RemoveRequest{SkillRefs: refs, Scope: scope})
+	return err
+}
+
+// saveInjections records injection states whose skill lists changed.
+func (s *Service) saveInjections(injections []store.InjectionState) error {
+	if len(injections) == 0 {
+		return nil
+	}
+	st, err := store.LoadState(s.StateRoot)
+	if err != nil {
+		return err
+	}
+	for _, inj := range injections {
+		inj.UpdatedAt = time.Now().UTC()
+		store.SetInjection(&st, inj)
+	}
+	return store.SaveState(s.StateRoot, st)
+}
+
+func (r *Report) addFailedReinject(agent string, err error) {
+	r.FailedReinjects = append(r.FailedReinjects, fmt.Sprintf("%s (%s)", agent, err))
+	r.FailedReinjectDetails = append(r.FailedReinjectDetails, ReinjectIssue{
+		Agent:   agent,
+		Code:    audit.ErrorCode(err),
+		Message: err.Error(),
+	})
+}
+
+func (r *Report) addSkippedReinject(seen map[string]struct{}, agent string) {
+	if _, ok := seen[agent]; ok {
+		return
+	}
+	appendUnique(&r.SkippedReinjects, seen, agent)
+	r.SkippedReinjectDetails = append(r.SkippedReinjectDetails, ReinjectIssue{
+		Agent:   agent,
+		Code:    reinjectSkippedCode,
+		Message: "adapter runtime unavailable",
+	})
+}
+
+func sortReinjectIssues(issues []ReinjectIssue) {
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].Agent != issues[j].Agent {
+			return issues[i].Agent < issues[j].Agent
+		}
+		return issues[i].Message < issues[j].Message
+	})
+}
+
 func appendUnique(target *[]string, seen map[string]struct{}, value string) {
 	if _, ok := seen[value]; ok {
 		return
@@ -4,8 +4,10 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"skillpm/internal/adapter"
 	"skillpm/internal/config"
@@ -13,6 +15,7 @@ import (
 	"skillpm/internal/resolver"
 	"skillpm/internal/source"
 	"skillpm/internal/store"
+	"skillpm/pkg/adapterapi"
 )
 
 func TestRunRequiresConfiguredDependencies(t *testing.T) {
@@ -54,6 +57,24 @@ func TestRunPropagatesSourceUpdateError(t *testing.T) {
 	}
 }
 
+func TestRunRetriesTransientSourceUpdateFailures(t *testing.T) {
+	sources := source.NewManager(nil, t.TempDir(), true)
+	sources.Retries = 1
+	sources.RetryBackoff = time.Millisecond
+	svc := &Service{
+		Sources:   sources,
+		Resolver:  &resolver.Service{Sources: sources},
+		Installer: &installer.Service{Root: t.TempDir()},
+		StateRoot: t.TempDir(),
+	}
+	cfg := testConfig(t)
+	cfg.Sources[0].URL = "http://127.0.0.1:1/skills.git"
+	_, err := svc.Run(context.Background(), cfg, filepath.Join(t.TempDir(), "skills.lock"), false, false)
+	if err == nil || !strings.Contains(err.Error(), "after 2 attempts") {
+		t.Fatalf("expected the source update retried once, got %v", err)
+	}
+}
+
 func TestRunReturnsEarlyWhenNoInstalledSkills(t *testing.T) {
 	sources := source.NewManager(nil, t.TempDir(), false)
 	svc := &Service{
@@ -172,6 +193,9 @@ func TestRunRecordsRuntimeGetErrorDuringReinject(t *testing.T) {
 	if len(report.FailedReinjects) != 1 || !strings.Contains(report.FailedReinjects[0], "ADP_NOT_SUPPORTED") {
 		t.Fatalf("expected ADP_NOT_SUPPORTED in failed reinjections, got %+v", report.FailedReinjects)
 	}
+	if len(report.FailedReinjectDetails) != 1 || report.FailedReinjectDetails[0].Agent != "ghost" || report.FailedReinjectDetails[0].Code != "ADP_NOT_SUPPORTED" {
+		t.Fatalf("expected structured ADP_NOT_SUPPORTED entry for ghost, got %+v", report.FailedReinjectDetails)
+	}
 }
 
 func TestRunRecordsSkippedReinjectionsWhenRuntimeUnavailable(t *testing.T) {
@@ -204,6 +228,41 @@ func TestRunRecordsSkippedReinjectionsWhenRuntimeUnavailable(t *testing.T) {
 	if len(report.SkippedReinjects) != 1 || report.SkippedReinjects[0] != "ghost" {
 		t.Fatalf("expected skipped reinjection for ghost, got %+v", report.SkippedReinjects)
 	}
+	want := []ReinjectIssue{{Agent: "ghost", Code: "SYNC_RUNTIME_UNAVAILABLE", Message: "adapter runtime unavailable"}}
+	if !reflect.DeepEqual(report.SkippedReinjectDetails, want) {
+		t.Fatalf("expected structured skipped entry %+v, got %+v", want, report.SkippedReinjectDetails)
+	}
+}
+
+func TestRunOnlyReinjectsRecordsFromOwnScope(t *testing.T) {
+	stateRoot := t.TempDir()
+	st := store.State{
+		Installed: []store.InstalledSkill{{SkillRef: "local/alpha", ResolvedVersion: "0.0.0+git.latest"}},
+		Injections: []store.InjectionState{
+			{Agent: "ghost", Scope: "global", Skills: []string{"local/alpha"}},
+			{Agent: "phantom", Scope: "project", Skills: []string{"local/alpha"}},
+			{Agent: "legacy", Skills: []string{"local/alpha"}},
+		},
+	}
+	if err := store.SaveState(stateRoot, st); err != nil {
+		t.Fatalf("save state failed: %v", err)
+	}
+
+	sources := source.NewManager(nil, t.TempDir(), false)
+	svc := &Service{
+		Sources:   sources,
+		Resolver:  &resolver.Service{Sources: sources},
+		Installer: &installer.Service{Root: t.TempDir()},
+		StateRoot: stateRoot,
+		Scope:     config.ScopeGlobal,
+	}
+	report, err := svc.Run(context.Background(), testConfig(t), filepath.Join(t.TempDir(), "skills.lock"), false, false)
+	if err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	if strings.Join(report.SkippedReinjects, ",") != "ghost,legacy" {
+		t.Fatalf("expected only global and legacy records, got %+v", report.SkippedReinjects)
+	}
 }
 
 func TestRunDeduplicatesReportLists(t *testing.T) {
@@ -373,6 +432,129 @@ func TestRunDryRunPlansChangesWithoutMutatingState(t *testing.T) {
 	}
 }
 
+func TestRunPruneRemovedUninstallsSkillsMissingFromLock(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	stateRoot := filepath.Join(home, ".skillpm")
+	installedDir := filepath.Join(store.InstalledRoot(stateRoot), "local_gone@1.0.0")
+	if err := os.MkdirAll(installedDir, 0o755); err != nil {
+		t.Fatalf("mkdir installed dir failed: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(installedDir, "SKILL.md"), []byte("---\nname: gone\ndescription: Gone\n---\n"), 0o644); err != nil {
+		t.Fatalf("write SKILL.md failed: %v", err)
+	}
+	cfg := testConfig(t)
+	cfg.Adapters = []config.AdapterConfig{{Name: "claude", Enabled: true, Scope: "global"}}
+	runtime, err := adapter.NewRuntime(stateRoot, *cfg, "")
+	if err != nil {
+		t.Fatalf("new runtime failed: %v", err)
+	}
+	adp, _ := runtime.Get("claude")
+	if _, err := adp.Inject(context.Background(), adapterapi.InjectRequest{SkillRefs: []string{"local/gone"}}); err != nil {
+		t.Fatalf("inject failed: %v", err)
+	}
+	if err := store.SaveState(stateRoot, store.State{
+		Installed:  []store.InstalledSkill{{SkillRef: "local/gone", Source: "local", Skill: "gone", ResolvedVersion: "1.0.0"}},
+		Injections: []store.InjectionState{{Agent: "claude", Skills: []string{"local/gone"}}},
+	}); err != nil {
+		t.Fatalf("save state failed: %v", err)
+	}
+	lockPath := filepath.Join(t.TempDir(), "skills.lock")
+	sources := source.NewManager(nil, t.TempDir(), false)
+	svc := &Service{
+		Sources:      sources,
+		Resolver:     &resolver.Service{Sources: sources},
+		Installer:    &installer.Service{Root: stateRoot},
+		Runtime:      runtime,
+		StateRoot:    stateRoot,
+		PruneRemoved: true,
+	}
+	if _, err := svc.Run(context.Background(), cfg, lockPath, false, false); err == nil || !strings.Contains(err.Error(), "SYNC_PRUNE_NO_LOCK") {
+		t.Fatalf("expected SYNC_PRUNE_NO_LOCK without a lockfile, got %v", err)
+	}
+	if err := store.SaveLockfile(lockPath, store.Lockfile{Version: store.LockVersion}); err != nil {
+		t.Fatalf("save lockfile failed: %v", err)
+	}
+
+	report, err := svc.Run(context.Background(), cfg, lockPath, false, true)
+	if err != nil || !reflect.DeepEqual(report.RemovedSkills, []string{"local/gone"}) {
+		t.Fatalf("expected dry-run to plan removing local/gone, got %+v (%v)", report.RemovedSkills, err)
+	}
+	if st, _ := store.LoadState(stateRoot); len(st.Installed) != 1 {
+		t.Fatalf("expected dry-run to keep local/gone installed, got %+v", st.Installed)
+	}
+
+	report, err = svc.Run(context.Background(), cfg, lockPath, false, false)
+	if err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	if !reflect.DeepEqual(report.RemovedSkills, []string{"local/gone"}) {
+		t.Fatalf("expected local/gone removed, got %+v", report.RemovedSkills)
+	}
+	st, err := store.LoadState(stateRoot)
+	if err != nil {
+		t.Fatalf("load state failed: %v", err)
+	}
+	if len(st.Installed) != 0 || len(st.Injections) != 1 || len(st.Injections[0].Skills) != 0 {
+		t.Fatalf("expected skill uninstalled and dropped from injections, got %+v", st)
+	}
+	if _, err := os.Stat(filepath.Join(home, ".claude", "skills", "gone")); !os.IsNotExist(err) {
+		t.Fatalf("expected injected skill dir removed, got %v", err)
+	}
+}
+
+func TestRunPruneRemovedKeepsSkillsItCannotDetach(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	stateRoot := filepath.Join(home, ".skillpm")
+	installedDir := filepath.Join(store.InstalledRoot(stateRoot), "local_gone@1.0.0")
+	if err := os.MkdirAll(installedDir, 0o755); err != nil {
+		t.Fatalf("mkdir installed dir failed: %v", err)
+	}
+	cfg := testConfig(t)
+	runtime, err := adapter.NewRuntime(stateRoot, *cfg, "")
+	if err != nil {
+		t.Fatalf("new runtime failed: %v", err)
+	}
+	if err := store.SaveState(stateRoot, store.State{
+		Installed:  []store.InstalledSkill{{SkillRef: "local/gone", Source: "local", Skill: "gone", ResolvedVersion: "1.0.0"}},
+		Injections: []store.InjectionState{{Agent: "ghost", Skills: []string{"local/gone"}}},
+	}); err != nil {
+		t.Fatalf("save state failed: %v", err)
+	}
+	lockPath := filepath.Join(t.TempDir(), "skills.lock")
+	if err := store.SaveLockfile(lockPath, store.Lockfile{Version: store.LockVersion}); err != nil {
+		t.Fatalf("save lockfile failed: %v", err)
+	}
+	sources := source.NewManager(nil, t.TempDir(), false)
+	svc := &Service{
+		Sources:      sources,
+		Resolver:     &resolver.Service{Sources: sources},
+		Installer:    &installer.Service{Root: stateRoot},
+		Runtime:      runtime,
+		StateRoot:    stateRoot,
+		PruneRemoved: true,
+	}
+
+	report, err := svc.Run(context.Background(), cfg, lockPath, false, false)
+	if err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	if len(report.RemovedSkills) != 0 || len(report.FailedReinjects) != 1 {
+		t.Fatalf("expected local/gone kept and the ghost agent reported, got %+v", report)
+	}
+	st, err := store.LoadState(stateRoot)
+	if err != nil {
+		t.Fatalf("load state failed: %v", err)
+	}
+	if len(st.Installed) != 1 || len(st.Injections) != 1 || len(st.Injections[0].Skills) != 1 {
+		t.Fatalf("expected local/gone still installed and injected, got %+v", st)
+	}
+	if _, err := os.Stat(installedDir); err != nil {
+		t.Fatalf("expected installed dir kept, got %v", err)
+	}
+}
+
 func testConfig(t *testing.T) *config.Config {
 	t.Helper()
 	repoURL := setupBareRepo(t, map[string]map[string]string{
@@ -0,0 +1,53 @@
+// Package workpool runs independent tasks with bounded concurrency.
+package workpool
+
+import (
+	"context"
+	"sync"
+)
+
+// Run calls fn for every index in [0, n) with at most workers calls in
+// flight and returns each call's error by index. Indexes that have not
+// started when ctx is done are not run and get ctx's error. workers below
+// one runs the tasks one at a time.
+func Run(ctx context.Context, n, workers int, fn func(i int) error) []error {
+	errs := make([]error, n)
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > n {
+		workers = n
+	}
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				// The send can win a race with ctx being done.
+				if err := ctx.Err(); err != nil {
+					errs[i] = err
+					continue
+				}
+				errs[i] = fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		if err := ctx.Err(); err != nil {
+			for ; i < n; i++ {
+				errs[i] = err
+			}
+			break
+		}
+		select {
+		case next <- i:
+		case <-ctx.Done():
+			errs[i] = ctx.Err()
+		}
+	}
+	close(next)
+	wg.Wait()
+	return errs
+}
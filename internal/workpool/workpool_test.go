@@ -0,0 +1,54 @@
+package workpool
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunBoundsConcurrencyAndKeepsErrorsByIndex(t *testing.T) {
+	var running, peak int32
+	errs := Run(context.Background(), 8, 3, func(i int) error {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		if i%2 == 1 {
+			return errors.New("odd")
+		}
+		return nil
+	})
+	if peak > 3 {
+		t.Fatalf("expected at most 3 tasks at once, saw %d", peak)
+	}
+	for i, err := range errs {
+		if (i%2 == 1) != (err != nil) {
+			t.Fatalf("task %d: unexpected error %v", i, err)
+		}
+	}
+}
+
+func TestRunStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var ran int32
+	errs := Run(ctx, 10, 1, func(i int) error {
+		atomic.AddInt32(&ran, 1)
+		if i == 1 {
+			cancel()
+		}
+		return nil
+	})
+	if ran > 3 {
+		t.Fatalf("expected cancel to stop the pool promptly, %d tasks ran", ran)
+	}
+	if !errors.Is(errs[9], context.Canceled) {
+		t.Fatalf("expected unstarted tasks to report cancellation, got %v", errs[9])
+	}
+}